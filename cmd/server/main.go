@@ -25,9 +25,37 @@
 //
 //	@tag.name		articles
 //	@tag.description	記事管理API
+//
+//	@tag.name		auth
+//	@tag.description	認証API
+//
+//	@tag.name		events
+//	@tag.description	Server-Sent Events によるイベント配信API
+//
+//	@tag.name		webhooks
+//	@tag.description	新着記事を外部サービスへ通知する Webhook の管理API
+//
+//	@tag.name		tags
+//	@tag.description	記事のタグの管理API
+//
+//	@tag.name		annotations
+//	@tag.description	記事の本文に付ける注釈（ハイライトとメモ）の管理API
+//
+//	@tag.name		rules
+//	@tag.description	取り込む記事を既読・後で見る・除外・タグ付けするルールの管理API
+//
+//	@tag.name		maintenance
+//	@tag.description	保持設定による古い記事の削除などデータベースの保守API
+//
+//	@securityDefinitions.apikey	BearerAuth
+//	@in							header
+//	@name						Authorization
+//	@description				個人APIトークンを "Bearer <token>" の形式で指定します。ブラウザからはセッションCookieで認証されます
 package main
 
 import (
+	"context"
+	"crypto/rand"
 	"database/sql"
 	"fmt"
 	"log"
@@ -44,12 +72,71 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 
 	"feedapp/internal/config"
+	"feedapp/internal/events"
+	"feedapp/internal/extractor"
+	"feedapp/internal/favicon"
+	"feedapp/internal/fetcher"
 	"feedapp/internal/handler"
+	"feedapp/internal/imageproxy"
+	"feedapp/internal/model"
+	"feedapp/internal/oidc"
+	"feedapp/internal/plugin"
 	"feedapp/internal/repository"
+	"feedapp/internal/secretbox"
 	"feedapp/internal/service"
+	"feedapp/internal/snapshot"
+	"feedapp/internal/websub"
 	_ "feedapp/docs" // Swagger docs
 )
 
+// refreshCheckInterval は更新対象のフィードを確認する間隔です。
+const refreshCheckInterval = time.Minute
+
+// webhookCheckInterval は再試行待ちの Webhook の配信を確認する間隔です。
+const webhookCheckInterval = 15 * time.Second
+
+// websubRenewCheckInterval は期限が近づいた WebSub の購読を確認する間隔です。
+const websubRenewCheckInterval = 10 * time.Minute
+
+// purgeInterval は保持設定による記事の削除を実行する間隔です。
+const purgeInterval = 24 * time.Hour
+
+// snapshotCheckInterval は取得待ちのスナップショットを確認する間隔です。
+const snapshotCheckInterval = 5 * time.Minute
+
+// migrationFiles は実行順に並べたマイグレーションファイルの一覧です。
+var migrationFiles = []string{
+	"migrations/20250628080159_create_tables.sql",
+	"migrations/20250628081751_insert_test_data.sql",
+	"migrations/20250705090000_create_article_sources.sql",
+	"migrations/20250705100000_add_article_metadata.sql",
+	"migrations/20250705110000_add_article_media_state.sql",
+	"migrations/20250705120000_add_article_raw_content.sql",
+	"migrations/20250705130000_add_full_content_extraction.sql",
+	"migrations/20250705140000_create_users.sql",
+	"migrations/20250705150000_create_subscriptions.sql",
+	"migrations/20250705160000_create_user_identities.sql",
+	"migrations/20250705170000_add_article_item_ids.sql",
+	"migrations/20250705180000_add_fever_api.sql",
+	"migrations/20250705190000_create_webhooks.sql",
+	"migrations/20250705200000_create_websub_subscriptions.sql",
+	"migrations/20250705210000_create_rules.sql",
+	"migrations/20250705220000_create_annotations.sql",
+	"migrations/20250705230000_add_article_retention.sql",
+	"migrations/20250706000000_create_article_snapshots.sql",
+	"migrations/20250706010000_create_feed_icons.sql",
+	"migrations/20250706020000_add_feed_fetch_limits.sql",
+	"migrations/20250706030000_add_feed_auth.sql",
+	"migrations/20250706040000_add_feed_proxy.sql",
+	"migrations/20250706050000_add_feed_parse_warning.sql",
+	"migrations/20250706060000_migrate_legacy_ownership.sql",
+	"migrations/20250706070000_drop_article_guid.sql",
+	"migrations/20250706080000_unshare_proxied_feeds.sql",
+	"migrations/20250706090000_drop_article_url_key.sql",
+	"migrations/20250706100000_add_private_articles.sql",
+	"migrations/20250706110000_hold_legacy_article_states.sql",
+}
+
 func main() {
 	// 設定の読み込み
 	cfg, err := config.LoadConfig()
@@ -64,20 +151,141 @@ func main() {
 	}
 	defer db.Close()
 
+	// フィードの認証情報の暗号化
+	secrets, err := secretbox.New(cfg.Encryption.Key)
+	if err != nil {
+		log.Fatalf("Failed to initialize encryption: %v", err)
+	}
+	if secrets == nil {
+		log.Println("ENCRYPTION_KEY is not set; feeds with credentials cannot be saved.")
+	}
+
 	// リポジトリの初期化
 	folderRepo := repository.NewFolderRepository(db)
-	feedRepo := repository.NewFeedRepository(db)
+	feedRepo := repository.NewFeedRepository(db, secrets)
 	articleRepo := repository.NewArticleRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	apiTokenRepo := repository.NewAPITokenRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	websubRepo := repository.NewWebSubRepository(db)
+	ruleRepo := repository.NewRuleRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	annotationRepo := repository.NewAnnotationRepository(db)
+	retentionRepo := repository.NewRetentionRepository(db)
+	snapshotRepo := repository.NewSnapshotRepository(db)
+	feedIconRepo := repository.NewFeedIconRepository(db)
+
+	// 外部サイトへのリクエストに使用するクライアント。フィードや記事のURLはユーザーが指定するため、
+	// 許可リストにないプライベートアドレスなどへの接続は拒否する。
+	// 取得先のサイトへの負荷を抑えるため、ホストごとのリクエスト数と同時リクエスト数を制限する
+	allowlist, err := fetcher.NewAllowlist(cfg.Fetch.AllowedHosts)
+	if err != nil {
+		log.Fatalf("Invalid FETCH_ALLOWED_HOSTS: %v", err)
+	}
+	fetchOptions := []fetcher.Option{
+		fetcher.WithPublicAddressesOnly(),
+		fetcher.WithAllowlist(allowlist),
+		fetcher.WithTimeout(cfg.Fetch.Timeout),
+		fetcher.WithMaxRedirects(cfg.Fetch.MaxRedirects),
+		fetcher.WithUserAgent(fetcher.UserAgent(cfg.Fetch.UserAgent, cfg.Fetch.ContactURL)),
+		fetcher.WithHostLimits(fetcher.HostLimits{
+			RequestsPerSecond: cfg.Fetch.RequestsPerSecond,
+			Burst:             cfg.Fetch.Burst,
+			MaxConcurrent:     cfg.Fetch.MaxConcurrentPerHost,
+		}),
+	}
+	if cfg.Fetch.Proxy != "" {
+		proxyURL, err := fetcher.ParseProxyURL(cfg.Fetch.Proxy)
+		if err != nil {
+			log.Fatalf("Invalid FETCH_PROXY: %v", err)
+		}
+		fetchOptions = append(fetchOptions, fetcher.WithProxy(proxyURL))
+	}
+	fetchClient := fetcher.NewClient(append(fetchOptions, fetcher.WithMaxBodySize(cfg.Fetch.MaxBodySize))...)
+	contentExtractor := extractor.New(fetchClient)
+
+	// 記事中の画像を中継する画像プロキシ
+	imageSigner := imageproxy.NewSigner(imageProxyKey(cfg.ImageProxy.Key), cfg.ImageProxy.BaseURL)
+	imageCache, err := imageproxy.NewCache(cfg.ImageProxy.CacheDir, cfg.ImageProxy.CacheSize)
+	if err != nil {
+		log.Fatalf("Failed to initialize image cache: %v", err)
+	}
+	imageClient := fetcher.NewClient(append(fetchOptions, fetcher.WithMaxBodySize(cfg.ImageProxy.MaxSize))...)
+
+	// 接続中のクライアントへイベントを配信するブローカー
+	eventBroker := events.NewBroker(events.DefaultBufferSize)
 
 	// サービスの初期化
 	folderService := service.NewFolderService(folderRepo)
-	feedService := service.NewFeedService(feedRepo)
-	articleService := service.NewArticleService(articleRepo)
+	feedService := service.NewFeedService(feedRepo, folderRepo)
+	feedIconService := service.NewFeedIconService(feedIconRepo, feedRepo, favicon.New(fetchClient))
+	snapshotService := service.NewSnapshotService(snapshotRepo, articleRepo, snapshot.NewStore(cfg.Snapshot.Dir), snapshot.New(fetchClient, cfg.Snapshot.MaxSize))
+	articleService := service.NewArticleService(articleRepo, feedRepo, folderRepo, contentExtractor, eventBroker, snapshotService, imageSigner)
+	imageProxyService := service.NewImageProxyService(imageSigner, imageproxy.New(imageClient, imageCache))
+	authService := service.NewAuthService(userRepo, sessionRepo, apiTokenRepo, cfg.Auth.SessionTTL)
+	webhookService := service.NewWebhookService(webhookRepo, feedRepo, folderRepo)
+	webhookDispatcher := service.NewWebhookDispatcher(webhookRepo, fetchClient)
+	websubService := service.NewWebSubService(websubRepo, websub.NewClient(fetchClient), cfg.WebSub.CallbackBaseURL, cfg.WebSub.LeaseSeconds)
+	tagService := service.NewTagService(tagRepo, articleRepo)
+	annotationService := service.NewAnnotationService(annotationRepo, articleRepo)
+	ruleService := service.NewRuleService(ruleRepo, articleRepo, tagRepo, feedRepo, folderRepo)
+	maintenanceService := service.NewMaintenanceService(retentionRepo, model.RetentionPolicy{
+		Days:               cfg.Retention.Days,
+		MaxArticlesPerFeed: cfg.Retention.MaxArticlesPerFeed,
+	})
+
+	// プラグインの登録とフィード定期取得の開始
+	plugins := plugin.NewRegistry()
+	plugins.Register("rss", plugin.NewRSSPlugin(fetchClient))
+	refreshService := service.NewRefreshService(feedRepo, articleRepo, ruleRepo, tagRepo, plugins, contentExtractor, webhookDispatcher, eventBroker, websubService, feedIconService, model.FetchPolicy{
+		RequestsPerSecond:    cfg.Fetch.RequestsPerSecond,
+		Burst:                cfg.Fetch.Burst,
+		MaxConcurrentPerHost: cfg.Fetch.MaxConcurrentPerHost,
+		MaxConcurrentFeeds:   cfg.Fetch.MaxConcurrentFeeds,
+		RobotsPluginTypes:    cfg.Fetch.RobotsPluginTypes,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go refreshService.Run(ctx, refreshCheckInterval)
+	go webhookDispatcher.Run(ctx, webhookCheckInterval)
+	go websubService.Run(ctx, websubRenewCheckInterval)
+	go maintenanceService.Run(ctx, purgeInterval)
+	go snapshotService.Run(ctx, snapshotCheckInterval)
 
 	// ハンドラの初期化
 	folderHandler := handler.NewFolderHandler(folderService)
 	feedHandler := handler.NewFeedHandler(feedService)
+	feedIconHandler := handler.NewFeedIconHandler(feedIconService)
 	articleHandler := handler.NewArticleHandler(articleService)
+	greaderHandler := handler.NewGReaderHandler(authService, folderService, feedService, articleService)
+	feverHandler := handler.NewFeverHandler(authService, folderService, feedService, articleService, feedIconService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	tagHandler := handler.NewTagHandler(tagService)
+	annotationHandler := handler.NewAnnotationHandler(annotationService)
+	ruleHandler := handler.NewRuleHandler(ruleService)
+	maintenanceHandler := handler.NewMaintenanceHandler(maintenanceService)
+	snapshotHandler := handler.NewSnapshotHandler(snapshotService)
+	imageProxyHandler := handler.NewImageProxyHandler(imageProxyService)
+	eventHandler := handler.NewEventHandler(eventBroker)
+	websubHandler := handler.NewWebSubHandler(websubService, refreshService)
+	authOptions := handler.AuthOptions{
+		SessionTTL:        cfg.Auth.SessionTTL,
+		CookieSecure:      cfg.Auth.CookieSecure,
+		AllowSignup:       cfg.Auth.AllowSignup,
+		PostLoginRedirect: cfg.OIDC.PostLoginRedirect,
+	}
+	if cfg.OIDC.Enabled() {
+		authOptions.OIDCProvider = oidc.New(oidc.Config{
+			Issuer:       cfg.OIDC.Issuer,
+			ClientID:     cfg.OIDC.ClientID,
+			ClientSecret: cfg.OIDC.ClientSecret,
+			RedirectURL:  cfg.OIDC.RedirectURL,
+			Scopes:       cfg.OIDC.Scopes,
+		}, nil)
+	}
+	authHandler := handler.NewAuthHandler(authService, authOptions)
 
 	// 環境変数からGIN_MODEを読み込み、Ginのモードを設定
 	ginMode := os.Getenv("GIN_MODE")
@@ -98,7 +306,7 @@ func main() {
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000"}, // フロントエンドのURLを許可
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "Last-Event-ID"},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
 		MaxAge:           86400, // 24時間
@@ -114,32 +322,132 @@ func main() {
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	// ルーティングの設定
+	// ユーザー登録・ログイン（OpenID Connect を含む）は認証なしで利用できる
+	auth := r.Group("/api/v1/auth")
+	{
+		auth.POST("/signup", authHandler.Signup)
+		auth.POST("/login", authHandler.Login)
+		auth.POST("/logout", authHandler.Logout)
+		auth.GET("/oidc/login", authHandler.OIDCLogin)
+		auth.GET("/oidc/callback", authHandler.OIDCCallback)
+	}
+
+	// それ以外のエンドポイントはセッションCookieまたはAPIトークンによる認証が必要
 	v1 := r.Group("/api/v1")
+	v1.Use(authHandler.RequireAuth())
 	{
+		v1.GET("/auth/me", authHandler.GetCurrentUser)
+		v1.GET("/auth/tokens", authHandler.GetAPITokens)
+		v1.POST("/auth/tokens", authHandler.CreateAPIToken)
+		v1.DELETE("/auth/tokens/:id", authHandler.DeleteAPIToken)
+		v1.PUT("/auth/fever", authHandler.SetFeverPassword)
+		v1.DELETE("/auth/fever", authHandler.DeleteFeverPassword)
+
 		v1.GET("/folders", folderHandler.GetAllFolders)
 		v1.GET("/folders/:id", folderHandler.GetFolderByID)
 		v1.POST("/folders", folderHandler.CreateFolder)
 		v1.PUT("/folders/:id", folderHandler.UpdateFolder)
 		v1.DELETE("/folders/:id", folderHandler.DeleteFolder)
+		v1.GET("/folders/:id/articles", articleHandler.GetArticlesByFolder)
 
 		v1.GET("/feeds", feedHandler.GetAllFeeds)
 		v1.GET("/feeds/:id", feedHandler.GetFeedByID)
 		v1.POST("/feeds", feedHandler.CreateFeed)
 		v1.PUT("/feeds/:id", feedHandler.UpdateFeed)
 		v1.DELETE("/feeds/:id", feedHandler.DeleteFeed)
+		v1.GET("/feeds/:id/articles", articleHandler.GetArticlesByFeed)
+		v1.GET("/feeds/:id/icon", feedIconHandler.GetFeedIcon)
 
 		v1.GET("/articles", articleHandler.GetAllArticles)
 		v1.GET("/articles/:id", articleHandler.GetArticleByID)
 		v1.PUT("/articles/:id/status", articleHandler.UpdateArticleStatus)
+		v1.PUT("/articles/:id/media", articleHandler.UpdateArticleMedia)
+		v1.POST("/articles/:id/extract", articleHandler.ExtractArticleContent)
+		v1.GET("/articles/:id/snapshot", snapshotHandler.GetArticleSnapshot)
 		v1.GET("/articles/later", articleHandler.GetLaterArticles)
+		v1.PUT("/articles/:id/tags/:tag", tagHandler.AddArticleTag)
+		v1.DELETE("/articles/:id/tags/:tag", tagHandler.RemoveArticleTag)
+
+		v1.GET("/articles/:id/annotations", annotationHandler.GetArticleAnnotations)
+		v1.GET("/articles/:id/annotations/export", annotationHandler.ExportArticleAnnotations)
+		v1.GET("/articles/:id/annotations/:annotation_id", annotationHandler.GetAnnotation)
+		v1.POST("/articles/:id/annotations", annotationHandler.CreateAnnotation)
+		v1.PUT("/articles/:id/annotations/:annotation_id", annotationHandler.UpdateAnnotation)
+		v1.DELETE("/articles/:id/annotations/:annotation_id", annotationHandler.DeleteAnnotation)
+
+		v1.GET("/tags", tagHandler.GetAllTags)
+
+		v1.GET("/annotations", annotationHandler.GetAllAnnotations)
+
+		v1.GET("/events", eventHandler.Stream)
+
+		v1.GET("/webhooks", webhookHandler.GetAllWebhooks)
+		v1.GET("/webhooks/:id", webhookHandler.GetWebhookByID)
+		v1.POST("/webhooks", webhookHandler.CreateWebhook)
+		v1.PUT("/webhooks/:id", webhookHandler.UpdateWebhook)
+		v1.DELETE("/webhooks/:id", webhookHandler.DeleteWebhook)
+		v1.GET("/webhooks/:id/deliveries", webhookHandler.GetWebhookDeliveries)
+
+		v1.GET("/rules", ruleHandler.GetAllRules)
+		v1.GET("/rules/:id", ruleHandler.GetRuleByID)
+		v1.POST("/rules", ruleHandler.CreateRule)
+		v1.PUT("/rules/:id", ruleHandler.UpdateRule)
+		v1.DELETE("/rules/:id", ruleHandler.DeleteRule)
+		v1.POST("/rules/:id/apply", ruleHandler.ApplyRule)
+
+		v1.POST("/maintenance/purge", maintenanceHandler.Purge)
 	}
 
+	// Google Reader API 互換のエンドポイント（Reeder・NetNewsWire などのモバイルアプリ向け）
+	// ClientLogin で発行したトークンを `Authorization: GoogleLogin auth=<token>` ヘッダーで送信する
+	r.POST("/accounts/ClientLogin", greaderHandler.ClientLogin)
+	r.GET("/accounts/ClientLogin", greaderHandler.ClientLogin)
+	greader := r.Group("/reader/api/0")
+	greader.Use(greaderHandler.RequireAuth())
+	{
+		greader.GET("/token", greaderHandler.Token)
+		greader.GET("/user-info", greaderHandler.UserInfo)
+		greader.GET("/subscription/list", greaderHandler.SubscriptionList)
+		greader.GET("/tag/list", greaderHandler.TagList)
+		greader.GET("/stream/contents/*streamId", greaderHandler.StreamContents)
+		greader.GET("/stream/items/ids", greaderHandler.StreamItemIDs)
+		greader.GET("/stream/items/contents", greaderHandler.StreamItemContents)
+		greader.POST("/stream/items/contents", greaderHandler.StreamItemContents)
+		greader.POST("/edit-tag", greaderHandler.EditTag)
+		greader.POST("/mark-all-as-read", greaderHandler.MarkAllAsRead)
+	}
+
+	// Fever API 互換のエンドポイント。api_key（MD5(メールアドレス:Feverパスワード)）で認証する
+	r.GET("/fever/", feverHandler.API)
+	r.POST("/fever/", feverHandler.API)
+
+	// 画像プロキシ。<img> 要素から読み込まれるため認証は行わず、記事の出力時に付与した署名で検証する
+	r.GET(imageproxy.Path, imageProxyHandler.GetImage)
+
+	// WebSub のハブからのコールバック。ハブからアクセスされるため認証は行わず、トピックと署名で検証する
+	r.GET("/websub/callback/:feed_id", websubHandler.Verify)
+	r.POST("/websub/callback/:feed_id", websubHandler.Receive)
+
 	log.Printf("Server starting on :%s", cfg.Server.Port) // 設定からポートを読み込む
 	if err := r.Run(":" + cfg.Server.Port); err != nil { // 設定からポートを読み込む
 		log.Fatalf("Server failed to start: %v", err)
 	}
 }
 
+// imageProxyKey は画像URLの署名に使用する鍵を返します。
+// 設定されていない場合は起動ごとに生成するため、再起動前に出力した画像のURLは無効になります。
+func imageProxyKey(key string) []byte {
+	if key != "" {
+		return []byte(key)
+	}
+	log.Println("IMAGE_PROXY_KEY is not set; generating a random key. Proxied image URLs will change on restart.")
+	generated := make([]byte, 32)
+	if _, err := rand.Read(generated); err != nil {
+		log.Fatalf("Failed to generate image proxy key: %v", err)
+	}
+	return generated
+}
+
 // newDBConnection はデータベース接続を確立します。
 func newDBConnection(cfg config.DatabaseConfig) (*sql.DB, error) {
 	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
@@ -179,28 +487,8 @@ func newDBConnection(cfg config.DatabaseConfig) (*sql.DB, error) {
 }
 
 // runMigrations はデータベースマイグレーションを実行します。
+//
+// migrationFiles に列挙したファイルのうち、未適用のものを順に実行します（repository.Migrate）。
 func runMigrations(db *sql.DB) error {
-	// create_tables.sql の実行
-	createTablesSQL, err := os.ReadFile("migrations/20250628080159_create_tables.sql")
-	if err != nil {
-		return fmt.Errorf("failed to read create_tables.sql: %w", err)
-	}
-	_, err = db.Exec(string(createTablesSQL))
-	if err != nil {
-		return fmt.Errorf("failed to execute create_tables.sql: %w", err)
-	}
-	log.Println("create_tables.sql executed successfully.")
-
-	// insert_test_data.sql の実行
-	insertTestDataSQL, err := os.ReadFile("migrations/20250628081751_insert_test_data.sql")
-	if err != nil {
-		return fmt.Errorf("failed to read insert_test_data.sql: %w", err)
-	}
-	_, err = db.Exec(string(insertTestDataSQL))
-	if err != nil {
-		return fmt.Errorf("failed to execute insert_test_data.sql: %w", err)
-	}
-	log.Println("insert_test_data.sql executed successfully.")
-
-	return nil
+	return repository.Migrate(db, migrationFiles)
 }
@@ -24,6 +24,43 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/annotations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "ログイン中のユーザーがすべての記事に付けた注釈を、記事のタイトル・URLとともに新しい順に取得します",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "annotations"
+                ],
+                "summary": "注釈一覧取得",
+                "responses": {
+                    "200": {
+                        "description": "注釈一覧",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Annotation"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/articles": {
             "get": {
                 "description": "データベースに保存されているすべての記事を取得します",
@@ -37,6 +74,26 @@ const docTemplate = `{
                     "articles"
                 ],
                 "summary": "記事一覧取得",
+                "parameters": [
+                    {
+                        "enum": [
+                            "any",
+                            "audio",
+                            "video",
+                            "image"
+                        ],
+                        "type": "string",
+                        "description": "添付メディアの種別で絞り込み",
+                        "name": "has_enclosure",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "タグで絞り込み",
+                        "name": "tag",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "記事一覧",
@@ -47,6 +104,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "400": {
+                        "description": "クエリパラメータが不正",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "サーバー内部エラー",
                         "schema": {
@@ -144,19 +210,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/articles/{id}/status": {
-            "put": {
-                "description": "指定されたIDの記事の読了状態や後で読む状態を更新します",
-                "consumes": [
-                    "application/json"
+        "/articles/{id}/annotations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "指定されたIDの記事に付けた注釈を作成順に取得します",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "articles"
+                    "annotations"
                 ],
-                "summary": "記事状態更新",
+                "summary": "記事の注釈一覧取得",
                 "parameters": [
                     {
                         "type": "string",
@@ -164,29 +232,16 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "記事状態情報",
-                        "name": "status",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.ArticleStatusRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "更新された記事",
-                        "schema": {
-                            "$ref": "#/definitions/model.Article"
-                        }
-                    },
-                    "400": {
-                        "description": "リクエストボディの形式が不正",
+                        "description": "注釈一覧",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Annotation"
+                            }
                         }
                     },
                     "404": {
@@ -208,11 +263,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/feeds": {
-            "get": {
-                "description": "データベースに保存されているすべてのフィードを取得します",
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "指定されたIDの記事に注釈を付けます。selector は W3C Web Annotation の TextQuoteSelector 形式（exact・prefix・suffix）です",
                 "consumes": [
                     "application/json"
                 ],
@@ -220,16 +278,47 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "feeds"
+                    "annotations"
+                ],
+                "summary": "注釈作成",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "記事ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "注釈情報",
+                        "name": "annotation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Annotation"
+                        }
+                    }
                 ],
-                "summary": "フィード一覧取得",
                 "responses": {
-                    "200": {
-                        "description": "フィード一覧",
+                    "201": {
+                        "description": "作成された注釈",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.Feed"
+                            "$ref": "#/definitions/model.Annotation"
+                        }
+                    },
+                    "400": {
+                        "description": "リクエストボディの形式が不正",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "記事が見つかりません",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
@@ -243,42 +332,46 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "新しいフィードを作成します",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/articles/{id}/annotations/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "指定されたIDの記事のタイトルとURL、注釈ごとに引用した文字列（引用ブロック）とメモを Markdown で出力します",
                 "produces": [
-                    "application/json"
+                    "text/markdown"
                 ],
                 "tags": [
-                    "feeds"
+                    "annotations"
                 ],
-                "summary": "フィード作成",
+                "summary": "記事の注釈の Markdown 出力",
                 "parameters": [
                     {
-                        "description": "フィード情報",
-                        "name": "feed",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.Feed"
-                        }
+                        "type": "string",
+                        "description": "記事ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "作成されたフィード",
+                    "200": {
+                        "description": "注釈の Markdown",
                         "schema": {
-                            "$ref": "#/definitions/model.Feed"
+                            "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "リクエストボディの形式が不正",
+                    "404": {
+                        "description": "記事が見つかりません",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
@@ -293,37 +386,46 @@ const docTemplate = `{
                 }
             }
         },
-        "/feeds/{id}": {
+        "/articles/{id}/annotations/{annotation_id}": {
             "get": {
-                "description": "指定されたIDのフィードを取得します",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "指定された記事の、指定されたIDの注釈を取得します",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "feeds"
+                    "annotations"
                 ],
-                "summary": "フィード詳細取得",
+                "summary": "注釈詳細取得",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "フィードID (UUID)",
+                        "description": "記事ID (UUID)",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "注釈ID (UUID)",
+                        "name": "annotation_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "フィード詳細",
+                        "description": "注釈詳細",
                         "schema": {
-                            "$ref": "#/definitions/model.Feed"
+                            "$ref": "#/definitions/model.Annotation"
                         }
                     },
                     "404": {
-                        "description": "フィードが見つかりません",
+                        "description": "注釈が見つかりません",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -343,7 +445,12 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "指定されたIDのフィードを更新します",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "指定されたIDの注釈の箇所・メモ・色を更新します",
                 "consumes": [
                     "application/json"
                 ],
@@ -351,32 +458,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "feeds"
+                    "annotations"
                 ],
-                "summary": "フィード更新",
+                "summary": "注釈更新",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "フィードID (UUID)",
+                        "description": "記事ID (UUID)",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "更新するフィード情報",
-                        "name": "feed",
+                        "type": "string",
+                        "description": "注釈ID (UUID)",
+                        "name": "annotation_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "更新する注釈情報",
+                        "name": "annotation",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.Feed"
+                            "$ref": "#/definitions/model.Annotation"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "更新されたフィード",
+                        "description": "更新された注釈",
                         "schema": {
-                            "$ref": "#/definitions/model.Feed"
+                            "$ref": "#/definitions/model.Annotation"
                         }
                     },
                     "400": {
@@ -387,7 +501,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "フィードが見つかりません",
+                        "description": "注釈が見つかりません",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -407,24 +521,30 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "指定されたIDのフィードを削除します",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "指定されたIDの注釈を削除します",
                 "tags": [
-                    "feeds"
+                    "annotations"
                 ],
-                "summary": "フィード削除",
+                "summary": "注釈削除",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "フィードID (UUID)",
+                        "description": "記事ID (UUID)",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "注釈ID (UUID)",
+                        "name": "annotation_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -432,7 +552,7 @@ const docTemplate = `{
                         "description": "削除成功"
                     },
                     "404": {
-                        "description": "フィードが見つかりません",
+                        "description": "注釈が見つかりません",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -452,9 +572,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/folders": {
-            "get": {
-                "description": "データベースに保存されているすべてのフォルダを取得します",
+        "/articles/{id}/extract": {
+            "post": {
+                "description": "指定されたIDの記事のURLを取得し、本文を抽出して記事の本文を置き換えます。フィードの本文は要約が空の場合のみ要約として残ります",
                 "consumes": [
                     "application/json"
                 ],
@@ -462,16 +582,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "folders"
+                    "articles"
+                ],
+                "summary": "記事本文抽出",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "記事ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "フォルダ一覧取得",
                 "responses": {
                     "200": {
-                        "description": "フォルダ一覧",
+                        "description": "本文を抽出した記事",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.Folder"
+                            "$ref": "#/definitions/model.Article"
+                        }
+                    },
+                    "404": {
+                        "description": "記事が見つかりません",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
@@ -483,11 +618,22 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "502": {
+                        "description": "記事ページの取得または本文の抽出に失敗",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
-            },
-            "post": {
-                "description": "新しいフォルダを作成します",
+            }
+        },
+        "/articles/{id}/media": {
+            "put": {
+                "description": "指定されたIDの記事の再生位置・再生済み・ダウンロード済み状態を更新します。省略したフィールドは変更されません",
                 "consumes": [
                     "application/json"
                 ],
@@ -495,25 +641,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "folders"
+                    "articles"
                 ],
-                "summary": "フォルダ作成",
+                "summary": "メディア再生状態更新",
                 "parameters": [
                     {
-                        "description": "フォルダ情報",
-                        "name": "folder",
+                        "type": "string",
+                        "description": "記事ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "メディア再生状態",
+                        "name": "media",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.Folder"
+                            "$ref": "#/definitions/handler.ArticleMediaRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "作成されたフォルダ",
+                    "200": {
+                        "description": "更新された記事",
                         "schema": {
-                            "$ref": "#/definitions/model.Folder"
+                            "$ref": "#/definitions/model.Article"
                         }
                     },
                     "400": {
@@ -523,6 +676,15 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "404": {
+                        "description": "記事が見つかりません",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "サーバー内部エラー",
                         "schema": {
@@ -535,23 +697,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/folders/{id}": {
+        "/articles/{id}/snapshot": {
             "get": {
-                "description": "指定されたIDのフォルダを取得します",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "「後で見る」に追加したときに保存した元ページのスナップショット（画像とスタイルシートを埋め込んだHTML）を返します。\nスクリプトは除去済みで、Content-Security-Policy の sandbox を付与して返します。取得状況は記事の snapshot_status で確認できます",
                 "produces": [
-                    "application/json"
+                    "text/html"
                 ],
                 "tags": [
-                    "folders"
+                    "articles"
                 ],
-                "summary": "フォルダ詳細取得",
+                "summary": "記事の元ページのスナップショット取得",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "フォルダID (UUID)",
+                        "description": "記事ID (UUID)",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -559,13 +723,25 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "フォルダ詳細",
+                        "description": "スナップショットのHTML",
                         "schema": {
-                            "$ref": "#/definitions/model.Folder"
+                            "type": "string"
                         }
                     },
+                    "304": {
+                        "description": "変更なし（If-None-Match が一致）"
+                    },
                     "404": {
-                        "description": "フォルダが見つかりません",
+                        "description": "記事またはスナップショットが見つかりません",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "スナップショットが取得待ちまたは取得失敗",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -583,9 +759,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/articles/{id}/status": {
             "put": {
-                "description": "指定されたIDのフォルダを更新します",
+                "description": "指定されたIDの記事の読了状態や後で読む状態を更新します",
                 "consumes": [
                     "application/json"
                 ],
@@ -593,32 +771,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "folders"
+                    "articles"
                 ],
-                "summary": "フォルダ更新",
+                "summary": "記事状態更新",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "フォルダID (UUID)",
+                        "description": "記事ID (UUID)",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "更新するフォルダ情報",
-                        "name": "folder",
+                        "description": "記事状態情報",
+                        "name": "status",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.Folder"
+                            "$ref": "#/definitions/handler.ArticleStatusRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "更新されたフォルダ",
+                        "description": "更新された記事",
                         "schema": {
-                            "$ref": "#/definitions/model.Folder"
+                            "$ref": "#/definitions/model.Article"
                         }
                     },
                     "400": {
@@ -629,7 +807,75 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "フォルダが見つかりません",
+                        "description": "記事が見つかりません",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/articles/{id}/tags/{tag}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "指定されたIDの記事にタグを付けます。タグが存在しない場合は作成され、既に付いている場合は何もしません",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "記事にタグを付ける",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "記事ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "タグ名（最大64文字）",
+                        "name": "tag",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "タグを付けた記事",
+                        "schema": {
+                            "$ref": "#/definitions/model.Article"
+                        }
+                    },
+                    "400": {
+                        "description": "タグ名が不正",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "記事が見つかりません",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -649,32 +895,53 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "指定されたIDのフォルダを削除します",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "指定されたIDの記事からタグを外します。タグが付いた記事がなくなった場合はタグも削除されます",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "folders"
+                    "tags"
                 ],
-                "summary": "フォルダ削除",
+                "summary": "記事からタグを外す",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "フォルダID (UUID)",
+                        "description": "記事ID (UUID)",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "タグ名",
+                        "name": "tag",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "削除成功"
+                    "200": {
+                        "description": "タグを外した記事",
+                        "schema": {
+                            "$ref": "#/definitions/model.Article"
+                        }
+                    },
+                    "400": {
+                        "description": "タグ名が不正",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     },
                     "404": {
-                        "description": "フォルダが見つかりません",
+                        "description": "記事が見つからない、または記事にタグが付いていない",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -693,41 +960,2307 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handler.ArticleStatusRequest": {
-            "type": "object",
-            "properties": {
-                "is_later": {
-                    "type": "boolean",
-                    "example": false
-                },
-                "is_read": {
-                    "type": "boolean",
-                    "example": true
+        },
+        "/auth/fever": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fever API 対応アプリで使用するパスワードを設定します。アプリにはメールアドレスとこのパスワードを入力します。ログインパスワードとは別のパスワードを指定してください",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Fever API パスワード設定",
+                "parameters": [
+                    {
+                        "description": "Fever API 用パスワード",
+                        "name": "password",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.FeverPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "設定成功"
+                    },
+                    "400": {
+                        "description": "リクエストボディの形式が不正",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "未認証",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fever API 用のパスワードを削除し、Fever API によるアクセスを無効にします",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Fever API パスワード削除",
+                "responses": {
+                    "204": {
+                        "description": "削除成功"
+                    },
+                    "401": {
+                        "description": "未認証",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
                 }
             }
         },
-        "model.Article": {
-            "type": "object",
-            "properties": {
-                "content": {
-                    "description": "記事本文（省略可能）",
-                    "type": "string"
-                },
-                "created_at": {
-                    "description": "作成日時",
-                    "type": "string"
-                },
+        "/auth/login": {
+            "post": {
+                "description": "メールアドレスとパスワードを検証し、HTTP-onlyのセッションCookieを発行します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "ログイン",
+                "parameters": [
+                    {
+                        "description": "ログイン情報",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ログインしたユーザー",
+                        "schema": {
+                            "$ref": "#/definitions/model.User"
+                        }
+                    },
+                    "400": {
+                        "description": "リクエストボディの形式が不正",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "メールアドレスまたはパスワードが正しくない",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "description": "セッションを破棄し、セッションCookieを削除します",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "ログアウト",
+                "responses": {
+                    "204": {
+                        "description": "ログアウト成功"
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "セッションCookieまたはAPIトークンで認証されたユーザーを取得します",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "ログインユーザー取得",
+                "responses": {
+                    "200": {
+                        "description": "ログイン中のユーザー",
+                        "schema": {
+                            "$ref": "#/definitions/model.User"
+                        }
+                    },
+                    "401": {
+                        "description": "未認証",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/callback": {
+            "get": {
+                "description": "認可コードを検証してセッションCookieを発行し、ログイン後の画面にリダイレクトします。未登録のアカウントの場合はユーザーを作成します",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "OpenID Connect コールバック",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "認可コード",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ログイン開始時に発行した state",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "ログイン後の画面へリダイレクト"
+                    },
+                    "400": {
+                        "description": "state が不正、またはメールアドレスを取得できない",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "認証に失敗",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "ユーザー登録が無効",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "OpenID Connect が設定されていない",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "未確認のメールアドレスが登録済み",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/login": {
+            "get": {
+                "description": "認可コードフロー（PKCE）で OpenID Connect プロバイダーのログイン画面にリダイレクトします",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "OpenID Connect ログイン開始",
+                "responses": {
+                    "302": {
+                        "description": "プロバイダーの認可エンドポイントへリダイレクト"
+                    },
+                    "404": {
+                        "description": "OpenID Connect が設定されていない",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "502": {
+                        "description": "プロバイダーの設定を取得できない",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/signup": {
+            "post": {
+                "description": "メールアドレスとパスワードでユーザーを登録し、セッションCookieを発行します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "ユーザー登録",
+                "parameters": [
+                    {
+                        "description": "登録情報",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.SignupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "登録されたユーザー",
+                        "schema": {
+                            "$ref": "#/definitions/model.User"
+                        }
+                    },
+                    "400": {
+                        "description": "リクエストボディの形式が不正",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "ユーザー登録が無効",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "メールアドレスが登録済み",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/tokens": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "ログイン中のユーザーが発行したAPIトークンの一覧を取得します。トークン文字列は含まれません",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "APIトークン一覧取得",
+                "responses": {
+                    "200": {
+                        "description": "APIトークン一覧",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.APIToken"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "未認証",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "スクリプトから ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` で利用する個人APIトークンを発行します。トークン文字列はこのレスポンスでのみ返されます",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "APIトークン発行",
+                "parameters": [
+                    {
+                        "description": "トークン情報",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.APITokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "発行されたAPIトークン",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIToken"
+                        }
+                    },
+                    "400": {
+                        "description": "リクエストボディの形式が不正",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "未認証",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/tokens/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "指定されたIDのAPIトークンを削除し、以降の利用を無効にします",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "APIトークン削除",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "APIトークンID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "削除成功"
+                    },
+                    "401": {
+                        "description": "未認証",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "APIトークンが見つかりません",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/events": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "記事の取り込みや既読状態の変更を Server-Sent Events で配信します。イベントの種別は article.created（内容は本文を除いた記事）、article.updated（既読・後で見る状態の変更）、feed.refreshed、feed.error です。\n再接続時に ` + "`" + `Last-Event-ID` + "`" + ` ヘッダー（または last_event_id クエリパラメータ）を指定すると、以降のイベントを再送します。\n再送できない場合は reset イベントを送信するため、クライアントは表示中のデータを取得し直してください",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "イベントストリーム",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "最後に受信したイベントのID",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "最後に受信したイベントのID（ヘッダーを指定できないクライアント向け）",
+                        "name": "last_event_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "イベントストリーム",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "未認証",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds": {
+            "get": {
+                "description": "ログイン中のユーザーが購読しているすべてのフィードを取得します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "フィード一覧取得",
+                "responses": {
+                    "200": {
+                        "description": "フィード一覧",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Feed"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "フィードを購読します。同じURLのフィードを他のユーザーが購読している場合は取得元を共有します。\nauth（認証情報・リクエストヘッダー）を指定した場合は共有せず、認証情報は暗号化して保存します。応答ではユーザー名以外を \"********\" に置き換えます。\nproxy（http・https・socks5 のURL）を指定した場合は共有せず、フィード・アイコン・記事ページの取得に使用します。パスワードは暗号化して保存し、応答では \"********\" に置き換えます",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "フィード作成",
+                "parameters": [
+                    {
+                        "description": "フィード情報",
+                        "name": "feed",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Feed"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "作成されたフィード",
+                        "schema": {
+                            "$ref": "#/definitions/model.Feed"
+                        }
+                    },
+                    "400": {
+                        "description": "リクエストボディの形式が不正、フォルダが存在しない、または認証情報・プロキシが不正",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "既に購読しているフィード",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{id}": {
+            "get": {
+                "description": "指定されたIDのフィードを取得します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "フィード詳細取得",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "フィードID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "フィード詳細",
+                        "schema": {
+                            "$ref": "#/definitions/model.Feed"
+                        }
+                    },
+                    "404": {
+                        "description": "フィードが見つかりません",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "指定されたIDのフィードを更新します。\nauth の各項目・proxy のパスワードに \"********\" を指定した場合は保存済みの値を維持します。\nretention_days・max_articles・rate_limit・max_connections・respect_robots・auth・proxy は、省略した場合は保存済みの値を維持し、null を指定した場合は全体設定に戻します（auth・proxy は削除します）。\n他のユーザーと共有しているフィードには認証情報を設定できず、プロキシと保持設定（retention_days・max_articles）を変更できません（409）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "フィード更新",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "フィードID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "更新するフィード情報",
+                        "name": "feed",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Feed"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "更新されたフィード",
+                        "schema": {
+                            "$ref": "#/definitions/model.Feed"
+                        }
+                    },
+                    "400": {
+                        "description": "リクエストボディの形式が不正、フォルダが存在しない、URL・プラグイン種別の変更、または認証情報・プロキシが不正",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "フィードが見つかりません",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "共有しているフィードへの認証情報の設定・プロキシや保持設定・取得の設定の変更、または同じURLの共有のフィードが既にある",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "指定されたIDのフィードを削除します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "フィード削除",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "フィードID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "削除成功"
+                    },
+                    "404": {
+                        "description": "フィードが見つかりません",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{id}/articles": {
+            "get": {
+                "description": "指定されたフィードが配信した記事の一覧を取得します。複数のフィードから配信された記事はそれぞれのフィードに含まれます",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "articles"
+                ],
+                "summary": "フィード内記事一覧取得",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "フィードID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "記事一覧",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Article"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "フィードが見つかりません",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{id}/icon": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "フィードの取得時に検出したサイトのアイコン（Atom の \u003cicon\u003e、\u003clink rel=\"icon\"\u003e、/favicon.ico）を返します。\nアイコンは1週間ごとに再取得します。ETag は内容のハッシュで、If-None-Match が一致する場合は 304 を返します",
+                "produces": [
+                    "image/png",
+                    "image/x-icon",
+                    "image/gif",
+                    "image/jpeg",
+                    "image/webp"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "フィードのアイコン取得",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "フィードID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "アイコン",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "304": {
+                        "description": "変更なし（If-None-Match が一致）"
+                    },
+                    "404": {
+                        "description": "フィードまたはアイコンが見つかりません",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/folders": {
+            "get": {
+                "description": "ログイン中のユーザーが所有するすべてのフォルダを取得します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "folders"
+                ],
+                "summary": "フォルダ一覧取得",
+                "responses": {
+                    "200": {
+                        "description": "フォルダ一覧",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Folder"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "新しいフォルダを作成します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "folders"
+                ],
+                "summary": "フォルダ作成",
+                "parameters": [
+                    {
+                        "description": "フォルダ情報",
+                        "name": "folder",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Folder"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "作成されたフォルダ",
+                        "schema": {
+                            "$ref": "#/definitions/model.Folder"
+                        }
+                    },
+                    "400": {
+                        "description": "リクエストボディの形式が不正",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/folders/{id}": {
+            "get": {
+                "description": "指定されたIDのフォルダを取得します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "folders"
+                ],
+                "summary": "フォルダ詳細取得",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "フォルダID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "フォルダ詳細",
+                        "schema": {
+                            "$ref": "#/definitions/model.Folder"
+                        }
+                    },
+                    "404": {
+                        "description": "フォルダが見つかりません",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "指定されたIDのフォルダを更新します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "folders"
+                ],
+                "summary": "フォルダ更新",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "フォルダID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "更新するフォルダ情報",
+                        "name": "folder",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Folder"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "更新されたフォルダ",
+                        "schema": {
+                            "$ref": "#/definitions/model.Folder"
+                        }
+                    },
+                    "400": {
+                        "description": "リクエストボディの形式が不正",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "フォルダが見つかりません",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "指定されたIDのフォルダを削除します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "folders"
+                ],
+                "summary": "フォルダ削除",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "フォルダID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "削除成功"
+                    },
+                    "404": {
+                        "description": "フォルダが見つかりません",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/folders/{id}/articles": {
+            "get": {
+                "description": "指定されたフォルダに属するフィードが配信した記事の一覧を取得します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "articles"
+                ],
+                "summary": "フォルダ内記事一覧取得",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "フォルダID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "記事一覧",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Article"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "フォルダが見つかりません",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/maintenance/purge": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "購読しているフィードの記事のうち、保持日数を過ぎた既読の記事と、フィードごとの上限件数を超えた古い記事を削除し、フィードごとの件数を返します。\n他のフィードの記事は削除しません（すべてのフィードの記事は定期実行で削除します）。保持日数による削除は、購読者全員が既読にした記事に限ります。\n「後で見る」・タグ・注釈が付いた記事は削除しません。保持設定はフィードの retention_days・max_articles、未指定の場合は全体設定を使用します。\n削除した記事のURLは記録され、次回の取得で未読として再登録されません。dry_run=true の場合は削除せず、対象の件数のみを返します",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "maintenance"
+                ],
+                "summary": "古い記事の削除",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "削除対象の確認のみを行う",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "削除結果",
+                        "schema": {
+                            "$ref": "#/definitions/model.PurgeResult"
+                        }
+                    },
+                    "400": {
+                        "description": "クエリパラメータが不正",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/proxy/image": {
+            "get": {
+                "description": "記事の本文・概要・画像URLの画像は、このエンドポイントの署名付きURLに書き換えて返されます。\nサーバーが画像を取得してキャッシュし、読者のブラウザが画像の配信元へ直接アクセスしないようにします。\n\u003cimg\u003e 要素から読み込めるよう認証は不要で、署名によって記事中の画像以外の取得を拒否します",
+                "produces": [
+                    "image/jpeg",
+                    "image/png",
+                    "image/gif",
+                    "image/webp"
+                ],
+                "tags": [
+                    "articles"
+                ],
+                "summary": "画像プロキシ",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "画像の元のURL",
+                        "name": "url",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "画像URLの署名",
+                        "name": "sig",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "画像",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "画像URLが不正です",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "署名が一致しないか、接続を許可しないアドレスです",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "502": {
+                        "description": "画像の取得に失敗したか、画像形式・サイズが許可されていません",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/rules": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "ログイン中のユーザーが作成したすべてのルールを作成順に取得します",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rules"
+                ],
+                "summary": "ルール一覧取得",
+                "responses": {
+                    "200": {
+                        "description": "ルール一覧",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Rule"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "取り込む記事を自動的に処理するルールを作成します。作成後に取り込まれる記事に適用されます。\nconditions のすべてに一致した記事に action（mark_read・mark_later・skip・add_tag）を実行します。\nfolder_id・feed_id をいずれも省略した場合は、購読しているすべてのフィードに適用されます",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rules"
+                ],
+                "summary": "ルール作成",
+                "parameters": [
+                    {
+                        "description": "ルール情報",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Rule"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "作成されたルール",
+                        "schema": {
+                            "$ref": "#/definitions/model.Rule"
+                        }
+                    },
+                    "400": {
+                        "description": "リクエストボディの形式が不正、正規表現が不正、またはフィード・フォルダが存在しない",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/rules/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "指定されたIDのルールを取得します",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rules"
+                ],
+                "summary": "ルール詳細取得",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ルールID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ルール詳細",
+                        "schema": {
+                            "$ref": "#/definitions/model.Rule"
+                        }
+                    },
+                    "404": {
+                        "description": "ルールが見つかりません",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "指定されたIDのルールを更新します。既に取り込まれた記事には適用されません",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rules"
+                ],
+                "summary": "ルール更新",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ルールID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "更新するルール情報",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Rule"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "更新されたルール",
+                        "schema": {
+                            "$ref": "#/definitions/model.Rule"
+                        }
+                    },
+                    "400": {
+                        "description": "リクエストボディの形式が不正、正規表現が不正、またはフィード・フォルダが存在しない",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "ルールが見つかりません",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "指定されたIDのルールを削除します。適用済みの記事の状態は変わりません",
+                "tags": [
+                    "rules"
+                ],
+                "summary": "ルール削除",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ルールID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "削除成功"
+                    },
+                    "404": {
+                        "description": "ルールが見つかりません",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/rules/{id}/apply": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "ルールの適用範囲内の既存の記事のうち、条件に一致した記事にアクションを実行し、一致した件数を返します。\ndry_run=true の場合はアクションを実行せず、一致する件数のみを返します",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rules"
+                ],
+                "summary": "既存の記事へのルールの適用",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ルールID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "件数の確認のみを行う",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "適用結果",
+                        "schema": {
+                            "$ref": "#/definitions/model.RuleApplyResult"
+                        }
+                    },
+                    "400": {
+                        "description": "クエリパラメータが不正",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "ルールが見つかりません",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "ログイン中のユーザーのタグを名前順に、タグが付いた記事と未読の記事の件数とともに取得します",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "タグ一覧取得",
+                "responses": {
+                    "200": {
+                        "description": "タグ一覧",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Tag"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "ログイン中のユーザーが作成したすべての Webhook を取得します。署名の鍵は含まれません",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Webhook 一覧取得",
+                "responses": {
+                    "200": {
+                        "description": "Webhook 一覧",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Webhook"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "新着記事を通知する Webhook を作成します。通知は ` + "`" + `X-FeedApp-Signature: sha256=\u003cHMAC-SHA256\u003e` + "`" + ` ヘッダー付きの JSON の POST で送信されます。\npayload_template は text/template 形式で、` + "`" + `.Event` + "`" + `・` + "`" + `.WebhookID` + "`" + `・` + "`" + `.Feed` + "`" + `・` + "`" + `.Article` + "`" + ` と、値をJSONとしてエスケープする ` + "`" + `json` + "`" + ` 関数を使用できます。\nsecret を省略した場合は自動生成され、このレスポンスでのみ返されます",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Webhook 作成",
+                "parameters": [
+                    {
+                        "description": "Webhook 情報",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Webhook"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "作成された Webhook",
+                        "schema": {
+                            "$ref": "#/definitions/model.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "リクエストボディの形式が不正、またはフィード・フォルダが存在しない",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "指定されたIDの Webhook を取得します。署名の鍵は含まれません",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Webhook 詳細取得",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhook 詳細",
+                        "schema": {
+                            "$ref": "#/definitions/model.Webhook"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook が見つかりません",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "指定されたIDの Webhook を更新します。secret を省略した場合は現在の鍵を維持します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Webhook 更新",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "更新する Webhook 情報",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Webhook"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "更新された Webhook",
+                        "schema": {
+                            "$ref": "#/definitions/model.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "リクエストボディの形式が不正、またはフィード・フォルダが存在しない",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook が見つかりません",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "指定されたIDの Webhook と配信ログを削除します",
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Webhook 削除",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "削除成功"
+                    },
+                    "404": {
+                        "description": "Webhook が見つかりません",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "指定されたIDの Webhook の配信（送信待ち・再試行待ちを含む）を新しい順に最大100件取得します",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Webhook 配信ログ取得",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "配信ログ",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook が見つかりません",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "サーバー内部エラー",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handler.APITokenRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "backup script"
+                }
+            }
+        },
+        "handler.ArticleMediaRequest": {
+            "type": "object",
+            "properties": {
+                "downloaded": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "playback_position_seconds": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 754
+                },
+                "played": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "handler.ArticleStatusRequest": {
+            "type": "object",
+            "properties": {
+                "is_later": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "is_read": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.FeverPasswordRequest": {
+            "type": "object",
+            "required": [
+                "password"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 8,
+                    "example": "fever app password"
+                }
+            }
+        },
+        "handler.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "correct horse battery staple"
+                }
+            }
+        },
+        "handler.SignupRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "user@example.com"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "山田太郎"
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 72,
+                    "minLength": 8,
+                    "example": "correct horse battery staple"
+                }
+            }
+        },
+        "model.APIToken": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "作成日時",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "トークンの一意識別子",
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "description": "最終使用日時",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "トークン名",
+                    "type": "string"
+                },
+                "token": {
+                    "description": "トークン文字列（作成時のみ）",
+                    "type": "string"
+                }
+            }
+        },
+        "model.Annotation": {
+            "type": "object",
+            "properties": {
+                "article_id": {
+                    "description": "記事ID",
+                    "type": "string"
+                },
+                "article_title": {
+                    "description": "記事のタイトル",
+                    "type": "string"
+                },
+                "article_url": {
+                    "description": "記事の元URL",
+                    "type": "string"
+                },
+                "body": {
+                    "description": "メモ",
+                    "type": "string",
+                    "maxLength": 100000
+                },
+                "color": {
+                    "description": "ハイライトの色",
+                    "type": "string",
+                    "enum": [
+                        "yellow",
+                        "green",
+                        "blue",
+                        "pink",
+                        "purple"
+                    ]
+                },
+                "created_at": {
+                    "description": "作成日時",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "注釈の一意識別子",
+                    "type": "string"
+                },
+                "selector": {
+                    "description": "注釈を付けた箇所（必須）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.TextQuoteSelector"
+                        }
+                    ]
+                },
+                "updated_at": {
+                    "description": "最終更新日時",
+                    "type": "string"
+                }
+            }
+        },
+        "model.Article": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "description": "著者",
+                    "type": "string"
+                },
+                "categories": {
+                    "description": "カテゴリ一覧",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "content": {
+                    "description": "記事本文（省略可能）",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "作成日時",
+                    "type": "string"
+                },
+                "downloaded": {
+                    "description": "ダウンロード済みフラグ",
+                    "type": "boolean"
+                },
+                "enclosures": {
+                    "description": "添付メディア一覧",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Enclosure"
+                    }
+                },
+                "episode": {
+                    "description": "エピソード番号",
+                    "type": "integer"
+                },
+                "extracted_at": {
+                    "description": "本文抽出日時",
+                    "type": "string"
+                },
                 "feed_id": {
                     "description": "所属フィードID",
                     "type": "string"
                 },
+                "guid": {
+                    "description": "feed_id のフィードでのエントリー識別子",
+                    "type": "string"
+                },
                 "id": {
                     "description": "記事の一意識別子",
                     "type": "string"
                 },
+                "image_url": {
+                    "description": "サムネイル画像URL",
+                    "type": "string"
+                },
                 "is_later": {
                     "description": "後で見るフラグ",
                     "type": "boolean"
@@ -736,10 +3269,33 @@ const docTemplate = `{
                     "description": "既読フラグ",
                     "type": "boolean"
                 },
+                "playback_position_seconds": {
+                    "description": "再生位置（秒）",
+                    "type": "integer"
+                },
+                "played": {
+                    "description": "再生済みフラグ",
+                    "type": "boolean"
+                },
                 "published_at": {
                     "description": "公開日時",
                     "type": "string"
                 },
+                "snapshot_status": {
+                    "description": "元ページのスナップショットの取得状況（SnapshotStatus* 定数）",
+                    "type": "string"
+                },
+                "summary": {
+                    "description": "要約",
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "タグ一覧",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "title": {
                     "description": "記事タイトル",
                     "type": "string"
@@ -750,6 +3306,27 @@ const docTemplate = `{
                 }
             }
         },
+        "model.Enclosure": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "description": "再生時間（秒）",
+                    "type": "integer"
+                },
+                "length": {
+                    "description": "ファイルサイズ（バイト）",
+                    "type": "integer"
+                },
+                "mime_type": {
+                    "description": "MIMEタイプ",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "メディアURL",
+                    "type": "string"
+                }
+            }
+        },
         "model.Feed": {
             "type": "object",
             "required": [
@@ -758,10 +3335,22 @@ const docTemplate = `{
                 "url"
             ],
             "properties": {
+                "auth": {
+                    "description": "取得時の認証情報（nil は認証なし）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.FeedAuth"
+                        }
+                    ]
+                },
                 "created_at": {
                     "description": "作成日時",
                     "type": "string"
                 },
+                "fetch_full_content": {
+                    "description": "本文抽出フラグ",
+                    "type": "boolean"
+                },
                 "folder_id": {
                     "description": "所属フォルダID",
                     "type": "string"
@@ -774,14 +3363,46 @@ const docTemplate = `{
                     "description": "最終更新日時",
                     "type": "string"
                 },
+                "max_articles": {
+                    "description": "保持する記事の上限件数（nil は全体設定）",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "max_connections": {
+                    "description": "ホストへの同時リクエスト数（nil は全体設定）",
+                    "type": "integer",
+                    "minimum": 0
+                },
                 "name": {
                     "description": "フィード名（必須）",
                     "type": "string"
                 },
+                "parse_warning": {
+                    "description": "直近の取得での解析時の警告（空は問題なし）",
+                    "type": "string"
+                },
                 "plugin_type": {
                     "description": "プラグイン種別（必須）",
                     "type": "string"
                 },
+                "proxy": {
+                    "description": "取得時のプロキシのURL（空は全体設定）",
+                    "type": "string"
+                },
+                "rate_limit": {
+                    "description": "ホストへの1秒あたりのリクエスト数（nil は全体設定）",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "respect_robots": {
+                    "description": "robots.txt を確認するかどうか（nil は全体設定）",
+                    "type": "boolean"
+                },
+                "retention_days": {
+                    "description": "既読記事の保持日数（nil は全体設定）",
+                    "type": "integer",
+                    "minimum": 0
+                },
                 "update_interval": {
                     "description": "更新間隔（分）",
                     "type": "integer"
@@ -792,6 +3413,40 @@ const docTemplate = `{
                 }
             }
         },
+        "model.FeedAuth": {
+            "type": "object",
+            "properties": {
+                "cookie": {
+                    "type": "string"
+                },
+                "headers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "password": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.FeedPurgeResult": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "integer"
+                },
+                "feed_id": {
+                    "type": "string"
+                }
+            }
+        },
         "model.Folder": {
             "type": "object",
             "required": [
@@ -811,12 +3466,310 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "user_id": {
-                    "description": "将来のマルチユーザー対応用",
+                    "description": "所有者のユーザーID",
+                    "type": "string"
+                }
+            }
+        },
+        "model.PurgeResult": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "feeds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FeedPurgeResult"
+                    }
+                }
+            }
+        },
+        "model.Rule": {
+            "type": "object",
+            "required": [
+                "action",
+                "conditions",
+                "name"
+            ],
+            "properties": {
+                "action": {
+                    "description": "アクション（必須）",
+                    "type": "string",
+                    "enum": [
+                        "mark_read",
+                        "mark_later",
+                        "skip",
+                        "add_tag"
+                    ]
+                },
+                "conditions": {
+                    "description": "条件（必須）",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/model.RuleCondition"
+                    }
+                },
+                "created_at": {
+                    "description": "作成日時",
+                    "type": "string"
+                },
+                "feed_id": {
+                    "description": "適用するフィードID",
+                    "type": "string"
+                },
+                "folder_id": {
+                    "description": "適用するフォルダID",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ルールの一意識別子",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "表示名（必須）",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "tag": {
+                    "description": "付けるタグ名",
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "model.RuleApplyResult": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "description": "件数の確認のみか",
+                    "type": "boolean"
+                },
+                "matched": {
+                    "description": "条件に一致した記事の件数",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.RuleCondition": {
+            "type": "object",
+            "required": [
+                "field",
+                "operator",
+                "value"
+            ],
+            "properties": {
+                "field": {
+                    "description": "対象のフィールド",
+                    "type": "string",
+                    "enum": [
+                        "title",
+                        "content",
+                        "author",
+                        "url"
+                    ]
+                },
+                "ignore_case": {
+                    "description": "大文字小文字を区別しない",
+                    "type": "boolean"
+                },
+                "operator": {
+                    "description": "比較方法",
+                    "type": "string",
+                    "enum": [
+                        "contains",
+                        "regex"
+                    ]
+                },
+                "value": {
+                    "description": "比較する値",
+                    "type": "string",
+                    "maxLength": 1000
+                }
+            }
+        },
+        "model.Tag": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "記事の件数",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "タグ名",
+                    "type": "string"
+                },
+                "unread_count": {
+                    "description": "未読の記事の件数",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.TextQuoteSelector": {
+            "type": "object",
+            "required": [
+                "exact"
+            ],
+            "properties": {
+                "exact": {
+                    "description": "引用した文字列（必須）",
+                    "type": "string",
+                    "maxLength": 10000
+                },
+                "prefix": {
+                    "description": "引用の直前の文字列",
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "suffix": {
+                    "description": "引用の直後の文字列",
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "type": {
+                    "description": "セレクターの種別",
+                    "type": "string"
+                }
+            }
+        },
+        "model.User": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "作成日時",
+                    "type": "string"
+                },
+                "email": {
+                    "description": "メールアドレス",
+                    "type": "string"
+                },
+                "fever_enabled": {
+                    "description": "Fever API のパスワード設定済みフラグ",
+                    "type": "boolean"
+                },
+                "id": {
+                    "description": "ユーザーの一意識別子",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "表示名",
+                    "type": "string"
+                }
+            }
+        },
+        "model.Webhook": {
+            "type": "object",
+            "required": [
+                "name",
+                "url"
+            ],
+            "properties": {
+                "created_at": {
+                    "description": "作成日時",
+                    "type": "string"
+                },
+                "feed_id": {
+                    "description": "通知対象のフィードID",
+                    "type": "string"
+                },
+                "folder_id": {
+                    "description": "通知対象のフォルダID",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Webhook の一意識別子",
+                    "type": "string"
+                },
+                "keyword": {
+                    "description": "絞り込みキーワード",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "表示名（必須）",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "payload_template": {
+                    "description": "ペイロードのテンプレート",
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "署名の鍵（作成時のみ）",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "url": {
+                    "description": "通知先URL（必須、URL形式）",
+                    "type": "string"
+                }
+            }
+        },
+        "model.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "article_id": {
+                    "description": "記事ID",
+                    "type": "string"
+                },
+                "attempts": {
+                    "description": "送信回数",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "作成日時",
+                    "type": "string"
+                },
+                "error": {
+                    "description": "最後のエラー",
+                    "type": "string"
+                },
+                "event": {
+                    "description": "イベント名",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "配信の一意識別子",
+                    "type": "string"
+                },
+                "last_attempt_at": {
+                    "description": "最終送信日時",
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "description": "次回送信日時",
+                    "type": "string"
+                },
+                "payload": {
+                    "description": "送信したJSON",
+                    "type": "string"
+                },
+                "response_status": {
+                    "description": "最後のHTTPステータス",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "配信状態",
+                    "type": "string"
+                },
+                "webhook_id": {
+                    "description": "Webhook ID",
                     "type": "string"
                 }
             }
         }
     },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "個人APIトークンを \"Bearer \u003ctoken\u003e\" の形式で指定します。ブラウザからはセッションCookieで認証されます",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
+    },
     "tags": [
         {
             "description": "フォルダ管理API",
@@ -829,6 +3782,34 @@ const docTemplate = `{
         {
             "description": "記事管理API",
             "name": "articles"
+        },
+        {
+            "description": "認証API",
+            "name": "auth"
+        },
+        {
+            "description": "Server-Sent Events によるイベント配信API",
+            "name": "events"
+        },
+        {
+            "description": "新着記事を外部サービスへ通知する Webhook の管理API",
+            "name": "webhooks"
+        },
+        {
+            "description": "記事のタグの管理API",
+            "name": "tags"
+        },
+        {
+            "description": "記事の本文に付ける注釈（ハイライトとメモ）の管理API",
+            "name": "annotations"
+        },
+        {
+            "description": "取り込む記事を既読・後で見る・除外・タグ付けするルールの管理API",
+            "name": "rules"
+        },
+        {
+            "description": "保持設定による古い記事の削除などデータベースの保守API",
+            "name": "maintenance"
         }
     ]
 }`
@@ -1,13 +1,26 @@
 "use client";
 
-import useSWR from 'swr';
+import { useEffect } from 'react';
+import useSWR, { useSWRConfig } from 'swr';
 import { Folder } from '../services/folder';
 import { Article } from '../services/article';
 import ArticleList from '../components/ArticleList';
+import { subscribeEvents } from '../services/events';
 
 export default function Home() {
   const { data: folders, error: foldersError } = useSWR<Folder[]>('/folders');
   const { data: articles, error: articlesError } = useSWR<Article[]>('/articles');
+  const { mutate } = useSWRConfig();
+
+  // 新着記事や他のタブ・端末での既読変更を受信したら記事一覧を再取得する
+  useEffect(() => {
+    return subscribeEvents((type) => {
+      if (type === 'article.created' || type === 'article.updated' || type === 'reset') {
+        mutate('/articles');
+        mutate('/articles/later');
+      }
+    });
+  }, [mutate]);
 
   if (foldersError || articlesError) return <div>Failed to load data</div>;
   if (!folders || !articles) return <div>Loading...</div>;
@@ -40,6 +40,8 @@ const api = axios.create({
   headers: {
     'Content-Type': 'application/json',
   },
+  // セッションCookieで認証するため、Cookieを送信する
+  withCredentials: true,
 });
 
 export default api;
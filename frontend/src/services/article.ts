@@ -1,14 +1,32 @@
 import api from './api';
 
+export interface Enclosure {
+  url: string;
+  mime_type?: string;
+  length?: number;
+  duration?: number;
+}
+
 export interface Article {
   id: string;
   feed_id: string;
+  guid?: string;
   title: string;
+  author?: string;
+  summary?: string;
   content?: string;
   url: string;
+  image_url?: string;
+  categories?: string[];
+  enclosures?: Enclosure[];
+  episode?: number;
   published_at?: string;
   is_read: boolean;
   is_later: boolean;
+  playback_position_seconds: number;
+  played: boolean;
+  downloaded: boolean;
+  extracted_at?: string;
   created_at: string;
 }
 
@@ -28,6 +46,19 @@ export const articleService = {
     return response.data;
   },
 
+  updateArticleMedia: async (
+    id: string,
+    media: { playback_position_seconds?: number; played?: boolean; downloaded?: boolean },
+  ): Promise<Article> => {
+    const response = await api.put<Article>(`/articles/${id}/media`, media);
+    return response.data;
+  },
+
+  extractArticleContent: async (id: string): Promise<Article> => {
+    const response = await api.post<Article>(`/articles/${id}/extract`);
+    return response.data;
+  },
+
   getLaterArticles: async (): Promise<Article[]> => {
     const response = await api.get<Article[]>('/articles/later');
     return response.data;
@@ -0,0 +1,101 @@
+/**
+ * @fileoverview 認証関連のAPIサービスと型定義
+ *
+ * ユーザー登録・ログイン・ログアウトと、スクリプト用の個人APIトークンの
+ * 管理を行うAPIクライアント機能を提供します。ブラウザではログイン時に
+ * 発行されるHTTP-onlyのセッションCookieで認証されます。
+ *
+ * @author FeedApp Team
+ * @version 1.0.0
+ */
+
+import api from './api';
+
+/**
+ * ユーザーのデータ型定義
+ *
+ * @interface User
+ */
+export interface User {
+  /** ユーザーの一意識別子（UUID形式） */
+  id: string;
+  /** メールアドレス */
+  email: string;
+  /** 表示名 */
+  name: string;
+  /** Fever API のパスワードが設定されているか */
+  fever_enabled: boolean;
+  /** ユーザーの作成日時（ISO 8601形式） */
+  created_at: string;
+}
+
+/**
+ * 個人APIトークンのデータ型定義
+ *
+ * @interface APIToken
+ */
+export interface APIToken {
+  /** トークンの一意識別子（UUID形式） */
+  id: string;
+  /** トークンの用途を表す名前 */
+  name: string;
+  /** トークン文字列（発行時のレスポンスにのみ含まれる） */
+  token?: string;
+  /** 最終使用日時（ISO 8601形式） */
+  last_used_at?: string;
+  /** トークンの作成日時（ISO 8601形式） */
+  created_at: string;
+}
+
+export const authService = {
+  signup: async (email: string, password: string, name?: string): Promise<User> => {
+    const response = await api.post<User>('/auth/signup', { email, password, name });
+    return response.data;
+  },
+
+  login: async (email: string, password: string): Promise<User> => {
+    const response = await api.post<User>('/auth/login', { email, password });
+    return response.data;
+  },
+
+  /**
+   * OpenID Connect プロバイダー（Google など）のログインURLを返します。
+   * ブラウザをこのURLに遷移させると、ログイン後にセッションCookieが発行されます。
+   */
+  oidcLoginURL: (): string => `${api.defaults.baseURL}/auth/oidc/login`,
+
+  logout: async (): Promise<void> => {
+    await api.post('/auth/logout');
+  },
+
+  getCurrentUser: async (): Promise<User> => {
+    const response = await api.get<User>('/auth/me');
+    return response.data;
+  },
+
+  getAPITokens: async (): Promise<APIToken[]> => {
+    const response = await api.get<APIToken[]>('/auth/tokens');
+    return response.data;
+  },
+
+  createAPIToken: async (name: string): Promise<APIToken> => {
+    const response = await api.post<APIToken>('/auth/tokens', { name });
+    return response.data;
+  },
+
+  deleteAPIToken: async (id: string): Promise<void> => {
+    await api.delete(`/auth/tokens/${id}`);
+  },
+
+  /**
+   * Fever API 対応アプリで使用するパスワードを設定します。
+   * アプリにはメールアドレスとこのパスワードを入力します。
+   */
+  setFeverPassword: async (password: string): Promise<void> => {
+    await api.put('/auth/fever', { password });
+  },
+
+  deleteFeverPassword: async (): Promise<void> => {
+    await api.delete('/auth/fever');
+  },
+};
@@ -0,0 +1,37 @@
+import api from './api';
+
+/** サーバーから配信されるイベントの種別 */
+export type ServerEventType =
+  | 'article.created'
+  | 'article.updated'
+  | 'feed.refreshed'
+  | 'feed.error'
+  | 'reset';
+
+const eventTypes: ServerEventType[] = [
+  'article.created',
+  'article.updated',
+  'feed.refreshed',
+  'feed.error',
+  'reset',
+];
+
+/**
+ * `GET /events` の Server-Sent Events を購読します。
+ *
+ * 切断時はブラウザが `Last-Event-ID` を付けて自動的に再接続し、切断中のイベントが再送されます。
+ * 再送できない場合は `reset` イベントが届くため、表示中のデータを取得し直してください。
+ *
+ * @returns 購読を終了する関数
+ */
+export const subscribeEvents = (
+  onEvent: (type: ServerEventType, data: unknown) => void,
+): (() => void) => {
+  const source = new EventSource(`${api.defaults.baseURL}/events`, { withCredentials: true });
+  for (const type of eventTypes) {
+    source.addEventListener(type, (event) => {
+      onEvent(type, JSON.parse((event as MessageEvent).data));
+    });
+  }
+  return () => source.close();
+};
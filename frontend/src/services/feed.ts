@@ -7,6 +7,7 @@ export interface Feed {
   plugin_type: string;
   folder_id?: string;
   update_interval: number;
+  fetch_full_content: boolean;
   last_updated?: string;
   created_at: string;
 }
@@ -0,0 +1,222 @@
+// Package events はブラウザやモバイルアプリへ配信するイベントのブローカーを提供します。
+//
+// 記事の取り込みや既読状態の変更などをユーザー単位のイベントとして発行し、
+// Server-Sent Events（GET /api/v1/events）で接続中のクライアントへ配信します。
+// 直近のイベントは固定長のリングバッファに保持し、再接続したクライアントが
+// Last-Event-ID 以降に発行されたイベントを受け取れるようにします。
+// イベントはプロセスのメモリ上にのみ保持し、サーバーの再起動で失われます。
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// イベントの種別です。
+const (
+	ArticleCreated = "article.created" // 購読しているフィードに新しい記事が取り込まれた
+	ArticleUpdated = "article.updated" // 記事の既読・後で見る状態が変更された
+	FeedRefreshed  = "feed.refreshed"  // 購読しているフィードを取得した
+	FeedError      = "feed.error"      // 購読しているフィードの取得に失敗した
+	// Reset は Last-Event-ID 以降のイベントを再送できない場合に送信します。
+	// 受信したクライアントは表示中のデータを取得し直す必要があります。
+	Reset = "reset"
+)
+
+const (
+	// DefaultBufferSize は再送のために保持するイベントのデフォルトの件数です。
+	DefaultBufferSize = 1024
+	// subscriberBufferSize は購読者ごとの未送信イベントの上限です。
+	// 上限を超えた購読者は切断され、再接続時にリングバッファから再送されます。
+	subscriberBufferSize = 64
+)
+
+// ArticleUpdatedData は article.updated イベントの内容です。
+//
+// ArticleIDs が空の場合は一括既読の操作で、FeedID / FolderID（いずれも空の場合はすべての記事）に
+// 一致する、Until より前に取り込まれた記事が既読になったことを表します。
+type ArticleUpdatedData struct {
+	ArticleIDs []string   `json:"article_ids,omitempty"` // 状態が変更された記事のID
+	FeedID     string     `json:"feed_id,omitempty"`     // 一括既読の対象フィード
+	FolderID   string     `json:"folder_id,omitempty"`   // 一括既読の対象フォルダ
+	Until      *time.Time `json:"until,omitempty"`       // 一括既読の対象となる取り込み日時の上限
+	IsRead     *bool      `json:"is_read,omitempty"`     // 変更後の既読フラグ（変更しない場合は省略）
+	IsLater    *bool      `json:"is_later,omitempty"`    // 変更後の後で見るフラグ（変更しない場合は省略）
+}
+
+// FeedRefreshedData は feed.refreshed イベントの内容です。
+type FeedRefreshedData struct {
+	FeedID      string    `json:"feed_id"`      // フィードID
+	Created     int       `json:"created"`      // 新しく取り込んだ記事の件数
+	LastUpdated time.Time `json:"last_updated"` // 取得日時
+}
+
+// FeedErrorData は feed.error イベントの内容です。
+type FeedErrorData struct {
+	FeedID string `json:"feed_id"` // フィードID
+	Error  string `json:"error"`   // エラーの内容
+}
+
+// Event は1件のイベントです。
+type Event struct {
+	ID      string          // イベントID（"<起動ID>-<連番>"。SSE の id フィールドの値）
+	Type    string          // イベントの種別
+	Data    json.RawMessage // イベントの内容（JSON）
+	seq     uint64
+	userIDs []string
+}
+
+// Subscription はイベントの購読です。
+type Subscription struct {
+	// Missed は Last-Event-ID 以降に発行され、再送するイベントです。
+	Missed []Event
+	// Reset は Last-Event-ID 以降のイベントを再送できなかったことを表します。
+	// リングバッファから既に削除された場合や、サーバーが再起動した場合に true となります。
+	Reset bool
+	// C は新しいイベントを受信するチャネルです。購読者の受信が追いつかない場合は閉じられます。
+	C <-chan Event
+
+	userID string
+	ch     chan Event
+}
+
+// Broker はユーザー単位のイベントを購読者へ配信します。
+type Broker struct {
+	mu          sync.Mutex
+	bootID      string
+	seq         uint64
+	buffer      []Event // 発行順のリングバッファ
+	next        int     // 次に書き込む buffer の位置
+	size        int     // buffer に保持しているイベントの件数
+	subscribers map[*Subscription]struct{}
+}
+
+// NewBroker は直近 bufferSize 件のイベントを保持する Broker を作成します。
+// bufferSize が0以下の場合は DefaultBufferSize を使用します。
+func NewBroker(bufferSize int) *Broker {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &Broker{
+		bootID:      strconv.FormatInt(time.Now().UnixNano(), 36),
+		buffer:      make([]Event, bufferSize),
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish は userIDs のユーザーへイベントを発行します。data はJSONに変換して配信します。
+func (b *Broker) Publish(userIDs []string, eventType string, data any) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s event: %w", eventType, err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	event := Event{
+		ID:      b.bootID + "-" + strconv.FormatUint(b.seq, 10),
+		Type:    eventType,
+		Data:    payload,
+		seq:     b.seq,
+		userIDs: userIDs,
+	}
+	b.buffer[b.next] = event
+	b.next = (b.next + 1) % len(b.buffer)
+	if b.size < len(b.buffer) {
+		b.size++
+	}
+
+	for sub := range b.subscribers {
+		if !event.isFor(sub.userID) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			// 受信が追いつかない購読者は切断し、再接続時の再送に任せる
+			b.remove(sub)
+		}
+	}
+	return nil
+}
+
+// Subscribe は userID 宛てのイベントを購読します。
+//
+// lastEventID を指定した場合は、そのイベントより後に発行された userID 宛てのイベントを
+// Missed に設定します。購読が不要になったら Unsubscribe を呼び出してください。
+func (b *Broker) Subscribe(userID, lastEventID string) *Subscription {
+	ch := make(chan Event, subscriberBufferSize)
+	sub := &Subscription{C: ch, userID: userID, ch: ch}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if lastEventID != "" {
+		sub.Missed, sub.Reset = b.since(userID, lastEventID)
+	}
+	b.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// Unsubscribe は購読を終了し、C を閉じます。既に終了している場合は何もしません。
+func (b *Broker) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.remove(sub)
+}
+
+// remove は購読者を削除してチャネルを閉じます。b.mu を保持した状態で呼び出します。
+func (b *Broker) remove(sub *Subscription) {
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+	delete(b.subscribers, sub)
+	close(sub.ch)
+}
+
+// since は lastEventID より後に発行された userID 宛てのイベントを返します。
+// 途中のイベントがリングバッファから削除されている、または別の起動で発行されたIDの場合は
+// 再送できないため、2番目の戻り値に true を返します。b.mu を保持した状態で呼び出します。
+func (b *Broker) since(userID, lastEventID string) ([]Event, bool) {
+	bootID, seqText, found := strings.Cut(lastEventID, "-")
+	seq, err := strconv.ParseUint(seqText, 10, 64)
+	if !found || err != nil || bootID != b.bootID || seq > b.seq {
+		return nil, true
+	}
+
+	if b.size == 0 || seq == b.seq {
+		return nil, false
+	}
+	if oldest := b.seq - uint64(b.size) + 1; seq+1 < oldest {
+		return nil, true
+	}
+
+	var missed []Event
+	start := (b.next - b.size + len(b.buffer)) % len(b.buffer)
+	for i := 0; i < b.size; i++ {
+		event := b.buffer[(start+i)%len(b.buffer)]
+		if event.seq > seq && event.isFor(userID) {
+			missed = append(missed, event)
+		}
+	}
+	return missed, false
+}
+
+// isFor はイベントが userID 宛てかを判定します。
+func (e Event) isFor(userID string) bool {
+	for _, id := range e.userIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package events
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// receive は購読のチャネルに届いているイベントをすべて取り出します。
+func receive(sub *Subscription) []Event {
+	var received []Event
+	for {
+		select {
+		case event, ok := <-sub.C:
+			if !ok {
+				return received
+			}
+			received = append(received, event)
+		default:
+			return received
+		}
+	}
+}
+
+func eventTypes(events []Event) []string {
+	types := make([]string, 0, len(events))
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func TestBroker_PublishDeliversToTargetUsers(t *testing.T) {
+	broker := NewBroker(16)
+	alice := broker.Subscribe("alice", "")
+	bob := broker.Subscribe("bob", "")
+	defer broker.Unsubscribe(alice)
+	defer broker.Unsubscribe(bob)
+
+	require.NoError(t, broker.Publish([]string{"alice", "bob"}, FeedRefreshed, map[string]string{"feed_id": "f1"}))
+	require.NoError(t, broker.Publish([]string{"alice"}, ArticleUpdated, map[string]any{"article_ids": []string{"a1"}}))
+	require.NoError(t, broker.Publish(nil, ArticleCreated, map[string]string{"id": "ignored"}))
+
+	aliceEvents := receive(alice)
+	assert.Equal(t, []string{FeedRefreshed, ArticleUpdated}, eventTypes(aliceEvents))
+	assert.JSONEq(t, `{"feed_id":"f1"}`, string(aliceEvents[0].Data))
+	assert.NotEqual(t, aliceEvents[0].ID, aliceEvents[1].ID)
+	assert.Equal(t, []string{FeedRefreshed}, eventTypes(receive(bob)))
+}
+
+func TestBroker_SubscribeResumesFromLastEventID(t *testing.T) {
+	broker := NewBroker(4)
+	first := broker.Subscribe("alice", "")
+	require.NoError(t, broker.Publish([]string{"alice"}, ArticleCreated, "a1"))
+	received := receive(first)
+	require.Len(t, received, 1)
+	lastEventID := received[0].ID
+	broker.Unsubscribe(first)
+
+	// 切断中に発行されたイベント（他のユーザー宛てを含む）
+	require.NoError(t, broker.Publish([]string{"alice"}, ArticleCreated, "a2"))
+	require.NoError(t, broker.Publish([]string{"bob"}, ArticleCreated, "b1"))
+	require.NoError(t, broker.Publish([]string{"alice"}, ArticleUpdated, "a1"))
+
+	t.Run("replays missed events for the user", func(t *testing.T) {
+		sub := broker.Subscribe("alice", lastEventID)
+		defer broker.Unsubscribe(sub)
+
+		assert.False(t, sub.Reset)
+		require.Len(t, sub.Missed, 2)
+		assert.JSONEq(t, `"a2"`, string(sub.Missed[0].Data))
+		assert.Equal(t, ArticleUpdated, sub.Missed[1].Type)
+	})
+
+	t.Run("replays nothing when up to date", func(t *testing.T) {
+		latest := broker.Subscribe("bob", "")
+		require.NoError(t, broker.Publish([]string{"bob"}, FeedError, "e"))
+		events := receive(latest)
+		broker.Unsubscribe(latest)
+		require.Len(t, events, 1)
+
+		sub := broker.Subscribe("bob", events[0].ID)
+		defer broker.Unsubscribe(sub)
+		assert.False(t, sub.Reset)
+		assert.Empty(t, sub.Missed)
+	})
+
+	t.Run("requests reset when events were evicted", func(t *testing.T) {
+		for i := 0; i < 4; i++ {
+			require.NoError(t, broker.Publish([]string{"alice"}, ArticleCreated, i))
+		}
+		sub := broker.Subscribe("alice", lastEventID)
+		defer broker.Unsubscribe(sub)
+
+		assert.True(t, sub.Reset)
+		assert.Empty(t, sub.Missed)
+	})
+
+	t.Run("requests reset for ids from another process", func(t *testing.T) {
+		for _, id := range []string{"unknown-1", "garbage", strings.Split(lastEventID, "-")[0] + "-999999"} {
+			sub := broker.Subscribe("alice", id)
+			assert.True(t, sub.Reset, id)
+			broker.Unsubscribe(sub)
+		}
+	})
+}
+
+func TestBroker_DisconnectsSlowSubscriber(t *testing.T) {
+	broker := NewBroker(0)
+	sub := broker.Subscribe("alice", "")
+
+	for i := 0; i <= subscriberBufferSize; i++ {
+		require.NoError(t, broker.Publish([]string{"alice"}, ArticleCreated, i))
+	}
+
+	received := receive(sub)
+	assert.Len(t, received, subscriberBufferSize)
+	_, ok := <-sub.C
+	assert.False(t, ok, "channel should be closed")
+
+	// 切断後の Unsubscribe は何もしない
+	broker.Unsubscribe(sub)
+}
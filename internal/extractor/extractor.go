@@ -0,0 +1,360 @@
+// Package extractor は記事ページから本文を抽出する処理を提供します。
+//
+// 要約しか配信しないフィードのために、記事URLのHTMLを取得し、
+// Readability と同様のアルゴリズム（段落ごとのスコアリングと、ナビゲーションや
+// 広告などの不要要素の除去）で本文部分のHTMLを抽出します。
+// 抽出結果はサニタイズ前のHTMLのため、保存前に sanitizer.Sanitize を通してください。
+package extractor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"feedapp/internal/fetcher"
+)
+
+// ErrNoContent は本文と判断できる要素が見つからなかった場合に返されます。
+var ErrNoContent = errors.New("no readable content found")
+
+// minParagraphLength はスコアリング対象とする段落の最小文字数です。
+const minParagraphLength = 25
+
+var (
+	// unlikelyCandidates は本文ではない可能性が高い要素の class / id です。
+	unlikelyCandidates = regexp.MustCompile(`(?i)banner|breadcrumb|combx|comment|community|cover-wrap|disqus|extra|footer|gdpr|header|legends|menu|related|remark|replies|rss|shoutbox|sidebar|skyscraper|social|sponsor|supplemental|ad-break|agegate|pagination|pager|popup|yom-remote|share|cookie|newsletter|subscribe`)
+	// maybeCandidates は unlikelyCandidates に一致しても除去しない class / id です。
+	maybeCandidates = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+	// positiveNames は本文である可能性が高い class / id です。
+	positiveNames = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|pagination|post|text|blog|story`)
+	// negativeNames は本文ではない可能性が高い class / id です。
+	negativeNames = regexp.MustCompile(`(?i)-ad-|^ad-|^ads?$|hidden|banner|combx|comment|com-|contact|foot|footer|footnote|gdpr|masthead|media|meta|nav|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget`)
+)
+
+// removedTags は抽出前に子要素を含めて除去するタグです。
+var removedTags = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Nav:      true,
+	atom.Header:   true,
+	atom.Footer:   true,
+	atom.Aside:    true,
+	atom.Form:     true,
+	atom.Iframe:   true,
+	atom.Button:   true,
+	atom.Select:   true,
+	atom.Svg:      true,
+}
+
+// Extractor は記事URLを取得して本文を抽出します。
+type Extractor struct {
+	client *fetcher.Client
+}
+
+// New は client を使用して記事を取得する Extractor を作成します。
+func New(client *fetcher.Client) *Extractor {
+	return &Extractor{client: client}
+}
+
+// Fetch は pageURL のHTMLを取得し、本文部分のHTMLを返します。
+// リダイレクトされた場合は、相対URLの解決に最終URLを使用できるよう
+// 最終URLも返します。
+func (e *Extractor) Fetch(ctx context.Context, pageURL string) (content string, finalURL string, err error) {
+	resp, err := e.client.Get(ctx, pageURL)
+	if err != nil {
+		return "", "", err
+	}
+	content, err = Extract(resp.Body)
+	if err != nil {
+		return "", "", err
+	}
+	return content, resp.URL, nil
+}
+
+// Extract はHTML文書から本文部分のHTMLを抽出します。
+//
+// アルゴリズムの概要:
+//  1. script・nav・footer などの要素と、class / id から本文ではないと判断できる要素を除去する
+//  2. 一定以上の長さの段落ごとにスコア（カンマの数と文字数）を計算し、親要素に加算、祖父母要素に半分を加算する
+//  3. 各候補のスコアを class / id の重みとリンク密度で補正し、最もスコアの高い要素を本文とする
+//  4. 本文要素と同じ親を持つ兄弟要素のうち、スコアの高いものや本文らしい段落を結合する
+func Extract(document []byte) (string, error) {
+	doc, err := html.Parse(bytes.NewReader(document))
+	if err != nil {
+		return "", err
+	}
+
+	body := findFirst(doc, atom.Body)
+	if body == nil {
+		return "", ErrNoContent
+	}
+	removeUnlikely(body)
+
+	scores := scoreParagraphs(body)
+	top := topCandidate(scores)
+	if top == nil {
+		return "", ErrNoContent
+	}
+
+	var b strings.Builder
+	for _, n := range collectSiblings(top, scores) {
+		if err := html.Render(&b, n); err != nil {
+			return "", err
+		}
+	}
+	return b.String(), nil
+}
+
+// removeUnlikely は本文ではない要素を除去します。
+func removeUnlikely(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		switch c.Type {
+		case html.ElementNode:
+			names := className(c)
+			unlikely := unlikelyCandidates.MatchString(names) && !maybeCandidates.MatchString(names) && c.DataAtom != atom.Body && c.DataAtom != atom.A
+			if removedTags[c.DataAtom] || unlikely || isHidden(c) {
+				n.RemoveChild(c)
+			} else {
+				removeUnlikely(c)
+			}
+		case html.CommentNode:
+			n.RemoveChild(c)
+		}
+		c = next
+	}
+}
+
+// scoreParagraphs は段落のスコアを親要素・祖父母要素に加算し、候補要素ごとのスコアを返します。
+func scoreParagraphs(body *html.Node) map[*html.Node]float64 {
+	scores := make(map[*html.Node]float64)
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			switch c.DataAtom {
+			case atom.P, atom.Pre, atom.Td, atom.Blockquote:
+				scoreParagraph(c, scores)
+			default:
+				walk(c)
+			}
+		}
+	}
+	walk(body)
+
+	// リンク密度が高い候補（リンク集など）はスコアを下げる
+	for n, score := range scores {
+		scores[n] = score * (1 - linkDensity(n))
+	}
+	return scores
+}
+
+// scoreParagraph は1つの段落のスコアを計算し、祖先要素に加算します。
+func scoreParagraph(p *html.Node, scores map[*html.Node]float64) {
+	text := textContent(p)
+	length := len([]rune(text))
+	if length < minParagraphLength {
+		return
+	}
+
+	score := 1 + float64(strings.Count(text, ",")+strings.Count(text, "、")+strings.Count(text, "，"))
+	score += math.Min(float64(length)/100, 3)
+
+	parent := p.Parent
+	if parent == nil || parent.Type != html.ElementNode {
+		return
+	}
+	initialize(parent, scores)
+	scores[parent] += score
+
+	if grandparent := parent.Parent; grandparent != nil && grandparent.Type == html.ElementNode {
+		initialize(grandparent, scores)
+		scores[grandparent] += score / 2
+	}
+}
+
+// initialize は候補要素の初期スコアをタグ名と class / id から設定します。
+func initialize(n *html.Node, scores map[*html.Node]float64) {
+	if _, ok := scores[n]; ok {
+		return
+	}
+	var score float64
+	switch n.DataAtom {
+	case atom.Article:
+		score = 10
+	case atom.Main, atom.Div:
+		score = 5
+	case atom.Section, atom.Pre, atom.Td, atom.Blockquote:
+		score = 3
+	case atom.Address, atom.Ol, atom.Ul, atom.Dl, atom.Dd, atom.Dt, atom.Li:
+		score = -3
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Th:
+		score = -5
+	}
+	scores[n] = score + classWeight(n)
+}
+
+// classWeight は class / id から本文らしさの重みを計算します。
+func classWeight(n *html.Node) float64 {
+	var weight float64
+	for _, name := range []string{getAttr(n, "class"), getAttr(n, "id")} {
+		if name == "" {
+			continue
+		}
+		if negativeNames.MatchString(name) {
+			weight -= 25
+		}
+		if positiveNames.MatchString(name) {
+			weight += 25
+		}
+	}
+	return weight
+}
+
+// topCandidate は最もスコアの高い候補要素を返します。
+func topCandidate(scores map[*html.Node]float64) *html.Node {
+	candidates := make([]*html.Node, 0, len(scores))
+	for n := range scores {
+		if n.DataAtom == atom.Body || n.DataAtom == atom.Html {
+			continue
+		}
+		candidates = append(candidates, n)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if scores[candidates[i]] != scores[candidates[j]] {
+			return scores[candidates[i]] > scores[candidates[j]]
+		}
+		// 同点の場合はより外側の要素を優先する
+		return depth(candidates[i]) < depth(candidates[j])
+	})
+	return candidates[0]
+}
+
+// collectSiblings は本文要素と、本文の一部とみなせる兄弟要素を文書順に返します。
+func collectSiblings(top *html.Node, scores map[*html.Node]float64) []*html.Node {
+	parent := top.Parent
+	if parent == nil {
+		return []*html.Node{top}
+	}
+
+	threshold := math.Max(10, scores[top]*0.2)
+	var nodes []*html.Node
+	for s := parent.FirstChild; s != nil; s = s.NextSibling {
+		if s.Type != html.ElementNode {
+			continue
+		}
+		if s == top {
+			nodes = append(nodes, s)
+			continue
+		}
+		if score, ok := scores[s]; ok && score >= threshold {
+			nodes = append(nodes, s)
+			continue
+		}
+		if s.DataAtom == atom.P {
+			text := textContent(s)
+			density := linkDensity(s)
+			length := len([]rune(text))
+			if (length > 80 && density < 0.25) || (length > 0 && density == 0 && strings.ContainsAny(text, ".。")) {
+				nodes = append(nodes, s)
+			}
+		}
+	}
+	return nodes
+}
+
+// linkDensity は要素のテキストのうちリンクテキストが占める割合を返します。
+func linkDensity(n *html.Node) float64 {
+	total := len([]rune(textContent(n)))
+	if total == 0 {
+		return 0
+	}
+	linkLength := 0
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && c.DataAtom == atom.A {
+				linkLength += len([]rune(textContent(c)))
+				continue
+			}
+			walk(c)
+		}
+	}
+	walk(n)
+	return float64(linkLength) / float64(total)
+}
+
+// textContent は要素内のテキストを空白を正規化して返します。
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+			b.WriteString(" ")
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// isHidden は style や hidden 属性で非表示にされている要素かどうかを判定します。
+func isHidden(n *html.Node) bool {
+	for _, attr := range n.Attr {
+		if attr.Key == "hidden" || (attr.Key == "aria-hidden" && attr.Val == "true") {
+			return true
+		}
+	}
+	style := strings.ReplaceAll(strings.ToLower(getAttr(n, "style")), " ", "")
+	return strings.Contains(style, "display:none") || strings.Contains(style, "visibility:hidden")
+}
+
+func className(n *html.Node) string {
+	return getAttr(n, "class") + " " + getAttr(n, "id")
+}
+
+func findFirst(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findFirst(c, a); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func depth(n *html.Node) int {
+	d := 0
+	for p := n.Parent; p != nil; p = p.Parent {
+		d++
+	}
+	return d
+}
+
+func getAttr(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package extractor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtract(t *testing.T) {
+	document := []byte(`<!DOCTYPE html>
+<html>
+<head><title>Example</title><script>var tracking = true;</script></head>
+<body>
+  <header><a href="/">Home</a> <a href="/about">About</a></header>
+  <nav class="menu"><ul><li><a href="/a">Category A</a></li><li><a href="/b">Category B</a></li></ul></nav>
+  <div id="sidebar">
+    <p>Subscribe to our newsletter, get updates, news, and offers every single week.</p>
+  </div>
+  <div class="ad-banner"><p>Buy now, limited offer, great discount, only today, hurry up!</p></div>
+  <article class="post">
+    <h1>Article title</h1>
+    <div class="entry-content">
+      <p>The first paragraph of the article explains the topic in detail, with commas, clauses, and enough text to be scored.</p>
+      <p>The second paragraph continues the story, adding more context, examples, and explanations for the reader.</p>
+      <p><img src="figure.png" alt="figure"></p>
+      <p>The third paragraph wraps up the article, summarizing the main points, and giving a conclusion.</p>
+    </div>
+  </article>
+  <div class="related-links"><a href="/x">Related article one with a long title</a></div>
+  <footer><p>Copyright 2025, Example Inc., all rights reserved, terms of service.</p></footer>
+</body>
+</html>`)
+
+	content, err := Extract(document)
+	require.NoError(t, err)
+
+	assert.Contains(t, content, "The first paragraph")
+	assert.Contains(t, content, "The third paragraph")
+	assert.Contains(t, content, `<img src="figure.png" alt="figure"/>`)
+	assert.NotContains(t, content, "newsletter")
+	assert.NotContains(t, content, "Buy now")
+	assert.NotContains(t, content, "Category A")
+	assert.NotContains(t, content, "Copyright")
+	assert.NotContains(t, content, "Related article")
+	assert.NotContains(t, content, "tracking")
+}
+
+func TestExtract_PrefersTextOverLinkLists(t *testing.T) {
+	document := []byte(`<html><body>
+  <div class="links">
+    <p><a href="/1">A very long link text that looks like a paragraph, but is only a link</a></p>
+    <p><a href="/2">Another very long link text that looks like a paragraph, but is a link</a></p>
+  </div>
+  <div>
+    <p>本文の段落です。この段落には十分な長さの文章が含まれており、本文として抽出されるべきです。</p>
+    <p>二つ目の段落です。読みやすさのアルゴリズムでは、句読点の数と文字数でスコアを計算します。</p>
+  </div>
+</body></html>`)
+
+	content, err := Extract(document)
+	require.NoError(t, err)
+	assert.Contains(t, content, "本文の段落です。")
+	assert.NotContains(t, content, "link text")
+}
+
+func TestExtract_NoContent(t *testing.T) {
+	_, err := Extract([]byte(`<html><body><nav><a href="/">Home</a></nav><p>short</p></body></html>`))
+	assert.ErrorIs(t, err, ErrNoContent)
+}
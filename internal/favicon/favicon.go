@@ -0,0 +1,215 @@
+// Package favicon はフィードのサイトのアイコン（ファビコン）の検出と取得を提供します。
+//
+// アイコンは次の順に探し、最初に取得できた画像を使用します。
+//  1. フィードが指定するアイコン（Atom の <icon>）
+//  2. サイトのトップページの <link rel="icon">（apple-touch-icon は最後に試す）
+//  3. サイトのルートの /favicon.ico
+//
+// 取得した内容は画像形式を内容から判定して検証し、SVG など画像以外の内容は使用しません。
+package favicon
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"feedapp/internal/fetcher"
+)
+
+// MaxSize はアイコン1件の最大サイズ（バイト）です。
+const MaxSize = 256 << 10
+
+// ErrNotFound は使用できるアイコンが見つからなかった場合に返されます。
+var ErrNotFound = errors.New("favicon not found")
+
+// allowedTypes は使用を許可するアイコンのメディアタイプです。
+// スクリプトを含められる SVG は、このサーバーのオリジンで配信すると危険なため許可しません。
+var allowedTypes = map[string]bool{
+	"image/x-icon":             true,
+	"image/vnd.microsoft.icon": true,
+	"image/png":                true,
+	"image/gif":                true,
+	"image/jpeg":               true,
+	"image/webp":               true,
+	"image/bmp":                true,
+}
+
+// Source はアイコンを探す手がかりです。
+type Source struct {
+	FeedURL string // フィードのURL（相対URLの基準）
+	SiteURL string // フィードのサイトのURL（空の場合はフィードのURLのルート）
+	IconURL string // フィードが指定するアイコンのURL
+}
+
+// Icon は取得したアイコンです。
+type Icon struct {
+	URL      string // 取得元のURL
+	MimeType string // 内容から判定したメディアタイプ
+	Data     []byte // 画像の内容
+}
+
+// Finder はフィードのアイコンを検出して取得します。
+type Finder struct {
+	client *fetcher.Client
+}
+
+// New は client を使用してアイコンを取得する Finder を作成します。
+func New(client *fetcher.Client) *Finder {
+	return &Finder{client: client}
+}
+
+// Find は src を手がかりにアイコンを探して取得します。
+// 使用できるアイコンが見つからない場合は ErrNotFound を返します。
+func (f *Finder) Find(ctx context.Context, src Source) (Icon, error) {
+	base, err := url.Parse(src.FeedURL)
+	if err != nil {
+		return Icon{}, ErrNotFound
+	}
+	tried := map[string]bool{}
+	try := func(rawURL string) (Icon, bool) {
+		if rawURL == "" || tried[rawURL] || ctx.Err() != nil {
+			return Icon{}, false
+		}
+		tried[rawURL] = true
+		return f.fetch(ctx, rawURL)
+	}
+
+	if icon, ok := try(resolve(base, src.IconURL)); ok {
+		return icon, nil
+	}
+
+	site := resolve(base, src.SiteURL)
+	if site == "" {
+		site = resolve(base, "/")
+	}
+	siteURL := base
+	if resp, err := f.client.Get(ctx, site); err == nil {
+		if final, err := url.Parse(resp.URL); err == nil {
+			siteURL = final
+		}
+		if isHTML(resp) {
+			for _, href := range iconLinks(resp.Body) {
+				if icon, ok := try(resolve(siteURL, href)); ok {
+					return icon, nil
+				}
+			}
+		}
+	} else if parsed, err := url.Parse(site); err == nil {
+		siteURL = parsed
+	}
+
+	for _, root := range []*url.URL{siteURL, base} {
+		if icon, ok := try(resolve(root, "/favicon.ico")); ok {
+			return icon, nil
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return Icon{}, err
+	}
+	return Icon{}, ErrNotFound
+}
+
+// fetch はアイコンを取得し、サイズと画像形式を検証します。
+func (f *Finder) fetch(ctx context.Context, rawURL string) (Icon, bool) {
+	resp, err := f.client.Get(ctx, rawURL)
+	if err != nil || len(resp.Body) == 0 || len(resp.Body) > MaxSize {
+		return Icon{}, false
+	}
+	mimeType, ok := detectType(resp.Header.Get("Content-Type"), resp.Body)
+	if !ok {
+		return Icon{}, false
+	}
+	return Icon{URL: resp.URL, MimeType: mimeType, Data: resp.Body}, true
+}
+
+// detectType は内容からアイコンのメディアタイプを判定します。
+// 内容から判定できない形式の場合のみ Content-Type ヘッダーを使用します。
+// favicon.ico は text/plain などの誤った Content-Type で配信されることが多いため、内容の判定を優先します。
+func detectType(contentType string, data []byte) (string, bool) {
+	sniffed := http.DetectContentType(data)
+	if allowedTypes[sniffed] {
+		return sniffed, true
+	}
+	if sniffed != "application/octet-stream" {
+		return "", false
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", false
+	}
+	mediaType = strings.ToLower(mediaType)
+	return mediaType, allowedTypes[mediaType]
+}
+
+// iconLinks はHTMLの <link rel="icon"> の href を返します。apple-touch-icon は最後に並べます。
+func iconLinks(document []byte) []string {
+	doc, err := html.Parse(bytes.NewReader(document))
+	if err != nil {
+		return nil
+	}
+	var icons, touchIcons []string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.DataAtom == atom.Body {
+			return
+		}
+		if n.Type == html.ElementNode && n.DataAtom == atom.Link {
+			var rel, href string
+			for _, a := range n.Attr {
+				switch a.Key {
+				case "rel":
+					rel = strings.ToLower(a.Val)
+				case "href":
+					href = strings.TrimSpace(a.Val)
+				}
+			}
+			if href != "" {
+				for _, r := range strings.Fields(rel) {
+					if r == "icon" {
+						icons = append(icons, href)
+						break
+					}
+					if r == "apple-touch-icon" || r == "apple-touch-icon-precomposed" {
+						touchIcons = append(touchIcons, href)
+						break
+					}
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	return append(icons, touchIcons...)
+}
+
+// isHTML はレスポンスがHTML文書かを判定します。
+func isHTML(resp *fetcher.Response) bool {
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return strings.HasPrefix(http.DetectContentType(resp.Body), "text/html")
+	}
+	return mediaType == "text/html" || mediaType == "application/xhtml+xml"
+}
+
+// resolve は base を基準に ref を http・https の絶対URLに変換します。変換できない場合は空文字列を返します。
+func resolve(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+	u, err := base.Parse(ref)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ""
+	}
+	u.Fragment = ""
+	return u.String()
+}
@@ -0,0 +1,118 @@
+package favicon
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"feedapp/internal/fetcher"
+)
+
+var (
+	// pngPixel は 1x1 の PNG 画像です。
+	pngPixel, _ = base64.StdEncoding.DecodeString("iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg==")
+	// icoHeader は ICO 形式の先頭のバイト列です。
+	icoHeader = []byte{0x00, 0x00, 0x01, 0x00, 0x01, 0x00, 0x10, 0x10}
+)
+
+// newSite はテスト用のサイトを作成します。handlers にないパスは 404 を返します。
+func newSite(t *testing.T, handlers map[string]http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h, ok := handlers[r.URL.Path]; ok {
+			h(w, r)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func serve(contentType string, body []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Write(body)
+	}
+}
+
+func TestFinder_Find(t *testing.T) {
+	finder := New(fetcher.NewClient())
+
+	// 正常系: フィードが指定するアイコンを優先する
+	t.Run("should use feed icon", func(t *testing.T) {
+		site := newSite(t, map[string]http.HandlerFunc{
+			"/feed-icon.png": serve("image/png", pngPixel),
+			"/favicon.ico":   serve("image/x-icon", icoHeader),
+		})
+
+		icon, err := finder.Find(context.Background(), Source{FeedURL: site.URL + "/feed.xml", IconURL: "/feed-icon.png"})
+		require.NoError(t, err)
+		assert.Equal(t, site.URL+"/feed-icon.png", icon.URL)
+		assert.Equal(t, "image/png", icon.MimeType)
+		assert.Equal(t, pngPixel, icon.Data)
+	})
+
+	// 正常系: サイトのページの <link rel="icon"> を使用し、SVG は使用しない
+	t.Run("should use link rel icon", func(t *testing.T) {
+		page := `<html><head>
+<link rel="apple-touch-icon" href="/touch.png">
+<link rel="icon" type="image/svg+xml" href="/icon.svg">
+<link rel="shortcut icon" href="icons/icon.png">
+</head><body><link rel="icon" href="/body.png"></body></html>`
+		site := newSite(t, map[string]http.HandlerFunc{
+			"/blog/":               serve("text/html; charset=utf-8", []byte(page)),
+			"/icon.svg":            serve("image/svg+xml", []byte(`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`)),
+			"/blog/icons/icon.png": serve("image/png", pngPixel),
+			"/touch.png":           serve("image/png", pngPixel),
+		})
+
+		icon, err := finder.Find(context.Background(), Source{FeedURL: site.URL + "/feed.xml", SiteURL: site.URL + "/blog/"})
+		require.NoError(t, err)
+		assert.Equal(t, site.URL+"/blog/icons/icon.png", icon.URL)
+		assert.Equal(t, "image/png", icon.MimeType)
+	})
+
+	// 正常系: /favicon.ico は Content-Type が誤っていても内容で判定する
+	t.Run("should fall back to favicon.ico", func(t *testing.T) {
+		site := newSite(t, map[string]http.HandlerFunc{
+			"/":            serve("text/html", []byte("<html><head><title>No icon</title></head></html>")),
+			"/favicon.ico": serve("text/plain", icoHeader),
+		})
+
+		icon, err := finder.Find(context.Background(), Source{FeedURL: site.URL + "/feed.xml"})
+		require.NoError(t, err)
+		assert.Equal(t, site.URL+"/favicon.ico", icon.URL)
+		assert.Equal(t, "image/x-icon", icon.MimeType)
+	})
+
+	// 異常系: 画像でない内容と最大サイズを超える画像は使用しない
+	t.Run("should return ErrNotFound if no valid icon", func(t *testing.T) {
+		site := newSite(t, map[string]http.HandlerFunc{
+			"/":            serve("text/html", []byte(`<html><head><link rel="icon" href="/large.png"></head></html>`)),
+			"/large.png":   serve("image/png", append(pngPixel, make([]byte, MaxSize)...)),
+			"/favicon.ico": serve("image/x-icon", []byte("<html><body>Not Found</body></html>")),
+		})
+
+		_, err := finder.Find(context.Background(), Source{FeedURL: site.URL + "/feed.xml"})
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestIconLinks(t *testing.T) {
+	links := iconLinks([]byte(`<html><head>
+<link rel="apple-touch-icon-precomposed" href="/touch.png">
+<link rel="stylesheet" href="/style.css">
+<link rel="ICON" href=" /a.ico ">
+<link rel="mask-icon" href="/mask.svg">
+<link rel="icon" href="">
+</head></html>`))
+	assert.Equal(t, []string{"/a.ico", "/touch.png"}, links)
+	assert.Empty(t, iconLinks([]byte(strings.Repeat("x", 10))))
+}
@@ -0,0 +1,76 @@
+package fetcher
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// credentialsKey はリクエストに付与するヘッダーを格納するコンテキストのキーです。
+type credentialsKey struct{}
+
+// credentials は特定のオリジン（スキーム・ホスト・ポート）へのリクエストにのみ付与するヘッダーです。
+type credentials struct {
+	origin origin
+	header http.Header
+}
+
+// origin はリクエスト先のスキーム・ホスト・ポートの組です。
+type origin struct {
+	scheme string
+	host   string
+	port   string
+}
+
+// originOf は URL のオリジンを返します。ポートが省略されている場合はスキームの既定のポートとします。
+func originOf(u *url.URL) origin {
+	o := origin{scheme: strings.ToLower(u.Scheme), host: strings.ToLower(u.Hostname()), port: u.Port()}
+	if o.port == "" {
+		switch o.scheme {
+		case "http":
+			o.port = "80"
+		case "https":
+			o.port = "443"
+		}
+	}
+	return o
+}
+
+// ContextWithCredentials は ctx で発行する target と同じオリジンへのリクエストに header を付与します。
+// フィードの認証情報（Authorization・Cookie など）や任意のヘッダーを指定する場合に使用します。
+//
+// header はスキーム・ホスト・ポートのいずれかが target と異なるリクエストやリダイレクトには付与しないため、
+// 記事ページが別のサイトにある場合や、別のサイト・http・別のポートへリダイレクトされた場合も認証情報は送信されません。
+func ContextWithCredentials(ctx context.Context, target *url.URL, header http.Header) context.Context {
+	if len(header) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, credentialsKey{}, credentials{origin: originOf(target), header: header})
+}
+
+// withoutCredentials は ctx で指定したヘッダーを付与しないコンテキストを返します。
+func withoutCredentials(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(credentialsKey{}).(credentials); !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, credentialsKey{}, nil)
+}
+
+// applyCredentials はリクエストのオリジンがコンテキストで指定したオリジンと一致する場合にヘッダーを付与し、
+// 一致しない場合は付与済みのヘッダーを取り除きます。
+func applyCredentials(req *http.Request) {
+	creds, ok := req.Context().Value(credentialsKey{}).(credentials)
+	if !ok {
+		return
+	}
+	if originOf(req.URL) != creds.origin {
+		for name := range creds.header {
+			req.Header.Del(name)
+		}
+		return
+	}
+	for name, values := range creds.header {
+		req.Header[http.CanonicalHeaderKey(name)] = values
+	}
+}
@@ -0,0 +1,259 @@
+// Package fetcher は外部サイトからのHTTP取得処理を提供します。
+//
+// フィード取得やスクレイピングなど、サーバーから外部へ発行するリクエストは
+// すべてこのパッケージの Client を経由させ、タイムアウトやレスポンスサイズの
+// 上限、User-Agent などの設定を一箇所で管理します。
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultUserAgent は外部リクエストに付与するデフォルトのUser-Agentです。
+	DefaultUserAgent = "FeedApp/1.0"
+	// DefaultTimeout は1リクエストあたりのデフォルトのタイムアウトです。
+	DefaultTimeout = 30 * time.Second
+	// DefaultMaxBodySize はレスポンスボディのデフォルトの最大サイズ（バイト）です。
+	DefaultMaxBodySize = 10 << 20
+	// DefaultMaxRedirects はたどるリダイレクトのデフォルトの最大回数です。
+	DefaultMaxRedirects = 5
+)
+
+var (
+	// ErrBodyTooLarge はレスポンスボディが上限サイズを超えた場合に返されます。
+	ErrBodyTooLarge = errors.New("response body too large")
+	// ErrAddressNotAllowed は接続先がプライベートアドレスなど、接続を許可しないアドレスの場合に返されます。
+	ErrAddressNotAllowed = errors.New("address not allowed")
+	// ErrTooManyRedirects はリダイレクトが上限回数を超えた場合に返されます。
+	ErrTooManyRedirects = errors.New("too many redirects")
+)
+
+// StatusError は2xx以外のHTTPステータスが返された場合のエラーです。
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d from %s", e.StatusCode, e.URL)
+}
+
+// Response は取得結果を表します。
+type Response struct {
+	URL        string      // リダイレクト後の最終URL
+	StatusCode int         // HTTPステータスコード
+	Header     http.Header // レスポンスヘッダー
+	Body       []byte      // レスポンスボディ
+}
+
+// Client は外部サイトへのHTTPリクエストを発行するクライアントです。
+type Client struct {
+	httpClient   *http.Client
+	userAgent    string
+	maxBodySize  int64
+	timeout      time.Duration
+	maxRedirects int
+	publicOnly   bool
+	allowlist    Allowlist
+	resolver     *net.Resolver
+	proxy        *url.URL
+	hostLimits   HostLimits
+	limiters     hostLimiters
+	robots       robotsCache
+}
+
+// Option は Client の設定を変更します。
+type Option func(*Client)
+
+// WithUserAgent は外部リクエストに付与する User-Agent を設定します。
+// robots.txt は User-Agent の先頭の製品名（"/" より前）に一致するグループを適用します。
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		if ua != "" {
+			c.userAgent = ua
+		}
+	}
+}
+
+// UserAgent は製品名とバージョンに連絡先のURLを付けた User-Agent（例: "FeedApp/1.0 (+https://example.com/bot)"）を返します。
+// 取得先のサイト管理者が問い合わせられるよう、連絡先を含めることを推奨します。
+func UserAgent(product, contactURL string) string {
+	if product == "" {
+		product = DefaultUserAgent
+	}
+	if contactURL = strings.TrimSpace(contactURL); contactURL == "" {
+		return product
+	}
+	return fmt.Sprintf("%s (+%s)", product, contactURL)
+}
+
+// WithMaxBodySize はレスポンスボディの最大サイズ（バイト）を設定します。
+func WithMaxBodySize(n int64) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.maxBodySize = n
+		}
+	}
+}
+
+// WithTimeout は1リクエストあたりのタイムアウト（リダイレクトとボディの読み込みを含む）を設定します。
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		if d > 0 {
+			c.timeout = d
+		}
+	}
+}
+
+// WithMaxRedirects はたどるリダイレクトの最大回数を設定します。0 の場合はリダイレクトをたどりません。
+func WithMaxRedirects(n int) Option {
+	return func(c *Client) {
+		if n >= 0 {
+			c.maxRedirects = n
+		}
+	}
+}
+
+// WithPublicAddressesOnly はプライベート・ループバック・リンクローカル・マルチキャストなど、
+// インターネット上で到達できないアドレスへの接続を拒否します。
+//
+// ホスト名はクライアント自身が名前解決し、許可したアドレスにのみ接続します。
+// リダイレクト先も接続のたびに同じ判定を行うため、名前解決のたびに異なるアドレスを返す
+// ホスト（DNS リバインディング）に対しても有効です。
+// 社内のフィードなどは WithAllowlist で許可できます。
+func WithPublicAddressesOnly() Option {
+	return func(c *Client) {
+		c.publicOnly = true
+	}
+}
+
+// WithAllowlist は WithPublicAddressesOnly でも接続を許可するホストとアドレスの範囲を設定します。
+func WithAllowlist(allowlist Allowlist) Option {
+	return func(c *Client) {
+		c.allowlist = allowlist
+	}
+}
+
+// NewClient は Client を作成します。オプションを省略した場合はデフォルト設定となります。
+// 環境変数のプロキシ（HTTP_PROXY など）は使用せず、プロキシは WithProxy・ContextWithProxy で指定します。
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		userAgent:    DefaultUserAgent,
+		maxBodySize:  DefaultMaxBodySize,
+		timeout:      DefaultTimeout,
+		maxRedirects: DefaultMaxRedirects,
+		resolver:     net.DefaultResolver,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = c.timeout
+	transport.Proxy = c.proxyFor
+	if c.publicOnly {
+		// 設定したプロキシは社内のアドレスでも接続を許可する
+		allowlist := c.allowlist
+		if c.proxy != nil {
+			allowlist.hosts = append(slices.Clip(allowlist.hosts), strings.ToLower(c.proxy.Hostname()))
+		}
+		transport.DialContext = (&guardedDialer{
+			dialer:    &net.Dialer{Timeout: c.timeout, KeepAlive: 30 * time.Second},
+			resolver:  c.resolver,
+			allowlist: allowlist,
+		}).DialContext
+	}
+	c.httpClient = &http.Client{
+		Timeout:       c.timeout,
+		Transport:     transport,
+		CheckRedirect: c.checkRedirect,
+	}
+	return c
+}
+
+// checkRedirect はリダイレクトの回数とリダイレクト先のスキームを検査します。
+// ContextWithCredentials で指定したヘッダーは、リダイレクト先が別のオリジン（スキーム・ホスト・ポート）の場合に取り除きます。
+func (c *Client) checkRedirect(req *http.Request, via []*http.Request) error {
+	applyCredentials(req)
+	if len(via) > c.maxRedirects {
+		return fmt.Errorf("%w: stopped after %d redirects", ErrTooManyRedirects, c.maxRedirects)
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return fmt.Errorf("%w: redirect to %s", ErrAddressNotAllowed, req.URL.Scheme)
+	}
+	return nil
+}
+
+// Get は指定URLをGETで取得します。
+//
+// 2xx以外のステータスの場合は *StatusError を、ボディが上限サイズを
+// 超えた場合は ErrBodyTooLarge を返します。
+func (c *Client) Get(ctx context.Context, rawURL string) (*Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	return c.Do(req)
+}
+
+// Do は任意のリクエストを発行し、レスポンスボディを読み込んで返します。
+//
+// ContextWithRobots を指定したリクエストは、robots.txt で許可されていない場合に ErrDisallowedByRobots を返します。
+// ホストごとの制限（WithHostLimits・ContextWithHostLimits）を超える場合は、発行できるまで待機します。
+func (c *Client) Do(req *http.Request) (*Response, error) {
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	applyCredentials(req)
+
+	ctx := req.Context()
+	if robotsRequired(ctx) {
+		if err := c.checkRobots(ctx, req.URL); err != nil {
+			return nil, err
+		}
+	}
+	var override *HostLimits
+	if l, ok := ctx.Value(hostLimitsKey{}).(HostLimits); ok {
+		override = &l
+	}
+	release, err := c.limiters.acquire(ctx, req.URL.Hostname(), c.hostLimits, override)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", req.URL, err)
+	}
+	defer release()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", req.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &StatusError{URL: req.URL.String(), StatusCode: resp.StatusCode}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, c.maxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if int64(len(body)) > c.maxBodySize {
+		return nil, ErrBodyTooLarge
+	}
+
+	return &Response{
+		URL:        resp.Request.URL.String(),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+	}, nil
+}
@@ -0,0 +1,175 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+)
+
+// reservedPrefixes は netip.Addr のメソッドで判定できない、到達できないアドレスの範囲です。
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // このネットワーク（RFC 1122）
+	netip.MustParsePrefix("100.64.0.0/10"),   // キャリアグレードNATの共有アドレス空間（RFC 6598）
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF プロトコル割り当て（RFC 6890）
+	netip.MustParsePrefix("192.0.2.0/24"),    // 文書用（TEST-NET-1、RFC 5737）
+	netip.MustParsePrefix("198.18.0.0/15"),   // ベンチマーク用（RFC 2544）
+	netip.MustParsePrefix("198.51.100.0/24"), // 文書用（TEST-NET-2、RFC 5737）
+	netip.MustParsePrefix("203.0.113.0/24"),  // 文書用（TEST-NET-3、RFC 5737）
+	netip.MustParsePrefix("240.0.0.0/4"),     // 将来の利用のための予約とブロードキャスト 255.255.255.255（RFC 1112、RFC 919）
+	netip.MustParsePrefix("::/96"),           // IPv4 互換アドレス（非推奨、RFC 4291）
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // ローカルで使用する NAT64（RFC 8215）
+	netip.MustParsePrefix("100::/64"),        // 破棄用（RFC 6666）
+	netip.MustParsePrefix("2001:db8::/32"),   // 文書用（RFC 3849）
+}
+
+// IPv4 アドレスを埋め込んだ IPv6 アドレスの範囲です。埋め込まれた IPv4 アドレスで判定します。
+var (
+	nat64Prefix     = netip.MustParsePrefix("64:ff9b::/96") // NAT64 の Well-Known Prefix（RFC 6052）
+	sixToFourPrefix = netip.MustParsePrefix("2002::/16")    // 6to4（RFC 3056）
+)
+
+// IsPublicAddr はインターネット上で到達できるアドレスかを返します。
+// IPv4 射影アドレス・NAT64・6to4 のアドレスは、埋め込まれた IPv4 アドレスで判定します。
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if v4, ok := embeddedIPv4(addr); ok {
+		addr = v4
+	}
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return addr.IsValid() &&
+		!addr.IsUnspecified() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast()
+}
+
+// embeddedIPv4 は NAT64（64:ff9b::/96）と 6to4（2002::/16）のアドレスに埋め込まれた IPv4 アドレスを返します。
+func embeddedIPv4(addr netip.Addr) (netip.Addr, bool) {
+	b := addr.As16()
+	switch {
+	case !addr.Is6():
+		return netip.Addr{}, false
+	case nat64Prefix.Contains(addr):
+		return netip.AddrFrom4([4]byte(b[12:16])), true
+	case sixToFourPrefix.Contains(addr):
+		return netip.AddrFrom4([4]byte(b[2:6])), true
+	}
+	return netip.Addr{}, false
+}
+
+// Allowlist は WithPublicAddressesOnly でも接続を許可するホストとアドレスの範囲です。
+// 社内のフィードなど、プライベートアドレスのサイトを取得する場合に設定します。
+type Allowlist struct {
+	hosts    []string       // ホスト名（小文字）。"." で始まるものはそのドメインのサブドメインに一致する
+	prefixes []netip.Prefix // アドレスの範囲
+}
+
+// NewAllowlist は許可するホストとアドレスの一覧から Allowlist を作成します。
+//
+// 各要素は次のいずれかです。
+//   - ホスト名（例: intranet.example.com）
+//   - "*." または "." で始まるドメイン（例: *.corp.example.com）。サブドメインに一致する
+//   - IPアドレス（例: 10.0.0.5）または CIDR 表記の範囲（例: 10.0.0.0/8）
+func NewAllowlist(entries []string) (Allowlist, error) {
+	var a Allowlist
+	for _, entry := range entries {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+			continue
+		case strings.Contains(entry, "/"):
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return Allowlist{}, fmt.Errorf("invalid allowlist entry %q: %w", entry, err)
+			}
+			a.prefixes = append(a.prefixes, prefix.Masked())
+		default:
+			if addr, err := netip.ParseAddr(entry); err == nil {
+				a.prefixes = append(a.prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+				continue
+			}
+			if strings.ContainsAny(entry, ":*") && !strings.HasPrefix(entry, "*.") {
+				return Allowlist{}, fmt.Errorf("invalid allowlist entry %q", entry)
+			}
+			a.hosts = append(a.hosts, strings.TrimPrefix(entry, "*"))
+		}
+	}
+	return a, nil
+}
+
+// allowsHost はホスト名が許可されているかを返します。
+func (a Allowlist) allowsHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, h := range a.hosts {
+		if host == h || (strings.HasPrefix(h, ".") && strings.HasSuffix(host, h)) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsAddr はアドレスが許可されているかを返します。
+func (a Allowlist) allowsAddr(addr netip.Addr) bool {
+	for _, prefix := range a.prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// guardedDialer は名前解決したアドレスを検査してから接続するダイアラーです。
+// 接続は検査したアドレスに対して行うため、検査後に名前解決の結果が変わっても影響を受けません。
+type guardedDialer struct {
+	dialer    *net.Dialer
+	resolver  *net.Resolver
+	allowlist Allowlist
+}
+
+// DialContext はホストを名前解決し、接続を許可するアドレスに順に接続します。
+// 許可するアドレスがない場合は ErrAddressNotAllowed を返します。
+func (d *guardedDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	lookupNetwork := "ip"
+	switch network {
+	case "tcp4":
+		lookupNetwork = "ip4"
+	case "tcp6":
+		lookupNetwork = "ip6"
+	}
+	addrs, err := d.resolver.LookupNetIP(ctx, lookupNetwork, host)
+	if err != nil {
+		return nil, err
+	}
+
+	hostAllowed := d.allowlist.allowsHost(host)
+	var lastErr error
+	for _, addr := range addrs {
+		addr = addr.Unmap()
+		if !hostAllowed && !IsPublicAddr(addr) && !d.allowlist.allowsAddr(addr) {
+			lastErr = fmt.Errorf("%w: %s (%s)", ErrAddressNotAllowed, host, addr)
+			continue
+		}
+		conn, err := d.dialer.DialContext(ctx, network, net.JoinHostPort(addr.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no addresses found for %s", host)
+	}
+	return nil, lastErr
+}
@@ -0,0 +1,229 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// limiterIdleTimeout は使用されていないホストの制限を破棄するまでの時間です。
+const limiterIdleTimeout = 10 * time.Minute
+
+// HostLimits はホストごとのリクエストの制限です。
+type HostLimits struct {
+	RequestsPerSecond float64 // 1秒あたりのリクエスト数（0以下は無制限）
+	Burst             int     // 間隔を空けずに発行できるリクエスト数（1未満は1）
+	MaxConcurrent     int     // 同時に発行するリクエスト数（0以下は無制限）
+}
+
+// unlimited は制限がないかを返します。
+func (l HostLimits) unlimited() bool {
+	return l.RequestsPerSecond <= 0 && l.MaxConcurrent <= 0
+}
+
+// tighterThan は l のうち base より厳しい制限のみを返します。base 以下に緩める制限は含みません。
+func (l HostLimits) tighterThan(base HostLimits) HostLimits {
+	var tighter HostLimits
+	if l.RequestsPerSecond > 0 && (base.RequestsPerSecond <= 0 || l.RequestsPerSecond < base.RequestsPerSecond) {
+		tighter.RequestsPerSecond = l.RequestsPerSecond
+		tighter.Burst = l.Burst
+		if base.RequestsPerSecond > 0 && base.Burst < tighter.Burst {
+			tighter.Burst = base.Burst
+		}
+	}
+	if l.MaxConcurrent > 0 && (base.MaxConcurrent <= 0 || l.MaxConcurrent < base.MaxConcurrent) {
+		tighter.MaxConcurrent = l.MaxConcurrent
+	}
+	return tighter
+}
+
+// key は追加の制限を共有するリクエストを識別するキーを返します。
+func (l HostLimits) key(host string) string {
+	return fmt.Sprintf("%s|%g|%d|%d", host, l.RequestsPerSecond, l.Burst, l.MaxConcurrent)
+}
+
+// WithHostLimits はホストごとのリクエストの制限を設定します。
+// ContextWithHostLimits で個別に制限を指定したリクエストには、その制限を適用します。
+func WithHostLimits(limits HostLimits) Option {
+	return func(c *Client) {
+		c.hostLimits = limits
+	}
+}
+
+// hostLimitsKey はリクエストごとの制限を格納するコンテキストのキーです。
+type hostLimitsKey struct{}
+
+// ContextWithHostLimits は ctx で発行するリクエストに、クライアントの設定に加えて limits を適用します。
+// フィードごとの設定で取得する場合に使用します。
+//
+// ホストへのリクエスト数と同時リクエスト数は、limits によらずホストごとにクライアントの設定で数えます。
+// limits はクライアントの設定より厳しい項目のみを追加で適用し、ホストへの上限を緩めることはありません。
+func ContextWithHostLimits(ctx context.Context, limits HostLimits) context.Context {
+	return context.WithValue(ctx, hostLimitsKey{}, limits)
+}
+
+// withoutHostLimits は ContextWithHostLimits で指定した制限を適用しないコンテキストを返します。
+func withoutHostLimits(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(hostLimitsKey{}).(HostLimits); !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, hostLimitsKey{}, nil)
+}
+
+// hostLimiters はホストごとの制限の状態を管理します。
+type hostLimiters struct {
+	mu       sync.Mutex
+	limiters map[string]*hostLimiter
+	lastGC   time.Time
+}
+
+// acquire はホストへのリクエストを発行できるまで待機し、発行後に呼び出す解放関数を返します。
+//
+// limits はホスト全体の制限で、ホストごとに1つのトークンバケットと同時リクエスト数の枠を共有します。
+// override は ContextWithHostLimits で指定した制限で、limits より厳しい項目のみを追加で適用します。
+func (h *hostLimiters) acquire(ctx context.Context, host string, limits HostLimits, override *HostLimits) (func(), error) {
+	host = strings.ToLower(host)
+	var keys []string
+	var keyLimits []HostLimits
+	// 追加の制限を先に待機し、待機中にホスト全体の同時リクエスト数の枠を占有しないようにする
+	if override != nil {
+		if tighter := override.tighterThan(limits); !tighter.unlimited() {
+			keys, keyLimits = append(keys, tighter.key(host)), append(keyLimits, tighter)
+		}
+	}
+	if !limits.unlimited() {
+		keys, keyLimits = append(keys, host), append(keyLimits, limits)
+	}
+
+	acquired := make([]*hostLimiter, 0, len(keys))
+	releaseAll := func() {
+		for _, l := range acquired {
+			l.release()
+		}
+	}
+	for i, key := range keys {
+		l := h.get(key, keyLimits[i])
+		if err := l.wait(ctx); err != nil {
+			l.done()
+			releaseAll()
+			return nil, err
+		}
+		acquired = append(acquired, l)
+	}
+	var once sync.Once
+	return func() { once.Do(releaseAll) }, nil
+}
+
+// get はキーに対応する hostLimiter を返します。使用されていないものは定期的に破棄します。
+func (h *hostLimiters) get(key string, limits HostLimits) *hostLimiter {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := time.Now()
+	if h.limiters == nil {
+		h.limiters = map[string]*hostLimiter{}
+	}
+	if now.Sub(h.lastGC) > limiterIdleTimeout {
+		for key, l := range h.limiters {
+			if l.idle(now) {
+				delete(h.limiters, key)
+			}
+		}
+		h.lastGC = now
+	}
+	l, ok := h.limiters[key]
+	if !ok {
+		l = newHostLimiter(limits, now)
+		h.limiters[key] = l
+	}
+	l.mu.Lock()
+	l.users++
+	l.lastUsed = now
+	l.mu.Unlock()
+	return l
+}
+
+// hostLimiter は1つのホストに対するトークンバケットと同時リクエスト数の制限です。
+type hostLimiter struct {
+	limits HostLimits
+	slots  chan struct{} // 同時リクエスト数の制限（nil は無制限）
+
+	mu       sync.Mutex
+	tokens   float64   // 残りのトークン。負の値は待機中のリクエストの予約
+	last     time.Time // トークンを最後に補充した日時
+	users    int       // 待機中または発行中のリクエスト数
+	lastUsed time.Time
+}
+
+func newHostLimiter(limits HostLimits, now time.Time) *hostLimiter {
+	if limits.Burst < 1 {
+		limits.Burst = 1
+	}
+	l := &hostLimiter{limits: limits, tokens: float64(limits.Burst), last: now, lastUsed: now}
+	if limits.MaxConcurrent > 0 {
+		l.slots = make(chan struct{}, limits.MaxConcurrent)
+	}
+	return l
+}
+
+// wait はトークンと同時リクエスト数の空きを待ちます。
+func (l *hostLimiter) wait(ctx context.Context) error {
+	if rps := l.limits.RequestsPerSecond; rps > 0 {
+		l.mu.Lock()
+		now := time.Now()
+		l.tokens += now.Sub(l.last).Seconds() * rps
+		if max := float64(l.limits.Burst); l.tokens > max {
+			l.tokens = max
+		}
+		l.last = now
+		l.tokens--
+		delay := time.Duration(-l.tokens / rps * float64(time.Second))
+		l.mu.Unlock()
+
+		if delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				// 使用しなかった予約を返却する
+				l.mu.Lock()
+				l.tokens++
+				l.mu.Unlock()
+				return ctx.Err()
+			}
+		}
+	}
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// release は発行したリクエストの同時リクエスト数の枠を解放します。
+func (l *hostLimiter) release() {
+	if l.slots != nil {
+		<-l.slots
+	}
+	l.done()
+}
+
+// done はリクエストの終了を記録します。
+func (l *hostLimiter) done() {
+	l.mu.Lock()
+	l.users--
+	l.lastUsed = time.Now()
+	l.mu.Unlock()
+}
+
+// idle は一定時間使用されておらず、破棄してよいかを返します。
+func (l *hostLimiter) idle(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.users == 0 && now.Sub(l.lastUsed) > limiterIdleTimeout
+}
@@ -0,0 +1,257 @@
+package fetcher
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// robotsTTL は取得した robots.txt をキャッシュする期間です。
+	robotsTTL = 24 * time.Hour
+	// robotsErrorTTL は robots.txt を取得できなかった場合に、すべて拒否として扱う期間です。
+	robotsErrorTTL = time.Hour
+)
+
+// ErrDisallowedByRobots は robots.txt でリクエストが許可されていない場合に返されます。
+var ErrDisallowedByRobots = errors.New("disallowed by robots.txt")
+
+// robotsKey は robots.txt の確認を要求するコンテキストのキーです。
+type robotsKey struct{}
+
+// ContextWithRobots は ctx で発行するリクエストの前に、サイトの robots.txt で許可されているかを確認します。
+// 許可されていない場合、リクエストは ErrDisallowedByRobots となります。
+func ContextWithRobots(ctx context.Context) context.Context {
+	return context.WithValue(ctx, robotsKey{}, true)
+}
+
+// robotsRequired は ctx が robots.txt の確認を要求しているかを返します。
+func robotsRequired(ctx context.Context) bool {
+	required, _ := ctx.Value(robotsKey{}).(bool)
+	return required
+}
+
+// robotsRule は robots.txt の allow・disallow の1行です。
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+// robotsRules はこのクライアントに適用する robots.txt の規則です。
+type robotsRules struct {
+	rules []robotsRule
+}
+
+// allowed は path（クエリを含む）へのアクセスが許可されているかを返します。
+// 一致する規則のうちパターンが最も長いものを適用し、同じ長さの場合は allow を優先します（RFC 9309）。
+func (r *robotsRules) allowed(path string) bool {
+	matched := -1
+	allow := true
+	for _, rule := range r.rules {
+		if !matchRobotsPattern(rule.pattern, path) {
+			continue
+		}
+		if n := len(rule.pattern); n > matched || (n == matched && rule.allow) {
+			matched = n
+			allow = rule.allow
+		}
+	}
+	return allow
+}
+
+// disallowAll はすべてのパスを拒否する規則です。
+var disallowAll = &robotsRules{rules: []robotsRule{{allow: false, pattern: "/"}}}
+
+// parseRobots は robots.txt から product（User-Agent の製品名）に適用する規則を返します。
+// product に一致するグループがない場合は "*" のグループを使用します。
+func parseRobots(data []byte, product string) *robotsRules {
+	product = strings.ToLower(product)
+	var specific, wildcard []robotsRule
+	var matchesProduct, matchesWildcard, inAgents bool
+	foundSpecific := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			// 規則の後の user-agent は新しいグループの開始
+			if !inAgents {
+				matchesProduct, matchesWildcard = false, false
+				inAgents = true
+			}
+			agent := strings.ToLower(value)
+			if agent == "*" {
+				matchesWildcard = true
+			} else if agent == product {
+				matchesProduct = true
+				foundSpecific = true
+			}
+		case "allow", "disallow":
+			inAgents = false
+			if value == "" {
+				continue
+			}
+			rule := robotsRule{allow: key == "allow", pattern: value}
+			if matchesProduct {
+				specific = append(specific, rule)
+			}
+			if matchesWildcard {
+				wildcard = append(wildcard, rule)
+			}
+		default:
+			inAgents = false
+		}
+	}
+	if foundSpecific {
+		return &robotsRules{rules: specific}
+	}
+	return &robotsRules{rules: wildcard}
+}
+
+// matchRobotsPattern は robots.txt のパターン（"*" は任意の文字列、末尾の "$" はパスの終端）がパスに一致するかを返します。
+func matchRobotsPattern(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	if anchored {
+		pattern = strings.TrimSuffix(pattern, "$")
+	}
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	pos := len(parts[0])
+	for i, part := range parts[1:] {
+		if i == len(parts)-2 && anchored {
+			return strings.HasSuffix(path[pos:], part)
+		}
+		idx := strings.Index(path[pos:], part)
+		if idx < 0 {
+			return false
+		}
+		pos += idx + len(part)
+	}
+	return !anchored || pos == len(path)
+}
+
+// robotsEntry はキャッシュした1つのオリジンの robots.txt です。
+type robotsEntry struct {
+	ready   chan struct{} // 取得が完了すると閉じる
+	rules   *robotsRules
+	expires time.Time
+}
+
+// robotsCache はオリジンごとの robots.txt の規則をキャッシュします。
+type robotsCache struct {
+	mu      sync.Mutex
+	entries map[string]*robotsEntry
+}
+
+// checkRobots は URL へのリクエストがサイトの robots.txt で許可されているかを確認します。
+func (c *Client) checkRobots(ctx context.Context, u *url.URL) error {
+	origin := u.Scheme + "://" + u.Host
+	rules, err := c.robots.get(ctx, origin, c.fetchRobots)
+	if err != nil {
+		return err
+	}
+	if !rules.allowed(u.EscapedPath() + queryPart(u)) {
+		return fmt.Errorf("%w: %s", ErrDisallowedByRobots, u)
+	}
+	return nil
+}
+
+// fetchRobots はオリジンの robots.txt を取得して解析します。
+// 存在しない場合（4xx）はすべて許可し、サーバーエラーや接続できない場合はすべて拒否します（RFC 9309）。
+// 規則はオリジンごとに全フィードで共有するため、フィードの認証情報・ヘッダー・プロキシ・取得の制限は使用しません。
+func (c *Client) fetchRobots(ctx context.Context, origin string) (*robotsRules, time.Duration) {
+	// robots.txt 自体の取得では robots.txt を確認しない
+	ctx = context.WithValue(withoutHostLimits(withoutProxy(withoutCredentials(ctx))), robotsKey{}, false)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, origin+"/robots.txt", nil)
+	if err != nil {
+		return disallowAll, robotsErrorTTL
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode >= 400 && statusErr.StatusCode < 500 {
+			return &robotsRules{}, robotsTTL
+		}
+		return disallowAll, robotsErrorTTL
+	}
+	return parseRobots(resp.Body, productName(c.userAgent)), robotsTTL
+}
+
+// get はオリジンの規則をキャッシュから返し、ない場合や期限切れの場合は fetch で取得します。
+// 同じオリジンを同時に取得しないよう、取得中の場合は完了を待ちます。
+func (r *robotsCache) get(ctx context.Context, origin string, fetch func(context.Context, string) (*robotsRules, time.Duration)) (*robotsRules, error) {
+	r.mu.Lock()
+	if r.entries == nil {
+		r.entries = map[string]*robotsEntry{}
+	}
+	entry, ok := r.entries[origin]
+	if ok {
+		select {
+		case <-entry.ready:
+			if time.Now().After(entry.expires) {
+				ok = false
+			}
+		default:
+		}
+	}
+	if !ok {
+		entry = &robotsEntry{ready: make(chan struct{})}
+		r.entries[origin] = entry
+		r.mu.Unlock()
+
+		rules, ttl := fetch(ctx, origin)
+		if ctx.Err() != nil {
+			// キャンセルによる失敗はキャッシュしない
+			ttl = 0
+		}
+		r.mu.Lock()
+		entry.rules = rules
+		entry.expires = time.Now().Add(ttl)
+		close(entry.ready)
+		r.mu.Unlock()
+		return rules, ctx.Err()
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-entry.ready:
+		return entry.rules, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// queryPart は URL のクエリを "?" 付きで返します。クエリがない場合は空文字列です。
+func queryPart(u *url.URL) string {
+	if u.RawQuery == "" {
+		return ""
+	}
+	return "?" + u.RawQuery
+}
+
+// productName は User-Agent の先頭の製品名（例: "FeedApp/1.0 (+https://...)" の "FeedApp"）を返します。
+func productName(userAgent string) string {
+	name, _, _ := strings.Cut(userAgent, "/")
+	name, _, _ = strings.Cut(name, " ")
+	return name
+}
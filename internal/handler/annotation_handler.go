@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"feedapp/internal/model"
+	"feedapp/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AnnotationHandler は記事の注釈関連のHTTPリクエストを処理します。
+//
+// サポートするエンドポイント:
+//   - GET /annotations - すべての記事の注釈一覧取得
+//   - GET /articles/{id}/annotations - 記事の注釈一覧取得
+//   - GET /articles/{id}/annotations/export - 記事の注釈の Markdown 出力
+//   - GET /articles/{id}/annotations/{annotation_id} - 特定注釈取得
+//   - POST /articles/{id}/annotations - 新規注釈作成
+//   - PUT /articles/{id}/annotations/{annotation_id} - 注釈更新
+//   - DELETE /articles/{id}/annotations/{annotation_id} - 注釈削除
+type AnnotationHandler struct {
+	annotationService service.AnnotationService
+}
+
+// NewAnnotationHandler は新しい AnnotationHandler インスタンスを作成します。
+func NewAnnotationHandler(s service.AnnotationService) *AnnotationHandler {
+	return &AnnotationHandler{
+		annotationService: s,
+	}
+}
+
+// GetAllAnnotations はログイン中のユーザーがすべての記事に付けた注釈を取得します。
+//
+//	@Summary		注釈一覧取得
+//	@Description	ログイン中のユーザーがすべての記事に付けた注釈を、記事のタイトル・URLとともに新しい順に取得します
+//	@Tags			annotations
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		model.Annotation	"注釈一覧"
+//	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
+//	@Router			/annotations [get]
+func (h *AnnotationHandler) GetAllAnnotations(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	annotations, err := h.annotationService.GetAllAnnotations(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get annotations"})
+		return
+	}
+	c.JSON(http.StatusOK, annotations)
+}
+
+// GetArticleAnnotations は記事に付けた注釈を取得します。
+//
+//	@Summary		記事の注釈一覧取得
+//	@Description	指定されたIDの記事に付けた注釈を作成順に取得します
+//	@Tags			annotations
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		string				true	"記事ID (UUID)"
+//	@Success		200	{array}		model.Annotation	"注釈一覧"
+//	@Failure		404	{object}	map[string]string	"記事が見つかりません"
+//	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
+//	@Router			/articles/{id}/annotations [get]
+func (h *AnnotationHandler) GetArticleAnnotations(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	annotations, err := h.annotationService.GetArticleAnnotations(user.ID, c.Param("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrArticleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get annotations"})
+		return
+	}
+	c.JSON(http.StatusOK, annotations)
+}
+
+// ExportArticleAnnotations は記事に付けた注釈を Markdown で出力します。
+//
+//	@Summary		記事の注釈の Markdown 出力
+//	@Description	指定されたIDの記事のタイトルとURL、注釈ごとに引用した文字列（引用ブロック）とメモを Markdown で出力します
+//	@Tags			annotations
+//	@Produce		text/markdown
+//	@Security		BearerAuth
+//	@Param			id	path		string				true	"記事ID (UUID)"
+//	@Success		200	{string}	string				"注釈の Markdown"
+//	@Failure		404	{object}	map[string]string	"記事が見つかりません"
+//	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
+//	@Router			/articles/{id}/annotations/export [get]
+func (h *AnnotationHandler) ExportArticleAnnotations(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	markdown, err := h.annotationService.ExportArticleAnnotations(user.ID, c.Param("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrArticleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export annotations"})
+		return
+	}
+	c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(markdown))
+}
+
+// GetAnnotation は指定されたIDの注釈を取得します。
+//
+//	@Summary		注釈詳細取得
+//	@Description	指定された記事の、指定されたIDの注釈を取得します
+//	@Tags			annotations
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id				path		string				true	"記事ID (UUID)"
+//	@Param			annotation_id	path		string				true	"注釈ID (UUID)"
+//	@Success		200				{object}	model.Annotation	"注釈詳細"
+//	@Failure		404				{object}	map[string]string	"注釈が見つかりません"
+//	@Failure		500				{object}	map[string]string	"サーバー内部エラー"
+//	@Router			/articles/{id}/annotations/{annotation_id} [get]
+func (h *AnnotationHandler) GetAnnotation(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	annotation, err := h.annotationService.GetAnnotation(user.ID, c.Param("id"), c.Param("annotation_id"))
+	if err != nil {
+		if errors.Is(err, service.ErrAnnotationNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Annotation not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get annotation"})
+		return
+	}
+	c.JSON(http.StatusOK, annotation)
+}
+
+// CreateAnnotation は記事に新しい注釈を付けます。
+//
+//	@Summary		注釈作成
+//	@Description	指定されたIDの記事に注釈を付けます。selector は W3C Web Annotation の TextQuoteSelector 形式（exact・prefix・suffix）です
+//	@Tags			annotations
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id			path		string					true	"記事ID (UUID)"
+//	@Param			annotation	body		model.Annotation		true	"注釈情報"
+//	@Success		201			{object}	model.Annotation		"作成された注釈"
+//	@Failure		400			{object}	map[string]interface{}	"リクエストボディの形式が不正"
+//	@Failure		404			{object}	map[string]string		"記事が見つかりません"
+//	@Failure		500			{object}	map[string]string		"サーバー内部エラー"
+//	@Router			/articles/{id}/annotations [post]
+func (h *AnnotationHandler) CreateAnnotation(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var annotation model.Annotation
+	if err := c.ShouldBindJSON(&annotation); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	created, err := h.annotationService.CreateAnnotation(user.ID, c.Param("id"), annotation)
+	if err != nil {
+		if errors.Is(err, service.ErrArticleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create annotation"})
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// UpdateAnnotation は指定されたIDの注釈を更新します。
+//
+//	@Summary		注釈更新
+//	@Description	指定されたIDの注釈の箇所・メモ・色を更新します
+//	@Tags			annotations
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id				path		string					true	"記事ID (UUID)"
+//	@Param			annotation_id	path		string					true	"注釈ID (UUID)"
+//	@Param			annotation		body		model.Annotation		true	"更新する注釈情報"
+//	@Success		200				{object}	model.Annotation		"更新された注釈"
+//	@Failure		400				{object}	map[string]interface{}	"リクエストボディの形式が不正"
+//	@Failure		404				{object}	map[string]string		"注釈が見つかりません"
+//	@Failure		500				{object}	map[string]string		"サーバー内部エラー"
+//	@Router			/articles/{id}/annotations/{annotation_id} [put]
+func (h *AnnotationHandler) UpdateAnnotation(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var annotation model.Annotation
+	if err := c.ShouldBindJSON(&annotation); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	updated, err := h.annotationService.UpdateAnnotation(user.ID, c.Param("id"), c.Param("annotation_id"), annotation)
+	if err != nil {
+		if errors.Is(err, service.ErrAnnotationNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Annotation not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update annotation"})
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteAnnotation は指定されたIDの注釈を削除します。
+//
+//	@Summary		注釈削除
+//	@Description	指定されたIDの注釈を削除します
+//	@Tags			annotations
+//	@Security		BearerAuth
+//	@Param			id				path	string	true	"記事ID (UUID)"
+//	@Param			annotation_id	path	string	true	"注釈ID (UUID)"
+//	@Success		204				"削除成功"
+//	@Failure		404				{object}	map[string]string	"注釈が見つかりません"
+//	@Failure		500				{object}	map[string]string	"サーバー内部エラー"
+//	@Router			/articles/{id}/annotations/{annotation_id} [delete]
+func (h *AnnotationHandler) DeleteAnnotation(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	if err := h.annotationService.DeleteAnnotation(user.ID, c.Param("id"), c.Param("annotation_id")); err != nil {
+		if errors.Is(err, service.ErrAnnotationNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Annotation not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete annotation"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
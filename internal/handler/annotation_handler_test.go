@@ -0,0 +1,290 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"feedapp/internal/model"
+	"feedapp/internal/service"
+)
+
+// MockAnnotationService は service.AnnotationService のモック実装です。
+type MockAnnotationService struct {
+	mock.Mock
+}
+
+func (m *MockAnnotationService) GetAllAnnotations(userID string) ([]model.Annotation, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]model.Annotation), args.Error(1)
+}
+
+func (m *MockAnnotationService) GetArticleAnnotations(userID, articleID string) ([]model.Annotation, error) {
+	args := m.Called(userID, articleID)
+	return args.Get(0).([]model.Annotation), args.Error(1)
+}
+
+func (m *MockAnnotationService) GetAnnotation(userID, articleID, id string) (model.Annotation, error) {
+	args := m.Called(userID, articleID, id)
+	return args.Get(0).(model.Annotation), args.Error(1)
+}
+
+func (m *MockAnnotationService) CreateAnnotation(userID, articleID string, annotation model.Annotation) (model.Annotation, error) {
+	args := m.Called(userID, articleID, annotation)
+	return args.Get(0).(model.Annotation), args.Error(1)
+}
+
+func (m *MockAnnotationService) UpdateAnnotation(userID, articleID, id string, annotation model.Annotation) (model.Annotation, error) {
+	args := m.Called(userID, articleID, id, annotation)
+	return args.Get(0).(model.Annotation), args.Error(1)
+}
+
+func (m *MockAnnotationService) DeleteAnnotation(userID, articleID, id string) error {
+	args := m.Called(userID, articleID, id)
+	return args.Error(0)
+}
+
+func (m *MockAnnotationService) ExportArticleAnnotations(userID, articleID string) (string, error) {
+	args := m.Called(userID, articleID)
+	return args.String(0), args.Error(1)
+}
+
+// newAnnotationContext はログイン済みのテスト用コンテキストを作成します。body が空の場合はリクエストボディを設定しません。
+func newAnnotationContext(method, articleID, annotationID, body string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set(userContextKey, testUser)
+	c.Params = gin.Params{{Key: "id", Value: articleID}}
+	if annotationID != "" {
+		c.Params = append(c.Params, gin.Param{Key: "annotation_id", Value: annotationID})
+	}
+	c.Request = httptest.NewRequest(method, "/", bytes.NewBufferString(body))
+	if body != "" {
+		c.Request.Header.Set("Content-Type", "application/json")
+	}
+	return c, w
+}
+
+// testAnnotation はテストで使用する注釈です。
+var testAnnotation = model.Annotation{
+	Selector: model.TextQuoteSelector{Type: model.TextQuoteSelectorType, Exact: "goroutines are cheap", Prefix: "In Go, ", Suffix: " to create."},
+	Body:     "Check the scheduler design doc",
+	Color:    model.AnnotationColorGreen,
+}
+
+func TestAnnotationHandler_GetAllAnnotations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockAnnotationService)
+	handler := NewAnnotationHandler(mockService)
+
+	// 正常系: すべての記事の注釈を返す
+	t.Run("should return all annotations", func(t *testing.T) {
+		annotation := testAnnotation
+		annotation.ID = "n1"
+		annotation.ArticleID = "1"
+		annotation.ArticleTitle = "Go scheduler"
+		annotation.ArticleURL = "http://example.com/a1"
+		expected := []model.Annotation{annotation}
+		mockService.On("GetAllAnnotations", testUser.ID).Return(expected, nil).Once()
+
+		c, w := newAnnotationContext(http.MethodGet, "", "", "")
+		handler.GetAllAnnotations(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var actual []model.Annotation
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &actual))
+		assert.Equal(t, expected, actual)
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: サービスエラー
+	t.Run("should return 500 if service error", func(t *testing.T) {
+		mockService.On("GetAllAnnotations", testUser.ID).Return([]model.Annotation{}, assert.AnError).Once()
+
+		c, w := newAnnotationContext(http.MethodGet, "", "", "")
+		handler.GetAllAnnotations(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "Failed to get annotations")
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestAnnotationHandler_GetArticleAnnotations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockAnnotationService)
+	handler := NewAnnotationHandler(mockService)
+
+	// 異常系: 購読していないフィードの記事または存在しない記事
+	t.Run("should return 404 if article not found", func(t *testing.T) {
+		mockService.On("GetArticleAnnotations", testUser.ID, "other").Return([]model.Annotation(nil), service.ErrArticleNotFound).Once()
+
+		c, w := newAnnotationContext(http.MethodGet, "other", "", "")
+		handler.GetArticleAnnotations(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Article not found")
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestAnnotationHandler_ExportArticleAnnotations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockAnnotationService)
+	handler := NewAnnotationHandler(mockService)
+
+	// 正常系: Markdown を text/markdown で返す
+	t.Run("should return markdown", func(t *testing.T) {
+		markdown := "# Go scheduler\n\n<http://example.com/a1>\n\n> goroutines are cheap\n\nCheck the scheduler design doc\n"
+		mockService.On("ExportArticleAnnotations", testUser.ID, "1").Return(markdown, nil).Once()
+
+		c, w := newAnnotationContext(http.MethodGet, "1", "", "")
+		handler.ExportArticleAnnotations(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/markdown; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, markdown, w.Body.String())
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 記事が見つからない場合
+	t.Run("should return 404 if article not found", func(t *testing.T) {
+		mockService.On("ExportArticleAnnotations", testUser.ID, "other").Return("", service.ErrArticleNotFound).Once()
+
+		c, w := newAnnotationContext(http.MethodGet, "other", "", "")
+		handler.ExportArticleAnnotations(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Article not found")
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestAnnotationHandler_CreateAnnotation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// 異常系: 引用した文字列の不足・色の不正はサービスを呼び出さずに 400 を返す
+	t.Run("should return 400 if invalid request body", func(t *testing.T) {
+		mockService := new(MockAnnotationService)
+		handler := NewAnnotationHandler(mockService)
+		for _, body := range []string{
+			`{"body":"note only"}`,
+			`{"selector":{"exact":"quote"},"color":"orange"}`,
+		} {
+			c, w := newAnnotationContext(http.MethodPost, "1", "", body)
+			handler.CreateAnnotation(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, body)
+			assert.Contains(t, w.Body.String(), "Invalid input")
+		}
+		mockService.AssertNotCalled(t, "CreateAnnotation", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	mockService := new(MockAnnotationService)
+	handler := NewAnnotationHandler(mockService)
+
+	// 正常系: 作成した注釈を返す
+	t.Run("should create annotation successfully", func(t *testing.T) {
+		created := testAnnotation
+		created.ID = "n1"
+		created.ArticleID = "1"
+		mockService.On("CreateAnnotation", testUser.ID, "1", testAnnotation).Return(created, nil).Once()
+
+		body, _ := json.Marshal(testAnnotation)
+		c, w := newAnnotationContext(http.MethodPost, "1", "", string(body))
+		handler.CreateAnnotation(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var actual model.Annotation
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &actual))
+		assert.Equal(t, created, actual)
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 記事が見つからない場合
+	t.Run("should return 404 if article not found", func(t *testing.T) {
+		mockService.On("CreateAnnotation", testUser.ID, "other", testAnnotation).Return(model.Annotation{}, service.ErrArticleNotFound).Once()
+
+		body, _ := json.Marshal(testAnnotation)
+		c, w := newAnnotationContext(http.MethodPost, "other", "", string(body))
+		handler.CreateAnnotation(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Article not found")
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestAnnotationHandler_UpdateAnnotation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockAnnotationService)
+	handler := NewAnnotationHandler(mockService)
+
+	// 正常系: 更新した注釈を返す
+	t.Run("should update annotation successfully", func(t *testing.T) {
+		input := testAnnotation
+		input.Color = model.AnnotationColorPink
+		updated := input
+		updated.ID = "n1"
+		updated.ArticleID = "1"
+		mockService.On("UpdateAnnotation", testUser.ID, "1", "n1", input).Return(updated, nil).Once()
+
+		body, _ := json.Marshal(input)
+		c, w := newAnnotationContext(http.MethodPut, "1", "n1", string(body))
+		handler.UpdateAnnotation(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var actual model.Annotation
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &actual))
+		assert.Equal(t, updated, actual)
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 他のユーザーの注釈または存在しない注釈
+	t.Run("should return 404 if annotation not found", func(t *testing.T) {
+		mockService.On("UpdateAnnotation", testUser.ID, "1", "other", testAnnotation).Return(model.Annotation{}, service.ErrAnnotationNotFound).Once()
+
+		body, _ := json.Marshal(testAnnotation)
+		c, w := newAnnotationContext(http.MethodPut, "1", "other", string(body))
+		handler.UpdateAnnotation(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Annotation not found")
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestAnnotationHandler_DeleteAnnotation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockAnnotationService)
+	handler := NewAnnotationHandler(mockService)
+
+	// 正常系: 削除成功
+	t.Run("should delete annotation successfully", func(t *testing.T) {
+		mockService.On("DeleteAnnotation", testUser.ID, "1", "n1").Return(nil).Once()
+
+		c, _ := newAnnotationContext(http.MethodDelete, "1", "n1", "")
+		handler.DeleteAnnotation(c)
+
+		assert.Equal(t, http.StatusNoContent, c.Writer.Status())
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 注釈が見つからない場合
+	t.Run("should return 404 if annotation not found", func(t *testing.T) {
+		mockService.On("DeleteAnnotation", testUser.ID, "1", "nonexistent").Return(service.ErrAnnotationNotFound).Once()
+
+		c, w := newAnnotationContext(http.MethodDelete, "1", "nonexistent", "")
+		handler.DeleteAnnotation(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Annotation not found")
+		mockService.AssertExpectations(t)
+	})
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	
+	"feedapp/internal/model"
 	"feedapp/internal/service"
 )
 
@@ -28,11 +29,31 @@ func NewArticleHandler(s service.ArticleService) *ArticleHandler {
 //	@Tags			articles
 //	@Accept			json
 //	@Produce		json
+//	@Param			has_enclosure	query		string	false	"添付メディアの種別で絞り込み"	Enums(any, audio, video, image)
+//	@Param			tag				query		string	false	"タグで絞り込み"
 //	@Success		200	{array}		model.Article	"記事一覧"
+//	@Failure		400	{object}	map[string]string	"クエリパラメータが不正"
 //	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
 //	@Router			/articles [get]
 func (h *ArticleHandler) GetAllArticles(c *gin.Context) {
-	articles, err := h.articleService.GetAllArticles()
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var filter model.ArticleFilter
+	switch hasEnclosure := c.Query("has_enclosure"); hasEnclosure {
+	case "":
+	case "true":
+		filter.EnclosureType = model.EnclosureTypeAny
+	case model.EnclosureTypeAny, model.EnclosureTypeAudio, model.EnclosureTypeVideo, model.EnclosureTypeImage:
+		filter.EnclosureType = hasEnclosure
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid has_enclosure parameter"})
+		return
+	}
+	filter.Tag = c.Query("tag")
+
+	articles, err := h.articleService.GetAllArticles(user.ID, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get articles"})
 		return
@@ -53,8 +74,12 @@ func (h *ArticleHandler) GetAllArticles(c *gin.Context) {
 //	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
 //	@Router			/articles/{id} [get]
 func (h *ArticleHandler) GetArticleByID(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
 	id := c.Param("id")
-	article, err := h.articleService.GetArticleByID(id)
+	article, err := h.articleService.GetArticleByID(user.ID, id)
 	if err != nil {
 		if errors.Is(err, service.ErrArticleNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
@@ -66,6 +91,66 @@ func (h *ArticleHandler) GetArticleByID(c *gin.Context) {
 	c.JSON(http.StatusOK, article)
 }
 
+// GetArticlesByFeed は指定されたフィードの記事を取得します。
+//
+//	@Summary		フィード内記事一覧取得
+//	@Description	指定されたフィードが配信した記事の一覧を取得します。複数のフィードから配信された記事はそれぞれのフィードに含まれます
+//	@Tags			articles
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"フィードID (UUID)"
+//	@Success		200	{array}		model.Article	"記事一覧"
+//	@Failure		404	{object}	map[string]string	"フィードが見つかりません"
+//	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
+//	@Router			/feeds/{id}/articles [get]
+func (h *ArticleHandler) GetArticlesByFeed(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	id := c.Param("id")
+	articles, err := h.articleService.GetArticlesByFeed(user.ID, id)
+	if err != nil {
+		if errors.Is(err, service.ErrFeedNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Feed not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get articles"})
+		return
+	}
+	c.JSON(http.StatusOK, articles)
+}
+
+// GetArticlesByFolder は指定されたフォルダ内の記事を取得します。
+//
+//	@Summary		フォルダ内記事一覧取得
+//	@Description	指定されたフォルダに属するフィードが配信した記事の一覧を取得します
+//	@Tags			articles
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"フォルダID (UUID)"
+//	@Success		200	{array}		model.Article	"記事一覧"
+//	@Failure		404	{object}	map[string]string	"フォルダが見つかりません"
+//	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
+//	@Router			/folders/{id}/articles [get]
+func (h *ArticleHandler) GetArticlesByFolder(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	id := c.Param("id")
+	articles, err := h.articleService.GetArticlesByFolder(user.ID, id)
+	if err != nil {
+		if errors.Is(err, service.ErrFolderNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Folder not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get articles"})
+		return
+	}
+	c.JSON(http.StatusOK, articles)
+}
+
 // ArticleStatusRequest は記事の状態更新リクエストを表します。
 type ArticleStatusRequest struct {
 	IsRead  bool `json:"is_read" example:"true"`
@@ -87,6 +172,10 @@ type ArticleStatusRequest struct {
 //	@Failure		500	{object}	map[string]string			"サーバー内部エラー"
 //	@Router			/articles/{id}/status [put]
 func (h *ArticleHandler) UpdateArticleStatus(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
 	id := c.Param("id")
 	var req ArticleStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -94,7 +183,7 @@ func (h *ArticleHandler) UpdateArticleStatus(c *gin.Context) {
 		return
 	}
 
-	updatedArticle, err := h.articleService.UpdateArticleStatus(id, req.IsRead, req.IsLater)
+	updatedArticle, err := h.articleService.UpdateArticleStatus(user.ID, id, req.IsRead, req.IsLater)
 	if err != nil {
 		if errors.Is(err, service.ErrArticleNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
@@ -106,6 +195,90 @@ func (h *ArticleHandler) UpdateArticleStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, updatedArticle)
 }
 
+// ArticleMediaRequest は記事の添付メディア再生状態の更新リクエストを表します。
+// 省略されたフィールドは現在の値を維持します。
+type ArticleMediaRequest struct {
+	PlaybackPosition *int  `json:"playback_position_seconds" binding:"omitempty,min=0" example:"754"`
+	Played           *bool `json:"played" example:"false"`
+	Downloaded       *bool `json:"downloaded" example:"true"`
+}
+
+// UpdateArticleMedia は記事の添付メディア再生状態を更新します。
+//
+//	@Summary		メディア再生状態更新
+//	@Description	指定されたIDの記事の再生位置・再生済み・ダウンロード済み状態を更新します。省略したフィールドは変更されません
+//	@Tags			articles
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string				true	"記事ID (UUID)"
+//	@Param			media	body		ArticleMediaRequest	true	"メディア再生状態"
+//	@Success		200	{object}	model.Article				"更新された記事"
+//	@Failure		400	{object}	map[string]interface{}		"リクエストボディの形式が不正"
+//	@Failure		404	{object}	map[string]string			"記事が見つかりません"
+//	@Failure		500	{object}	map[string]string			"サーバー内部エラー"
+//	@Router			/articles/{id}/media [put]
+func (h *ArticleHandler) UpdateArticleMedia(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	id := c.Param("id")
+	var req ArticleMediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	updatedArticle, err := h.articleService.UpdateArticleMedia(user.ID, id, service.MediaStateUpdate{
+		PlaybackPosition: req.PlaybackPosition,
+		Played:           req.Played,
+		Downloaded:       req.Downloaded,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrArticleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update article media state"})
+		return
+	}
+	c.JSON(http.StatusOK, updatedArticle)
+}
+
+// ExtractArticleContent は記事ページから本文を抽出します。
+//
+//	@Summary		記事本文抽出
+//	@Description	指定されたIDの記事のURLを取得し、本文を抽出して記事の本文を置き換えます。フィードの本文は要約が空の場合のみ要約として残ります
+//	@Tags			articles
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"記事ID (UUID)"
+//	@Success		200	{object}	model.Article		"本文を抽出した記事"
+//	@Failure		404	{object}	map[string]string	"記事が見つかりません"
+//	@Failure		502	{object}	map[string]string	"記事ページの取得または本文の抽出に失敗"
+//	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
+//	@Router			/articles/{id}/extract [post]
+func (h *ArticleHandler) ExtractArticleContent(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	id := c.Param("id")
+	article, err := h.articleService.ExtractArticleContent(c.Request.Context(), user.ID, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrArticleNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		case errors.Is(err, service.ErrExtractionFailed):
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to extract article content"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update article content"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, article)
+}
+
 // GetLaterArticles は「後で見る」に設定された記事を取得します。
 //
 //	@Summary		後で読む記事一覧取得
@@ -117,7 +290,11 @@ func (h *ArticleHandler) UpdateArticleStatus(c *gin.Context) {
 //	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
 //	@Router			/articles/later [get]
 func (h *ArticleHandler) GetLaterArticles(c *gin.Context) {
-	articles, err := h.articleService.GetLaterArticles()
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	articles, err := h.articleService.GetLaterArticles(user.ID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get later articles"})
 		return
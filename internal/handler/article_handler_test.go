@@ -2,7 +2,9 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -21,26 +23,61 @@ type MockArticleService struct {
 	mock.Mock
 }
 
-func (m *MockArticleService) GetAllArticles() ([]model.Article, error) {
-	args := m.Called()
+func (m *MockArticleService) GetAllArticles(userID string, filter model.ArticleFilter) ([]model.Article, error) {
+	args := m.Called(userID, filter)
 	return args.Get(0).([]model.Article), args.Error(1)
 }
 
-func (m *MockArticleService) GetArticleByID(id string) (model.Article, error) {
-	args := m.Called(id)
+func (m *MockArticleService) GetArticleByID(userID, id string) (model.Article, error) {
+	args := m.Called(userID, id)
 	return args.Get(0).(model.Article), args.Error(1)
 }
 
-func (m *MockArticleService) UpdateArticleStatus(id string, isRead, isLater bool) (model.Article, error) {
-	args := m.Called(id, isRead, isLater)
+func (m *MockArticleService) GetArticleItemIDs(userID string, filter model.ArticleFilter) ([]int64, error) {
+	args := m.Called(userID, filter)
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func (m *MockArticleService) GetArticlesByFeed(userID, feedID string) ([]model.Article, error) {
+	args := m.Called(userID, feedID)
+	return args.Get(0).([]model.Article), args.Error(1)
+}
+
+func (m *MockArticleService) GetArticlesByFolder(userID, folderID string) ([]model.Article, error) {
+	args := m.Called(userID, folderID)
+	return args.Get(0).([]model.Article), args.Error(1)
+}
+
+func (m *MockArticleService) UpdateArticleStatus(userID, id string, isRead, isLater bool) (model.Article, error) {
+	args := m.Called(userID, id, isRead, isLater)
+	return args.Get(0).(model.Article), args.Error(1)
+}
+
+func (m *MockArticleService) UpdateArticleMedia(userID, id string, update service.MediaStateUpdate) (model.Article, error) {
+	args := m.Called(userID, id, update)
 	return args.Get(0).(model.Article), args.Error(1)
 }
 
-func (m *MockArticleService) GetLaterArticles() ([]model.Article, error) {
-	args := m.Called()
+func (m *MockArticleService) UpdateArticleStates(userID string, ids []string, update service.ArticleStateUpdate) error {
+	args := m.Called(userID, ids, update)
+	return args.Error(0)
+}
+
+func (m *MockArticleService) MarkArticlesRead(userID string, filter model.ArticleFilter) (int64, error) {
+	args := m.Called(userID, filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockArticleService) GetLaterArticles(userID string) ([]model.Article, error) {
+	args := m.Called(userID)
 	return args.Get(0).([]model.Article), args.Error(1)
 }
 
+func (m *MockArticleService) ExtractArticleContent(ctx context.Context, userID, id string) (model.Article, error) {
+	args := m.Called(ctx, userID, id)
+	return args.Get(0).(model.Article), args.Error(1)
+}
+
 func TestArticleHandler_GetAllArticles(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockService := new(MockArticleService)
@@ -52,10 +89,12 @@ func TestArticleHandler_GetAllArticles(t *testing.T) {
 			{ID: "1", Title: "Article 1", URL: "http://example.com/a1", IsRead: false, IsLater: false},
 			{ID: "2", Title: "Article 2", URL: "http://example.com/a2", IsRead: true, IsLater: false},
 		}
-		mockService.On("GetAllArticles").Return(expectedArticles, nil).Once()
+		mockService.On("GetAllArticles", testUser.ID, model.ArticleFilter{}).Return(expectedArticles, nil).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
 		handler.GetAllArticles(c)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -68,10 +107,12 @@ func TestArticleHandler_GetAllArticles(t *testing.T) {
 
 	// 正常系: 記事が0件の場合
 	t.Run("should return empty array if no articles", func(t *testing.T) {
-		mockService.On("GetAllArticles").Return([]model.Article{}, nil).Once()
+		mockService.On("GetAllArticles", testUser.ID, model.ArticleFilter{}).Return([]model.Article{}, nil).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
 		handler.GetAllArticles(c)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -81,16 +122,72 @@ func TestArticleHandler_GetAllArticles(t *testing.T) {
 
 	// 異常系: サービスエラー
 	t.Run("should return 500 if service error", func(t *testing.T) {
-		mockService.On("GetAllArticles").Return([]model.Article{}, assert.AnError).Once()
+		mockService.On("GetAllArticles", testUser.ID, model.ArticleFilter{}).Return([]model.Article{}, assert.AnError).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
 		handler.GetAllArticles(c)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 		assert.Contains(t, w.Body.String(), "Failed to get articles")
 		mockService.AssertExpectations(t)
 	})
+
+	// 正常系: 添付メディアの種別で絞り込む場合
+	t.Run("should filter articles by enclosure type", func(t *testing.T) {
+		expectedArticles := []model.Article{
+			{ID: "1", Title: "Episode 1", URL: "http://example.com/ep1", Enclosures: []model.Enclosure{{URL: "http://example.com/ep1.mp3", MimeType: "audio/mpeg"}}},
+		}
+		mockService.On("GetAllArticles", testUser.ID, model.ArticleFilter{EnclosureType: model.EnclosureTypeAudio}).Return(expectedArticles, nil).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Request = httptest.NewRequest(http.MethodGet, "/?has_enclosure=audio", nil)
+		handler.GetAllArticles(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var actualArticles []model.Article
+		err := json.Unmarshal(w.Body.Bytes(), &actualArticles)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedArticles, actualArticles)
+		mockService.AssertExpectations(t)
+	})
+
+	// 正常系: タグで絞り込む場合
+	t.Run("should filter articles by tag", func(t *testing.T) {
+		expectedArticles := []model.Article{
+			{ID: "1", Title: "Article 1", URL: "http://example.com/a1", Tags: []string{"research", "to-share"}},
+		}
+		mockService.On("GetAllArticles", testUser.ID, model.ArticleFilter{Tag: "to-share"}).Return(expectedArticles, nil).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Request = httptest.NewRequest(http.MethodGet, "/?tag=to-share", nil)
+		handler.GetAllArticles(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var actualArticles []model.Article
+		err := json.Unmarshal(w.Body.Bytes(), &actualArticles)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedArticles, actualArticles)
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 不正な添付メディア種別
+	t.Run("should return 400 if has_enclosure is invalid", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Request = httptest.NewRequest(http.MethodGet, "/?has_enclosure=text", nil)
+		handler.GetAllArticles(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid has_enclosure parameter")
+	})
 }
 
 func TestArticleHandler_GetArticleByID(t *testing.T) {
@@ -101,10 +198,11 @@ func TestArticleHandler_GetArticleByID(t *testing.T) {
 	// 正常系: 記事が見つかる場合
 	t.Run("should return article by ID", func(t *testing.T) {
 		expectedArticle := model.Article{ID: "1", Title: "Article 1", URL: "http://example.com/a1", IsRead: false, IsLater: false}
-		mockService.On("GetArticleByID", "1").Return(expectedArticle, nil).Once()
+		mockService.On("GetArticleByID", testUser.ID, "1").Return(expectedArticle, nil).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "1"}}
 		handler.GetArticleByID(c)
 
@@ -118,10 +216,11 @@ func TestArticleHandler_GetArticleByID(t *testing.T) {
 
 	// 異常系: 記事が見つからない場合
 	t.Run("should return 404 if article not found", func(t *testing.T) {
-		mockService.On("GetArticleByID", "nonexistent").Return(model.Article{}, service.ErrArticleNotFound).Once()
+		mockService.On("GetArticleByID", testUser.ID, "nonexistent").Return(model.Article{}, service.ErrArticleNotFound).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "nonexistent"}}
 		handler.GetArticleByID(c)
 
@@ -132,10 +231,11 @@ func TestArticleHandler_GetArticleByID(t *testing.T) {
 
 	// 異常系: サービスエラー
 	t.Run("should return 500 if service error", func(t *testing.T) {
-		mockService.On("GetArticleByID", "errorID").Return(model.Article{}, assert.AnError).Once()
+		mockService.On("GetArticleByID", testUser.ID, "errorID").Return(model.Article{}, assert.AnError).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "errorID"}}
 		handler.GetArticleByID(c)
 
@@ -145,6 +245,121 @@ func TestArticleHandler_GetArticleByID(t *testing.T) {
 	})
 }
 
+func TestArticleHandler_GetArticlesByFeed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService)
+
+	// 正常系: フィードの記事が取得できる場合
+	t.Run("should return articles of feed", func(t *testing.T) {
+		expectedArticles := []model.Article{
+			{ID: "1", FeedID: "feed1", Title: "Article 1", URL: "http://example.com/a1"},
+		}
+		mockService.On("GetArticlesByFeed", testUser.ID, "feed1").Return(expectedArticles, nil).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Params = gin.Params{{Key: "id", Value: "feed1"}}
+		handler.GetArticlesByFeed(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var actualArticles []model.Article
+		err := json.Unmarshal(w.Body.Bytes(), &actualArticles)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedArticles, actualArticles)
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 購読していないフィード
+	t.Run("should return 404 if feed is not accessible", func(t *testing.T) {
+		mockService.On("GetArticlesByFeed", testUser.ID, "other").Return([]model.Article{}, service.ErrFeedNotFound).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Params = gin.Params{{Key: "id", Value: "other"}}
+		handler.GetArticlesByFeed(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Feed not found")
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: サービスエラー
+	t.Run("should return 500 if service error", func(t *testing.T) {
+		mockService.On("GetArticlesByFeed", testUser.ID, "errorID").Return([]model.Article{}, assert.AnError).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Params = gin.Params{{Key: "id", Value: "errorID"}}
+		handler.GetArticlesByFeed(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "Failed to get articles")
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestArticleHandler_GetArticlesByFolder(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService)
+
+	// 正常系: フォルダの記事が取得できる場合
+	t.Run("should return articles of folder", func(t *testing.T) {
+		expectedArticles := []model.Article{
+			{ID: "1", FeedID: "feed1", Title: "Article 1", URL: "http://example.com/a1"},
+			{ID: "2", FeedID: "feed2", Title: "Article 2", URL: "http://example.com/a2"},
+		}
+		mockService.On("GetArticlesByFolder", testUser.ID, "folder1").Return(expectedArticles, nil).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Params = gin.Params{{Key: "id", Value: "folder1"}}
+		handler.GetArticlesByFolder(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var actualArticles []model.Article
+		err := json.Unmarshal(w.Body.Bytes(), &actualArticles)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedArticles, actualArticles)
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 他のユーザーのフォルダ
+	t.Run("should return 404 if folder is not accessible", func(t *testing.T) {
+		mockService.On("GetArticlesByFolder", testUser.ID, "other").Return([]model.Article{}, service.ErrFolderNotFound).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Params = gin.Params{{Key: "id", Value: "other"}}
+		handler.GetArticlesByFolder(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Folder not found")
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: サービスエラー
+	t.Run("should return 500 if service error", func(t *testing.T) {
+		mockService.On("GetArticlesByFolder", testUser.ID, "errorID").Return([]model.Article{}, assert.AnError).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Params = gin.Params{{Key: "id", Value: "errorID"}}
+		handler.GetArticlesByFolder(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "Failed to get articles")
+		mockService.AssertExpectations(t)
+	})
+}
+
 func TestArticleHandler_UpdateArticleStatus(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockService := new(MockArticleService)
@@ -153,10 +368,11 @@ func TestArticleHandler_UpdateArticleStatus(t *testing.T) {
 	// 正常系: 記事ステータス更新成功 (既読)
 	t.Run("should update article status to read", func(t *testing.T) {
 		updatedArticle := model.Article{ID: "1", Title: "Article 1", URL: "http://example.com/a1", IsRead: true, IsLater: false}
-		mockService.On("UpdateArticleStatus", "1", true, false).Return(updatedArticle, nil).Once()
+		mockService.On("UpdateArticleStatus", testUser.ID, "1", true, false).Return(updatedArticle, nil).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "1"}}
 		c.Request = httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(`{"is_read":true,"is_later":false}`))
 		c.Request.Header.Set("Content-Type", "application/json")
@@ -173,10 +389,11 @@ func TestArticleHandler_UpdateArticleStatus(t *testing.T) {
 	// 正常系: 記事ステータス更新成功 (後で見る)
 	t.Run("should update article status to later", func(t *testing.T) {
 		updatedArticle := model.Article{ID: "1", Title: "Article 1", URL: "http://example.com/a1", IsRead: false, IsLater: true}
-		mockService.On("UpdateArticleStatus", "1", false, true).Return(updatedArticle, nil).Once()
+		mockService.On("UpdateArticleStatus", testUser.ID, "1", false, true).Return(updatedArticle, nil).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "1"}}
 		c.Request = httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(`{"is_read":false,"is_later":true}`))
 		c.Request.Header.Set("Content-Type", "application/json")
@@ -192,10 +409,11 @@ func TestArticleHandler_UpdateArticleStatus(t *testing.T) {
 
 	// 異常系: 記事が見つからない場合
 	t.Run("should return 404 if article not found", func(t *testing.T) {
-		mockService.On("UpdateArticleStatus", "nonexistent", mock.Anything, mock.Anything).Return(model.Article{}, service.ErrArticleNotFound).Once()
+		mockService.On("UpdateArticleStatus", testUser.ID, "nonexistent", mock.Anything, mock.Anything).Return(model.Article{}, service.ErrArticleNotFound).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "nonexistent"}}
 		c.Request = httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(`{"is_read":true,"is_later":false}`))
 		c.Request.Header.Set("Content-Type", "application/json")
@@ -210,6 +428,7 @@ func TestArticleHandler_UpdateArticleStatus(t *testing.T) {
 	t.Run("should return 400 if invalid request body", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "1"}}
 		c.Request = httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(`{"is_read":"invalid"}`))
 		c.Request.Header.Set("Content-Type", "application/json")
@@ -221,10 +440,11 @@ func TestArticleHandler_UpdateArticleStatus(t *testing.T) {
 
 	// 異常系: サービスエラー
 	t.Run("should return 500 if service error", func(t *testing.T) {
-		mockService.On("UpdateArticleStatus", "errorID", mock.Anything, mock.Anything).Return(model.Article{}, assert.AnError).Once()
+		mockService.On("UpdateArticleStatus", testUser.ID, "errorID", mock.Anything, mock.Anything).Return(model.Article{}, assert.AnError).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "errorID"}}
 		c.Request = httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(`{"is_read":true,"is_later":false}`))
 		c.Request.Header.Set("Content-Type", "application/json")
@@ -236,6 +456,82 @@ func TestArticleHandler_UpdateArticleStatus(t *testing.T) {
 	})
 }
 
+func TestArticleHandler_UpdateArticleMedia(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService)
+
+	// 正常系: 再生位置のみ更新
+	t.Run("should update playback position only", func(t *testing.T) {
+		position := 754
+		updatedArticle := model.Article{ID: "1", Title: "Episode 1", URL: "http://example.com/ep1", PlaybackPosition: 754}
+		mockService.On("UpdateArticleMedia", testUser.ID, "1", service.MediaStateUpdate{PlaybackPosition: &position}).Return(updatedArticle, nil).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Params = gin.Params{{Key: "id", Value: "1"}}
+		c.Request = httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(`{"playback_position_seconds":754}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.UpdateArticleMedia(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var actualArticle model.Article
+		err := json.Unmarshal(w.Body.Bytes(), &actualArticle)
+		assert.NoError(t, err)
+		assert.Equal(t, updatedArticle, actualArticle)
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 負の再生位置
+	t.Run("should return 400 if playback position is negative", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Params = gin.Params{{Key: "id", Value: "1"}}
+		c.Request = httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(`{"playback_position_seconds":-1}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.UpdateArticleMedia(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid input")
+	})
+
+	// 異常系: 記事が見つからない場合
+	t.Run("should return 404 if article not found", func(t *testing.T) {
+		mockService.On("UpdateArticleMedia", testUser.ID, "nonexistent", mock.Anything).Return(model.Article{}, service.ErrArticleNotFound).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Params = gin.Params{{Key: "id", Value: "nonexistent"}}
+		c.Request = httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(`{"played":true}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.UpdateArticleMedia(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Article not found")
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: サービスエラー
+	t.Run("should return 500 if service error", func(t *testing.T) {
+		mockService.On("UpdateArticleMedia", testUser.ID, "errorID", mock.Anything).Return(model.Article{}, assert.AnError).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Params = gin.Params{{Key: "id", Value: "errorID"}}
+		c.Request = httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(`{"downloaded":true}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.UpdateArticleMedia(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "Failed to update article media state")
+		mockService.AssertExpectations(t)
+	})
+}
+
 func TestArticleHandler_GetLaterArticles(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockService := new(MockArticleService)
@@ -247,10 +543,11 @@ func TestArticleHandler_GetLaterArticles(t *testing.T) {
 			{ID: "1", Title: "Later Article 1", URL: "http://example.com/l1", IsRead: false, IsLater: true},
 			{ID: "2", Title: "Later Article 2", URL: "http://example.com/l2", IsRead: true, IsLater: true},
 		}
-		mockService.On("GetLaterArticles").Return(expectedArticles, nil).Once()
+		mockService.On("GetLaterArticles", testUser.ID).Return(expectedArticles, nil).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		handler.GetLaterArticles(c)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -263,10 +560,11 @@ func TestArticleHandler_GetLaterArticles(t *testing.T) {
 
 	// 正常系: 後で見る記事が0件の場合
 	t.Run("should return empty array if no later articles", func(t *testing.T) {
-		mockService.On("GetLaterArticles").Return([]model.Article{}, nil).Once()
+		mockService.On("GetLaterArticles", testUser.ID).Return([]model.Article{}, nil).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		handler.GetLaterArticles(c)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -276,14 +574,72 @@ func TestArticleHandler_GetLaterArticles(t *testing.T) {
 
 	// 異常系: サービスエラー
 	t.Run("should return 500 if service error", func(t *testing.T) {
-		mockService.On("GetLaterArticles").Return([]model.Article{}, assert.AnError).Once()
+		mockService.On("GetLaterArticles", testUser.ID).Return([]model.Article{}, assert.AnError).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		handler.GetLaterArticles(c)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 		assert.Contains(t, w.Body.String(), "Failed to get later articles")
 		mockService.AssertExpectations(t)
 	})
-}
\ No newline at end of file
+}
+func TestArticleHandler_ExtractArticleContent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService)
+
+	// 正常系: 本文を抽出した記事を返す
+	t.Run("should return article with extracted content", func(t *testing.T) {
+		expectedArticle := model.Article{ID: "1", Title: "Article 1", URL: "http://example.com/a1", Content: "<p>Full body</p>"}
+		mockService.On("ExtractArticleContent", mock.Anything, testUser.ID, "1").Return(expectedArticle, nil).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+		handler.ExtractArticleContent(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var actualArticle model.Article
+		err := json.Unmarshal(w.Body.Bytes(), &actualArticle)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedArticle.Content, actualArticle.Content)
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 記事が見つからない場合
+	t.Run("should return 404 if article not found", func(t *testing.T) {
+		mockService.On("ExtractArticleContent", mock.Anything, testUser.ID, "nonexistent").Return(model.Article{}, service.ErrArticleNotFound).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "nonexistent"}}
+		handler.ExtractArticleContent(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"error": "Article not found"}`, w.Body.String())
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 記事ページの取得や本文抽出に失敗した場合
+	t.Run("should return 502 if extraction fails", func(t *testing.T) {
+		mockService.On("ExtractArticleContent", mock.Anything, testUser.ID, "1").Return(model.Article{}, fmt.Errorf("%w: timeout", service.ErrExtractionFailed)).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+		handler.ExtractArticleContent(c)
+
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+		assert.JSONEq(t, `{"error": "Failed to extract article content"}`, w.Body.String())
+		mockService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,541 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"feedapp/internal/model"
+	"feedapp/internal/oidc"
+	"feedapp/internal/service"
+)
+
+const (
+	// SessionCookieName はセッショントークンを保持する Cookie の名前です。
+	SessionCookieName = "feedapp_session"
+	// OIDCCookieName は OpenID Connect のログイン中に state・nonce・コードベリファイアを保持する Cookie の名前です。
+	OIDCCookieName = "feedapp_oidc"
+	// userContextKey は認証済みユーザーを gin.Context に保存するキーです。
+	userContextKey = "user"
+	// oidcCookiePath は OIDCCookieName の Cookie を送信するパスです。
+	oidcCookiePath = "/api/v1/auth/oidc"
+	// oidcCookieMaxAge は OpenID Connect のログインを完了するまでの制限時間（秒）です。
+	oidcCookieMaxAge = 600
+)
+
+// OIDCProvider は OpenID Connect プロバイダーとのやり取りを定義するインターフェースです。
+// oidc.Provider が実装します。
+type OIDCProvider interface {
+	Issuer() string
+	AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error)
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (oidc.Claims, error)
+}
+
+// AuthHandler は認証関連のHTTPリクエストを処理します。
+type AuthHandler struct {
+	authService       service.AuthService
+	sessionTTL        time.Duration
+	cookieSecure      bool
+	allowSignup       bool
+	oidcProvider      OIDCProvider
+	postLoginRedirect string
+}
+
+// AuthOptions は AuthHandler の動作設定です。
+type AuthOptions struct {
+	SessionTTL   time.Duration // セッションCookieの有効期限
+	CookieSecure bool          // セッションCookieに Secure 属性を付与するか
+	AllowSignup  bool          // 新規ユーザー登録を許可するか
+	// OIDCProvider は OpenID Connect によるログインに使用するプロバイダーです。nil の場合は無効となります。
+	OIDCProvider OIDCProvider
+	// PostLoginRedirect は OpenID Connect によるログイン後にリダイレクトするURLです。空の場合は "/" となります。
+	PostLoginRedirect string
+}
+
+// NewAuthHandler は新しい AuthHandler インスタンスを作成します。
+func NewAuthHandler(s service.AuthService, opts AuthOptions) *AuthHandler {
+	if opts.SessionTTL <= 0 {
+		opts.SessionTTL = service.DefaultSessionTTL
+	}
+	if opts.PostLoginRedirect == "" {
+		opts.PostLoginRedirect = "/"
+	}
+	return &AuthHandler{
+		authService:       s,
+		sessionTTL:        opts.SessionTTL,
+		cookieSecure:      opts.CookieSecure,
+		allowSignup:       opts.AllowSignup,
+		oidcProvider:      opts.OIDCProvider,
+		postLoginRedirect: opts.PostLoginRedirect,
+	}
+}
+
+// RequireAuth は認証済みのリクエストのみを通過させるミドルウェアです。
+//
+// `Authorization: Bearer <token>` ヘッダーの個人APIトークン、または
+// セッションCookieで認証します。認証に失敗した場合は 401 を返します。
+// 認証済みユーザーは CurrentUser で取得できます。
+func (h *AuthHandler) RequireAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var user model.User
+		var err error
+		if token, ok := bearerToken(c); ok {
+			user, err = h.authService.AuthenticateAPIToken(token)
+		} else if sessionToken, cookieErr := c.Cookie(SessionCookieName); cookieErr == nil && sessionToken != "" {
+			user, err = h.authService.AuthenticateSession(sessionToken)
+		} else {
+			err = service.ErrUnauthenticated
+		}
+
+		if err != nil {
+			if errors.Is(err, service.ErrUnauthenticated) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to authenticate"})
+			return
+		}
+
+		c.Set(userContextKey, user)
+		c.Next()
+	}
+}
+
+// CurrentUser は RequireAuth で認証されたユーザーを返します。
+func CurrentUser(c *gin.Context) (model.User, bool) {
+	value, ok := c.Get(userContextKey)
+	if !ok {
+		return model.User{}, false
+	}
+	user, ok := value.(model.User)
+	return user, ok
+}
+
+// requireUser は認証されたユーザーを返します。
+// ユーザーが存在しない場合は 401 を返し、false を返します。
+func requireUser(c *gin.Context) (model.User, bool) {
+	user, ok := CurrentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+	}
+	return user, ok
+}
+
+// bearerToken は Authorization ヘッダーから Bearer トークンを取り出します。
+func bearerToken(c *gin.Context) (string, bool) {
+	header := c.GetHeader("Authorization")
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// FeverPasswordRequest は Fever API 用パスワードの設定リクエストを表します。
+type FeverPasswordRequest struct {
+	Password string `json:"password" binding:"required,min=8,max=255" example:"fever app password"`
+}
+
+// SignupRequest はユーザー登録リクエストを表します。
+type SignupRequest struct {
+	Email    string `json:"email" binding:"required,email,max=255" example:"user@example.com"`
+	Password string `json:"password" binding:"required,min=8,max=72" example:"correct horse battery staple"`
+	Name     string `json:"name" binding:"max=255" example:"山田太郎"`
+}
+
+// LoginRequest はログインリクエストを表します。
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required" example:"user@example.com"`
+	Password string `json:"password" binding:"required" example:"correct horse battery staple"`
+}
+
+// APITokenRequest はAPIトークンの発行リクエストを表します。
+type APITokenRequest struct {
+	Name string `json:"name" binding:"required,max=255" example:"backup script"`
+}
+
+// Signup は新しいユーザーを登録し、ログイン状態にします。
+//
+//	@Summary		ユーザー登録
+//	@Description	メールアドレスとパスワードでユーザーを登録し、セッションCookieを発行します
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			user	body		SignupRequest	true	"登録情報"
+//	@Success		201	{object}	model.User				"登録されたユーザー"
+//	@Failure		400	{object}	map[string]interface{}	"リクエストボディの形式が不正"
+//	@Failure		403	{object}	map[string]string		"ユーザー登録が無効"
+//	@Failure		409	{object}	map[string]string		"メールアドレスが登録済み"
+//	@Failure		500	{object}	map[string]string		"サーバー内部エラー"
+//	@Router			/auth/signup [post]
+func (h *AuthHandler) Signup(c *gin.Context) {
+	if !h.allowSignup {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Signup is disabled"})
+		return
+	}
+
+	var req SignupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	user, err := h.authService.Signup(req.Email, req.Password, req.Name)
+	if err != nil {
+		if errors.Is(err, service.ErrEmailAlreadyExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Email already exists"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
+		return
+	}
+
+	_, sessionToken, err := h.authService.Login(req.Email, req.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+		return
+	}
+	h.setSessionCookie(c, sessionToken)
+	c.JSON(http.StatusCreated, user)
+}
+
+// Login はメールアドレスとパスワードでログインします。
+//
+//	@Summary		ログイン
+//	@Description	メールアドレスとパスワードを検証し、HTTP-onlyのセッションCookieを発行します
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			credentials	body		LoginRequest	true	"ログイン情報"
+//	@Success		200	{object}	model.User				"ログインしたユーザー"
+//	@Failure		400	{object}	map[string]interface{}	"リクエストボディの形式が不正"
+//	@Failure		401	{object}	map[string]string		"メールアドレスまたはパスワードが正しくない"
+//	@Failure		500	{object}	map[string]string		"サーバー内部エラー"
+//	@Router			/auth/login [post]
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	user, sessionToken, err := h.authService.Login(req.Email, req.Password)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCredentials) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to login"})
+		return
+	}
+	h.setSessionCookie(c, sessionToken)
+	c.JSON(http.StatusOK, user)
+}
+
+// Logout はセッションを破棄してログアウトします。
+//
+//	@Summary		ログアウト
+//	@Description	セッションを破棄し、セッションCookieを削除します
+//	@Tags			auth
+//	@Produce		json
+//	@Success		204	"ログアウト成功"
+//	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
+//	@Router			/auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	if sessionToken, err := c.Cookie(SessionCookieName); err == nil && sessionToken != "" {
+		if err := h.authService.Logout(sessionToken); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to logout"})
+			return
+		}
+	}
+	h.clearSessionCookie(c)
+	c.Status(http.StatusNoContent)
+}
+
+// GetCurrentUser はログイン中のユーザーを取得します。
+//
+//	@Summary		ログインユーザー取得
+//	@Description	セッションCookieまたはAPIトークンで認証されたユーザーを取得します
+//	@Tags			auth
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	model.User			"ログイン中のユーザー"
+//	@Failure		401	{object}	map[string]string	"未認証"
+//	@Router			/auth/me [get]
+func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// GetAPITokens はログイン中のユーザーのAPIトークン一覧を取得します。
+//
+//	@Summary		APIトークン一覧取得
+//	@Description	ログイン中のユーザーが発行したAPIトークンの一覧を取得します。トークン文字列は含まれません
+//	@Tags			auth
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		model.APIToken		"APIトークン一覧"
+//	@Failure		401	{object}	map[string]string	"未認証"
+//	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
+//	@Router			/auth/tokens [get]
+func (h *AuthHandler) GetAPITokens(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	tokens, err := h.authService.GetAPITokens(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get api tokens"})
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+// CreateAPIToken はAPIトークンを発行します。
+//
+//	@Summary		APIトークン発行
+//	@Description	スクリプトから `Authorization: Bearer <token>` で利用する個人APIトークンを発行します。トークン文字列はこのレスポンスでのみ返されます
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			token	body		APITokenRequest	true	"トークン情報"
+//	@Success		201	{object}	model.APIToken			"発行されたAPIトークン"
+//	@Failure		400	{object}	map[string]interface{}	"リクエストボディの形式が不正"
+//	@Failure		401	{object}	map[string]string		"未認証"
+//	@Failure		500	{object}	map[string]string		"サーバー内部エラー"
+//	@Router			/auth/tokens [post]
+func (h *AuthHandler) CreateAPIToken(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req APITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+	token, err := h.authService.CreateAPIToken(user.ID, req.Name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create api token"})
+		return
+	}
+	c.JSON(http.StatusCreated, token)
+}
+
+// DeleteAPIToken はAPIトークンを失効させます。
+//
+//	@Summary		APIトークン削除
+//	@Description	指定されたIDのAPIトークンを削除し、以降の利用を無効にします
+//	@Tags			auth
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path	string	true	"APIトークンID (UUID)"
+//	@Success		204	"削除成功"
+//	@Failure		401	{object}	map[string]string	"未認証"
+//	@Failure		404	{object}	map[string]string	"APIトークンが見つかりません"
+//	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
+//	@Router			/auth/tokens/{id} [delete]
+func (h *AuthHandler) DeleteAPIToken(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	if err := h.authService.DeleteAPIToken(user.ID, c.Param("id")); err != nil {
+		if errors.Is(err, service.ErrAPITokenNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "API token not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete api token"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// SetFeverPassword は Fever API 用のパスワードを設定します。
+//
+//	@Summary		Fever API パスワード設定
+//	@Description	Fever API 対応アプリで使用するパスワードを設定します。アプリにはメールアドレスとこのパスワードを入力します。ログインパスワードとは別のパスワードを指定してください
+//	@Tags			auth
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Param			password	body	FeverPasswordRequest	true	"Fever API 用パスワード"
+//	@Success		204	"設定成功"
+//	@Failure		400	{object}	map[string]interface{}	"リクエストボディの形式が不正"
+//	@Failure		401	{object}	map[string]string		"未認証"
+//	@Failure		500	{object}	map[string]string		"サーバー内部エラー"
+//	@Router			/auth/fever [put]
+func (h *AuthHandler) SetFeverPassword(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var req FeverPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+	if err := h.authService.SetFeverPassword(user.ID, req.Password); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set fever password"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteFeverPassword は Fever API 用のパスワードを削除し、Fever API を無効にします。
+//
+//	@Summary		Fever API パスワード削除
+//	@Description	Fever API 用のパスワードを削除し、Fever API によるアクセスを無効にします
+//	@Tags			auth
+//	@Security		BearerAuth
+//	@Success		204	"削除成功"
+//	@Failure		401	{object}	map[string]string	"未認証"
+//	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
+//	@Router			/auth/fever [delete]
+func (h *AuthHandler) DeleteFeverPassword(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	if err := h.authService.DeleteFeverPassword(user.ID); err != nil {
+		if errors.Is(err, service.ErrUnauthenticated) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete fever password"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// OIDCLogin は OpenID Connect プロバイダーのログイン画面にリダイレクトします。
+//
+// state・nonce・PKCE のコードベリファイアを生成し、HTTP-onlyの Cookie に保存します。
+//
+//	@Summary		OpenID Connect ログイン開始
+//	@Description	認可コードフロー（PKCE）で OpenID Connect プロバイダーのログイン画面にリダイレクトします
+//	@Tags			auth
+//	@Success		302	"プロバイダーの認可エンドポイントへリダイレクト"
+//	@Failure		404	{object}	map[string]string	"OpenID Connect が設定されていない"
+//	@Failure		502	{object}	map[string]string	"プロバイダーの設定を取得できない"
+//	@Router			/auth/oidc/login [get]
+func (h *AuthHandler) OIDCLogin(c *gin.Context) {
+	if h.oidcProvider == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "OIDC login is not configured"})
+		return
+	}
+
+	values := make([]string, 3)
+	for i := range values {
+		value, err := oidc.GenerateRandom()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start OIDC login"})
+			return
+		}
+		values[i] = value
+	}
+	state, nonce, codeVerifier := values[0], values[1], values[2]
+
+	authURL, err := h.oidcProvider.AuthCodeURL(c.Request.Context(), state, nonce, codeVerifier)
+	if err != nil {
+		log.Printf("Failed to build OIDC authorization URL: %v", err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to contact OIDC provider"})
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(OIDCCookieName, strings.Join(values, "."), oidcCookieMaxAge, oidcCookiePath, "", h.cookieSecure, true)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// OIDCCallback は OpenID Connect プロバイダーからのリダイレクトを受け取り、ログインします。
+//
+// state を検証したうえで認可コードをIDトークンに交換し、sub クレームに対応するユーザーで
+// セッションを作成します。初回ログイン時はユーザーを作成します。
+//
+//	@Summary		OpenID Connect コールバック
+//	@Description	認可コードを検証してセッションCookieを発行し、ログイン後の画面にリダイレクトします。未登録のアカウントの場合はユーザーを作成します
+//	@Tags			auth
+//	@Param			code	query	string	true	"認可コード"
+//	@Param			state	query	string	true	"ログイン開始時に発行した state"
+//	@Success		302	"ログイン後の画面へリダイレクト"
+//	@Failure		400	{object}	map[string]string	"state が不正、またはメールアドレスを取得できない"
+//	@Failure		401	{object}	map[string]string	"認証に失敗"
+//	@Failure		403	{object}	map[string]string	"ユーザー登録が無効"
+//	@Failure		404	{object}	map[string]string	"OpenID Connect が設定されていない"
+//	@Failure		409	{object}	map[string]string	"未確認のメールアドレスが登録済み"
+//	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
+//	@Router			/auth/oidc/callback [get]
+func (h *AuthHandler) OIDCCallback(c *gin.Context) {
+	if h.oidcProvider == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "OIDC login is not configured"})
+		return
+	}
+
+	cookie, err := c.Cookie(OIDCCookieName)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(OIDCCookieName, "", -1, oidcCookiePath, "", h.cookieSecure, true)
+	values := strings.Split(cookie, ".")
+	state := c.Query("state")
+	if err != nil || len(values) != 3 || state == "" || subtle.ConstantTimeCompare([]byte(values[0]), []byte(state)) != 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid OIDC state"})
+		return
+	}
+	if errorCode := c.Query("error"); errorCode != "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "OIDC authentication failed", "details": errorCode})
+		return
+	}
+	nonce, codeVerifier := values[1], values[2]
+
+	claims, err := h.oidcProvider.Exchange(c.Request.Context(), c.Query("code"), codeVerifier, nonce)
+	if err != nil {
+		log.Printf("Failed to exchange OIDC authorization code: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "OIDC authentication failed"})
+		return
+	}
+
+	_, sessionToken, err := h.authService.LoginWithIdentity(service.ExternalIdentity{
+		Issuer:        h.oidcProvider.Issuer(),
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+	}, h.allowSignup)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrSignupDisabled):
+			c.JSON(http.StatusForbidden, gin.H{"error": "Signup is disabled"})
+		case errors.Is(err, service.ErrEmailAlreadyExists):
+			c.JSON(http.StatusConflict, gin.H{"error": "Email already exists"})
+		case errors.Is(err, service.ErrEmailRequired):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Email is not provided by OIDC provider"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to login"})
+		}
+		return
+	}
+	h.setSessionCookie(c, sessionToken)
+	c.Redirect(http.StatusFound, h.postLoginRedirect)
+}
+
+// setSessionCookie はセッショントークンを HTTP-only Cookie に設定します。
+func (h *AuthHandler) setSessionCookie(c *gin.Context, token string) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(SessionCookieName, token, int(h.sessionTTL.Seconds()), "/", "", h.cookieSecure, true)
+}
+
+// clearSessionCookie はセッションCookieを削除します。
+func (h *AuthHandler) clearSessionCookie(c *gin.Context) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(SessionCookieName, "", -1, "/", "", h.cookieSecure, true)
+}
@@ -0,0 +1,523 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"feedapp/internal/model"
+	"feedapp/internal/oidc"
+	"feedapp/internal/oidc/oidctest"
+	"feedapp/internal/service"
+)
+
+// testUser は認証済みユーザーとしてコンテキストに設定するテスト用のユーザーです。
+var testUser = model.User{ID: "user-1", Email: "user@example.com"}
+
+// MockAuthService は service.AuthService のモック実装です。
+type MockAuthService struct {
+	mock.Mock
+}
+
+func (m *MockAuthService) Signup(email, password, name string) (model.User, error) {
+	args := m.Called(email, password, name)
+	return args.Get(0).(model.User), args.Error(1)
+}
+
+func (m *MockAuthService) Login(email, password string) (model.User, string, error) {
+	args := m.Called(email, password)
+	return args.Get(0).(model.User), args.String(1), args.Error(2)
+}
+
+func (m *MockAuthService) LoginWithIdentity(identity service.ExternalIdentity, allowSignup bool) (model.User, string, error) {
+	args := m.Called(identity, allowSignup)
+	return args.Get(0).(model.User), args.String(1), args.Error(2)
+}
+
+func (m *MockAuthService) Logout(sessionToken string) error {
+	args := m.Called(sessionToken)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) AuthenticateSession(sessionToken string) (model.User, error) {
+	args := m.Called(sessionToken)
+	return args.Get(0).(model.User), args.Error(1)
+}
+
+func (m *MockAuthService) AuthenticateAPIToken(token string) (model.User, error) {
+	args := m.Called(token)
+	return args.Get(0).(model.User), args.Error(1)
+}
+
+func (m *MockAuthService) GetAPITokens(userID string) ([]model.APIToken, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]model.APIToken), args.Error(1)
+}
+
+func (m *MockAuthService) CreateAPIToken(userID, name string) (model.APIToken, error) {
+	args := m.Called(userID, name)
+	return args.Get(0).(model.APIToken), args.Error(1)
+}
+
+func (m *MockAuthService) DeleteAPIToken(userID, id string) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) SetFeverPassword(userID, password string) error {
+	args := m.Called(userID, password)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) DeleteFeverPassword(userID string) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) AuthenticateFeverKey(apiKey string) (model.User, error) {
+	args := m.Called(apiKey)
+	return args.Get(0).(model.User), args.Error(1)
+}
+
+// newAuthRouter は RequireAuth で保護されたテスト用ルーターを作成します。
+func newAuthRouter(h *AuthHandler) *gin.Engine {
+	r := gin.New()
+	r.GET("/protected", h.RequireAuth(), func(c *gin.Context) {
+		user, _ := CurrentUser(c)
+		c.JSON(http.StatusOK, gin.H{"user_id": user.ID})
+	})
+	return r
+}
+
+func TestAuthHandler_RequireAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockAuthService)
+	handler := NewAuthHandler(mockService, AuthOptions{AllowSignup: true})
+	router := newAuthRouter(handler)
+	user := model.User{ID: "user-1", Email: "user@example.com"}
+
+	// 正常系: APIトークンで認証する
+	t.Run("should authenticate with bearer token", func(t *testing.T) {
+		mockService.On("AuthenticateAPIToken", "fa_token").Return(user, nil).Once()
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+		req.Header.Set("Authorization", "Bearer fa_token")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"user_id": "user-1"}`, w.Body.String())
+		mockService.AssertExpectations(t)
+	})
+
+	// 正常系: セッションCookieで認証する
+	t.Run("should authenticate with session cookie", func(t *testing.T) {
+		mockService.On("AuthenticateSession", "session-token").Return(user, nil).Once()
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+		req.AddCookie(&http.Cookie{Name: SessionCookieName, Value: "session-token"})
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 認証情報がない場合
+	t.Run("should return 401 without credentials", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/protected", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.JSONEq(t, `{"error": "Unauthorized"}`, w.Body.String())
+	})
+
+	// 異常系: トークンが無効な場合
+	t.Run("should return 401 with invalid token", func(t *testing.T) {
+		mockService.On("AuthenticateAPIToken", "invalid").Return(model.User{}, service.ErrUnauthenticated).Once()
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+		req.Header.Set("Authorization", "Bearer invalid")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestAuthHandler_Signup(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// 正常系: ユーザーを登録してセッションCookieを発行する
+	t.Run("should create user and set session cookie", func(t *testing.T) {
+		mockService := new(MockAuthService)
+		handler := NewAuthHandler(mockService, AuthOptions{AllowSignup: true})
+		user := model.User{ID: "user-1", Email: "user@example.com", Name: "User"}
+		mockService.On("Signup", "user@example.com", "password123", "User").Return(user, nil).Once()
+		mockService.On("Login", "user@example.com", "password123").Return(user, "session-token", nil).Once()
+
+		body, _ := json.Marshal(SignupRequest{Email: "user@example.com", Password: "password123", Name: "User"})
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/auth/signup", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.Signup(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		cookies := w.Result().Cookies()
+		if assert.Len(t, cookies, 1) {
+			assert.Equal(t, SessionCookieName, cookies[0].Name)
+			assert.Equal(t, "session-token", cookies[0].Value)
+			assert.True(t, cookies[0].HttpOnly)
+		}
+		assert.NotContains(t, w.Body.String(), "password")
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: パスワードが短い場合
+	t.Run("should return 400 for short password", func(t *testing.T) {
+		mockService := new(MockAuthService)
+		handler := NewAuthHandler(mockService, AuthOptions{AllowSignup: true})
+
+		body, _ := json.Marshal(SignupRequest{Email: "user@example.com", Password: "short"})
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/auth/signup", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.Signup(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "Signup", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	// 異常系: メールアドレスが登録済みの場合
+	t.Run("should return 409 if email exists", func(t *testing.T) {
+		mockService := new(MockAuthService)
+		handler := NewAuthHandler(mockService, AuthOptions{AllowSignup: true})
+		mockService.On("Signup", "user@example.com", "password123", "").Return(model.User{}, service.ErrEmailAlreadyExists).Once()
+
+		body, _ := json.Marshal(SignupRequest{Email: "user@example.com", Password: "password123"})
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/auth/signup", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.Signup(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.JSONEq(t, `{"error": "Email already exists"}`, w.Body.String())
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: ユーザー登録が無効な場合
+	t.Run("should return 403 if signup is disabled", func(t *testing.T) {
+		mockService := new(MockAuthService)
+		handler := NewAuthHandler(mockService, AuthOptions{AllowSignup: false})
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/auth/signup", bytes.NewBufferString(`{}`))
+		handler.Signup(c)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestAuthHandler_Login(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockAuthService)
+	handler := NewAuthHandler(mockService, AuthOptions{})
+
+	// 正常系: ログインしてセッションCookieを発行する
+	t.Run("should login and set session cookie", func(t *testing.T) {
+		user := model.User{ID: "user-1", Email: "user@example.com"}
+		mockService.On("Login", "user@example.com", "password123").Return(user, "session-token", nil).Once()
+
+		body, _ := json.Marshal(LoginRequest{Email: "user@example.com", Password: "password123"})
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/auth/login", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.Login(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		cookies := w.Result().Cookies()
+		if assert.Len(t, cookies, 1) {
+			assert.Equal(t, "session-token", cookies[0].Value)
+			assert.True(t, cookies[0].HttpOnly)
+			assert.Equal(t, http.SameSiteLaxMode, cookies[0].SameSite)
+		}
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: パスワードが正しくない場合
+	t.Run("should return 401 for invalid credentials", func(t *testing.T) {
+		mockService.On("Login", "user@example.com", "wrong").Return(model.User{}, "", service.ErrInvalidCredentials).Once()
+
+		body, _ := json.Marshal(LoginRequest{Email: "user@example.com", Password: "wrong"})
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/auth/login", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.Login(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.JSONEq(t, `{"error": "Invalid email or password"}`, w.Body.String())
+		assert.Empty(t, w.Result().Cookies())
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestAuthHandler_Logout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockAuthService)
+	handler := NewAuthHandler(mockService, AuthOptions{})
+
+	// 正常系: セッションを破棄してCookieを削除する
+	t.Run("should delete session and clear cookie", func(t *testing.T) {
+		mockService.On("Logout", "session-token").Return(nil).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/auth/logout", nil)
+		c.Request.AddCookie(&http.Cookie{Name: SessionCookieName, Value: "session-token"})
+		handler.Logout(c)
+
+		assert.Equal(t, http.StatusNoContent, c.Writer.Status())
+		cookies := w.Result().Cookies()
+		if assert.Len(t, cookies, 1) {
+			assert.Equal(t, "", cookies[0].Value)
+			assert.Less(t, cookies[0].MaxAge, 0)
+		}
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestAuthHandler_APITokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockAuthService)
+	handler := NewAuthHandler(mockService, AuthOptions{})
+	user := model.User{ID: "user-1"}
+
+	// 正常系: APIトークンを発行する
+	t.Run("should create api token", func(t *testing.T) {
+		token := model.APIToken{ID: "token-1", Name: "script", Token: "fa_secret"}
+		mockService.On("CreateAPIToken", "user-1", "script").Return(token, nil).Once()
+
+		body, _ := json.Marshal(APITokenRequest{Name: "script"})
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, user)
+		c.Request = httptest.NewRequest(http.MethodPost, "/auth/tokens", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.CreateAPIToken(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var actual model.APIToken
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &actual))
+		assert.Equal(t, "fa_secret", actual.Token)
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 他のユーザーのトークンなど、見つからない場合
+	t.Run("should return 404 if token not found", func(t *testing.T) {
+		mockService.On("DeleteAPIToken", "user-1", "other").Return(service.ErrAPITokenNotFound).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, user)
+		c.Request = httptest.NewRequest(http.MethodDelete, "/auth/tokens/other", nil)
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "other"}}
+		handler.DeleteAPIToken(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"error": "API token not found"}`, w.Body.String())
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestAuthHandler_FeverPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockAuthService)
+	handler := NewAuthHandler(mockService, AuthOptions{})
+
+	// 異常系: パスワードが短い場合
+	t.Run("should return 400 for short password", func(t *testing.T) {
+		body, _ := json.Marshal(FeverPasswordRequest{Password: "short"})
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Request = httptest.NewRequest(http.MethodPut, "/auth/fever", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.SetFeverPassword(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "SetFeverPassword", mock.Anything, mock.Anything)
+	})
+
+	// 正常系: Fever API 用パスワードを設定する
+	t.Run("should set fever password", func(t *testing.T) {
+		mockService.On("SetFeverPassword", testUser.ID, "fever-password").Return(nil).Once()
+
+		body, _ := json.Marshal(FeverPasswordRequest{Password: "fever-password"})
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Request = httptest.NewRequest(http.MethodPut, "/auth/fever", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.SetFeverPassword(c)
+
+		assert.Equal(t, http.StatusNoContent, c.Writer.Status())
+		mockService.AssertExpectations(t)
+	})
+
+	// 正常系: Fever API 用パスワードを削除する
+	t.Run("should delete fever password", func(t *testing.T) {
+		mockService.On("DeleteFeverPassword", testUser.ID).Return(nil).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Request = httptest.NewRequest(http.MethodDelete, "/auth/fever", nil)
+		handler.DeleteFeverPassword(c)
+
+		assert.Equal(t, http.StatusNoContent, c.Writer.Status())
+		mockService.AssertExpectations(t)
+	})
+}
+
+// startOIDCLogin は OIDCLogin を呼び出してテスト用プロバイダーで認可し、
+// コールバックのリクエストを返します。
+func startOIDCLogin(t *testing.T, handler *AuthHandler, server *oidctest.Server) *http.Request {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/auth/oidc/login", nil)
+	handler.OIDCLogin(c)
+
+	require.Equal(t, http.StatusFound, w.Code)
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, OIDCCookieName, cookies[0].Name)
+	assert.True(t, cookies[0].HttpOnly)
+
+	callback, err := server.Authorize(w.Header().Get("Location"))
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodGet, callback.String(), nil)
+	req.AddCookie(cookies[0])
+	return req
+}
+
+func TestAuthHandler_OIDC(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := oidctest.NewServer("client-1", "secret-1")
+	defer server.Close()
+	provider := oidc.New(oidc.Config{
+		Issuer:       server.URL,
+		ClientID:     server.ClientID,
+		ClientSecret: server.ClientSecret,
+		RedirectURL:  "http://localhost:8080/api/v1/auth/oidc/callback",
+	}, nil)
+	mockService := new(MockAuthService)
+	handler := NewAuthHandler(mockService, AuthOptions{AllowSignup: true, OIDCProvider: provider, PostLoginRedirect: "http://localhost:3000/"})
+
+	// 正常系: 認可コードフローでログインし、セッションCookieを発行する
+	t.Run("should login with authorization code flow", func(t *testing.T) {
+		identity := service.ExternalIdentity{Issuer: server.URL, Subject: "subject-1", Email: "user@example.com", EmailVerified: true, Name: "Test User"}
+		mockService.On("LoginWithIdentity", identity, true).Return(testUser, "session-token", nil).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = startOIDCLogin(t, handler, server)
+		handler.OIDCCallback(c)
+
+		assert.Equal(t, http.StatusFound, w.Code)
+		assert.Equal(t, "http://localhost:3000/", w.Header().Get("Location"))
+		var sessionCookie *http.Cookie
+		for _, cookie := range w.Result().Cookies() {
+			if cookie.Name == SessionCookieName {
+				sessionCookie = cookie
+			}
+		}
+		if assert.NotNil(t, sessionCookie) {
+			assert.Equal(t, "session-token", sessionCookie.Value)
+		}
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: state が一致しない場合
+	t.Run("should return 400 if state does not match", func(t *testing.T) {
+		req := startOIDCLogin(t, handler, server)
+		query := req.URL.Query()
+		query.Set("state", "forged")
+		req.URL.RawQuery = query.Encode()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		handler.OIDCCallback(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "Invalid OIDC state"}`, w.Body.String())
+	})
+
+	// 異常系: ログイン開始時の Cookie がない場合
+	t.Run("should return 400 without state cookie", func(t *testing.T) {
+		req := startOIDCLogin(t, handler, server)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, req.URL.String(), nil)
+		handler.OIDCCallback(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	// 異常系: 認可コードが不正な場合
+	t.Run("should return 401 if code exchange fails", func(t *testing.T) {
+		req := startOIDCLogin(t, handler, server)
+		query := req.URL.Query()
+		query.Set("code", "invalid")
+		req.URL.RawQuery = query.Encode()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		handler.OIDCCallback(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.JSONEq(t, `{"error": "OIDC authentication failed"}`, w.Body.String())
+	})
+
+	// 異常系: ユーザー登録が無効で未登録のアカウントの場合
+	t.Run("should return 403 if signup is disabled", func(t *testing.T) {
+		server.SetUser(oidctest.User{Subject: "subject-2", Email: "new@example.com", EmailVerified: true})
+		defer server.SetUser(oidctest.User{Subject: "subject-1", Email: "user@example.com", EmailVerified: true, Name: "Test User"})
+		closedHandler := NewAuthHandler(mockService, AuthOptions{OIDCProvider: provider})
+		identity := service.ExternalIdentity{Issuer: server.URL, Subject: "subject-2", Email: "new@example.com", EmailVerified: true}
+		mockService.On("LoginWithIdentity", identity, false).Return(model.User{}, "", service.ErrSignupDisabled).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = startOIDCLogin(t, closedHandler, server)
+		closedHandler.OIDCCallback(c)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: OpenID Connect が設定されていない場合
+	t.Run("should return 404 if oidc is not configured", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/auth/oidc/login", nil)
+		NewAuthHandler(mockService, AuthOptions{}).OIDCLogin(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
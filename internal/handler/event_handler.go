@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"feedapp/internal/events"
+
+	"github.com/gin-gonic/gin"
+)
+
+// eventHeartbeatInterval は接続を維持するためにコメント行を送信する間隔です。
+// プロキシやロードバランサーによるアイドル接続の切断を防ぎます。
+const eventHeartbeatInterval = 30 * time.Second
+
+// eventRetryMillis は切断時にブラウザが再接続するまでの待ち時間（ミリ秒）です。
+const eventRetryMillis = 3000
+
+// EventHandler は Server-Sent Events によるイベントの配信を処理します。
+//
+// サポートするエンドポイント:
+//   - GET /events - ログイン中のユーザー宛てのイベントのストリーム
+type EventHandler struct {
+	broker *events.Broker
+}
+
+// NewEventHandler は新しい EventHandler インスタンスを作成します。
+func NewEventHandler(broker *events.Broker) *EventHandler {
+	return &EventHandler{
+		broker: broker,
+	}
+}
+
+// Stream はログイン中のユーザー宛てのイベントを Server-Sent Events で配信します。
+//
+//	@Summary		イベントストリーム
+//	@Description	記事の取り込みや既読状態の変更を Server-Sent Events で配信します。イベントの種別は article.created（内容は本文を除いた記事）、article.updated（既読・後で見る状態の変更）、feed.refreshed、feed.error です。
+//	@Description	再接続時に `Last-Event-ID` ヘッダー（または last_event_id クエリパラメータ）を指定すると、以降のイベントを再送します。
+//	@Description	再送できない場合は reset イベントを送信するため、クライアントは表示中のデータを取得し直してください
+//	@Tags			events
+//	@Produce		text/event-stream
+//	@Security		BearerAuth
+//	@Param			Last-Event-ID	header		string				false	"最後に受信したイベントのID"
+//	@Param			last_event_id	query		string				false	"最後に受信したイベントのID（ヘッダーを指定できないクライアント向け）"
+//	@Success		200				{string}	string				"イベントストリーム"
+//	@Failure		401				{object}	map[string]string	"未認証"
+//	@Router			/events [get]
+func (h *EventHandler) Stream(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+
+	sub := h.broker.Subscribe(user.ID, lastEventID)
+	defer h.broker.Unsubscribe(sub)
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no") // nginx のバッファリングを無効にする
+	c.Status(http.StatusOK)
+
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", eventRetryMillis)
+	if sub.Reset {
+		fmt.Fprintf(c.Writer, "event: %s\ndata: {}\n\n", events.Reset)
+	}
+	for _, event := range sub.Missed {
+		writeEvent(c, event)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(eventHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-sub.C:
+			if !ok {
+				// 受信が追いつかずに切断された。クライアントは Last-Event-ID で再接続する
+				return
+			}
+			writeEvent(c, event)
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": ping\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
+
+// writeEvent はイベントを Server-Sent Events の形式で書き込みます。
+func writeEvent(c *gin.Context, event events.Event) {
+	fmt.Fprintf(c.Writer, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Data)
+}
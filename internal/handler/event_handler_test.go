@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"feedapp/internal/events"
+)
+
+// newEventServer はログイン済みのユーザーでイベントストリームを提供するテスト用サーバーを起動します。
+func newEventServer(t *testing.T, broker *events.Broker) *httptest.Server {
+	gin.SetMode(gin.TestMode)
+	handler := NewEventHandler(broker)
+	r := gin.New()
+	r.GET("/events", func(c *gin.Context) {
+		c.Set(userContextKey, testUser)
+	}, handler.Stream)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// openEventStream はイベントストリームに接続し、最初の retry ブロックを読み飛ばした状態で返します。
+func openEventStream(t *testing.T, server *httptest.Server, lastEventID string) *bufio.Reader {
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/events", nil)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		cancel()
+		resp.Body.Close()
+	})
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	reader := bufio.NewReader(resp.Body)
+	assert.Equal(t, map[string]string{"retry": "3000"}, readEventBlock(t, reader))
+	return reader
+}
+
+// readEventBlock は空行で区切られた1件分のフィールドを読み込みます。
+func readEventBlock(t *testing.T, reader *bufio.Reader) map[string]string {
+	fields := map[string]string{}
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return fields
+		}
+		name, value, _ := strings.Cut(line, ": ")
+		fields[name] = value
+	}
+}
+
+func TestEventHandler_Stream(t *testing.T) {
+	broker := events.NewBroker(16)
+	server := newEventServer(t, broker)
+
+	// 正常系: ログイン中のユーザー宛てのイベントのみ配信する
+	var lastEventID string
+	t.Run("should stream events for the current user", func(t *testing.T) {
+		reader := openEventStream(t, server, "")
+
+		isRead := true
+		require.NoError(t, broker.Publish([]string{"other-user"}, events.FeedRefreshed, events.FeedRefreshedData{FeedID: "hidden"}))
+		require.NoError(t, broker.Publish([]string{testUser.ID}, events.ArticleUpdated, events.ArticleUpdatedData{ArticleIDs: []string{"a1"}, IsRead: &isRead}))
+
+		block := readEventBlock(t, reader)
+		assert.Equal(t, events.ArticleUpdated, block["event"])
+		assert.NotEmpty(t, block["id"])
+		assert.JSONEq(t, `{"article_ids":["a1"],"is_read":true}`, block["data"])
+		lastEventID = block["id"]
+	})
+
+	// 正常系: Last-Event-ID 以降のイベントを再送する
+	t.Run("should replay missed events with Last-Event-ID", func(t *testing.T) {
+		require.NoError(t, broker.Publish([]string{testUser.ID}, events.FeedError, events.FeedErrorData{FeedID: "f1", Error: "timeout"}))
+
+		reader := openEventStream(t, server, lastEventID)
+
+		block := readEventBlock(t, reader)
+		assert.Equal(t, events.FeedError, block["event"])
+		assert.JSONEq(t, `{"feed_id":"f1","error":"timeout"}`, block["data"])
+	})
+
+	// 正常系: 再送できない Last-Event-ID の場合は reset を送信する
+	t.Run("should send reset for unknown Last-Event-ID", func(t *testing.T) {
+		reader := openEventStream(t, server, "previous-process-1")
+
+		block := readEventBlock(t, reader)
+		assert.Equal(t, events.Reset, block["event"])
+	})
+}
+
+func TestEventHandler_Stream_Unauthorized(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewEventHandler(events.NewBroker(16))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/events", nil)
+	handler.Stream(c)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
+	"sort"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"feedapp/internal/model"
 	"feedapp/internal/service"
 )
@@ -24,7 +27,7 @@ func NewFeedHandler(s service.FeedService) *FeedHandler {
 // GetAllFeeds はすべてのフィードを取得します。
 //
 //	@Summary		フィード一覧取得
-//	@Description	データベースに保存されているすべてのフィードを取得します
+//	@Description	ログイン中のユーザーが購読しているすべてのフィードを取得します
 //	@Tags			feeds
 //	@Accept			json
 //	@Produce		json
@@ -32,7 +35,11 @@ func NewFeedHandler(s service.FeedService) *FeedHandler {
 //	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
 //	@Router			/feeds [get]
 func (h *FeedHandler) GetAllFeeds(c *gin.Context) {
-	feeds, err := h.feedService.GetAllFeeds()
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	feeds, err := h.feedService.GetAllFeeds(user.ID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get feeds"})
 		return
@@ -53,8 +60,12 @@ func (h *FeedHandler) GetAllFeeds(c *gin.Context) {
 //	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
 //	@Router			/feeds/{id} [get]
 func (h *FeedHandler) GetFeedByID(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
 	id := c.Param("id")
-	feed, err := h.feedService.GetFeedByID(id)
+	feed, err := h.feedService.GetFeedByID(user.ID, id)
 	if err != nil {
 		if errors.Is(err, service.ErrFeedNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Feed not found"})
@@ -69,24 +80,42 @@ func (h *FeedHandler) GetFeedByID(c *gin.Context) {
 // CreateFeed は新しいフィードを作成します。
 //
 //	@Summary		フィード作成
-//	@Description	新しいフィードを作成します
+//	@Description	フィードを購読します。同じURLのフィードを他のユーザーが購読している場合は取得元を共有します。
+//	@Description	auth（認証情報・リクエストヘッダー）を指定した場合は共有せず、認証情報は暗号化して保存します。応答ではユーザー名以外を "********" に置き換えます。
+//	@Description	proxy（http・https・socks5 のURL）を指定した場合は共有せず、フィード・アイコン・記事ページの取得に使用します。パスワードは暗号化して保存し、応答では "********" に置き換えます
 //	@Tags			feeds
 //	@Accept			json
 //	@Produce		json
 //	@Param			feed	body		model.Feed	true	"フィード情報"
 //	@Success		201		{object}	model.Feed	"作成されたフィード"
-//	@Failure		400		{object}	map[string]interface{}	"リクエストボディの形式が不正"
+//	@Failure		400		{object}	map[string]interface{}	"リクエストボディの形式が不正、フォルダが存在しない、または認証情報・プロキシが不正"
+//	@Failure		409		{object}	map[string]string	"既に購読しているフィード"
 //	@Failure		500		{object}	map[string]string	"サーバー内部エラー"
 //	@Router			/feeds [post]
 func (h *FeedHandler) CreateFeed(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
 	var feed model.Feed
 	if err := c.ShouldBindJSON(&feed); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
 		return
 	}
 
-	createdFeed, err := h.feedService.CreateFeed(feed)
+	createdFeed, err := h.feedService.CreateFeed(user.ID, feed)
 	if err != nil {
+		if errors.Is(err, service.ErrFolderNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid folder_id"})
+			return
+		}
+		if errors.Is(err, service.ErrFeedAlreadyExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Feed already exists"})
+			return
+		}
+		if writeFeedAuthError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create feed"})
 		return
 	}
@@ -96,38 +125,116 @@ func (h *FeedHandler) CreateFeed(c *gin.Context) {
 // UpdateFeed は指定されたIDのフィードを更新します。
 //
 //	@Summary		フィード更新
-//	@Description	指定されたIDのフィードを更新します
+//	@Description	指定されたIDのフィードを更新します。
+//	@Description	auth の各項目・proxy のパスワードに "********" を指定した場合は保存済みの値を維持します。
+//	@Description	retention_days・max_articles・rate_limit・max_connections・respect_robots・auth・proxy は、省略した場合は保存済みの値を維持し、null を指定した場合は全体設定に戻します（auth・proxy は削除します）。
+//	@Description	他のユーザーと共有しているフィードには認証情報を設定できず、プロキシと保持設定（retention_days・max_articles）を変更できません（409）
 //	@Tags			feeds
 //	@Accept			json
 //	@Produce		json
 //	@Param			id		path		string		true	"フィードID (UUID)"
 //	@Param			feed	body		model.Feed	true	"更新するフィード情報"
 //	@Success		200		{object}	model.Feed	"更新されたフィード"
-//	@Failure		400		{object}	map[string]interface{}	"リクエストボディの形式が不正"
+//	@Failure		400		{object}	map[string]interface{}	"リクエストボディの形式が不正、フォルダが存在しない、URL・プラグイン種別の変更、または認証情報・プロキシが不正"
 //	@Failure		404		{object}	map[string]string	"フィードが見つかりません"
+//	@Failure		409		{object}	map[string]string	"共有しているフィードへの認証情報の設定・プロキシや保持設定・取得の設定の変更、または同じURLの共有のフィードが既にある"
 //	@Failure		500		{object}	map[string]string	"サーバー内部エラー"
 //	@Router			/feeds/{id} [put]
 func (h *FeedHandler) UpdateFeed(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
 	id := c.Param("id")
 	var feed model.Feed
-	if err := c.ShouldBindJSON(&feed); err != nil {
+	if err := c.ShouldBindBodyWith(&feed, binding.JSON); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
 		return
 	}
+	var fields map[string]json.RawMessage
+	if err := c.ShouldBindBodyWith(&fields, binding.JSON); err == nil {
+		feed.ClearedFields = nullFields(fields)
+		feed.OmittedFields = omittedFields(fields, "fetch_full_content", "update_interval")
+	}
 
 	feed.ID = id
-	updatedFeed, err := h.feedService.UpdateFeed(id, feed)
+	updatedFeed, err := h.feedService.UpdateFeed(user.ID, id, feed)
 	if err != nil {
 		if errors.Is(err, service.ErrFeedNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Feed not found"})
 			return
 		}
+		if errors.Is(err, service.ErrFolderNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid folder_id"})
+			return
+		}
+		if errors.Is(err, service.ErrFeedSourceChanged) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Feed url and plugin_type cannot be changed"})
+			return
+		}
+		if errors.Is(err, service.ErrFeedAlreadyExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Feed already exists"})
+			return
+		}
+		if errors.Is(err, service.ErrFeedRetentionShared) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Retention cannot be changed on a feed shared with other users"})
+			return
+		}
+		if errors.Is(err, service.ErrFeedFetchSettingsShared) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Fetch settings cannot be changed on a feed shared with other users"})
+			return
+		}
+		if writeFeedAuthError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update feed"})
 		return
 	}
 	c.JSON(http.StatusOK, updatedFeed)
 }
 
+// nullFields は JSON オブジェクトのうち null を指定した項目の名前を名前順に返します。
+func nullFields(fields map[string]json.RawMessage) []string {
+	var names []string
+	for name, value := range fields {
+		if string(value) == "null" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// omittedFields は names のうち JSON オブジェクトに含まれない項目の名前を返します。
+func omittedFields(fields map[string]json.RawMessage, names ...string) []string {
+	var omitted []string
+	for _, name := range names {
+		if _, ok := fields[name]; !ok {
+			omitted = append(omitted, name)
+		}
+	}
+	return omitted
+}
+
+// writeFeedAuthError はフィードの認証情報・プロキシに関するエラーの場合にエラーレスポンスを返し、true を返します。
+func writeFeedAuthError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrInvalidFeedAuth):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid auth", "details": err.Error()})
+	case errors.Is(err, service.ErrInvalidFeedProxy):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid proxy", "details": err.Error()})
+	case errors.Is(err, service.ErrFeedAuthUnavailable):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Feed auth and proxy are not available: encryption key is not configured"})
+	case errors.Is(err, service.ErrFeedAuthShared):
+		c.JSON(http.StatusConflict, gin.H{"error": "Auth cannot be added to a feed shared with other users"})
+	case errors.Is(err, service.ErrFeedProxyShared):
+		c.JSON(http.StatusConflict, gin.H{"error": "Proxy cannot be changed on a feed shared with other users"})
+	default:
+		return false
+	}
+	return true
+}
+
 // DeleteFeed は指定されたIDのフィードを削除します。
 //
 //	@Summary		フィード削除
@@ -141,8 +248,12 @@ func (h *FeedHandler) UpdateFeed(c *gin.Context) {
 //	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
 //	@Router			/feeds/{id} [delete]
 func (h *FeedHandler) DeleteFeed(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
 	id := c.Param("id")
-	err := h.feedService.DeleteFeed(id)
+	err := h.feedService.DeleteFeed(user.ID, id)
 	if err != nil {
 		if errors.Is(err, service.ErrFeedNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Feed not found"})
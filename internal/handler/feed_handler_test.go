@@ -3,6 +3,7 @@ package handler
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -20,28 +21,28 @@ type MockFeedService struct {
 	mock.Mock
 }
 
-func (m *MockFeedService) GetAllFeeds() ([]model.Feed, error) {
-	args := m.Called()
+func (m *MockFeedService) GetAllFeeds(userID string) ([]model.Feed, error) {
+	args := m.Called(userID)
 	return args.Get(0).([]model.Feed), args.Error(1)
 }
 
-func (m *MockFeedService) GetFeedByID(id string) (model.Feed, error) {
-	args := m.Called(id)
+func (m *MockFeedService) GetFeedByID(userID, id string) (model.Feed, error) {
+	args := m.Called(userID, id)
 	return args.Get(0).(model.Feed), args.Error(1)
 }
 
-func (m *MockFeedService) CreateFeed(feed model.Feed) (model.Feed, error) {
-	args := m.Called(feed)
+func (m *MockFeedService) CreateFeed(userID string, feed model.Feed) (model.Feed, error) {
+	args := m.Called(userID, feed)
 	return args.Get(0).(model.Feed), args.Error(1)
 }
 
-func (m *MockFeedService) UpdateFeed(id string, feed model.Feed) (model.Feed, error) {
-	args := m.Called(id, feed)
+func (m *MockFeedService) UpdateFeed(userID, id string, feed model.Feed) (model.Feed, error) {
+	args := m.Called(userID, id, feed)
 	return args.Get(0).(model.Feed), args.Error(1)
 }
 
-func (m *MockFeedService) DeleteFeed(id string) error {
-	args := m.Called(id)
+func (m *MockFeedService) DeleteFeed(userID, id string) error {
+	args := m.Called(userID, id)
 	return args.Error(0)
 }
 
@@ -56,10 +57,11 @@ func TestFeedHandler_GetAllFeeds(t *testing.T) {
 			{ID: "1", Name: "Feed 1", URL: "http://example.com/feed1", PluginType: "rss"},
 			{ID: "2", Name: "Feed 2", URL: "http://example.com/feed2", PluginType: "rss"},
 		}
-		mockService.On("GetAllFeeds").Return(expectedFeeds, nil).Once()
+		mockService.On("GetAllFeeds", testUser.ID).Return(expectedFeeds, nil).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		handler.GetAllFeeds(c)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -72,10 +74,11 @@ func TestFeedHandler_GetAllFeeds(t *testing.T) {
 
 	// 正常系: フィードが0件の場合
 	t.Run("should return empty array if no feeds", func(t *testing.T) {
-		mockService.On("GetAllFeeds").Return([]model.Feed{}, nil).Once()
+		mockService.On("GetAllFeeds", testUser.ID).Return([]model.Feed{}, nil).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		handler.GetAllFeeds(c)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -85,10 +88,11 @@ func TestFeedHandler_GetAllFeeds(t *testing.T) {
 
 	// 異常系: サービスエラー
 	t.Run("should return 500 if service error", func(t *testing.T) {
-		mockService.On("GetAllFeeds").Return([]model.Feed{}, assert.AnError).Once()
+		mockService.On("GetAllFeeds", testUser.ID).Return([]model.Feed{}, assert.AnError).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		handler.GetAllFeeds(c)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
@@ -105,10 +109,11 @@ func TestFeedHandler_GetFeedByID(t *testing.T) {
 	// 正常系: フィードが見つかる場合
 	t.Run("should return feed by ID", func(t *testing.T) {
 		expectedFeed := model.Feed{ID: "1", Name: "Feed 1", URL: "http://example.com/feed1", PluginType: "rss"}
-		mockService.On("GetFeedByID", "1").Return(expectedFeed, nil).Once()
+		mockService.On("GetFeedByID", testUser.ID, "1").Return(expectedFeed, nil).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "1"}}
 		handler.GetFeedByID(c)
 
@@ -122,10 +127,11 @@ func TestFeedHandler_GetFeedByID(t *testing.T) {
 
 	// 異常系: フィードが見つからない場合
 	t.Run("should return 404 if feed not found", func(t *testing.T) {
-		mockService.On("GetFeedByID", "nonexistent").Return(model.Feed{}, service.ErrFeedNotFound).Once()
+		mockService.On("GetFeedByID", testUser.ID, "nonexistent").Return(model.Feed{}, service.ErrFeedNotFound).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "nonexistent"}}
 		handler.GetFeedByID(c)
 
@@ -136,10 +142,11 @@ func TestFeedHandler_GetFeedByID(t *testing.T) {
 
 	// 異常系: サービスエラー
 	t.Run("should return 500 if service error", func(t *testing.T) {
-		mockService.On("GetFeedByID", "errorID").Return(model.Feed{}, assert.AnError).Once()
+		mockService.On("GetFeedByID", testUser.ID, "errorID").Return(model.Feed{}, assert.AnError).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "errorID"}}
 		handler.GetFeedByID(c)
 
@@ -158,10 +165,11 @@ func TestFeedHandler_CreateFeed(t *testing.T) {
 	t.Run("should create feed successfully", func(t *testing.T) {
 		newFeed := model.Feed{Name: "New Feed", URL: "http://example.com/newfeed", PluginType: "rss", FolderID: "folder1"}
 		createdFeed := model.Feed{ID: "3", Name: "New Feed", URL: "http://example.com/newfeed", PluginType: "rss", FolderID: "folder1"}
-		mockService.On("CreateFeed", newFeed).Return(createdFeed, nil).Once()
+		mockService.On("CreateFeed", testUser.ID, newFeed).Return(createdFeed, nil).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name":"New Feed","url":"http://example.com/newfeed","plugin_type":"rss","folder_id":"folder1"}`))
 		c.Request.Header.Set("Content-Type", "application/json")
 		handler.CreateFeed(c)
@@ -178,6 +186,7 @@ func TestFeedHandler_CreateFeed(t *testing.T) {
 	t.Run("should return 400 if invalid request body (no url)", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name":"New Feed","plugin_type":"rss"}`))
 		c.Request.Header.Set("Content-Type", "application/json")
 		handler.CreateFeed(c)
@@ -190,6 +199,7 @@ func TestFeedHandler_CreateFeed(t *testing.T) {
 	t.Run("should return 400 if invalid request body (invalid url)", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name":"New Feed","url":"invalid-url","plugin_type":"rss"}`))
 		c.Request.Header.Set("Content-Type", "application/json")
 		handler.CreateFeed(c)
@@ -198,13 +208,112 @@ func TestFeedHandler_CreateFeed(t *testing.T) {
 		assert.Contains(t, w.Body.String(), "Invalid input")
 	})
 
+	// 異常系: 既に購読しているフィード
+	t.Run("should return 409 if feed already subscribed", func(t *testing.T) {
+		newFeed := model.Feed{Name: "Dup Feed", URL: "http://example.com/dup", PluginType: "rss"}
+		mockService.On("CreateFeed", testUser.ID, newFeed).Return(model.Feed{}, service.ErrFeedAlreadyExists).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name":"Dup Feed","url":"http://example.com/dup","plugin_type":"rss"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.CreateFeed(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "Feed already exists")
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 他のユーザーのフォルダを指定
+	t.Run("should return 400 if folder is not owned by user", func(t *testing.T) {
+		newFeed := model.Feed{Name: "New Feed", URL: "http://example.com/newfeed", PluginType: "rss", FolderID: "other"}
+		mockService.On("CreateFeed", testUser.ID, newFeed).Return(model.Feed{}, service.ErrFolderNotFound).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name":"New Feed","url":"http://example.com/newfeed","plugin_type":"rss","folder_id":"other"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.CreateFeed(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid folder_id")
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 認証情報が不正
+	t.Run("should return 400 if auth is invalid", func(t *testing.T) {
+		newFeed := model.Feed{Name: "Private Feed", URL: "http://example.com/private", PluginType: "rss", Auth: &model.FeedAuth{Username: "user", Token: "token"}}
+		mockService.On("CreateFeed", testUser.ID, newFeed).Return(model.Feed{}, fmt.Errorf("%w: username and token cannot be specified together", service.ErrInvalidFeedAuth)).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name":"Private Feed","url":"http://example.com/private","plugin_type":"rss","auth":{"username":"user","token":"token"}}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.CreateFeed(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid auth")
+		assert.Contains(t, w.Body.String(), "username and token")
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: プロキシのURLが不正
+	t.Run("should return 400 if proxy is invalid", func(t *testing.T) {
+		newFeed := model.Feed{Name: "Proxied Feed", URL: "http://example.com/proxied", PluginType: "rss", Proxy: "ftp://proxy.example.com"}
+		mockService.On("CreateFeed", testUser.ID, newFeed).Return(model.Feed{}, fmt.Errorf("%w: unsupported scheme", service.ErrInvalidFeedProxy)).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name":"Proxied Feed","url":"http://example.com/proxied","plugin_type":"rss","proxy":"ftp://proxy.example.com"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.CreateFeed(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid proxy")
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 暗号化キーが未設定で認証情報を保存できない
+	t.Run("should return 400 if encryption key is not configured", func(t *testing.T) {
+		newFeed := model.Feed{Name: "Private Feed", URL: "http://example.com/private", PluginType: "rss", Auth: &model.FeedAuth{Token: "token"}}
+		mockService.On("CreateFeed", testUser.ID, newFeed).Return(model.Feed{}, service.ErrFeedAuthUnavailable).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name":"Private Feed","url":"http://example.com/private","plugin_type":"rss","auth":{"token":"token"}}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.CreateFeed(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "encryption key is not configured")
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 未認証
+	t.Run("should return 401 if user is not authenticated", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name":"New Feed","url":"http://example.com/newfeed","plugin_type":"rss"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.CreateFeed(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
 	// 異常系: サービスエラー
 	t.Run("should return 500 if service error", func(t *testing.T) {
 		newFeed := model.Feed{Name: "Error Feed", URL: "http://example.com/errorfeed", PluginType: "rss"}
-		mockService.On("CreateFeed", newFeed).Return(model.Feed{}, assert.AnError).Once()
+		mockService.On("CreateFeed", testUser.ID, newFeed).Return(model.Feed{}, assert.AnError).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name":"Error Feed","url":"http://example.com/errorfeed","plugin_type":"rss"}`))
 		c.Request.Header.Set("Content-Type", "application/json")
 		handler.CreateFeed(c)
@@ -219,14 +328,18 @@ func TestFeedHandler_UpdateFeed(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockService := new(MockFeedService)
 	handler := NewFeedHandler(mockService)
+	// 省略した共有の設定項目（保存済みの値を維持する）
+	omittedSettings := []string{"fetch_full_content", "update_interval"}
 
 	// 正常系: フィード更新成功
 	t.Run("should update feed successfully", func(t *testing.T) {
-		updatedFeed := model.Feed{ID: "1", Name: "Updated Feed", URL: "http://example.com/updatedfeed", PluginType: "rss", FolderID: "folder1"}
-		mockService.On("UpdateFeed", "1", updatedFeed).Return(updatedFeed, nil).Once()
+		updatedFeed := model.Feed{ID: "1", Name: "Updated Feed", URL: "http://example.com/updatedfeed", PluginType: "rss", FolderID: "folder1", OmittedFields: omittedSettings}
+		savedFeed := model.Feed{ID: "1", Name: "Updated Feed", URL: "http://example.com/updatedfeed", PluginType: "rss", FolderID: "folder1"}
+		mockService.On("UpdateFeed", testUser.ID, "1", updatedFeed).Return(savedFeed, nil).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "1"}}
 		c.Request = httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(`{"name":"Updated Feed","url":"http://example.com/updatedfeed","plugin_type":"rss","folder_id":"folder1"}`))
 		c.Request.Header.Set("Content-Type", "application/json")
@@ -236,17 +349,18 @@ func TestFeedHandler_UpdateFeed(t *testing.T) {
 		var actualFeed model.Feed
 		err := json.Unmarshal(w.Body.Bytes(), &actualFeed)
 		assert.NoError(t, err)
-		assert.Equal(t, updatedFeed, actualFeed)
+		assert.Equal(t, savedFeed, actualFeed)
 		mockService.AssertExpectations(t)
 	})
 
 	// 異常系: フィードが見つからない場合
 	t.Run("should return 404 if feed not found", func(t *testing.T) {
-		updatedFeed := model.Feed{ID: "nonexistent", Name: "Updated Feed", URL: "http://example.com/updatedfeed", PluginType: "rss"}
-		mockService.On("UpdateFeed", "nonexistent", updatedFeed).Return(model.Feed{}, service.ErrFeedNotFound).Once()
+		updatedFeed := model.Feed{ID: "nonexistent", Name: "Updated Feed", URL: "http://example.com/updatedfeed", PluginType: "rss", OmittedFields: omittedSettings}
+		mockService.On("UpdateFeed", testUser.ID, "nonexistent", updatedFeed).Return(model.Feed{}, service.ErrFeedNotFound).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "nonexistent"}}
 		c.Request = httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(`{"name":"Updated Feed","url":"http://example.com/updatedfeed","plugin_type":"rss"}`))
 		c.Request.Header.Set("Content-Type", "application/json")
@@ -257,10 +371,29 @@ func TestFeedHandler_UpdateFeed(t *testing.T) {
 		mockService.AssertExpectations(t)
 	})
 
+	// 異常系: URLの変更
+	t.Run("should return 400 if url is changed", func(t *testing.T) {
+		updatedFeed := model.Feed{ID: "1", Name: "Updated Feed", URL: "http://example.com/otherfeed", PluginType: "rss", OmittedFields: omittedSettings}
+		mockService.On("UpdateFeed", testUser.ID, "1", updatedFeed).Return(model.Feed{}, service.ErrFeedSourceChanged).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Params = gin.Params{{Key: "id", Value: "1"}}
+		c.Request = httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(`{"name":"Updated Feed","url":"http://example.com/otherfeed","plugin_type":"rss"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.UpdateFeed(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "cannot be changed")
+		mockService.AssertExpectations(t)
+	})
+
 	// 異常系: リクエストボディが不正
 	t.Run("should return 400 if invalid request body", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "1"}}
 		c.Request = httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(`{"name":"Updated Feed","plugin_type":"rss"}`))
 		c.Request.Header.Set("Content-Type", "application/json")
@@ -270,13 +403,104 @@ func TestFeedHandler_UpdateFeed(t *testing.T) {
 		assert.Contains(t, w.Body.String(), "Invalid input")
 	})
 
+	// 異常系: 他のユーザーと共有しているフィードに認証情報を設定
+	t.Run("should return 409 if auth is added to a shared feed", func(t *testing.T) {
+		updatedFeed := model.Feed{ID: "1", Name: "Updated Feed", URL: "http://example.com/feed1", PluginType: "rss", Auth: &model.FeedAuth{Cookie: "session=abc"}, OmittedFields: omittedSettings}
+		mockService.On("UpdateFeed", testUser.ID, "1", updatedFeed).Return(model.Feed{}, service.ErrFeedAuthShared).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Params = gin.Params{{Key: "id", Value: "1"}}
+		c.Request = httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(`{"name":"Updated Feed","url":"http://example.com/feed1","plugin_type":"rss","auth":{"cookie":"session=abc"}}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.UpdateFeed(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "shared with other users")
+		mockService.AssertExpectations(t)
+	})
+
+	// 正常系: null を指定した共有の設定項目は全体設定に戻す
+	t.Run("should pass fields set to null", func(t *testing.T) {
+		updatedFeed := model.Feed{ID: "1", Name: "Updated Feed", URL: "http://example.com/feed1", PluginType: "rss", ClearedFields: []string{"proxy", "retention_days"}, OmittedFields: omittedSettings}
+		mockService.On("UpdateFeed", testUser.ID, "1", updatedFeed).Return(model.Feed{ID: "1", Name: "Updated Feed", URL: "http://example.com/feed1", PluginType: "rss"}, nil).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Params = gin.Params{{Key: "id", Value: "1"}}
+		c.Request = httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(`{"name":"Updated Feed","url":"http://example.com/feed1","plugin_type":"rss","retention_days":null,"proxy":null}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.UpdateFeed(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 他のユーザーと共有しているフィードのプロキシを変更
+	t.Run("should return 409 if proxy is changed on a shared feed", func(t *testing.T) {
+		updatedFeed := model.Feed{ID: "1", Name: "Updated Feed", URL: "http://example.com/feed1", PluginType: "rss", Proxy: "socks5://proxy.example.com:1080", OmittedFields: omittedSettings}
+		mockService.On("UpdateFeed", testUser.ID, "1", updatedFeed).Return(model.Feed{}, service.ErrFeedProxyShared).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Params = gin.Params{{Key: "id", Value: "1"}}
+		c.Request = httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(`{"name":"Updated Feed","url":"http://example.com/feed1","plugin_type":"rss","proxy":"socks5://proxy.example.com:1080"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.UpdateFeed(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "shared with other users")
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 他のユーザーと共有しているフィードの保持設定を変更
+	t.Run("should return 409 if retention is changed on a shared feed", func(t *testing.T) {
+		maxArticles := 10
+		updatedFeed := model.Feed{ID: "1", Name: "Updated Feed", URL: "http://example.com/feed1", PluginType: "rss", MaxArticles: &maxArticles, OmittedFields: omittedSettings}
+		mockService.On("UpdateFeed", testUser.ID, "1", updatedFeed).Return(model.Feed{}, service.ErrFeedRetentionShared).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Params = gin.Params{{Key: "id", Value: "1"}}
+		c.Request = httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(`{"name":"Updated Feed","url":"http://example.com/feed1","plugin_type":"rss","max_articles":10}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.UpdateFeed(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "Retention cannot be changed")
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 他のユーザーと共有しているフィードの取得の設定を変更
+	t.Run("should return 409 if fetch settings are changed on a shared feed", func(t *testing.T) {
+		updatedFeed := model.Feed{ID: "1", Name: "Updated Feed", URL: "http://example.com/feed1", PluginType: "rss", UpdateInterval: 5, OmittedFields: []string{"fetch_full_content"}}
+		mockService.On("UpdateFeed", testUser.ID, "1", updatedFeed).Return(model.Feed{}, service.ErrFeedFetchSettingsShared).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
+		c.Params = gin.Params{{Key: "id", Value: "1"}}
+		c.Request = httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(`{"name":"Updated Feed","url":"http://example.com/feed1","plugin_type":"rss","update_interval":5}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.UpdateFeed(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "Fetch settings cannot be changed")
+		mockService.AssertExpectations(t)
+	})
+
 	// 異常系: サービスエラー
 	t.Run("should return 500 if service error", func(t *testing.T) {
-		updatedFeed := model.Feed{ID: "errorID", Name: "Error Feed", URL: "http://example.com/errorfeed", PluginType: "rss"}
-		mockService.On("UpdateFeed", "errorID", updatedFeed).Return(model.Feed{}, assert.AnError).Once()
+		updatedFeed := model.Feed{ID: "errorID", Name: "Error Feed", URL: "http://example.com/errorfeed", PluginType: "rss", OmittedFields: omittedSettings}
+		mockService.On("UpdateFeed", testUser.ID, "errorID", updatedFeed).Return(model.Feed{}, assert.AnError).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "errorID"}}
 		c.Request = httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(`{"name":"Error Feed","url":"http://example.com/errorfeed","plugin_type":"rss"}`))
 		c.Request.Header.Set("Content-Type", "application/json")
@@ -295,10 +519,11 @@ func TestFeedHandler_DeleteFeed(t *testing.T) {
 
 	// 正常系: フィード削除成功
 	t.Run("should delete feed successfully", func(t *testing.T) {
-		mockService.On("DeleteFeed", "1").Return(nil).Once()
+		mockService.On("DeleteFeed", testUser.ID, "1").Return(nil).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "1"}}
 		handler.DeleteFeed(c)
 
@@ -307,10 +532,11 @@ func TestFeedHandler_DeleteFeed(t *testing.T) {
 
 	// 異常系: フィードが見つからない場合
 	t.Run("should return 404 if feed not found", func(t *testing.T) {
-		mockService.On("DeleteFeed", "nonexistent").Return(service.ErrFeedNotFound).Once()
+		mockService.On("DeleteFeed", testUser.ID, "nonexistent").Return(service.ErrFeedNotFound).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "nonexistent"}}
 		handler.DeleteFeed(c)
 
@@ -321,10 +547,11 @@ func TestFeedHandler_DeleteFeed(t *testing.T) {
 
 	// 異常系: サービスエラー
 	t.Run("should return 500 if service error", func(t *testing.T) {
-		mockService.On("DeleteFeed", "errorID").Return(assert.AnError).Once()
+		mockService.On("DeleteFeed", testUser.ID, "errorID").Return(assert.AnError).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "errorID"}}
 		handler.DeleteFeed(c)
 
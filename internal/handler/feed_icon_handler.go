@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"feedapp/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeedIconHandler はフィードのアイコン関連のHTTPリクエストを処理します。
+//
+// サポートするエンドポイント:
+//   - GET /feeds/{id}/icon - フィードのアイコン取得
+type FeedIconHandler struct {
+	feedIconService service.FeedIconService
+}
+
+// NewFeedIconHandler は新しい FeedIconHandler インスタンスを作成します。
+func NewFeedIconHandler(s service.FeedIconService) *FeedIconHandler {
+	return &FeedIconHandler{
+		feedIconService: s,
+	}
+}
+
+// GetFeedIcon はフィードのアイコン（ファビコン）を返します。
+//
+//	@Summary		フィードのアイコン取得
+//	@Description	フィードの取得時に検出したサイトのアイコン（Atom の <icon>、<link rel="icon">、/favicon.ico）を返します。
+//	@Description	アイコンは1週間ごとに再取得します。ETag は内容のハッシュで、If-None-Match が一致する場合は 304 を返します
+//	@Tags			feeds
+//	@Produce		image/png,image/x-icon,image/gif,image/jpeg,image/webp
+//	@Security		BearerAuth
+//	@Param			id	path		string				true	"フィードID (UUID)"
+//	@Success		200	{file}		binary				"アイコン"
+//	@Success		304	"変更なし（If-None-Match が一致）"
+//	@Failure		404	{object}	map[string]string	"フィードまたはアイコンが見つかりません"
+//	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
+//	@Router			/feeds/{id}/icon [get]
+func (h *FeedIconHandler) GetFeedIcon(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	icon, err := h.feedIconService.GetIcon(user.ID, c.Param("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrFeedNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Feed not found"})
+		case errors.Is(err, service.ErrFeedIconNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Icon not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get icon"})
+		}
+		return
+	}
+
+	etag := `"` + icon.Hash + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache")
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Header("Content-Security-Policy", imageContentSecurityPolicy)
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, icon.MimeType, icon.Data)
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"feedapp/internal/model"
+	"feedapp/internal/service"
+)
+
+// MockFeedIconService は service.FeedIconService のモック実装です。
+type MockFeedIconService struct {
+	mock.Mock
+}
+
+func (m *MockFeedIconService) GetIcon(userID, feedID string) (model.FeedIcon, error) {
+	args := m.Called(userID, feedID)
+	return args.Get(0).(model.FeedIcon), args.Error(1)
+}
+
+func (m *MockFeedIconService) GetIcons(userID string) ([]model.FeedIcon, error) {
+	args := m.Called(userID)
+	icons, _ := args.Get(0).([]model.FeedIcon)
+	return icons, args.Error(1)
+}
+
+func (m *MockFeedIconService) RefreshIcon(ctx context.Context, feed model.Feed, siteURL, iconURL string) {
+	m.Called(ctx, feed, siteURL, iconURL)
+}
+
+// newFeedIconContext はログイン済みのテスト用コンテキストを作成します。
+func newFeedIconContext(feedID, ifNoneMatch string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set(userContextKey, testUser)
+	c.Params = gin.Params{{Key: "id", Value: feedID}}
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if ifNoneMatch != "" {
+		c.Request.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	return c, w
+}
+
+func TestFeedIconHandler_GetFeedIcon(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockFeedIconService)
+	handler := NewFeedIconHandler(mockService)
+
+	hash := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	icon := model.FeedIcon{FeedID: "1", URL: "https://example.com/favicon.ico", MimeType: "image/x-icon", Hash: hash, Data: []byte{0, 0, 1, 0}}
+
+	// 正常系: アイコンを ETag とともに返す
+	t.Run("should return icon", func(t *testing.T) {
+		mockService.On("GetIcon", testUser.ID, "1").Return(icon, nil).Once()
+
+		c, w := newFeedIconContext("1", "")
+		handler.GetFeedIcon(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image/x-icon", w.Header().Get("Content-Type"))
+		assert.Equal(t, `"`+hash+`"`, w.Header().Get("ETag"))
+		assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, icon.Data, w.Body.Bytes())
+		mockService.AssertExpectations(t)
+	})
+
+	// 正常系: ETag が一致する場合は 304 を返す
+	t.Run("should return 304 if etag matches", func(t *testing.T) {
+		mockService.On("GetIcon", testUser.ID, "1").Return(icon, nil).Once()
+
+		c, w := newFeedIconContext("1", `"`+hash+`"`)
+		handler.GetFeedIcon(c)
+
+		assert.Equal(t, http.StatusNotModified, c.Writer.Status())
+		assert.Empty(t, w.Body.String())
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: アイコンがない場合
+	t.Run("should return 404 if icon not found", func(t *testing.T) {
+		mockService.On("GetIcon", testUser.ID, "2").Return(model.FeedIcon{}, service.ErrFeedIconNotFound).Once()
+
+		c, w := newFeedIconContext("2", "")
+		handler.GetFeedIcon(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Icon not found")
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 購読していないフィード
+	t.Run("should return 404 if feed not found", func(t *testing.T) {
+		mockService.On("GetIcon", testUser.ID, "other").Return(model.FeedIcon{}, service.ErrFeedNotFound).Once()
+
+		c, w := newFeedIconContext("other", "")
+		handler.GetFeedIcon(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Feed not found")
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: サービス層でエラーが発生した場合
+	t.Run("should return 500 on service error", func(t *testing.T) {
+		mockService.On("GetIcon", testUser.ID, "3").Return(model.FeedIcon{}, errors.New("db error")).Once()
+
+		c, w := newFeedIconContext("3", "")
+		handler.GetFeedIcon(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "Failed to get icon")
+		mockService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,502 @@
+package handler
+
+import (
+	"encoding/base64"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"feedapp/internal/model"
+	"feedapp/internal/service"
+)
+
+const (
+	// feverAPIVersion は応答する Fever API のバージョンです。
+	feverAPIVersion = 3
+	// feverMaxItems は items で一度に返す記事の最大件数です。
+	feverMaxItems = 50
+)
+
+// errFeverInvalidParameter は Fever API のパラメータが不正な場合のエラーです。
+var errFeverInvalidParameter = errors.New("invalid parameter")
+
+// FeverHandler は Fever API 互換のHTTPリクエストを処理します。
+//
+// Fever API はフォルダをグループ、「後で見る」を保存済み（saved）として扱い、
+// 記事・フィード・フォルダを整数IDで識別するため、それぞれの連番
+// （Article.ItemID・Feed.IntID・Folder.IntID）を使用します。
+// 認証には MD5(メールアドレス:Feverパスワード) を api_key として使用します。
+type FeverHandler struct {
+	authService    service.AuthService
+	folderService  service.FolderService
+	feedService    service.FeedService
+	articleService service.ArticleService
+	iconService    service.FeedIconService
+}
+
+// NewFeverHandler は新しい FeverHandler インスタンスを作成します。
+func NewFeverHandler(authService service.AuthService, folderService service.FolderService, feedService service.FeedService, articleService service.ArticleService, iconService service.FeedIconService) *FeverHandler {
+	return &FeverHandler{
+		authService:    authService,
+		folderService:  folderService,
+		feedService:    feedService,
+		articleService: articleService,
+		iconService:    iconService,
+	}
+}
+
+// feverGroup は groups のグループ（フォルダ）です。
+type feverGroup struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+}
+
+// feverFeedsGroup はグループに属するフィードのIDです。feed_ids はカンマ区切りです。
+type feverFeedsGroup struct {
+	GroupID int64  `json:"group_id"`
+	FeedIDs string `json:"feed_ids"`
+}
+
+// feverFeed は feeds のフィードです。
+type feverFeed struct {
+	ID                int64  `json:"id"`
+	FaviconID         int64  `json:"favicon_id"`
+	Title             string `json:"title"`
+	URL               string `json:"url"`
+	SiteURL           string `json:"site_url"`
+	IsSpark           int    `json:"is_spark"`
+	LastUpdatedOnTime int64  `json:"last_updated_on_time"`
+}
+
+// feverFavicon は favicons のアイコンです。data は "image/png;base64,..." 形式です。
+// アイコンはフィードごとに1件のため、ID にはフィードの ID（Feed.IntID）を使用します。
+type feverFavicon struct {
+	ID   int64  `json:"id"`
+	Data string `json:"data"`
+}
+
+// feverItem は items の記事です。
+type feverItem struct {
+	ID            int64  `json:"id"`
+	FeedID        int64  `json:"feed_id"`
+	Title         string `json:"title"`
+	Author        string `json:"author"`
+	HTML          string `json:"html"`
+	URL           string `json:"url"`
+	IsSaved       int    `json:"is_saved"`
+	IsRead        int    `json:"is_read"`
+	CreatedOnTime int64  `json:"created_on_time"`
+}
+
+// feverRequest はリクエストごとに読み込むユーザーのフォルダとフィードです。
+type feverRequest struct {
+	userID  string
+	folders []model.Folder
+	feeds   []model.Feed
+	icons   map[string]model.FeedIcon // フィードIDとアイコン（feeds・favicons を要求された場合のみ読み込む）
+}
+
+// API は Fever API のリクエストを処理します。
+//
+// api_key で認証し、クエリまたはフォームの groups・feeds・favicons・items・
+// unread_item_ids・saved_item_ids に対応するデータを返します。
+// mark・as・id・before で記事・フィード・グループの既読や保存済みを更新します。
+// 認証に失敗した場合は auth が 0 のレスポンスを返します。
+func (h *FeverHandler) API(c *gin.Context) {
+	if err := c.Request.ParseForm(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	response := gin.H{"api_version": feverAPIVersion, "auth": 0}
+
+	user, err := h.authService.AuthenticateFeverKey(c.Request.PostFormValue("api_key"))
+	if err != nil {
+		if errors.Is(err, service.ErrUnauthenticated) {
+			c.JSON(http.StatusOK, response)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to authenticate"})
+		return
+	}
+	response["auth"] = 1
+
+	req, err := h.newRequest(user.ID)
+	if err != nil {
+		log.Printf("Failed to load subscriptions for Fever API: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get feeds"})
+		return
+	}
+	response["last_refreshed_on_time"] = req.lastRefreshed()
+
+	if err := h.handle(c, req, response); err != nil {
+		if errors.Is(err, errFeverInvalidParameter) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Failed to process Fever API request: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process request"})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// handle は要求されたデータを response に追加します。更新（mark）は取得より先に処理します。
+func (h *FeverHandler) handle(c *gin.Context, req *feverRequest, response gin.H) error {
+	// 更新後は、更新した状態の記事IDの一覧を要求がなくても返す
+	var updated string
+	if mark := c.Request.FormValue("mark"); mark != "" {
+		key, err := h.mark(c, req, mark)
+		if err != nil {
+			return err
+		}
+		updated = key
+	}
+	has := func(key string) bool {
+		_, ok := c.Request.Form[key]
+		return ok || key == updated
+	}
+
+	if has("feeds") || has("favicons") {
+		icons, err := h.iconService.GetIcons(req.userID)
+		if err != nil {
+			return err
+		}
+		req.icons = make(map[string]model.FeedIcon, len(icons))
+		for _, icon := range icons {
+			req.icons[icon.FeedID] = icon
+		}
+	}
+	if has("groups") {
+		response["groups"] = req.groups()
+		response["feeds_groups"] = req.feedsGroups()
+	}
+	if has("feeds") {
+		response["feeds"] = req.feverFeeds()
+		response["feeds_groups"] = req.feedsGroups()
+	}
+	if has("favicons") {
+		response["favicons"] = req.favicons()
+	}
+	if has("items") {
+		items, total, err := h.items(c, req)
+		if err != nil {
+			return err
+		}
+		response["items"] = items
+		response["total_items"] = total
+	}
+	if has("unread_item_ids") {
+		ids, err := h.itemIDs(req.userID, model.ArticleFilter{IsRead: boolPointer(false)})
+		if err != nil {
+			return err
+		}
+		response["unread_item_ids"] = ids
+	}
+	if has("saved_item_ids") {
+		ids, err := h.itemIDs(req.userID, model.ArticleFilter{IsLater: boolPointer(true)})
+		if err != nil {
+			return err
+		}
+		response["saved_item_ids"] = ids
+	}
+	return nil
+}
+
+// mark は記事・フィード・グループの状態を更新し、更新後に返すべき記事IDの一覧のキーを返します。
+//
+//   - mark=item: as=read|unread|saved|unsaved で id の記事を更新する
+//   - mark=feed / mark=group: as=read で id のフィード・グループの記事のうち before（UNIX秒）より前に取得したものを既読にする
+//
+// グループID 0 はすべての記事を表します。存在しないフィード・グループは無視します。
+func (h *FeverHandler) mark(c *gin.Context, req *feverRequest, mark string) (string, error) {
+	as := c.Request.FormValue("as")
+	id, err := strconv.ParseInt(c.Request.FormValue("id"), 10, 64)
+	if err != nil {
+		return "", errFeverInvalidParameter
+	}
+
+	if mark == "item" {
+		var update service.ArticleStateUpdate
+		key := "unread_item_ids"
+		switch as {
+		case "read":
+			update.IsRead = boolPointer(true)
+		case "unread":
+			update.IsRead = boolPointer(false)
+		case "saved":
+			update.IsLater = boolPointer(true)
+			key = "saved_item_ids"
+		case "unsaved":
+			update.IsLater = boolPointer(false)
+			key = "saved_item_ids"
+		default:
+			return "", errFeverInvalidParameter
+		}
+		articles, err := h.articleService.GetAllArticles(req.userID, model.ArticleFilter{ItemIDs: []int64{id}})
+		if err != nil {
+			return "", err
+		}
+		ids := make([]string, 0, len(articles))
+		for _, article := range articles {
+			ids = append(ids, article.ID)
+		}
+		return key, h.articleService.UpdateArticleStates(req.userID, ids, update)
+	}
+
+	if as != "read" {
+		return "", errFeverInvalidParameter
+	}
+	var filter model.ArticleFilter
+	switch {
+	case mark == "feed":
+		feed, ok := req.feedByIntID(id)
+		if !ok {
+			return "", nil
+		}
+		filter.FeedID = feed.ID
+	case mark == "group" && id == 0:
+		// グループID 0（Kindling）はすべての記事を表す
+	case mark == "group":
+		folder, ok := req.folderByIntID(id)
+		if !ok {
+			return "", nil
+		}
+		filter.FolderID = folder.ID
+	default:
+		return "", errFeverInvalidParameter
+	}
+	if before := c.Request.FormValue("before"); before != "" {
+		seconds, err := strconv.ParseInt(before, 10, 64)
+		if err != nil {
+			return "", errFeverInvalidParameter
+		}
+		if seconds > 0 {
+			filter.Until = time.Unix(seconds, 0)
+		}
+	}
+	if _, err := h.articleService.MarkArticlesRead(req.userID, filter); err != nil {
+		if errors.Is(err, service.ErrFeedNotFound) || errors.Is(err, service.ErrFolderNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	return "unread_item_ids", nil
+}
+
+// items は記事を最大 feverMaxItems 件返します。
+//
+// since_id を指定した場合はそれより後の記事を古い順、max_id を指定した場合はそれより前の記事を新しい順、
+// with_ids（カンマ区切り）を指定した場合はその記事を返します。指定がない場合は最も古い記事から返します。
+// 2つ目の戻り値はユーザーが参照できる記事の総数です。
+func (h *FeverHandler) items(c *gin.Context, req *feverRequest) ([]feverItem, int, error) {
+	filter := model.ArticleFilter{Limit: feverMaxItems, OldestFirst: true}
+	switch {
+	case c.Request.FormValue("with_ids") != "":
+		ids, err := parseFeverIDs(c.Request.FormValue("with_ids"))
+		if err != nil || len(ids) > feverMaxItems {
+			return nil, 0, errFeverInvalidParameter
+		}
+		filter.ItemIDs = ids
+	case c.Request.FormValue("max_id") != "":
+		maxID, err := strconv.ParseInt(c.Request.FormValue("max_id"), 10, 64)
+		if err != nil || maxID < 0 {
+			return nil, 0, errFeverInvalidParameter
+		}
+		// max_id=0 は最新の記事から取得することを表す
+		filter.OldestFirst = false
+		filter.Cursor = maxID
+	case c.Request.FormValue("since_id") != "":
+		sinceID, err := strconv.ParseInt(c.Request.FormValue("since_id"), 10, 64)
+		if err != nil || sinceID < 0 {
+			return nil, 0, errFeverInvalidParameter
+		}
+		filter.Cursor = sinceID
+	}
+
+	articles, err := h.articleService.GetAllArticles(req.userID, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := h.articleService.GetArticleItemIDs(req.userID, model.ArticleFilter{})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	feedIDs := make(map[string]int64, len(req.feeds))
+	for _, feed := range req.feeds {
+		feedIDs[feed.ID] = feed.IntID
+	}
+	items := make([]feverItem, 0, len(articles))
+	for _, article := range articles {
+		html := article.Content
+		if html == "" {
+			html = article.Summary
+		}
+		items = append(items, feverItem{
+			ID:            article.ItemID,
+			FeedID:        feedIDs[article.FeedID],
+			Title:         article.Title,
+			Author:        article.Author,
+			HTML:          html,
+			URL:           article.URL,
+			IsSaved:       feverBool(article.IsLater),
+			IsRead:        feverBool(article.IsRead),
+			CreatedOnTime: articleTimestamp(article).Unix(),
+		})
+	}
+	return items, len(total), nil
+}
+
+// itemIDs は絞り込み条件に一致する記事のIDをカンマ区切りで返します。
+func (h *FeverHandler) itemIDs(userID string, filter model.ArticleFilter) (string, error) {
+	ids, err := h.articleService.GetArticleItemIDs(userID, filter)
+	if err != nil {
+		return "", err
+	}
+	return joinFeverIDs(ids), nil
+}
+
+// newRequest はユーザーのフォルダとフィードを読み込みます。
+func (h *FeverHandler) newRequest(userID string) (*feverRequest, error) {
+	folders, err := h.folderService.GetAllFolders(userID)
+	if err != nil {
+		return nil, err
+	}
+	feeds, err := h.feedService.GetAllFeeds(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &feverRequest{userID: userID, folders: folders, feeds: feeds}, nil
+}
+
+// lastRefreshed はフィードの最終更新日時のうち最も新しいもの（UNIX秒）を返します。
+func (r *feverRequest) lastRefreshed() int64 {
+	var latest time.Time
+	for _, feed := range r.feeds {
+		if feed.LastUpdated.After(latest) {
+			latest = feed.LastUpdated
+		}
+	}
+	if latest.IsZero() {
+		return 0
+	}
+	return latest.Unix()
+}
+
+func (r *feverRequest) groups() []feverGroup {
+	groups := make([]feverGroup, 0, len(r.folders))
+	for _, folder := range r.folders {
+		groups = append(groups, feverGroup{ID: folder.IntID, Title: folder.Name})
+	}
+	return groups
+}
+
+// feedsGroups はフォルダごとに所属するフィードのIDを返します。
+func (r *feverRequest) feedsGroups() []feverFeedsGroup {
+	feedIDs := make(map[string][]int64, len(r.folders))
+	for _, feed := range r.feeds {
+		if feed.FolderID != "" {
+			feedIDs[feed.FolderID] = append(feedIDs[feed.FolderID], feed.IntID)
+		}
+	}
+	groups := make([]feverFeedsGroup, 0, len(r.folders))
+	for _, folder := range r.folders {
+		if ids, ok := feedIDs[folder.ID]; ok {
+			groups = append(groups, feverFeedsGroup{GroupID: folder.IntID, FeedIDs: joinFeverIDs(ids)})
+		}
+	}
+	return groups
+}
+
+func (r *feverRequest) feverFeeds() []feverFeed {
+	feeds := make([]feverFeed, 0, len(r.feeds))
+	for _, feed := range r.feeds {
+		var lastUpdated int64
+		if !feed.LastUpdated.IsZero() {
+			lastUpdated = feed.LastUpdated.Unix()
+		}
+		var faviconID int64
+		if _, ok := r.icons[feed.ID]; ok {
+			faviconID = feed.IntID
+		}
+		feeds = append(feeds, feverFeed{
+			ID:                feed.IntID,
+			FaviconID:         faviconID,
+			Title:             feed.Name,
+			URL:               feed.URL,
+			SiteURL:           feed.URL,
+			LastUpdatedOnTime: lastUpdated,
+		})
+	}
+	return feeds
+}
+
+func (r *feverRequest) favicons() []feverFavicon {
+	favicons := make([]feverFavicon, 0, len(r.icons))
+	for _, feed := range r.feeds {
+		if icon, ok := r.icons[feed.ID]; ok {
+			favicons = append(favicons, feverFavicon{
+				ID:   feed.IntID,
+				Data: icon.MimeType + ";base64," + base64.StdEncoding.EncodeToString(icon.Data),
+			})
+		}
+	}
+	return favicons
+}
+
+func (r *feverRequest) feedByIntID(id int64) (model.Feed, bool) {
+	for _, feed := range r.feeds {
+		if feed.IntID == id {
+			return feed, true
+		}
+	}
+	return model.Feed{}, false
+}
+
+func (r *feverRequest) folderByIntID(id int64) (model.Folder, bool) {
+	for _, folder := range r.folders {
+		if folder.IntID == id {
+			return folder, true
+		}
+	}
+	return model.Folder{}, false
+}
+
+// parseFeverIDs はカンマ区切りのIDを解析します。
+func parseFeverIDs(value string) ([]int64, error) {
+	var ids []int64
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// joinFeverIDs はIDをカンマ区切りの文字列に変換します。
+func joinFeverIDs(ids []int64) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(parts, ",")
+}
+
+// feverBool は真偽値を Fever API の 0/1 に変換します。
+func feverBool(v bool) int {
+	if v {
+		return 1
+	}
+	return 0
+}
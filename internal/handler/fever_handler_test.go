@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"feedapp/internal/model"
+	"feedapp/internal/service"
+)
+
+// feverAPIKey は testUser の Fever API の api_key です。
+var feverAPIKey = service.FeverAPIKey(testUser.Email, "fever-password")
+
+var (
+	feverFolders = []model.Folder{{ID: "folder-1", Name: "Tech", IntID: 3}}
+	feverFeeds   = []model.Feed{
+		{ID: "feed-1", Name: "Go Blog", URL: "https://go.dev/blog/feed.atom", FolderID: "folder-1", IntID: 10, LastUpdated: time.Unix(1751328000, 0)},
+		{ID: "feed-2", Name: "News", URL: "https://example.com/rss", IntID: 11, LastUpdated: time.Unix(1751320000, 0)},
+		{ID: "feed-3", Name: "Release Notes", URL: "https://example.com/releases", FolderID: "folder-1", IntID: 12},
+	}
+)
+
+// newFeverTest は testUser として認証される Fever API のテスト用ハンドラーとモックを作成します。
+func newFeverTest() (*FeverHandler, greaderMocks) {
+	gin.SetMode(gin.TestMode)
+	mocks := greaderMocks{
+		auth:     new(MockAuthService),
+		folders:  new(MockFolderService),
+		feeds:    new(MockFeedService),
+		articles: new(MockArticleService),
+		icons:    new(MockFeedIconService),
+	}
+	mocks.auth.On("AuthenticateFeverKey", feverAPIKey).Return(testUser, nil).Maybe()
+	mocks.folders.On("GetAllFolders", testUser.ID).Return(feverFolders, nil).Maybe()
+	mocks.feeds.On("GetAllFeeds", testUser.ID).Return(feverFeeds, nil).Maybe()
+	return NewFeverHandler(mocks.auth, mocks.folders, mocks.feeds, mocks.articles, mocks.icons), mocks
+}
+
+// callFever は query をクエリ文字列、form を api_key とともにフォームで送信し、レスポンスのJSONを返します。
+func callFever(t *testing.T, h *FeverHandler, query string, form url.Values) (int, map[string]any) {
+	if form == nil {
+		form = url.Values{}
+	}
+	if !form.Has("api_key") {
+		form.Set("api_key", feverAPIKey)
+	}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/fever/?"+query, strings.NewReader(form.Encode()))
+	c.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	h.API(c)
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return w.Code, body
+}
+
+func TestFeverHandler_Auth(t *testing.T) {
+	// 正常系: api_key で認証する
+	t.Run("should authenticate with api key", func(t *testing.T) {
+		h, mocks := newFeverTest()
+
+		code, body := callFever(t, h, "api", nil)
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, float64(3), body["api_version"])
+		assert.Equal(t, float64(1), body["auth"])
+		assert.Equal(t, float64(1751328000), body["last_refreshed_on_time"])
+		mocks.assertExpectations(t)
+	})
+
+	// 異常系: api_key が正しくない場合は auth を 0 とする
+	t.Run("should return auth 0 with invalid key", func(t *testing.T) {
+		h, mocks := newFeverTest()
+		mocks.auth.On("AuthenticateFeverKey", "wrong").Return(model.User{}, service.ErrUnauthenticated).Once()
+
+		code, body := callFever(t, h, "api&groups", url.Values{"api_key": {"wrong"}})
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, map[string]any{"api_version": float64(3), "auth": float64(0)}, body)
+		mocks.folders.AssertNotCalled(t, "GetAllFolders", mock.Anything)
+	})
+}
+
+func TestFeverHandler_GroupsAndFeeds(t *testing.T) {
+	h, mocks := newFeverTest()
+	icon := model.FeedIcon{FeedID: "feed-2", MimeType: "image/png", Data: []byte("png")}
+	mocks.icons.On("GetIcons", testUser.ID).Return([]model.FeedIcon{icon}, nil).Once()
+
+	code, body := callFever(t, h, "api&groups&feeds&favicons", nil)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []any{map[string]any{"id": float64(3), "title": "Tech"}}, body["groups"])
+	assert.Equal(t, []any{map[string]any{"group_id": float64(3), "feed_ids": "10,12"}}, body["feeds_groups"])
+	feeds := body["feeds"].([]any)
+	require.Len(t, feeds, 3)
+	assert.Equal(t, map[string]any{
+		"id": float64(10), "favicon_id": float64(0), "title": "Go Blog", "url": "https://go.dev/blog/feed.atom",
+		"site_url": "https://go.dev/blog/feed.atom", "is_spark": float64(0), "last_updated_on_time": float64(1751328000),
+	}, feeds[0])
+	assert.Equal(t, float64(11), feeds[1].(map[string]any)["favicon_id"])
+	assert.Equal(t, []any{map[string]any{"id": float64(11), "data": "image/png;base64,cG5n"}}, body["favicons"])
+	mocks.assertExpectations(t)
+}
+
+func TestFeverHandler_Items(t *testing.T) {
+	created := time.Unix(1751000000, 0)
+	articles := []model.Article{
+		{ID: "a", ItemID: 101, FeedID: "feed-1", Title: "First", Summary: "<p>summary</p>", URL: "https://go.dev/a", CreatedAt: created, IsLater: true},
+		{ID: "b", ItemID: 102, FeedID: "feed-2", Title: "Second", Content: "<p>content</p>", URL: "https://example.com/b", PublishedAt: created.Add(time.Hour), IsRead: true},
+	}
+
+	// 正常系: since_id より後の記事を古い順に取得する
+	t.Run("should return items since id", func(t *testing.T) {
+		h, mocks := newFeverTest()
+		mocks.articles.On("GetAllArticles", testUser.ID, model.ArticleFilter{Limit: 50, OldestFirst: true, Cursor: 100}).Return(articles, nil).Once()
+		mocks.articles.On("GetArticleItemIDs", testUser.ID, model.ArticleFilter{}).Return([]int64{102, 101, 100}, nil).Once()
+
+		code, body := callFever(t, h, "api&items&since_id=100", nil)
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, float64(3), body["total_items"])
+		assert.Equal(t, []any{
+			map[string]any{"id": float64(101), "feed_id": float64(10), "title": "First", "author": "", "html": "<p>summary</p>", "url": "https://go.dev/a", "is_saved": float64(1), "is_read": float64(0), "created_on_time": float64(1751000000)},
+			map[string]any{"id": float64(102), "feed_id": float64(11), "title": "Second", "author": "", "html": "<p>content</p>", "url": "https://example.com/b", "is_saved": float64(0), "is_read": float64(1), "created_on_time": float64(1751003600)},
+		}, body["items"])
+		mocks.assertExpectations(t)
+	})
+
+	// 正常系: max_id より前の記事を新しい順に取得する
+	t.Run("should return items before max id", func(t *testing.T) {
+		h, mocks := newFeverTest()
+		mocks.articles.On("GetAllArticles", testUser.ID, model.ArticleFilter{Limit: 50, Cursor: 103}).Return([]model.Article{}, nil).Once()
+		mocks.articles.On("GetArticleItemIDs", testUser.ID, model.ArticleFilter{}).Return([]int64{}, nil).Once()
+
+		code, body := callFever(t, h, "api&items&max_id=103", nil)
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []any{}, body["items"])
+		mocks.assertExpectations(t)
+	})
+
+	// 正常系: with_ids で指定した記事を取得する
+	t.Run("should return items with ids", func(t *testing.T) {
+		h, mocks := newFeverTest()
+		mocks.articles.On("GetAllArticles", testUser.ID, model.ArticleFilter{Limit: 50, OldestFirst: true, ItemIDs: []int64{101, 102}}).Return(articles, nil).Once()
+		mocks.articles.On("GetArticleItemIDs", testUser.ID, model.ArticleFilter{}).Return([]int64{102, 101}, nil).Once()
+
+		code, _ := callFever(t, h, "api&items&with_ids=101,102", nil)
+
+		assert.Equal(t, http.StatusOK, code)
+		mocks.assertExpectations(t)
+	})
+
+	// 異常系: since_id が数値でない場合
+	t.Run("should return 400 for invalid since_id", func(t *testing.T) {
+		h, _ := newFeverTest()
+
+		code, _ := callFever(t, h, "api&items&since_id=abc", nil)
+
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
+
+func TestFeverHandler_ItemIDs(t *testing.T) {
+	h, mocks := newFeverTest()
+	mocks.articles.On("GetArticleItemIDs", testUser.ID, model.ArticleFilter{IsRead: boolPointer(false)}).Return([]int64{3, 2, 1}, nil).Once()
+	mocks.articles.On("GetArticleItemIDs", testUser.ID, model.ArticleFilter{IsLater: boolPointer(true)}).Return([]int64{}, nil).Once()
+
+	code, body := callFever(t, h, "api&unread_item_ids&saved_item_ids", nil)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "3,2,1", body["unread_item_ids"])
+	assert.Equal(t, "", body["saved_item_ids"])
+	mocks.assertExpectations(t)
+}
+
+func TestFeverHandler_Mark(t *testing.T) {
+	// 正常系: 記事を保存済みにし、保存済みの記事IDを返す
+	t.Run("should mark item as saved", func(t *testing.T) {
+		h, mocks := newFeverTest()
+		mocks.articles.On("GetAllArticles", testUser.ID, model.ArticleFilter{ItemIDs: []int64{101}}).Return([]model.Article{{ID: "a", ItemID: 101}}, nil).Once()
+		mocks.articles.On("UpdateArticleStates", testUser.ID, []string{"a"}, service.ArticleStateUpdate{IsLater: boolPointer(true)}).Return(nil).Once()
+		mocks.articles.On("GetArticleItemIDs", testUser.ID, model.ArticleFilter{IsLater: boolPointer(true)}).Return([]int64{101}, nil).Once()
+
+		code, body := callFever(t, h, "api", url.Values{"mark": {"item"}, "as": {"saved"}, "id": {"101"}})
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "101", body["saved_item_ids"])
+		mocks.assertExpectations(t)
+	})
+
+	// 正常系: フィードの記事を指定日時より前まで既読にする
+	t.Run("should mark feed as read before", func(t *testing.T) {
+		h, mocks := newFeverTest()
+		mocks.articles.On("MarkArticlesRead", testUser.ID, model.ArticleFilter{FeedID: "feed-2", Until: time.Unix(1751000000, 0)}).Return(int64(2), nil).Once()
+		mocks.articles.On("GetArticleItemIDs", testUser.ID, model.ArticleFilter{IsRead: boolPointer(false)}).Return([]int64{5}, nil).Once()
+
+		code, body := callFever(t, h, "api", url.Values{"mark": {"feed"}, "as": {"read"}, "id": {"11"}, "before": {"1751000000"}})
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "5", body["unread_item_ids"])
+		mocks.assertExpectations(t)
+	})
+
+	// 正常系: グループの記事を既読にする。グループID 0 はすべての記事
+	t.Run("should mark group as read", func(t *testing.T) {
+		h, mocks := newFeverTest()
+		mocks.articles.On("MarkArticlesRead", testUser.ID, model.ArticleFilter{FolderID: "folder-1"}).Return(int64(1), nil).Once()
+		mocks.articles.On("MarkArticlesRead", testUser.ID, model.ArticleFilter{}).Return(int64(1), nil).Once()
+		mocks.articles.On("GetArticleItemIDs", testUser.ID, model.ArticleFilter{IsRead: boolPointer(false)}).Return([]int64{}, nil).Twice()
+
+		code, _ := callFever(t, h, "api", url.Values{"mark": {"group"}, "as": {"read"}, "id": {"3"}})
+		assert.Equal(t, http.StatusOK, code)
+		code, _ = callFever(t, h, "api", url.Values{"mark": {"group"}, "as": {"read"}, "id": {"0"}})
+		assert.Equal(t, http.StatusOK, code)
+		mocks.assertExpectations(t)
+	})
+
+	// 正常系: 存在しないフィードは無視する
+	t.Run("should ignore unknown feed", func(t *testing.T) {
+		h, mocks := newFeverTest()
+
+		code, _ := callFever(t, h, "api", url.Values{"mark": {"feed"}, "as": {"read"}, "id": {"999"}})
+
+		assert.Equal(t, http.StatusOK, code)
+		mocks.articles.AssertNotCalled(t, "MarkArticlesRead", mock.Anything, mock.Anything)
+	})
+
+	// 異常系: as が不正な場合
+	t.Run("should return 400 for invalid as", func(t *testing.T) {
+		h, _ := newFeverTest()
+
+		code, _ := callFever(t, h, "api", url.Values{"mark": {"item"}, "as": {"starred"}, "id": {"101"}})
+
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
@@ -50,7 +50,7 @@ func NewFolderHandler(s service.FolderService) *FolderHandler {
 // GetAllFolders はすべてのフォルダを取得します。
 //
 // GET /folders エンドポイントの実装です。
-// データベースに保存されているすべてのフォルダを取得し、JSON配列として返します。
+// ログイン中のユーザーが所有するすべてのフォルダを取得し、JSON配列として返します。
 //
 // HTTP Response:
 //   - 200 OK: フォルダ配列のJSON
@@ -60,7 +60,7 @@ func NewFolderHandler(s service.FolderService) *FolderHandler {
 //   - c: Ginのコンテキスト
 //
 //	@Summary		フォルダ一覧取得
-//	@Description	データベースに保存されているすべてのフォルダを取得します
+//	@Description	ログイン中のユーザーが所有するすべてのフォルダを取得します
 //	@Tags			folders
 //	@Accept			json
 //	@Produce		json
@@ -68,7 +68,11 @@ func NewFolderHandler(s service.FolderService) *FolderHandler {
 //	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
 //	@Router			/folders [get]
 func (h *FolderHandler) GetAllFolders(c *gin.Context) {
-	folders, err := h.folderService.GetAllFolders()
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	folders, err := h.folderService.GetAllFolders(user.ID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get folders"})
 		return
@@ -100,8 +104,12 @@ func (h *FolderHandler) GetAllFolders(c *gin.Context) {
 //	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
 //	@Router			/folders/{id} [get]
 func (h *FolderHandler) GetFolderByID(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
 	id := c.Param("id")
-	folder, err := h.folderService.GetFolderByID(id)
+	folder, err := h.folderService.GetFolderByID(user.ID, id)
 	if err != nil {
 		if errors.Is(err, service.ErrFolderNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Folder not found"})
@@ -121,7 +129,7 @@ func (h *FolderHandler) GetFolderByID(c *gin.Context) {
 //
 // Request Body:
 //   - name: フォルダ名（必須）
-//   - user_id: ユーザーID（省略可能、将来対応）
+//   - user_id: 無視されます（ログイン中のユーザーが所有者となります）
 //
 // HTTP Response:
 //   - 201 Created: 作成されたフォルダオブジェクトのJSON
@@ -142,13 +150,17 @@ func (h *FolderHandler) GetFolderByID(c *gin.Context) {
 //	@Failure		500		{object}	map[string]string	"サーバー内部エラー"
 //	@Router			/folders [post]
 func (h *FolderHandler) CreateFolder(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
 	var folder model.Folder
 	if err := c.ShouldBindJSON(&folder); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
 		return
 	}
 
-	createdFolder, err := h.folderService.CreateFolder(folder)
+	createdFolder, err := h.folderService.CreateFolder(user.ID, folder)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create folder"})
 		return
@@ -164,7 +176,7 @@ func (h *FolderHandler) CreateFolder(c *gin.Context) {
 //
 // Request Body:
 //   - name: フォルダ名（必須）
-//   - user_id: ユーザーID（省略可能、将来対応）
+//   - user_id: 無視されます（ログイン中のユーザーが所有者となります）
 //
 // HTTP Response:
 //   - 200 OK: 更新されたフォルダオブジェクトのJSON
@@ -188,6 +200,10 @@ func (h *FolderHandler) CreateFolder(c *gin.Context) {
 //	@Failure		500		{object}	map[string]string	"サーバー内部エラー"
 //	@Router			/folders/{id} [put]
 func (h *FolderHandler) UpdateFolder(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
 	id := c.Param("id")
 	var folder model.Folder
 	if err := c.ShouldBindJSON(&folder); err != nil {
@@ -196,7 +212,7 @@ func (h *FolderHandler) UpdateFolder(c *gin.Context) {
 	}
 
 	folder.ID = id
-	updatedFolder, err := h.folderService.UpdateFolder(id, folder)
+	updatedFolder, err := h.folderService.UpdateFolder(user.ID, id, folder)
 	if err != nil {
 		if errors.Is(err, service.ErrFolderNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Folder not found"})
@@ -233,8 +249,12 @@ func (h *FolderHandler) UpdateFolder(c *gin.Context) {
 //	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
 //	@Router			/folders/{id} [delete]
 func (h *FolderHandler) DeleteFolder(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
 	id := c.Param("id")
-	err := h.folderService.DeleteFolder(id)
+	err := h.folderService.DeleteFolder(user.ID, id)
 	if err != nil {
 		if errors.Is(err, service.ErrFolderNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Folder not found"})
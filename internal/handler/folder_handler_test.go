@@ -20,28 +20,28 @@ type MockFolderService struct {
 	mock.Mock
 }
 
-func (m *MockFolderService) GetAllFolders() ([]model.Folder, error) {
-	args := m.Called()
+func (m *MockFolderService) GetAllFolders(userID string) ([]model.Folder, error) {
+	args := m.Called(userID)
 	return args.Get(0).([]model.Folder), args.Error(1)
 }
 
-func (m *MockFolderService) GetFolderByID(id string) (model.Folder, error) {
-	args := m.Called(id)
+func (m *MockFolderService) GetFolderByID(userID, id string) (model.Folder, error) {
+	args := m.Called(userID, id)
 	return args.Get(0).(model.Folder), args.Error(1)
 }
 
-func (m *MockFolderService) CreateFolder(folder model.Folder) (model.Folder, error) {
-	args := m.Called(folder)
+func (m *MockFolderService) CreateFolder(userID string, folder model.Folder) (model.Folder, error) {
+	args := m.Called(userID, folder)
 	return args.Get(0).(model.Folder), args.Error(1)
 }
 
-func (m *MockFolderService) UpdateFolder(id string, folder model.Folder) (model.Folder, error) {
-	args := m.Called(id, folder)
+func (m *MockFolderService) UpdateFolder(userID, id string, folder model.Folder) (model.Folder, error) {
+	args := m.Called(userID, id, folder)
 	return args.Get(0).(model.Folder), args.Error(1)
 }
 
-func (m *MockFolderService) DeleteFolder(id string) error {
-	args := m.Called(id)
+func (m *MockFolderService) DeleteFolder(userID, id string) error {
+	args := m.Called(userID, id)
 	return args.Error(0)
 }
 
@@ -56,10 +56,11 @@ func TestFolderHandler_GetAllFolders(t *testing.T) {
 			{ID: "1", Name: "Folder 1"},
 			{ID: "2", Name: "Folder 2"},
 		}
-		mockService.On("GetAllFolders").Return(expectedFolders, nil).Once()
+		mockService.On("GetAllFolders", testUser.ID).Return(expectedFolders, nil).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		handler.GetAllFolders(c)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -72,10 +73,11 @@ func TestFolderHandler_GetAllFolders(t *testing.T) {
 
 	// 正常系: フォルダが0件の場合
 	t.Run("should return empty array if no folders", func(t *testing.T) {
-		mockService.On("GetAllFolders").Return([]model.Folder{}, nil).Once()
+		mockService.On("GetAllFolders", testUser.ID).Return([]model.Folder{}, nil).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		handler.GetAllFolders(c)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -85,10 +87,11 @@ func TestFolderHandler_GetAllFolders(t *testing.T) {
 
 	// 異常系: サービスエラー
 	t.Run("should return 500 if service error", func(t *testing.T) {
-		mockService.On("GetAllFolders").Return([]model.Folder{}, assert.AnError).Once()
+		mockService.On("GetAllFolders", testUser.ID).Return([]model.Folder{}, assert.AnError).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		handler.GetAllFolders(c)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
@@ -105,10 +108,11 @@ func TestFolderHandler_GetFolderByID(t *testing.T) {
 	// 正常系: フォルダが見つかる場合
 	t.Run("should return folder by ID", func(t *testing.T) {
 		expectedFolder := model.Folder{ID: "1", Name: "Folder 1"}
-		mockService.On("GetFolderByID", "1").Return(expectedFolder, nil).Once()
+		mockService.On("GetFolderByID", testUser.ID, "1").Return(expectedFolder, nil).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "1"}}
 		handler.GetFolderByID(c)
 
@@ -122,10 +126,11 @@ func TestFolderHandler_GetFolderByID(t *testing.T) {
 
 	// 異常系: フォルダが見つからない場合
 	t.Run("should return 404 if folder not found", func(t *testing.T) {
-		mockService.On("GetFolderByID", "nonexistent").Return(model.Folder{}, service.ErrFolderNotFound).Once()
+		mockService.On("GetFolderByID", testUser.ID, "nonexistent").Return(model.Folder{}, service.ErrFolderNotFound).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "nonexistent"}}
 		handler.GetFolderByID(c)
 
@@ -136,10 +141,11 @@ func TestFolderHandler_GetFolderByID(t *testing.T) {
 
 	// 異常系: サービスエラー
 	t.Run("should return 500 if service error", func(t *testing.T) {
-		mockService.On("GetFolderByID", "errorID").Return(model.Folder{}, assert.AnError).Once()
+		mockService.On("GetFolderByID", testUser.ID, "errorID").Return(model.Folder{}, assert.AnError).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "errorID"}}
 		handler.GetFolderByID(c)
 
@@ -158,10 +164,11 @@ func TestFolderHandler_CreateFolder(t *testing.T) {
 	t.Run("should create folder successfully", func(t *testing.T) {
 		newFolder := model.Folder{Name: "New Folder"}
 		createdFolder := model.Folder{ID: "3", Name: "New Folder"}
-		mockService.On("CreateFolder", newFolder).Return(createdFolder, nil).Once()
+		mockService.On("CreateFolder", testUser.ID, newFolder).Return(createdFolder, nil).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name":"New Folder"}`))
 		c.Request.Header.Set("Content-Type", "application/json")
 		handler.CreateFolder(c)
@@ -178,6 +185,7 @@ func TestFolderHandler_CreateFolder(t *testing.T) {
 	t.Run("should return 400 if invalid request body", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name":""}`))
 		c.Request.Header.Set("Content-Type", "application/json")
 		handler.CreateFolder(c)
@@ -189,10 +197,11 @@ func TestFolderHandler_CreateFolder(t *testing.T) {
 	// 異常系: サービスエラー
 	t.Run("should return 500 if service error", func(t *testing.T) {
 		newFolder := model.Folder{Name: "Error Folder"}
-		mockService.On("CreateFolder", newFolder).Return(model.Folder{}, assert.AnError).Once()
+		mockService.On("CreateFolder", testUser.ID, newFolder).Return(model.Folder{}, assert.AnError).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name":"Error Folder"}`))
 		c.Request.Header.Set("Content-Type", "application/json")
 		handler.CreateFolder(c)
@@ -211,10 +220,11 @@ func TestFolderHandler_UpdateFolder(t *testing.T) {
 	// 正常系: フォルダ更新成功
 	t.Run("should update folder successfully", func(t *testing.T) {
 		updatedFolder := model.Folder{ID: "1", Name: "Updated Folder"}
-		mockService.On("UpdateFolder", "1", updatedFolder).Return(updatedFolder, nil).Once()
+		mockService.On("UpdateFolder", testUser.ID, "1", updatedFolder).Return(updatedFolder, nil).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "1"}}
 		c.Request = httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(`{"name":"Updated Folder"}`))
 		c.Request.Header.Set("Content-Type", "application/json")
@@ -231,10 +241,11 @@ func TestFolderHandler_UpdateFolder(t *testing.T) {
 	// 異常系: フォルダが見つからない場合
 	t.Run("should return 404 if folder not found", func(t *testing.T) {
 		updatedFolder := model.Folder{ID: "nonexistent", Name: "Updated Folder"}
-		mockService.On("UpdateFolder", "nonexistent", updatedFolder).Return(model.Folder{}, service.ErrFolderNotFound).Once()
+		mockService.On("UpdateFolder", testUser.ID, "nonexistent", updatedFolder).Return(model.Folder{}, service.ErrFolderNotFound).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "nonexistent"}}
 		c.Request = httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(`{"name":"Updated Folder"}`))
 		c.Request.Header.Set("Content-Type", "application/json")
@@ -249,6 +260,7 @@ func TestFolderHandler_UpdateFolder(t *testing.T) {
 	t.Run("should return 400 if invalid request body", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "1"}}
 		c.Request = httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(`{"name":""}`))
 		c.Request.Header.Set("Content-Type", "application/json")
@@ -261,10 +273,11 @@ func TestFolderHandler_UpdateFolder(t *testing.T) {
 	// 異常系: サービスエラー
 	t.Run("should return 500 if service error", func(t *testing.T) {
 		updatedFolder := model.Folder{ID: "errorID", Name: "Error Folder"}
-		mockService.On("UpdateFolder", "errorID", updatedFolder).Return(model.Folder{}, assert.AnError).Once()
+		mockService.On("UpdateFolder", testUser.ID, "errorID", updatedFolder).Return(model.Folder{}, assert.AnError).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "errorID"}}
 		c.Request = httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(`{"name":"Error Folder"}`))
 		c.Request.Header.Set("Content-Type", "application/json")
@@ -283,10 +296,11 @@ func TestFolderHandler_DeleteFolder(t *testing.T) {
 
 	// 正常系: フォルダ削除成功
 	t.Run("should delete folder successfully", func(t *testing.T) {
-		mockService.On("DeleteFolder", "1").Return(nil).Once()
+		mockService.On("DeleteFolder", testUser.ID, "1").Return(nil).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "1"}}
 		handler.DeleteFolder(c)
 
@@ -295,10 +309,11 @@ func TestFolderHandler_DeleteFolder(t *testing.T) {
 
 	// 異常系: フォルダが見つからない場合
 	t.Run("should return 404 if folder not found", func(t *testing.T) {
-		mockService.On("DeleteFolder", "nonexistent").Return(service.ErrFolderNotFound).Once()
+		mockService.On("DeleteFolder", testUser.ID, "nonexistent").Return(service.ErrFolderNotFound).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "nonexistent"}}
 		handler.DeleteFolder(c)
 
@@ -309,10 +324,11 @@ func TestFolderHandler_DeleteFolder(t *testing.T) {
 
 	// 異常系: サービスエラー
 	t.Run("should return 500 if service error", func(t *testing.T) {
-		mockService.On("DeleteFolder", "errorID").Return(assert.AnError).Once()
+		mockService.On("DeleteFolder", testUser.ID, "errorID").Return(assert.AnError).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set(userContextKey, testUser)
 		c.Params = gin.Params{{Key: "id", Value: "errorID"}}
 		handler.DeleteFolder(c)
 
@@ -0,0 +1,773 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"feedapp/internal/model"
+	"feedapp/internal/service"
+)
+
+// Google Reader API のストリームIDとタグです。
+// ユーザー部分は "user/-/" に正規化して扱います。
+const (
+	greaderReadingList = "user/-/state/com.google/reading-list"
+	greaderRead        = "user/-/state/com.google/read"
+	greaderStarred     = "user/-/state/com.google/starred"
+	greaderKeptUnread  = "user/-/state/com.google/kept-unread"
+	greaderLabelPrefix = "user/-/label/"
+	greaderFeedPrefix  = "feed/"
+	greaderItemPrefix  = "tag:google.com,2005:reader/item/"
+)
+
+const (
+	// greaderDefaultCount は n パラメータが未指定の場合に返す記事の件数です。
+	greaderDefaultCount = 20
+	// greaderMaxItems は stream/contents・stream/items/contents で一度に返す記事の最大件数です。
+	greaderMaxItems = 1000
+	// greaderMaxItemIDs は stream/items/ids で一度に返す記事IDの最大件数です。
+	greaderMaxItemIDs = 10000
+)
+
+// errGReaderUnknownStream は対応していないストリームIDが指定された場合のエラーです。
+var errGReaderUnknownStream = errors.New("unknown stream")
+
+// GReaderHandler は Google Reader API 互換のHTTPリクエストを処理します。
+//
+// Reeder・FeedMe・NetNewsWire などのモバイルアプリから利用するためのAPIで、
+// フォルダをラベル、「後で見る」をスター付き（starred）として扱います。
+// 記事IDには整数の連番（Article.ItemID）を使用します。
+// プロトコルに合わせ、エラーはJSONではなくテキストで返します。
+type GReaderHandler struct {
+	authService    service.AuthService
+	folderService  service.FolderService
+	feedService    service.FeedService
+	articleService service.ArticleService
+}
+
+// NewGReaderHandler は新しい GReaderHandler インスタンスを作成します。
+func NewGReaderHandler(authService service.AuthService, folderService service.FolderService, feedService service.FeedService, articleService service.ArticleService) *GReaderHandler {
+	return &GReaderHandler{
+		authService:    authService,
+		folderService:  folderService,
+		feedService:    feedService,
+		articleService: articleService,
+	}
+}
+
+// ClientLogin はメールアドレスとパスワードでログインし、認証トークンを返します。
+//
+// Email・Passwd パラメータで認証し、`SID=...\nLSID=...\nAuth=<token>` 形式
+// （output=json の場合はJSON）で返します。Auth はセッショントークンです。
+// パスワードを持たない OpenID Connect のユーザーは、Passwd に個人APIトークンを
+// 指定してログインできます。この場合は APIトークンをそのまま Auth として返します。
+func (h *GReaderHandler) ClientLogin(c *gin.Context) {
+	email := strings.TrimSpace(c.Request.FormValue("Email"))
+	password := c.Request.FormValue("Passwd")
+	if email == "" || password == "" {
+		c.String(http.StatusUnauthorized, "Error=BadAuthentication\n")
+		return
+	}
+
+	var token string
+	var err error
+	if strings.HasPrefix(password, service.APITokenPrefix) {
+		var user model.User
+		user, err = h.authService.AuthenticateAPIToken(password)
+		if err == nil && !strings.EqualFold(user.Email, email) {
+			err = service.ErrInvalidCredentials
+		}
+		token = password
+	} else {
+		_, token, err = h.authService.Login(email, password)
+	}
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCredentials) || errors.Is(err, service.ErrUnauthenticated) {
+			c.String(http.StatusUnauthorized, "Error=BadAuthentication\n")
+			return
+		}
+		c.String(http.StatusInternalServerError, "Error=Unknown\n")
+		return
+	}
+
+	if c.Request.FormValue("output") == "json" {
+		c.JSON(http.StatusOK, gin.H{"SID": token, "LSID": token, "Auth": token})
+		return
+	}
+	c.String(http.StatusOK, "SID=%s\nLSID=%s\nAuth=%s\n", token, token, token)
+}
+
+// RequireAuth は `Authorization: GoogleLogin auth=<token>` ヘッダーで認証するミドルウェアです。
+// トークンには ClientLogin で発行したセッショントークン、または個人APIトークンを使用できます。
+func (h *GReaderHandler) RequireAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := googleLoginToken(c)
+		if !ok {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		var user model.User
+		var err error
+		if strings.HasPrefix(token, service.APITokenPrefix) {
+			user, err = h.authService.AuthenticateAPIToken(token)
+		} else {
+			user, err = h.authService.AuthenticateSession(token)
+		}
+		if err != nil {
+			if errors.Is(err, service.ErrUnauthenticated) {
+				c.AbortWithStatus(http.StatusUnauthorized)
+				return
+			}
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.Set(userContextKey, user)
+		c.Next()
+	}
+}
+
+// googleLoginToken は Authorization ヘッダーから GoogleLogin の auth トークンを取り出します。
+func googleLoginToken(c *gin.Context) (string, bool) {
+	scheme, params, found := strings.Cut(c.GetHeader("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "GoogleLogin") {
+		return "", false
+	}
+	key, token, found := strings.Cut(strings.TrimSpace(params), "=")
+	if !found || key != "auth" || token == "" {
+		return "", false
+	}
+	return token, true
+}
+
+// Token は更新系リクエストの T パラメータに使用するトークンを返します。
+//
+// 認証はヘッダーで行い Cookie を使用しないため、T パラメータは検証しません。
+// クライアントが要求するため、認証トークンから導出した値を返します。
+func (h *GReaderHandler) Token(c *gin.Context) {
+	token, _ := googleLoginToken(c)
+	sum := sha256.Sum256([]byte(token))
+	c.String(http.StatusOK, hex.EncodeToString(sum[:]))
+}
+
+// UserInfo はログイン中のユーザーの情報を返します。
+func (h *GReaderHandler) UserInfo(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	name := user.Name
+	if name == "" {
+		name = user.Email
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"userId":        user.ID,
+		"userName":      name,
+		"userProfileId": user.ID,
+		"userEmail":     user.Email,
+	})
+}
+
+// greaderCategory は購読が属するラベルです。
+type greaderCategory struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+}
+
+// greaderSubscription は subscription/list の購読です。
+type greaderSubscription struct {
+	ID         string            `json:"id"`
+	Title      string            `json:"title"`
+	Categories []greaderCategory `json:"categories"`
+	URL        string            `json:"url"`
+	HTMLURL    string            `json:"htmlUrl"`
+	IconURL    string            `json:"iconUrl"`
+}
+
+// SubscriptionList は購読しているフィードの一覧を返します。フォルダはラベルとして返します。
+func (h *GReaderHandler) SubscriptionList(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	feeds, err := h.feedService.GetAllFeeds(user.ID)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to get feeds")
+		return
+	}
+	folderNames, err := h.folderNames(user.ID)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to get folders")
+		return
+	}
+
+	subscriptions := make([]greaderSubscription, 0, len(feeds))
+	for _, feed := range feeds {
+		categories := []greaderCategory{}
+		if name, ok := folderNames[feed.FolderID]; ok {
+			categories = append(categories, greaderCategory{ID: greaderLabelPrefix + name, Label: name})
+		}
+		subscriptions = append(subscriptions, greaderSubscription{
+			ID:         greaderFeedPrefix + feed.ID,
+			Title:      feed.Name,
+			Categories: categories,
+			URL:        feed.URL,
+			HTMLURL:    feed.URL,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"subscriptions": subscriptions})
+}
+
+// greaderTag は tag/list のタグです。
+type greaderTag struct {
+	ID   string `json:"id"`
+	Type string `json:"type,omitempty"`
+}
+
+// TagList はタグの一覧を返します。スター付き（後で見る）とフォルダのラベルを返します。
+func (h *GReaderHandler) TagList(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	folders, err := h.folderService.GetAllFolders(user.ID)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to get folders")
+		return
+	}
+
+	tags := []greaderTag{{ID: greaderStarred}}
+	for _, folder := range folders {
+		tags = append(tags, greaderTag{ID: greaderLabelPrefix + folder.Name, Type: "folder"})
+	}
+	c.JSON(http.StatusOK, gin.H{"tags": tags})
+}
+
+// greaderLink は記事のリンクです。
+type greaderLink struct {
+	Href string `json:"href"`
+	Type string `json:"type,omitempty"`
+}
+
+// greaderContent は記事の本文です。
+type greaderContent struct {
+	Direction string `json:"direction"`
+	Content   string `json:"content"`
+}
+
+// greaderOrigin は記事の配信元フィードです。
+type greaderOrigin struct {
+	StreamID string `json:"streamId"`
+	Title    string `json:"title"`
+	HTMLURL  string `json:"htmlUrl"`
+}
+
+// greaderEnclosure は記事の添付メディアです。
+type greaderEnclosure struct {
+	Href   string `json:"href"`
+	Type   string `json:"type,omitempty"`
+	Length string `json:"length,omitempty"`
+}
+
+// greaderItem は stream/contents・stream/items/contents の記事です。
+type greaderItem struct {
+	ID            string             `json:"id"`
+	CrawlTimeMsec string             `json:"crawlTimeMsec"`
+	TimestampUsec string             `json:"timestampUsec"`
+	Published     int64              `json:"published"`
+	Updated       int64              `json:"updated"`
+	Title         string             `json:"title"`
+	Author        string             `json:"author,omitempty"`
+	Canonical     []greaderLink      `json:"canonical"`
+	Alternate     []greaderLink      `json:"alternate"`
+	Summary       greaderContent     `json:"summary"`
+	Categories    []string           `json:"categories"`
+	Origin        greaderOrigin      `json:"origin"`
+	Enclosure     []greaderEnclosure `json:"enclosure,omitempty"`
+}
+
+// greaderStream は stream/contents・stream/items/contents のレスポンスです。
+type greaderStream struct {
+	ID           string        `json:"id"`
+	Updated      int64         `json:"updated"`
+	Items        []greaderItem `json:"items"`
+	Continuation string        `json:"continuation,omitempty"`
+}
+
+// greaderItemRef は stream/items/ids の記事IDです。
+type greaderItemRef struct {
+	ID              string   `json:"id"`
+	DirectStreamIDs []string `json:"directStreamIds"`
+	TimestampUsec   string   `json:"timestampUsec"`
+}
+
+// StreamContents はストリームの記事を返します。
+//
+// ストリームIDはパス（/stream/contents/<streamId>）または s パラメータで指定します。
+// 対応するストリームは reading-list・starred・read・feed/<id>・user/-/label/<name> です。
+// n（件数）・r=o（古い順）・c（続きの取得）・xt/it（除外・限定する状態）・
+// ot/nt（取得日時の範囲、UNIX秒）パラメータで絞り込みます。
+func (h *GReaderHandler) StreamContents(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	streamID := strings.TrimPrefix(c.Param("streamId"), "/")
+	if streamID == "" {
+		streamID = c.Query("s")
+	}
+	if streamID == "" {
+		streamID = greaderReadingList
+	}
+	filter, ok := h.streamQuery(c, user.ID, streamID, greaderMaxItems)
+	if !ok {
+		return
+	}
+
+	articles, ok := h.getArticles(c, user.ID, filter)
+	if !ok {
+		return
+	}
+	items, err := h.items(user.ID, articles)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to get articles")
+		return
+	}
+	c.JSON(http.StatusOK, greaderStream{
+		ID:           streamID,
+		Updated:      time.Now().Unix(),
+		Items:        items,
+		Continuation: continuation(articles, filter.Limit),
+	})
+}
+
+// StreamItemIDs はストリームの記事IDを返します。パラメータは StreamContents と同じです。
+func (h *GReaderHandler) StreamItemIDs(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	streamID := c.Query("s")
+	if streamID == "" {
+		c.String(http.StatusBadRequest, "Missing stream id")
+		return
+	}
+	filter, ok := h.streamQuery(c, user.ID, streamID, greaderMaxItemIDs)
+	if !ok {
+		return
+	}
+
+	articles, ok := h.getArticles(c, user.ID, filter)
+	if !ok {
+		return
+	}
+	refs := make([]greaderItemRef, 0, len(articles))
+	for _, article := range articles {
+		refs = append(refs, greaderItemRef{
+			ID:              strconv.FormatInt(article.ItemID, 10),
+			DirectStreamIDs: []string{},
+			TimestampUsec:   strconv.FormatInt(articleTimestamp(article).UnixMicro(), 10),
+		})
+	}
+	response := gin.H{"itemRefs": refs}
+	if next := continuation(articles, filter.Limit); next != "" {
+		response["continuation"] = next
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// StreamItemContents は i パラメータで指定した記事を返します。
+// stream/items/ids で取得した記事IDの本文を取得するために使用します。
+func (h *GReaderHandler) StreamItemContents(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	itemIDs, ok := parseItemIDs(c)
+	if !ok {
+		return
+	}
+	if len(itemIDs) > greaderMaxItems {
+		c.String(http.StatusBadRequest, "Too many items")
+		return
+	}
+
+	articles, ok := h.getArticles(c, user.ID, model.ArticleFilter{ItemIDs: itemIDs})
+	if !ok {
+		return
+	}
+	items, err := h.items(user.ID, articles)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to get articles")
+		return
+	}
+	c.JSON(http.StatusOK, greaderStream{
+		ID:      greaderReadingList,
+		Updated: time.Now().Unix(),
+		Items:   items,
+	})
+}
+
+// EditTag は i パラメータで指定した記事に a パラメータのタグを付け、r パラメータのタグを外します。
+// 対応するタグは read（既読）・kept-unread（未読）・starred（後で見る）で、それ以外のタグは無視します。
+func (h *GReaderHandler) EditTag(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	itemIDs, ok := parseItemIDs(c)
+	if !ok {
+		return
+	}
+
+	var update service.ArticleStateUpdate
+	apply := func(tags []string, add bool) {
+		for _, tag := range tags {
+			switch normalizeStreamID(tag) {
+			case greaderRead:
+				update.IsRead = boolPointer(add)
+			case greaderKeptUnread:
+				if add {
+					update.IsRead = boolPointer(false)
+				}
+			case greaderStarred:
+				update.IsLater = boolPointer(add)
+			}
+		}
+	}
+	apply(c.Request.Form["a"], true)
+	apply(c.Request.Form["r"], false)
+	if update.IsRead == nil && update.IsLater == nil {
+		c.String(http.StatusOK, "OK")
+		return
+	}
+
+	articles, ok := h.getArticles(c, user.ID, model.ArticleFilter{ItemIDs: itemIDs})
+	if !ok {
+		return
+	}
+	ids := make([]string, 0, len(articles))
+	for _, article := range articles {
+		ids = append(ids, article.ID)
+	}
+	if err := h.articleService.UpdateArticleStates(user.ID, ids, update); err != nil {
+		c.String(http.StatusInternalServerError, "Failed to update articles")
+		return
+	}
+	c.String(http.StatusOK, "OK")
+}
+
+// MarkAllAsRead は s パラメータのストリームの記事をすべて既読にします。
+// ts パラメータ（UNIXマイクロ秒）を指定した場合は、それより前に取得した記事のみを既読にします。
+func (h *GReaderHandler) MarkAllAsRead(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	streamID := c.Request.FormValue("s")
+	if streamID == "" {
+		c.String(http.StatusBadRequest, "Missing stream id")
+		return
+	}
+	filter, ok := h.streamFilter(c, user.ID, streamID)
+	if !ok {
+		return
+	}
+	if ts := c.Request.FormValue("ts"); ts != "" {
+		usec, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil || usec < 0 {
+			c.String(http.StatusBadRequest, "Invalid ts parameter")
+			return
+		}
+		if usec > 0 {
+			filter.Until = time.UnixMicro(usec)
+		}
+	}
+
+	if _, err := h.articleService.MarkArticlesRead(user.ID, filter); err != nil {
+		if errors.Is(err, service.ErrFeedNotFound) || errors.Is(err, service.ErrFolderNotFound) {
+			c.String(http.StatusNotFound, "Stream not found")
+			return
+		}
+		c.String(http.StatusInternalServerError, "Failed to mark articles as read")
+		return
+	}
+	c.String(http.StatusOK, "OK")
+}
+
+// streamQuery はストリームIDとクエリパラメータを記事の絞り込み条件に変換します。
+// 不正なパラメータの場合はエラーレスポンスを返し、false を返します。
+func (h *GReaderHandler) streamQuery(c *gin.Context, userID, streamID string, maxCount int) (model.ArticleFilter, bool) {
+	filter, ok := h.streamFilter(c, userID, streamID)
+	if !ok {
+		return model.ArticleFilter{}, false
+	}
+
+	filter.Limit = greaderDefaultCount
+	if n := c.Query("n"); n != "" {
+		count, err := strconv.Atoi(n)
+		if err != nil || count <= 0 {
+			c.String(http.StatusBadRequest, "Invalid n parameter")
+			return model.ArticleFilter{}, false
+		}
+		filter.Limit = min(count, maxCount)
+	}
+	filter.OldestFirst = c.Query("r") == "o"
+	if cursor := c.Query("c"); cursor != "" {
+		itemID, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil || itemID <= 0 {
+			c.String(http.StatusBadRequest, "Invalid continuation")
+			return model.ArticleFilter{}, false
+		}
+		filter.Cursor = itemID
+	}
+	for _, param := range []string{"ot", "nt"} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || seconds < 0 {
+			c.String(http.StatusBadRequest, "Invalid %s parameter", param)
+			return model.ArticleFilter{}, false
+		}
+		if param == "ot" {
+			filter.Since = time.Unix(seconds, 0)
+		} else {
+			filter.Until = time.Unix(seconds, 0)
+		}
+	}
+
+	for _, tag := range c.QueryArray("xt") {
+		switch normalizeStreamID(tag) {
+		case greaderRead:
+			filter.IsRead = boolPointer(false)
+		case greaderStarred:
+			filter.IsLater = boolPointer(false)
+		}
+	}
+	for _, tag := range c.QueryArray("it") {
+		switch normalizeStreamID(tag) {
+		case greaderRead:
+			filter.IsRead = boolPointer(true)
+		case greaderStarred:
+			filter.IsLater = boolPointer(true)
+		}
+	}
+	return filter, true
+}
+
+// streamFilter はストリームIDを記事の絞り込み条件に変換します。
+// 対応していないストリームや存在しないラベルの場合はエラーレスポンスを返し、false を返します。
+func (h *GReaderHandler) streamFilter(c *gin.Context, userID, streamID string) (model.ArticleFilter, bool) {
+	filter, err := h.resolveStream(userID, normalizeStreamID(streamID))
+	if err != nil {
+		switch {
+		case errors.Is(err, errGReaderUnknownStream):
+			c.String(http.StatusBadRequest, "Unsupported stream")
+		case errors.Is(err, service.ErrFolderNotFound):
+			c.String(http.StatusNotFound, "Stream not found")
+		default:
+			c.String(http.StatusInternalServerError, "Failed to get folders")
+		}
+		return model.ArticleFilter{}, false
+	}
+	return filter, true
+}
+
+func (h *GReaderHandler) resolveStream(userID, streamID string) (model.ArticleFilter, error) {
+	switch {
+	case streamID == greaderReadingList:
+		return model.ArticleFilter{}, nil
+	case streamID == greaderStarred:
+		return model.ArticleFilter{IsLater: boolPointer(true)}, nil
+	case streamID == greaderRead:
+		return model.ArticleFilter{IsRead: boolPointer(true)}, nil
+	case strings.HasPrefix(streamID, greaderFeedPrefix) && len(streamID) > len(greaderFeedPrefix):
+		return model.ArticleFilter{FeedID: strings.TrimPrefix(streamID, greaderFeedPrefix)}, nil
+	case strings.HasPrefix(streamID, greaderLabelPrefix):
+		name := strings.TrimPrefix(streamID, greaderLabelPrefix)
+		folders, err := h.folderService.GetAllFolders(userID)
+		if err != nil {
+			return model.ArticleFilter{}, err
+		}
+		for _, folder := range folders {
+			if folder.Name == name {
+				return model.ArticleFilter{FolderID: folder.ID}, nil
+			}
+		}
+		return model.ArticleFilter{}, service.ErrFolderNotFound
+	}
+	return model.ArticleFilter{}, errGReaderUnknownStream
+}
+
+// getArticles は記事を取得します。取得に失敗した場合はエラーレスポンスを返し、false を返します。
+func (h *GReaderHandler) getArticles(c *gin.Context, userID string, filter model.ArticleFilter) ([]model.Article, bool) {
+	articles, err := h.articleService.GetAllArticles(userID, filter)
+	if err != nil {
+		if errors.Is(err, service.ErrFeedNotFound) || errors.Is(err, service.ErrFolderNotFound) {
+			c.String(http.StatusNotFound, "Stream not found")
+			return nil, false
+		}
+		log.Printf("Failed to get articles for Google Reader API: %v", err)
+		c.String(http.StatusInternalServerError, "Failed to get articles")
+		return nil, false
+	}
+	return articles, true
+}
+
+// items は記事を Google Reader API の形式に変換します。
+// 配信元フィードの名前とラベルを付けるため、ユーザーの購読とフォルダを参照します。
+func (h *GReaderHandler) items(userID string, articles []model.Article) ([]greaderItem, error) {
+	items := make([]greaderItem, 0, len(articles))
+	if len(articles) == 0 {
+		return items, nil
+	}
+	feeds, err := h.feedService.GetAllFeeds(userID)
+	if err != nil {
+		return nil, err
+	}
+	folderNames, err := h.folderNames(userID)
+	if err != nil {
+		return nil, err
+	}
+	feedsByID := make(map[string]model.Feed, len(feeds))
+	for _, feed := range feeds {
+		feedsByID[feed.ID] = feed
+	}
+
+	for _, article := range articles {
+		feed := feedsByID[article.FeedID]
+		categories := []string{greaderReadingList}
+		if article.IsRead {
+			categories = append(categories, greaderRead)
+		}
+		if article.IsLater {
+			categories = append(categories, greaderStarred)
+		}
+		if name, ok := folderNames[feed.FolderID]; ok {
+			categories = append(categories, greaderLabelPrefix+name)
+		}
+
+		content := article.Content
+		if content == "" {
+			content = article.Summary
+		}
+		timestamp := articleTimestamp(article)
+		item := greaderItem{
+			ID:            fmt.Sprintf("%s%016x", greaderItemPrefix, uint64(article.ItemID)),
+			CrawlTimeMsec: strconv.FormatInt(article.CreatedAt.UnixMilli(), 10),
+			TimestampUsec: strconv.FormatInt(timestamp.UnixMicro(), 10),
+			Published:     timestamp.Unix(),
+			Updated:       timestamp.Unix(),
+			Title:         article.Title,
+			Author:        article.Author,
+			Canonical:     []greaderLink{{Href: article.URL}},
+			Alternate:     []greaderLink{{Href: article.URL, Type: "text/html"}},
+			Summary:       greaderContent{Direction: "ltr", Content: content},
+			Categories:    categories,
+			Origin: greaderOrigin{
+				StreamID: greaderFeedPrefix + article.FeedID,
+				Title:    feed.Name,
+				HTMLURL:  feed.URL,
+			},
+		}
+		for _, enclosure := range article.Enclosures {
+			e := greaderEnclosure{Href: enclosure.URL, Type: enclosure.MimeType}
+			if enclosure.Length > 0 {
+				e.Length = strconv.FormatInt(enclosure.Length, 10)
+			}
+			item.Enclosure = append(item.Enclosure, e)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// folderNames はユーザーのフォルダ名をフォルダIDごとに返します。
+func (h *GReaderHandler) folderNames(userID string) (map[string]string, error) {
+	folders, err := h.folderService.GetAllFolders(userID)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(folders))
+	for _, folder := range folders {
+		names[folder.ID] = folder.Name
+	}
+	return names, nil
+}
+
+// parseItemIDs は i パラメータの記事IDを連番に変換します。
+// 記事IDは長い形式（tag:google.com,2005:reader/item/<16進数>）と10進数の短い形式のどちらも受け付けます。
+// 記事IDがない、または不正な場合はエラーレスポンスを返し、false を返します。
+func parseItemIDs(c *gin.Context) ([]int64, bool) {
+	if err := c.Request.ParseForm(); err != nil {
+		c.String(http.StatusBadRequest, "Invalid request")
+		return nil, false
+	}
+	values := c.Request.Form["i"]
+	if len(values) == 0 {
+		c.String(http.StatusBadRequest, "Missing item id")
+		return nil, false
+	}
+	itemIDs := make([]int64, 0, len(values))
+	for _, value := range values {
+		itemID, err := parseItemID(value)
+		if err != nil {
+			c.String(http.StatusBadRequest, "Invalid item id")
+			return nil, false
+		}
+		itemIDs = append(itemIDs, itemID)
+	}
+	return itemIDs, true
+}
+
+func parseItemID(value string) (int64, error) {
+	if hexID, found := strings.CutPrefix(value, greaderItemPrefix); found {
+		id, err := strconv.ParseUint(hexID, 16, 64)
+		return int64(id), err
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// normalizeStreamID はストリームIDやタグのユーザー部分（user/<userId>/）を "user/-/" に置き換えます。
+func normalizeStreamID(streamID string) string {
+	rest, found := strings.CutPrefix(streamID, "user/")
+	if !found {
+		return streamID
+	}
+	if _, path, found := strings.Cut(rest, "/"); found {
+		return "user/-/" + path
+	}
+	return streamID
+}
+
+// continuation は続きの記事がある可能性がある場合に、次のページの取得に使用する値を返します。
+func continuation(articles []model.Article, limit int) string {
+	if limit <= 0 || len(articles) < limit {
+		return ""
+	}
+	return strconv.FormatInt(articles[len(articles)-1].ItemID, 10)
+}
+
+// articleTimestamp は記事の公開日時を返します。公開日時が不明な場合は作成日時を返します。
+func articleTimestamp(article model.Article) time.Time {
+	if article.PublishedAt.IsZero() {
+		return article.CreatedAt
+	}
+	return article.PublishedAt
+}
+
+func boolPointer(v bool) *bool {
+	return &v
+}
@@ -0,0 +1,436 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"feedapp/internal/model"
+	"feedapp/internal/service"
+)
+
+// greaderMocks は GReaderHandler が使用するサービスのモックです。
+type greaderMocks struct {
+	auth     *MockAuthService
+	folders  *MockFolderService
+	feeds    *MockFeedService
+	articles *MockArticleService
+	icons    *MockFeedIconService // Fever API のみ
+}
+
+func (m greaderMocks) assertExpectations(t *testing.T) {
+	m.auth.AssertExpectations(t)
+	m.folders.AssertExpectations(t)
+	m.feeds.AssertExpectations(t)
+	m.articles.AssertExpectations(t)
+	if m.icons != nil {
+		m.icons.AssertExpectations(t)
+	}
+}
+
+// newGReaderRouter は main.go と同じ構成で Google Reader API のルーターを作成します。
+// リクエストは "GoogleLogin auth=session-token" で testUser として認証されます。
+func newGReaderRouter(t *testing.T) (*gin.Engine, greaderMocks) {
+	gin.SetMode(gin.TestMode)
+	mocks := greaderMocks{
+		auth:     new(MockAuthService),
+		folders:  new(MockFolderService),
+		feeds:    new(MockFeedService),
+		articles: new(MockArticleService),
+	}
+	h := NewGReaderHandler(mocks.auth, mocks.folders, mocks.feeds, mocks.articles)
+	mocks.auth.On("AuthenticateSession", "session-token").Return(testUser, nil).Maybe()
+
+	r := gin.New()
+	r.POST("/accounts/ClientLogin", h.ClientLogin)
+	greader := r.Group("/reader/api/0")
+	greader.Use(h.RequireAuth())
+	greader.GET("/token", h.Token)
+	greader.GET("/subscription/list", h.SubscriptionList)
+	greader.GET("/tag/list", h.TagList)
+	greader.GET("/stream/contents/*streamId", h.StreamContents)
+	greader.GET("/stream/items/ids", h.StreamItemIDs)
+	greader.POST("/stream/items/contents", h.StreamItemContents)
+	greader.POST("/edit-tag", h.EditTag)
+	greader.POST("/mark-all-as-read", h.MarkAllAsRead)
+	return r, mocks
+}
+
+func greaderRequest(method, target string, form url.Values) *http.Request {
+	var req *http.Request
+	if form != nil {
+		req = httptest.NewRequest(method, target, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		req = httptest.NewRequest(method, target, nil)
+	}
+	req.Header.Set("Authorization", "GoogleLogin auth=session-token")
+	return req
+}
+
+var (
+	greaderFolders = []model.Folder{{ID: "folder-1", Name: "Tech"}}
+	greaderFeeds   = []model.Feed{
+		{ID: "feed-1", Name: "Go Blog", URL: "https://go.dev/blog/feed.atom", FolderID: "folder-1"},
+		{ID: "feed-2", Name: "News", URL: "https://example.com/rss"},
+	}
+)
+
+func TestGReaderHandler_ClientLogin(t *testing.T) {
+	// 正常系: パスワードでログインし、セッショントークンを返す
+	t.Run("should return session token", func(t *testing.T) {
+		router, mocks := newGReaderRouter(t)
+		mocks.auth.On("Login", "user@example.com", "password123").Return(testUser, "session-token", nil).Once()
+
+		w := httptest.NewRecorder()
+		form := url.Values{"Email": {"user@example.com"}, "Passwd": {"password123"}}
+		router.ServeHTTP(w, greaderRequest(http.MethodPost, "/accounts/ClientLogin", form))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "SID=session-token\nLSID=session-token\nAuth=session-token\n", w.Body.String())
+		mocks.assertExpectations(t)
+	})
+
+	// 正常系: パスワードの代わりにAPIトークンでログインする
+	t.Run("should accept api token as password", func(t *testing.T) {
+		router, mocks := newGReaderRouter(t)
+		mocks.auth.On("AuthenticateAPIToken", "fa_token").Return(testUser, nil).Once()
+
+		w := httptest.NewRecorder()
+		form := url.Values{"Email": {"USER@example.com"}, "Passwd": {"fa_token"}, "output": {"json"}}
+		router.ServeHTTP(w, greaderRequest(http.MethodPost, "/accounts/ClientLogin", form))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"SID": "fa_token", "LSID": "fa_token", "Auth": "fa_token"}`, w.Body.String())
+		mocks.assertExpectations(t)
+	})
+
+	// 異常系: APIトークンの所有者とメールアドレスが一致しない場合
+	t.Run("should reject api token of another user", func(t *testing.T) {
+		router, mocks := newGReaderRouter(t)
+		mocks.auth.On("AuthenticateAPIToken", "fa_token").Return(testUser, nil).Once()
+
+		w := httptest.NewRecorder()
+		form := url.Values{"Email": {"other@example.com"}, "Passwd": {"fa_token"}}
+		router.ServeHTTP(w, greaderRequest(http.MethodPost, "/accounts/ClientLogin", form))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, "Error=BadAuthentication\n", w.Body.String())
+	})
+
+	// 異常系: パスワードが正しくない場合
+	t.Run("should return 401 with invalid credentials", func(t *testing.T) {
+		router, mocks := newGReaderRouter(t)
+		mocks.auth.On("Login", "user@example.com", "wrong").Return(model.User{}, "", service.ErrInvalidCredentials).Once()
+
+		w := httptest.NewRecorder()
+		form := url.Values{"Email": {"user@example.com"}, "Passwd": {"wrong"}}
+		router.ServeHTTP(w, greaderRequest(http.MethodPost, "/accounts/ClientLogin", form))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		mocks.assertExpectations(t)
+	})
+}
+
+func TestGReaderHandler_RequireAuth(t *testing.T) {
+	// 異常系: Authorization ヘッダーがない場合
+	t.Run("should return 401 without header", func(t *testing.T) {
+		router, _ := newGReaderRouter(t)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/reader/api/0/token", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	// 異常系: セッションが無効な場合
+	t.Run("should return 401 with expired session", func(t *testing.T) {
+		router, mocks := newGReaderRouter(t)
+		mocks.auth.On("AuthenticateSession", "expired").Return(model.User{}, service.ErrUnauthenticated).Once()
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/reader/api/0/token", nil)
+		req.Header.Set("Authorization", "GoogleLogin auth=expired")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		mocks.assertExpectations(t)
+	})
+
+	// 正常系: APIトークンで認証する
+	t.Run("should authenticate with api token", func(t *testing.T) {
+		router, mocks := newGReaderRouter(t)
+		mocks.auth.On("AuthenticateAPIToken", "fa_token").Return(testUser, nil).Once()
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/reader/api/0/token", nil)
+		req.Header.Set("Authorization", "GoogleLogin auth=fa_token")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotEmpty(t, w.Body.String())
+		mocks.assertExpectations(t)
+	})
+}
+
+func TestGReaderHandler_SubscriptionList(t *testing.T) {
+	router, mocks := newGReaderRouter(t)
+	mocks.feeds.On("GetAllFeeds", testUser.ID).Return(greaderFeeds, nil).Once()
+	mocks.folders.On("GetAllFolders", testUser.ID).Return(greaderFolders, nil).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, greaderRequest(http.MethodGet, "/reader/api/0/subscription/list?output=json", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"subscriptions": [
+		{"id": "feed/feed-1", "title": "Go Blog", "categories": [{"id": "user/-/label/Tech", "label": "Tech"}], "url": "https://go.dev/blog/feed.atom", "htmlUrl": "https://go.dev/blog/feed.atom", "iconUrl": ""},
+		{"id": "feed/feed-2", "title": "News", "categories": [], "url": "https://example.com/rss", "htmlUrl": "https://example.com/rss", "iconUrl": ""}
+	]}`, w.Body.String())
+	mocks.assertExpectations(t)
+}
+
+func TestGReaderHandler_TagList(t *testing.T) {
+	router, mocks := newGReaderRouter(t)
+	mocks.folders.On("GetAllFolders", testUser.ID).Return(greaderFolders, nil).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, greaderRequest(http.MethodGet, "/reader/api/0/tag/list?output=json", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"tags": [
+		{"id": "user/-/state/com.google/starred"},
+		{"id": "user/-/label/Tech", "type": "folder"}
+	]}`, w.Body.String())
+	mocks.assertExpectations(t)
+}
+
+func TestGReaderHandler_StreamContents(t *testing.T) {
+	published := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	article := model.Article{
+		ID: "article-1", ItemID: 42, FeedID: "feed-1", Title: "Go 1.25", URL: "https://go.dev/blog/go1.25",
+		Content: "<p>Released</p>", Author: "Go Team", PublishedAt: published, CreatedAt: published.Add(time.Hour),
+		IsRead: true, IsLater: true,
+	}
+
+	// 正常系: 未読の記事を古い順に取得する
+	t.Run("should map reading list and parameters to filter", func(t *testing.T) {
+		router, mocks := newGReaderRouter(t)
+		filter := model.ArticleFilter{IsRead: boolPointer(false), OldestFirst: true, Cursor: 100, Since: time.Unix(1700000000, 0), Limit: 2}
+		mocks.articles.On("GetAllArticles", testUser.ID, filter).Return([]model.Article{article}, nil).Once()
+		mocks.feeds.On("GetAllFeeds", testUser.ID).Return(greaderFeeds, nil).Once()
+		mocks.folders.On("GetAllFolders", testUser.ID).Return(greaderFolders, nil).Once()
+
+		w := httptest.NewRecorder()
+		target := "/reader/api/0/stream/contents/user/-/state/com.google/reading-list?n=2&r=o&c=100&ot=1700000000&xt=user/-/state/com.google/read"
+		router.ServeHTTP(w, greaderRequest(http.MethodGet, target, nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"id":"tag:google.com,2005:reader/item/000000000000002a"`)
+		assert.Contains(t, w.Body.String(), `"categories":["user/-/state/com.google/reading-list","user/-/state/com.google/read","user/-/state/com.google/starred","user/-/label/Tech"]`)
+		assert.Contains(t, w.Body.String(), `"origin":{"streamId":"feed/feed-1","title":"Go Blog","htmlUrl":"https://go.dev/blog/feed.atom"}`)
+		assert.Contains(t, w.Body.String(), `"timestampUsec":"1751371200000000"`)
+		assert.NotContains(t, w.Body.String(), "continuation")
+		mocks.assertExpectations(t)
+	})
+
+	// 正常系: スター付きは「後で見る」、ラベルはフォルダとして絞り込む
+	t.Run("should map starred and label streams", func(t *testing.T) {
+		router, mocks := newGReaderRouter(t)
+		mocks.articles.On("GetAllArticles", testUser.ID, model.ArticleFilter{IsLater: boolPointer(true), Limit: greaderDefaultCount}).Return([]model.Article{}, nil).Once()
+		mocks.folders.On("GetAllFolders", testUser.ID).Return(greaderFolders, nil).Once()
+		mocks.articles.On("GetAllArticles", testUser.ID, model.ArticleFilter{FolderID: "folder-1", Limit: greaderDefaultCount}).Return([]model.Article{}, nil).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, greaderRequest(http.MethodGet, "/reader/api/0/stream/contents/user%2F-%2Fstate%2Fcom.google%2Fstarred", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"id":"user/-/state/com.google/starred"`)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, greaderRequest(http.MethodGet, "/reader/api/0/stream/contents/user/1234/label/Tech", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		mocks.assertExpectations(t)
+	})
+
+	// 異常系: 購読していないフィードの場合
+	t.Run("should return 404 for unknown feed", func(t *testing.T) {
+		router, mocks := newGReaderRouter(t)
+		mocks.articles.On("GetAllArticles", testUser.ID, model.ArticleFilter{FeedID: "other", Limit: greaderDefaultCount}).Return([]model.Article(nil), service.ErrFeedNotFound).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, greaderRequest(http.MethodGet, "/reader/api/0/stream/contents/feed/other", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mocks.assertExpectations(t)
+	})
+
+	// 異常系: 存在しないラベルの場合
+	t.Run("should return 404 for unknown label", func(t *testing.T) {
+		router, mocks := newGReaderRouter(t)
+		mocks.folders.On("GetAllFolders", testUser.ID).Return(greaderFolders, nil).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, greaderRequest(http.MethodGet, "/reader/api/0/stream/contents/user/-/label/Unknown", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mocks.assertExpectations(t)
+	})
+
+	// 異常系: 件数が不正な場合
+	t.Run("should return 400 for invalid count", func(t *testing.T) {
+		router, _ := newGReaderRouter(t)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, greaderRequest(http.MethodGet, "/reader/api/0/stream/contents/user/-/state/com.google/reading-list?n=abc", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestGReaderHandler_StreamItemIDs(t *testing.T) {
+	router, mocks := newGReaderRouter(t)
+	created := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	articles := []model.Article{{ID: "a", ItemID: 9, CreatedAt: created}, {ID: "b", ItemID: 7, CreatedAt: created}}
+	mocks.articles.On("GetAllArticles", testUser.ID, model.ArticleFilter{FeedID: "feed-1", Limit: 2}).Return(articles, nil).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, greaderRequest(http.MethodGet, "/reader/api/0/stream/items/ids?s=feed/feed-1&n=2", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"itemRefs": [
+			{"id": "9", "directStreamIds": [], "timestampUsec": "1751328000000000"},
+			{"id": "7", "directStreamIds": [], "timestampUsec": "1751328000000000"}
+		],
+		"continuation": "7"
+	}`, w.Body.String())
+	mocks.assertExpectations(t)
+}
+
+func TestGReaderHandler_StreamItemContents(t *testing.T) {
+	// 正常系: 長い形式と短い形式の記事IDを受け付ける
+	t.Run("should accept long and short item ids", func(t *testing.T) {
+		router, mocks := newGReaderRouter(t)
+		articles := []model.Article{{ID: "a", ItemID: 42, FeedID: "feed-2", Title: "Hello"}}
+		mocks.articles.On("GetAllArticles", testUser.ID, model.ArticleFilter{ItemIDs: []int64{42, 7}}).Return(articles, nil).Once()
+		mocks.feeds.On("GetAllFeeds", testUser.ID).Return(greaderFeeds, nil).Once()
+		mocks.folders.On("GetAllFolders", testUser.ID).Return(greaderFolders, nil).Once()
+
+		w := httptest.NewRecorder()
+		form := url.Values{"i": {"tag:google.com,2005:reader/item/000000000000002a", "7"}}
+		router.ServeHTTP(w, greaderRequest(http.MethodPost, "/reader/api/0/stream/items/contents", form))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"title":"Hello"`)
+		mocks.assertExpectations(t)
+	})
+
+	// 異常系: 記事IDが不正な場合
+	t.Run("should return 400 for invalid item id", func(t *testing.T) {
+		router, _ := newGReaderRouter(t)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, greaderRequest(http.MethodPost, "/reader/api/0/stream/items/contents", url.Values{"i": {"not-a-number"}}))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestGReaderHandler_EditTag(t *testing.T) {
+	// 正常系: 既読にしてスターを外す
+	t.Run("should update read and later state", func(t *testing.T) {
+		router, mocks := newGReaderRouter(t)
+		mocks.articles.On("GetAllArticles", testUser.ID, model.ArticleFilter{ItemIDs: []int64{1, 2}}).Return([]model.Article{{ID: "a", ItemID: 1}, {ID: "b", ItemID: 2}}, nil).Once()
+		update := service.ArticleStateUpdate{IsRead: boolPointer(true), IsLater: boolPointer(false)}
+		mocks.articles.On("UpdateArticleStates", testUser.ID, []string{"a", "b"}, update).Return(nil).Once()
+
+		w := httptest.NewRecorder()
+		form := url.Values{"i": {"1", "2"}, "a": {"user/-/state/com.google/read"}, "r": {"user/-/state/com.google/starred"}, "T": {"token"}}
+		router.ServeHTTP(w, greaderRequest(http.MethodPost, "/reader/api/0/edit-tag", form))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "OK", w.Body.String())
+		mocks.assertExpectations(t)
+	})
+
+	// 正常系: kept-unread で未読に戻す
+	t.Run("should mark as unread with kept-unread", func(t *testing.T) {
+		router, mocks := newGReaderRouter(t)
+		mocks.articles.On("GetAllArticles", testUser.ID, model.ArticleFilter{ItemIDs: []int64{1}}).Return([]model.Article{{ID: "a", ItemID: 1}}, nil).Once()
+		mocks.articles.On("UpdateArticleStates", testUser.ID, []string{"a"}, service.ArticleStateUpdate{IsRead: boolPointer(false)}).Return(nil).Once()
+
+		w := httptest.NewRecorder()
+		form := url.Values{"i": {"1"}, "a": {"user/1234/state/com.google/kept-unread"}, "r": {"user/1234/state/com.google/read"}}
+		router.ServeHTTP(w, greaderRequest(http.MethodPost, "/reader/api/0/edit-tag", form))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mocks.assertExpectations(t)
+	})
+
+	// 正常系: 対応していないタグは無視する
+	t.Run("should ignore unsupported tags", func(t *testing.T) {
+		router, mocks := newGReaderRouter(t)
+
+		w := httptest.NewRecorder()
+		form := url.Values{"i": {"1"}, "a": {"user/-/label/Tech"}}
+		router.ServeHTTP(w, greaderRequest(http.MethodPost, "/reader/api/0/edit-tag", form))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mocks.articles.AssertNotCalled(t, "UpdateArticleStates", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	// 異常系: 記事IDがない場合
+	t.Run("should return 400 without item id", func(t *testing.T) {
+		router, _ := newGReaderRouter(t)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, greaderRequest(http.MethodPost, "/reader/api/0/edit-tag", url.Values{"a": {"user/-/state/com.google/read"}}))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestGReaderHandler_MarkAllAsRead(t *testing.T) {
+	// 正常系: 指定日時より前のフィードの記事を既読にする
+	t.Run("should mark feed articles before ts as read", func(t *testing.T) {
+		router, mocks := newGReaderRouter(t)
+		filter := model.ArticleFilter{FeedID: "feed-1", Until: time.UnixMicro(1751328000000000)}
+		mocks.articles.On("MarkArticlesRead", testUser.ID, filter).Return(int64(3), nil).Once()
+
+		w := httptest.NewRecorder()
+		form := url.Values{"s": {"feed/feed-1"}, "ts": {"1751328000000000"}}
+		router.ServeHTTP(w, greaderRequest(http.MethodPost, "/reader/api/0/mark-all-as-read", form))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "OK", w.Body.String())
+		mocks.assertExpectations(t)
+	})
+
+	// 異常系: 購読していないフィードの場合
+	t.Run("should return 404 for unknown feed", func(t *testing.T) {
+		router, mocks := newGReaderRouter(t)
+		mocks.articles.On("MarkArticlesRead", testUser.ID, model.ArticleFilter{FeedID: "other"}).Return(int64(0), service.ErrFeedNotFound).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, greaderRequest(http.MethodPost, "/reader/api/0/mark-all-as-read", url.Values{"s": {"feed/other"}}))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mocks.assertExpectations(t)
+	})
+
+	// 異常系: ストリームが未指定の場合
+	t.Run("should return 400 without stream", func(t *testing.T) {
+		router, _ := newGReaderRouter(t)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, greaderRequest(http.MethodPost, "/reader/api/0/mark-all-as-read", url.Values{}))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
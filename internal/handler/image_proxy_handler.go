@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"feedapp/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// imageContentSecurityPolicy は画像プロキシの応答に適用する Content-Security-Policy です。
+// 画像を直接開いた場合も、このサーバーのオリジンでスクリプトなどを実行させないようにします。
+const imageContentSecurityPolicy = "default-src 'none'; sandbox"
+
+// ImageProxyHandler は記事中の画像を中継する画像プロキシのHTTPリクエストを処理します。
+//
+// サポートするエンドポイント:
+//   - GET /proxy/image?url={url}&sig={sig} - 署名付きURLの画像の取得
+type ImageProxyHandler struct {
+	imageProxyService service.ImageProxyService
+}
+
+// NewImageProxyHandler は新しい ImageProxyHandler インスタンスを作成します。
+func NewImageProxyHandler(s service.ImageProxyService) *ImageProxyHandler {
+	return &ImageProxyHandler{
+		imageProxyService: s,
+	}
+}
+
+// GetImage は署名付きURLの画像を取得して返します。
+//
+//	@Summary		画像プロキシ
+//	@Description	記事の本文・概要・画像URLの画像は、このエンドポイントの署名付きURLに書き換えて返されます。
+//	@Description	サーバーが画像を取得してキャッシュし、読者のブラウザが画像の配信元へ直接アクセスしないようにします。
+//	@Description	<img> 要素から読み込めるよう認証は不要で、署名によって記事中の画像以外の取得を拒否します
+//	@Tags			articles
+//	@Produce		image/jpeg,image/png,image/gif,image/webp
+//	@Param			url	query		string				true	"画像の元のURL"
+//	@Param			sig	query		string				true	"画像URLの署名"
+//	@Success		200	{file}		binary				"画像"
+//	@Failure		400	{object}	map[string]string	"画像URLが不正です"
+//	@Failure		403	{object}	map[string]string	"署名が一致しないか、接続を許可しないアドレスです"
+//	@Failure		502	{object}	map[string]string	"画像の取得に失敗したか、画像形式・サイズが許可されていません"
+//	@Router			/proxy/image [get]
+func (h *ImageProxyHandler) GetImage(c *gin.Context) {
+	image, err := h.imageProxyService.GetImage(c.Request.Context(), c.Query("url"), c.Query("sig"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidImageSignature):
+			c.JSON(http.StatusForbidden, gin.H{"error": "Invalid signature"})
+		case errors.Is(err, service.ErrImageAddressNotAllowed):
+			c.JSON(http.StatusForbidden, gin.H{"error": "Image address is not allowed"})
+		case errors.Is(err, service.ErrInvalidImageURL):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid image URL"})
+		case errors.Is(err, service.ErrUnsupportedImage):
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Unsupported image type"})
+		case errors.Is(err, service.ErrImageTooLarge):
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Image is too large"})
+		default:
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to fetch image"})
+		}
+		return
+	}
+
+	// 署名付きURLは画像URLごとに固定のため、ブラウザにキャッシュさせる
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Header("Content-Security-Policy", imageContentSecurityPolicy)
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, image.ContentType, image.Data)
+}
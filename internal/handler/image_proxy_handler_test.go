@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"feedapp/internal/imageproxy"
+	"feedapp/internal/service"
+)
+
+// MockImageProxyService は service.ImageProxyService のモック実装です。
+type MockImageProxyService struct {
+	mock.Mock
+}
+
+func (m *MockImageProxyService) GetImage(ctx context.Context, rawURL, sig string) (imageproxy.Image, error) {
+	args := m.Called(ctx, rawURL, sig)
+	return args.Get(0).(imageproxy.Image), args.Error(1)
+}
+
+// newImageProxyContext は認証なしのテスト用コンテキストを作成します。
+func newImageProxyContext(rawURL, sig string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	query := url.Values{"url": {rawURL}, "sig": {sig}}
+	c.Request = httptest.NewRequest(http.MethodGet, imageproxy.Path+"?"+query.Encode(), nil)
+	return c, w
+}
+
+func TestImageProxyHandler_GetImage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockImageProxyService)
+	handler := NewImageProxyHandler(mockService)
+
+	imageURL := "http://example.com/a.png"
+
+	// 正常系: 画像をキャッシュ可能なヘッダーとともに返す
+	t.Run("should return image", func(t *testing.T) {
+		image := imageproxy.Image{ContentType: "image/png", Data: []byte("\x89PNG\r\n\x1a\n")}
+		mockService.On("GetImage", mock.Anything, imageURL, "valid").Return(image, nil).Once()
+
+		c, w := newImageProxyContext(imageURL, "valid")
+		handler.GetImage(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+		assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+		assert.Contains(t, w.Header().Get("Cache-Control"), "max-age=")
+		assert.Equal(t, image.Data, w.Body.Bytes())
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 取得に失敗した場合のステータスコード
+	tests := []struct {
+		name    string
+		err     error
+		code    int
+		message string
+	}{
+		{"should return 403 if signature is invalid", service.ErrInvalidImageSignature, http.StatusForbidden, "Invalid signature"},
+		{"should return 403 if address is not allowed", service.ErrImageAddressNotAllowed, http.StatusForbidden, "Image address is not allowed"},
+		{"should return 400 if url is invalid", service.ErrInvalidImageURL, http.StatusBadRequest, "Invalid image URL"},
+		{"should return 502 if type is unsupported", service.ErrUnsupportedImage, http.StatusBadGateway, "Unsupported image type"},
+		{"should return 502 if image is too large", service.ErrImageTooLarge, http.StatusBadGateway, "Image is too large"},
+		{"should return 502 if fetch fails", errors.New("connection refused"), http.StatusBadGateway, "Failed to fetch image"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService.On("GetImage", mock.Anything, imageURL, "sig").Return(imageproxy.Image{}, tt.err).Once()
+
+			c, w := newImageProxyContext(imageURL, "sig")
+			handler.GetImage(c)
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Contains(t, w.Body.String(), tt.message)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"feedapp/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaintenanceHandler はデータベースの保守に関するHTTPリクエストを処理します。
+//
+// サポートするエンドポイント:
+//   - POST /maintenance/purge - 保持設定による古い記事の削除（購読しているフィードの記事のみ）
+type MaintenanceHandler struct {
+	maintenanceService service.MaintenanceService
+}
+
+// NewMaintenanceHandler は新しい MaintenanceHandler インスタンスを作成します。
+func NewMaintenanceHandler(s service.MaintenanceService) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		maintenanceService: s,
+	}
+}
+
+// Purge は保持設定に従って、ログイン中のユーザーが購読しているフィードの古い記事を削除します。
+//
+//	@Summary		古い記事の削除
+//	@Description	購読しているフィードの記事のうち、保持日数を過ぎた既読の記事と、フィードごとの上限件数を超えた古い記事を削除し、フィードごとの件数を返します。
+//	@Description	他のフィードの記事は削除しません（すべてのフィードの記事は定期実行で削除します）。保持日数による削除は、購読者全員が既読にした記事に限ります。
+//	@Description	「後で見る」・タグ・注釈が付いた記事は削除しません。保持設定はフィードの retention_days・max_articles、未指定の場合は全体設定を使用します。
+//	@Description	削除した記事のURLは記録され、次回の取得で未読として再登録されません。dry_run=true の場合は削除せず、対象の件数のみを返します
+//	@Tags			maintenance
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			dry_run	query		bool				false	"削除対象の確認のみを行う"
+//	@Success		200		{object}	model.PurgeResult	"削除結果"
+//	@Failure		400		{object}	map[string]string	"クエリパラメータが不正"
+//	@Failure		500		{object}	map[string]string	"サーバー内部エラー"
+//	@Router			/maintenance/purge [post]
+func (h *MaintenanceHandler) Purge(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	dryRun := false
+	if value := c.Query("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dry_run parameter"})
+			return
+		}
+		dryRun = parsed
+	}
+
+	result, err := h.maintenanceService.Purge(user.ID, dryRun)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to purge articles"})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"feedapp/internal/model"
+)
+
+// MockMaintenanceService は service.MaintenanceService のモック実装です。
+type MockMaintenanceService struct {
+	mock.Mock
+}
+
+func (m *MockMaintenanceService) Purge(userID string, dryRun bool) (model.PurgeResult, error) {
+	args := m.Called(userID, dryRun)
+	return args.Get(0).(model.PurgeResult), args.Error(1)
+}
+
+func (m *MockMaintenanceService) Run(ctx context.Context, interval time.Duration) {
+	m.Called(ctx, interval)
+}
+
+// newMaintenanceContext はログイン済みのテスト用コンテキストを作成します。
+func newMaintenanceContext(target string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set(userContextKey, testUser)
+	c.Request = httptest.NewRequest(http.MethodPost, target, nil)
+	return c, w
+}
+
+func TestMaintenanceHandler_Purge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// 異常系: dry_run が真偽値でない場合はサービスを呼び出さずに 400 を返す
+	t.Run("should return 400 if dry_run is invalid", func(t *testing.T) {
+		mockService := new(MockMaintenanceService)
+		handler := NewMaintenanceHandler(mockService)
+
+		c, w := newMaintenanceContext("/maintenance/purge?dry_run=maybe")
+		handler.Purge(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid dry_run parameter")
+		mockService.AssertNotCalled(t, "Purge", mock.Anything, mock.Anything)
+	})
+
+	mockService := new(MockMaintenanceService)
+	handler := NewMaintenanceHandler(mockService)
+
+	// 正常系: dry_run=true の場合はログイン中のユーザーが購読しているフィードの削除対象の件数を返す
+	t.Run("should report purge candidates on dry run", func(t *testing.T) {
+		expected := model.PurgeResult{DryRun: true, Deleted: 3, Feeds: []model.FeedPurgeResult{{FeedID: "1", Deleted: 2}, {FeedID: "2", Deleted: 1}}}
+		mockService.On("Purge", testUser.ID, true).Return(expected, nil).Once()
+
+		c, w := newMaintenanceContext("/maintenance/purge?dry_run=true")
+		handler.Purge(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var actual model.PurgeResult
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &actual))
+		assert.Equal(t, expected, actual)
+		mockService.AssertExpectations(t)
+	})
+
+	// 正常系: dry_run を省略した場合はログイン中のユーザーが購読しているフィードの記事を削除する
+	t.Run("should purge articles", func(t *testing.T) {
+		expected := model.PurgeResult{Deleted: 1, Feeds: []model.FeedPurgeResult{{FeedID: "1", Deleted: 1}}}
+		mockService.On("Purge", testUser.ID, false).Return(expected, nil).Once()
+
+		c, w := newMaintenanceContext("/maintenance/purge")
+		handler.Purge(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var actual model.PurgeResult
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &actual))
+		assert.Equal(t, expected, actual)
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: サービスエラー
+	t.Run("should return 500 if service error", func(t *testing.T) {
+		mockService.On("Purge", testUser.ID, false).Return(model.PurgeResult{}, assert.AnError).Once()
+
+		c, w := newMaintenanceContext("/maintenance/purge")
+		handler.Purge(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "Failed to purge articles")
+		mockService.AssertExpectations(t)
+	})
+}
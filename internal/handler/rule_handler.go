@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"feedapp/internal/model"
+	"feedapp/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RuleHandler はルール関連のHTTPリクエストを処理します。
+//
+// サポートするエンドポイント:
+//   - GET /rules - ルール一覧取得
+//   - GET /rules/{id} - 特定ルール取得
+//   - POST /rules - 新規ルール作成
+//   - PUT /rules/{id} - ルール更新
+//   - DELETE /rules/{id} - ルール削除
+//   - POST /rules/{id}/apply - 既存の記事へのルールの適用
+type RuleHandler struct {
+	ruleService service.RuleService
+}
+
+// NewRuleHandler は新しい RuleHandler インスタンスを作成します。
+func NewRuleHandler(s service.RuleService) *RuleHandler {
+	return &RuleHandler{
+		ruleService: s,
+	}
+}
+
+// GetAllRules はログイン中のユーザーのルール一覧を取得します。
+//
+//	@Summary		ルール一覧取得
+//	@Description	ログイン中のユーザーが作成したすべてのルールを作成順に取得します
+//	@Tags			rules
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		model.Rule			"ルール一覧"
+//	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
+//	@Router			/rules [get]
+func (h *RuleHandler) GetAllRules(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	rules, err := h.ruleService.GetAllRules(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get rules"})
+		return
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+// GetRuleByID は指定されたIDのルールを取得します。
+//
+//	@Summary		ルール詳細取得
+//	@Description	指定されたIDのルールを取得します
+//	@Tags			rules
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		string				true	"ルールID (UUID)"
+//	@Success		200	{object}	model.Rule			"ルール詳細"
+//	@Failure		404	{object}	map[string]string	"ルールが見つかりません"
+//	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
+//	@Router			/rules/{id} [get]
+func (h *RuleHandler) GetRuleByID(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	rule, err := h.ruleService.GetRuleByID(user.ID, c.Param("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrRuleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Rule not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get rule"})
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// CreateRule は新しいルールを作成します。
+//
+//	@Summary		ルール作成
+//	@Description	取り込む記事を自動的に処理するルールを作成します。作成後に取り込まれる記事に適用されます。
+//	@Description	conditions のすべてに一致した記事に action（mark_read・mark_later・skip・add_tag）を実行します。
+//	@Description	folder_id・feed_id をいずれも省略した場合は、購読しているすべてのフィードに適用されます
+//	@Tags			rules
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			rule	body		model.Rule				true	"ルール情報"
+//	@Success		201		{object}	model.Rule				"作成されたルール"
+//	@Failure		400		{object}	map[string]interface{}	"リクエストボディの形式が不正、正規表現が不正、またはフィード・フォルダが存在しない"
+//	@Failure		500		{object}	map[string]string		"サーバー内部エラー"
+//	@Router			/rules [post]
+func (h *RuleHandler) CreateRule(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var rule model.Rule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	created, err := h.ruleService.CreateRule(user.ID, rule)
+	if err != nil {
+		if respondRuleValidationError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create rule"})
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// UpdateRule は指定されたIDのルールを更新します。
+//
+//	@Summary		ルール更新
+//	@Description	指定されたIDのルールを更新します。既に取り込まれた記事には適用されません
+//	@Tags			rules
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		string					true	"ルールID (UUID)"
+//	@Param			rule	body		model.Rule				true	"更新するルール情報"
+//	@Success		200		{object}	model.Rule				"更新されたルール"
+//	@Failure		400		{object}	map[string]interface{}	"リクエストボディの形式が不正、正規表現が不正、またはフィード・フォルダが存在しない"
+//	@Failure		404		{object}	map[string]string		"ルールが見つかりません"
+//	@Failure		500		{object}	map[string]string		"サーバー内部エラー"
+//	@Router			/rules/{id} [put]
+func (h *RuleHandler) UpdateRule(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	id := c.Param("id")
+	var rule model.Rule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	updated, err := h.ruleService.UpdateRule(user.ID, id, rule)
+	if err != nil {
+		if errors.Is(err, service.ErrRuleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Rule not found"})
+			return
+		}
+		if respondRuleValidationError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update rule"})
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteRule は指定されたIDのルールを削除します。適用済みの記事の状態は変わりません。
+//
+//	@Summary		ルール削除
+//	@Description	指定されたIDのルールを削除します。適用済みの記事の状態は変わりません
+//	@Tags			rules
+//	@Security		BearerAuth
+//	@Param			id	path	string	true	"ルールID (UUID)"
+//	@Success		204	"削除成功"
+//	@Failure		404	{object}	map[string]string	"ルールが見つかりません"
+//	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
+//	@Router			/rules/{id} [delete]
+func (h *RuleHandler) DeleteRule(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	if err := h.ruleService.DeleteRule(user.ID, c.Param("id")); err != nil {
+		if errors.Is(err, service.ErrRuleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Rule not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete rule"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ApplyRule は指定されたIDのルールを既存の記事に適用します。
+//
+//	@Summary		既存の記事へのルールの適用
+//	@Description	ルールの適用範囲内の既存の記事のうち、条件に一致した記事にアクションを実行し、一致した件数を返します。
+//	@Description	dry_run=true の場合はアクションを実行せず、一致する件数のみを返します
+//	@Tags			rules
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		string					true	"ルールID (UUID)"
+//	@Param			dry_run	query		bool					false	"件数の確認のみを行う"
+//	@Success		200		{object}	model.RuleApplyResult	"適用結果"
+//	@Failure		400		{object}	map[string]string		"クエリパラメータが不正"
+//	@Failure		404		{object}	map[string]string		"ルールが見つかりません"
+//	@Failure		500		{object}	map[string]string		"サーバー内部エラー"
+//	@Router			/rules/{id}/apply [post]
+func (h *RuleHandler) ApplyRule(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	dryRun := false
+	if value := c.Query("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dry_run parameter"})
+			return
+		}
+		dryRun = parsed
+	}
+
+	result, err := h.ruleService.ApplyRule(user.ID, c.Param("id"), dryRun)
+	if err != nil {
+		if errors.Is(err, service.ErrRuleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Rule not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to apply rule"})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// respondRuleValidationError はルールの入力値に関するエラーを 400 として返します。
+// 該当するエラーでない場合は何もせず false を返します。
+func respondRuleValidationError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrInvalidRule):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule", "details": err.Error()})
+	case errors.Is(err, service.ErrFeedNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid feed_id"})
+	case errors.Is(err, service.ErrFolderNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid folder_id"})
+	default:
+		return false
+	}
+	return true
+}
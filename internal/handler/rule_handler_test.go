@@ -0,0 +1,327 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"feedapp/internal/model"
+	"feedapp/internal/service"
+)
+
+// MockRuleService は service.RuleService のモック実装です。
+type MockRuleService struct {
+	mock.Mock
+}
+
+func (m *MockRuleService) GetAllRules(userID string) ([]model.Rule, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]model.Rule), args.Error(1)
+}
+
+func (m *MockRuleService) GetRuleByID(userID, id string) (model.Rule, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).(model.Rule), args.Error(1)
+}
+
+func (m *MockRuleService) CreateRule(userID string, rule model.Rule) (model.Rule, error) {
+	args := m.Called(userID, rule)
+	return args.Get(0).(model.Rule), args.Error(1)
+}
+
+func (m *MockRuleService) UpdateRule(userID, id string, rule model.Rule) (model.Rule, error) {
+	args := m.Called(userID, id, rule)
+	return args.Get(0).(model.Rule), args.Error(1)
+}
+
+func (m *MockRuleService) DeleteRule(userID, id string) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *MockRuleService) ApplyRule(userID, id string, dryRun bool) (model.RuleApplyResult, error) {
+	args := m.Called(userID, id, dryRun)
+	return args.Get(0).(model.RuleApplyResult), args.Error(1)
+}
+
+// newRuleContext はログイン済みのテスト用コンテキストを作成します。body が空の場合はリクエストボディを設定しません。
+func newRuleContext(method, id, target, body string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set(userContextKey, testUser)
+	if id != "" {
+		c.Params = gin.Params{{Key: "id", Value: id}}
+	}
+	c.Request = httptest.NewRequest(method, target, bytes.NewBufferString(body))
+	if body != "" {
+		c.Request.Header.Set("Content-Type", "application/json")
+	}
+	return c, w
+}
+
+// testRule はテストで使用する、タイトルの条件で skip するルールです。
+var testRule = model.Rule{
+	Name:       "No sponsored",
+	FeedID:     "feed-1",
+	Conditions: []model.RuleCondition{{Field: model.RuleFieldTitle, Operator: model.RuleOperatorContains, Value: "[PR]", IgnoreCase: true}},
+	Action:     model.RuleActionSkip,
+}
+
+func TestRuleHandler_GetAllRules(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockRuleService)
+	handler := NewRuleHandler(mockService)
+
+	// 正常系: ルール一覧を返す
+	t.Run("should return all rules", func(t *testing.T) {
+		rule := testRule
+		rule.ID = "1"
+		expected := []model.Rule{rule}
+		mockService.On("GetAllRules", testUser.ID).Return(expected, nil).Once()
+
+		c, w := newRuleContext(http.MethodGet, "", "/rules", "")
+		handler.GetAllRules(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var actual []model.Rule
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &actual))
+		assert.Equal(t, expected, actual)
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: サービスエラー
+	t.Run("should return 500 if service error", func(t *testing.T) {
+		mockService.On("GetAllRules", testUser.ID).Return([]model.Rule{}, assert.AnError).Once()
+
+		c, w := newRuleContext(http.MethodGet, "", "/rules", "")
+		handler.GetAllRules(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "Failed to get rules")
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestRuleHandler_GetRuleByID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockRuleService)
+	handler := NewRuleHandler(mockService)
+
+	// 異常系: 他のユーザーのルールまたは存在しないルール
+	t.Run("should return 404 if rule not found", func(t *testing.T) {
+		mockService.On("GetRuleByID", testUser.ID, "other").Return(model.Rule{}, service.ErrRuleNotFound).Once()
+
+		c, w := newRuleContext(http.MethodGet, "other", "/rules/other", "")
+		handler.GetRuleByID(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Rule not found")
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestRuleHandler_CreateRule(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// 異常系: 必須項目の不足・条件やアクションの値の不正はサービスを呼び出さずに 400 を返す
+	t.Run("should return 400 if invalid request body", func(t *testing.T) {
+		mockService := new(MockRuleService)
+		handler := NewRuleHandler(mockService)
+		for _, body := range []string{
+			`{"name":"Empty","conditions":[],"action":"skip"}`,
+			`{"name":"Field","conditions":[{"field":"body","operator":"contains","value":"x"}],"action":"skip"}`,
+			`{"name":"Operator","conditions":[{"field":"title","operator":"equals","value":"x"}],"action":"skip"}`,
+			`{"name":"Action","conditions":[{"field":"title","operator":"contains","value":"x"}],"action":"delete"}`,
+		} {
+			c, w := newRuleContext(http.MethodPost, "", "/rules", body)
+			handler.CreateRule(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, body)
+			assert.Contains(t, w.Body.String(), "Invalid input")
+		}
+		mockService.AssertNotCalled(t, "CreateRule", mock.Anything, mock.Anything)
+	})
+
+	mockService := new(MockRuleService)
+	handler := NewRuleHandler(mockService)
+
+	// 正常系: 作成したルールを返す
+	t.Run("should create rule successfully", func(t *testing.T) {
+		created := testRule
+		created.ID = "1"
+		mockService.On("CreateRule", testUser.ID, testRule).Return(created, nil).Once()
+
+		body, _ := json.Marshal(testRule)
+		c, w := newRuleContext(http.MethodPost, "", "/rules", string(body))
+		handler.CreateRule(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var actual model.Rule
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &actual))
+		assert.Equal(t, created, actual)
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 入力値の検証エラーは 400 を返す
+	validationErrors := []struct {
+		name    string
+		err     error
+		message string
+	}{
+		{"invalid regex", fmt.Errorf("%w: error parsing regexp: missing closing )", service.ErrInvalidRule), "Invalid rule"},
+		{"unknown feed", service.ErrFeedNotFound, "Invalid feed_id"},
+		{"unknown folder", service.ErrFolderNotFound, "Invalid folder_id"},
+	}
+	for _, tt := range validationErrors {
+		t.Run("should return 400 if "+tt.name, func(t *testing.T) {
+			input := testRule
+			input.Name = tt.name
+			mockService.On("CreateRule", testUser.ID, input).Return(model.Rule{}, tt.err).Once()
+
+			body, _ := json.Marshal(input)
+			c, w := newRuleContext(http.MethodPost, "", "/rules", string(body))
+			handler.CreateRule(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), tt.message)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRuleHandler_UpdateRule(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockRuleService)
+	handler := NewRuleHandler(mockService)
+
+	// 正常系: 更新したルールを返す
+	t.Run("should update rule successfully", func(t *testing.T) {
+		input := testRule
+		input.Action = model.RuleActionAddTag
+		input.Tag = "sponsored"
+		updated := input
+		updated.ID = "1"
+		mockService.On("UpdateRule", testUser.ID, "1", input).Return(updated, nil).Once()
+
+		body, _ := json.Marshal(input)
+		c, w := newRuleContext(http.MethodPut, "1", "/rules/1", string(body))
+		handler.UpdateRule(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var actual model.Rule
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &actual))
+		assert.Equal(t, updated, actual)
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: ルールが見つからない場合
+	t.Run("should return 404 if rule not found", func(t *testing.T) {
+		mockService.On("UpdateRule", testUser.ID, "nonexistent", testRule).Return(model.Rule{}, service.ErrRuleNotFound).Once()
+
+		body, _ := json.Marshal(testRule)
+		c, w := newRuleContext(http.MethodPut, "nonexistent", "/rules/nonexistent", string(body))
+		handler.UpdateRule(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Rule not found")
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestRuleHandler_DeleteRule(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockRuleService)
+	handler := NewRuleHandler(mockService)
+
+	// 正常系: 削除成功
+	t.Run("should delete rule successfully", func(t *testing.T) {
+		mockService.On("DeleteRule", testUser.ID, "1").Return(nil).Once()
+
+		c, _ := newRuleContext(http.MethodDelete, "1", "/rules/1", "")
+		handler.DeleteRule(c)
+
+		assert.Equal(t, http.StatusNoContent, c.Writer.Status())
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: ルールが見つからない場合
+	t.Run("should return 404 if rule not found", func(t *testing.T) {
+		mockService.On("DeleteRule", testUser.ID, "nonexistent").Return(service.ErrRuleNotFound).Once()
+
+		c, w := newRuleContext(http.MethodDelete, "nonexistent", "/rules/nonexistent", "")
+		handler.DeleteRule(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Rule not found")
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestRuleHandler_ApplyRule(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// 正常系: dry_run の場合は件数のみを返す
+	t.Run("should return matched count on dry run", func(t *testing.T) {
+		mockService := new(MockRuleService)
+		handler := NewRuleHandler(mockService)
+		expected := model.RuleApplyResult{Matched: 12, DryRun: true}
+		mockService.On("ApplyRule", testUser.ID, "1", true).Return(expected, nil).Once()
+
+		c, w := newRuleContext(http.MethodPost, "1", "/rules/1/apply?dry_run=true", "")
+		handler.ApplyRule(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var actual model.RuleApplyResult
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &actual))
+		assert.Equal(t, expected, actual)
+		mockService.AssertExpectations(t)
+	})
+
+	// 正常系: dry_run を省略した場合はアクションを実行する
+	t.Run("should apply rule without dry_run", func(t *testing.T) {
+		mockService := new(MockRuleService)
+		handler := NewRuleHandler(mockService)
+		mockService.On("ApplyRule", testUser.ID, "1", false).Return(model.RuleApplyResult{Matched: 3}, nil).Once()
+
+		c, w := newRuleContext(http.MethodPost, "1", "/rules/1/apply", "")
+		handler.ApplyRule(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"matched":3,"dry_run":false}`, w.Body.String())
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: dry_run の値が不正な場合はサービスを呼び出さずに 400 を返す
+	t.Run("should return 400 if invalid dry_run", func(t *testing.T) {
+		mockService := new(MockRuleService)
+		handler := NewRuleHandler(mockService)
+
+		c, w := newRuleContext(http.MethodPost, "1", "/rules/1/apply?dry_run=maybe", "")
+		handler.ApplyRule(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid dry_run parameter")
+		mockService.AssertNotCalled(t, "ApplyRule", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	// 異常系: ルールが見つからない場合
+	t.Run("should return 404 if rule not found", func(t *testing.T) {
+		mockService := new(MockRuleService)
+		handler := NewRuleHandler(mockService)
+		mockService.On("ApplyRule", testUser.ID, "nonexistent", false).Return(model.RuleApplyResult{}, service.ErrRuleNotFound).Once()
+
+		c, w := newRuleContext(http.MethodPost, "nonexistent", "/rules/nonexistent/apply", "")
+		handler.ApplyRule(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Rule not found")
+		mockService.AssertExpectations(t)
+	})
+}
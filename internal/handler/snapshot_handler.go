@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"feedapp/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// snapshotContentSecurityPolicy はスナップショットの表示に適用する Content-Security-Policy です。
+// 保存したHTMLは外部サイトの内容のため、スクリプトを実行させず、sandbox でこのサーバーのオリジンから切り離します。
+// 最大サイズを超えて埋め込まなかった画像・フォント・スタイルシートは元のURLから読み込みます。
+const snapshotContentSecurityPolicy = "default-src 'none'; img-src data: http: https:; font-src data: http: https:; style-src 'unsafe-inline' data: http: https:; sandbox"
+
+// SnapshotHandler は記事の元ページのスナップショット関連のHTTPリクエストを処理します。
+//
+// サポートするエンドポイント:
+//   - GET /articles/{id}/snapshot - 記事の元ページのスナップショット取得
+type SnapshotHandler struct {
+	snapshotService service.SnapshotService
+}
+
+// NewSnapshotHandler は新しい SnapshotHandler インスタンスを作成します。
+func NewSnapshotHandler(s service.SnapshotService) *SnapshotHandler {
+	return &SnapshotHandler{
+		snapshotService: s,
+	}
+}
+
+// GetArticleSnapshot は記事の元ページのスナップショットを返します。
+//
+//	@Summary		記事の元ページのスナップショット取得
+//	@Description	「後で見る」に追加したときに保存した元ページのスナップショット（画像とスタイルシートを埋め込んだHTML）を返します。
+//	@Description	スクリプトは除去済みで、Content-Security-Policy の sandbox を付与して返します。取得状況は記事の snapshot_status で確認できます
+//	@Tags			articles
+//	@Produce		html
+//	@Security		BearerAuth
+//	@Param			id	path		string				true	"記事ID (UUID)"
+//	@Success		200	{string}	string				"スナップショットのHTML"
+//	@Success		304	"変更なし（If-None-Match が一致）"
+//	@Failure		404	{object}	map[string]string	"記事またはスナップショットが見つかりません"
+//	@Failure		409	{object}	map[string]string	"スナップショットが取得待ちまたは取得失敗"
+//	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
+//	@Router			/articles/{id}/snapshot [get]
+func (h *SnapshotHandler) GetArticleSnapshot(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	snapshot, data, err := h.snapshotService.GetSnapshot(user.ID, c.Param("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrArticleNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		case errors.Is(err, service.ErrSnapshotNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Snapshot not found"})
+		case errors.Is(err, service.ErrSnapshotNotReady):
+			c.JSON(http.StatusConflict, gin.H{"error": "Snapshot is not ready", "status": snapshot.Status})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get snapshot"})
+		}
+		return
+	}
+
+	// 内容のハッシュは内容が変わらない限り同じため、そのまま ETag とする
+	etag := `"` + snapshot.Hash + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache")
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Header("Content-Security-Policy", snapshotContentSecurityPolicy)
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, "text/html", data)
+}
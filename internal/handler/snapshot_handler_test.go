@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"feedapp/internal/model"
+	"feedapp/internal/service"
+)
+
+// MockSnapshotService は service.SnapshotService のモック実装です。
+type MockSnapshotService struct {
+	mock.Mock
+}
+
+func (m *MockSnapshotService) Request(article model.Article) bool {
+	args := m.Called(article)
+	return args.Bool(0)
+}
+
+func (m *MockSnapshotService) GetSnapshot(userID, articleID string) (model.ArticleSnapshot, []byte, error) {
+	args := m.Called(userID, articleID)
+	data, _ := args.Get(1).([]byte)
+	return args.Get(0).(model.ArticleSnapshot), data, args.Error(2)
+}
+
+func (m *MockSnapshotService) Run(ctx context.Context, interval time.Duration) {
+	m.Called(ctx, interval)
+}
+
+// newSnapshotContext はログイン済みのテスト用コンテキストを作成します。
+func newSnapshotContext(articleID, ifNoneMatch string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set(userContextKey, testUser)
+	c.Params = gin.Params{{Key: "id", Value: articleID}}
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if ifNoneMatch != "" {
+		c.Request.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	return c, w
+}
+
+func TestSnapshotHandler_GetArticleSnapshot(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockSnapshotService)
+	handler := NewSnapshotHandler(mockService)
+
+	hash := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	ready := model.ArticleSnapshot{ArticleID: "1", URL: "http://example.com/a1", Status: model.SnapshotStatusReady, Hash: hash}
+
+	// 正常系: スナップショットのHTMLを sandbox 付きで返す
+	t.Run("should return snapshot html", func(t *testing.T) {
+		document := []byte("<html><body><h1>Article 1</h1></body></html>")
+		mockService.On("GetSnapshot", testUser.ID, "1").Return(ready, document, nil).Once()
+
+		c, w := newSnapshotContext("1", "")
+		handler.GetArticleSnapshot(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/html", w.Header().Get("Content-Type"))
+		assert.Equal(t, `"`+hash+`"`, w.Header().Get("ETag"))
+		assert.Contains(t, w.Header().Get("Content-Security-Policy"), "sandbox")
+		assert.Contains(t, w.Header().Get("Content-Security-Policy"), "default-src 'none'")
+		assert.Equal(t, string(document), w.Body.String())
+		mockService.AssertExpectations(t)
+	})
+
+	// 正常系: ETag が一致する場合は 304 を返す
+	t.Run("should return 304 if etag matches", func(t *testing.T) {
+		mockService.On("GetSnapshot", testUser.ID, "1").Return(ready, []byte("<html></html>"), nil).Once()
+
+		c, w := newSnapshotContext("1", `"`+hash+`"`)
+		handler.GetArticleSnapshot(c)
+
+		assert.Equal(t, http.StatusNotModified, c.Writer.Status())
+		assert.Empty(t, w.Body.String())
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 取得待ちの場合は取得状況とともに 409 を返す
+	t.Run("should return 409 if snapshot is pending", func(t *testing.T) {
+		pending := model.ArticleSnapshot{ArticleID: "2", Status: model.SnapshotStatusPending}
+		mockService.On("GetSnapshot", testUser.ID, "2").Return(pending, nil, service.ErrSnapshotNotReady).Once()
+
+		c, w := newSnapshotContext("2", "")
+		handler.GetArticleSnapshot(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "Snapshot is not ready")
+		assert.Contains(t, w.Body.String(), `"status":"pending"`)
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: スナップショットがない場合
+	t.Run("should return 404 if snapshot not found", func(t *testing.T) {
+		mockService.On("GetSnapshot", testUser.ID, "3").Return(model.ArticleSnapshot{}, nil, service.ErrSnapshotNotFound).Once()
+
+		c, w := newSnapshotContext("3", "")
+		handler.GetArticleSnapshot(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Snapshot not found")
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 購読していないフィードの記事または存在しない記事
+	t.Run("should return 404 if article not found", func(t *testing.T) {
+		mockService.On("GetSnapshot", testUser.ID, "other").Return(model.ArticleSnapshot{}, nil, service.ErrArticleNotFound).Once()
+
+		c, w := newSnapshotContext("other", "")
+		handler.GetArticleSnapshot(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Article not found")
+		mockService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"feedapp/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TagHandler は記事のタグ関連のHTTPリクエストを処理します。
+//
+// サポートするエンドポイント:
+//   - GET /tags - タグ一覧（記事の件数付き）取得
+//   - PUT /articles/{id}/tags/{tag} - 記事にタグを付ける
+//   - DELETE /articles/{id}/tags/{tag} - 記事からタグを外す
+type TagHandler struct {
+	tagService service.TagService
+}
+
+// NewTagHandler は新しい TagHandler インスタンスを作成します。
+func NewTagHandler(s service.TagService) *TagHandler {
+	return &TagHandler{
+		tagService: s,
+	}
+}
+
+// GetAllTags はログイン中のユーザーのタグ一覧を取得します。
+//
+//	@Summary		タグ一覧取得
+//	@Description	ログイン中のユーザーのタグを名前順に、タグが付いた記事と未読の記事の件数とともに取得します
+//	@Tags			tags
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		model.Tag			"タグ一覧"
+//	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
+//	@Router			/tags [get]
+func (h *TagHandler) GetAllTags(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	tags, err := h.tagService.GetAllTags(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get tags"})
+		return
+	}
+	c.JSON(http.StatusOK, tags)
+}
+
+// AddArticleTag は記事にタグを付けます。
+//
+//	@Summary		記事にタグを付ける
+//	@Description	指定されたIDの記事にタグを付けます。タグが存在しない場合は作成され、既に付いている場合は何もしません
+//	@Tags			tags
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		string				true	"記事ID (UUID)"
+//	@Param			tag	path		string				true	"タグ名（最大64文字）"
+//	@Success		200	{object}	model.Article		"タグを付けた記事"
+//	@Failure		400	{object}	map[string]string	"タグ名が不正"
+//	@Failure		404	{object}	map[string]string	"記事が見つかりません"
+//	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
+//	@Router			/articles/{id}/tags/{tag} [put]
+func (h *TagHandler) AddArticleTag(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	article, err := h.tagService.AddArticleTag(user.ID, c.Param("id"), c.Param("tag"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidTagName):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag name"})
+		case errors.Is(err, service.ErrArticleNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add tag"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, article)
+}
+
+// RemoveArticleTag は記事からタグを外します。
+//
+//	@Summary		記事からタグを外す
+//	@Description	指定されたIDの記事からタグを外します。タグが付いた記事がなくなった場合はタグも削除されます
+//	@Tags			tags
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		string				true	"記事ID (UUID)"
+//	@Param			tag	path		string				true	"タグ名"
+//	@Success		200	{object}	model.Article		"タグを外した記事"
+//	@Failure		400	{object}	map[string]string	"タグ名が不正"
+//	@Failure		404	{object}	map[string]string	"記事が見つからない、または記事にタグが付いていない"
+//	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
+//	@Router			/articles/{id}/tags/{tag} [delete]
+func (h *TagHandler) RemoveArticleTag(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	article, err := h.tagService.RemoveArticleTag(user.ID, c.Param("id"), c.Param("tag"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidTagName):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag name"})
+		case errors.Is(err, service.ErrArticleNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		case errors.Is(err, service.ErrTagNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove tag"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, article)
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"feedapp/internal/model"
+	"feedapp/internal/service"
+)
+
+// MockTagService は service.TagService のモック実装です。
+type MockTagService struct {
+	mock.Mock
+}
+
+func (m *MockTagService) GetAllTags(userID string) ([]model.Tag, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]model.Tag), args.Error(1)
+}
+
+func (m *MockTagService) AddArticleTag(userID, articleID, name string) (model.Article, error) {
+	args := m.Called(userID, articleID, name)
+	return args.Get(0).(model.Article), args.Error(1)
+}
+
+func (m *MockTagService) RemoveArticleTag(userID, articleID, name string) (model.Article, error) {
+	args := m.Called(userID, articleID, name)
+	return args.Get(0).(model.Article), args.Error(1)
+}
+
+// newTagContext はログイン済みのテスト用コンテキストを作成します。
+func newTagContext(method, articleID, tag string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set(userContextKey, testUser)
+	if articleID != "" {
+		c.Params = gin.Params{{Key: "id", Value: articleID}, {Key: "tag", Value: tag}}
+	}
+	c.Request = httptest.NewRequest(method, "/", nil)
+	return c, w
+}
+
+func TestTagHandler_GetAllTags(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockTagService)
+	handler := NewTagHandler(mockService)
+
+	// 正常系: タグ一覧を件数とともに返す
+	t.Run("should return all tags with counts", func(t *testing.T) {
+		expected := []model.Tag{
+			{Name: "research", Count: 12, UnreadCount: 3},
+			{Name: "to-share", Count: 2, UnreadCount: 0},
+		}
+		mockService.On("GetAllTags", testUser.ID).Return(expected, nil).Once()
+
+		c, w := newTagContext(http.MethodGet, "", "")
+		handler.GetAllTags(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var actual []model.Tag
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &actual))
+		assert.Equal(t, expected, actual)
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: サービスエラー
+	t.Run("should return 500 if service error", func(t *testing.T) {
+		mockService.On("GetAllTags", testUser.ID).Return([]model.Tag{}, assert.AnError).Once()
+
+		c, w := newTagContext(http.MethodGet, "", "")
+		handler.GetAllTags(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "Failed to get tags")
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestTagHandler_AddArticleTag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockTagService)
+	handler := NewTagHandler(mockService)
+
+	// 正常系: タグを付けた記事を返す
+	t.Run("should add tag to article", func(t *testing.T) {
+		expected := model.Article{ID: "1", Title: "Article 1", URL: "http://example.com/a1", Tags: []string{"to-share"}}
+		mockService.On("AddArticleTag", testUser.ID, "1", "to-share").Return(expected, nil).Once()
+
+		c, w := newTagContext(http.MethodPut, "1", "to-share")
+		handler.AddArticleTag(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var actual model.Article
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &actual))
+		assert.Equal(t, expected, actual)
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: タグ名が不正な場合
+	t.Run("should return 400 if tag name is invalid", func(t *testing.T) {
+		mockService.On("AddArticleTag", testUser.ID, "1", " ").Return(model.Article{}, service.ErrInvalidTagName).Once()
+
+		c, w := newTagContext(http.MethodPut, "1", " ")
+		handler.AddArticleTag(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid tag name")
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 購読していないフィードの記事または存在しない記事
+	t.Run("should return 404 if article not found", func(t *testing.T) {
+		mockService.On("AddArticleTag", testUser.ID, "other", "research").Return(model.Article{}, service.ErrArticleNotFound).Once()
+
+		c, w := newTagContext(http.MethodPut, "other", "research")
+		handler.AddArticleTag(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Article not found")
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestTagHandler_RemoveArticleTag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockTagService)
+	handler := NewTagHandler(mockService)
+
+	// 正常系: タグを外した記事を返す
+	t.Run("should remove tag from article", func(t *testing.T) {
+		expected := model.Article{ID: "1", Title: "Article 1", URL: "http://example.com/a1", Tags: []string{"research"}}
+		mockService.On("RemoveArticleTag", testUser.ID, "1", "to-share").Return(expected, nil).Once()
+
+		c, w := newTagContext(http.MethodDelete, "1", "to-share")
+		handler.RemoveArticleTag(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var actual model.Article
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &actual))
+		assert.Equal(t, expected, actual)
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 記事にタグが付いていない場合
+	t.Run("should return 404 if tag not found", func(t *testing.T) {
+		mockService.On("RemoveArticleTag", testUser.ID, "1", "unknown").Return(model.Article{}, service.ErrTagNotFound).Once()
+
+		c, w := newTagContext(http.MethodDelete, "1", "unknown")
+		handler.RemoveArticleTag(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Tag not found")
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 記事が見つからない場合
+	t.Run("should return 404 if article not found", func(t *testing.T) {
+		mockService.On("RemoveArticleTag", testUser.ID, "other", "research").Return(model.Article{}, service.ErrArticleNotFound).Once()
+
+		c, w := newTagContext(http.MethodDelete, "other", "research")
+		handler.RemoveArticleTag(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Article not found")
+		mockService.AssertExpectations(t)
+	})
+}
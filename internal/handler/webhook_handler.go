@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"feedapp/internal/model"
+	"feedapp/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandler は Webhook 関連のHTTPリクエストを処理します。
+//
+// サポートするエンドポイント:
+//   - GET /webhooks - Webhook 一覧取得
+//   - GET /webhooks/{id} - 特定 Webhook 取得
+//   - POST /webhooks - 新規 Webhook 作成
+//   - PUT /webhooks/{id} - Webhook 更新
+//   - DELETE /webhooks/{id} - Webhook 削除
+//   - GET /webhooks/{id}/deliveries - 配信ログ取得
+type WebhookHandler struct {
+	webhookService service.WebhookService
+}
+
+// NewWebhookHandler は新しい WebhookHandler インスタンスを作成します。
+func NewWebhookHandler(s service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: s,
+	}
+}
+
+// GetAllWebhooks はログイン中のユーザーの Webhook 一覧を取得します。
+//
+//	@Summary		Webhook 一覧取得
+//	@Description	ログイン中のユーザーが作成したすべての Webhook を取得します。署名の鍵は含まれません
+//	@Tags			webhooks
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		model.Webhook		"Webhook 一覧"
+//	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
+//	@Router			/webhooks [get]
+func (h *WebhookHandler) GetAllWebhooks(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	webhooks, err := h.webhookService.GetAllWebhooks(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get webhooks"})
+		return
+	}
+	c.JSON(http.StatusOK, webhooks)
+}
+
+// GetWebhookByID は指定されたIDの Webhook を取得します。
+//
+//	@Summary		Webhook 詳細取得
+//	@Description	指定されたIDの Webhook を取得します。署名の鍵は含まれません
+//	@Tags			webhooks
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		string				true	"Webhook ID (UUID)"
+//	@Success		200	{object}	model.Webhook		"Webhook 詳細"
+//	@Failure		404	{object}	map[string]string	"Webhook が見つかりません"
+//	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
+//	@Router			/webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhookByID(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	webhook, err := h.webhookService.GetWebhookByID(user.ID, c.Param("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrWebhookNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get webhook"})
+		return
+	}
+	c.JSON(http.StatusOK, webhook)
+}
+
+// CreateWebhook は新しい Webhook を作成します。
+//
+//	@Summary		Webhook 作成
+//	@Description	新着記事を通知する Webhook を作成します。通知は `X-FeedApp-Signature: sha256=<HMAC-SHA256>` ヘッダー付きの JSON の POST で送信されます。
+//	@Description	payload_template は text/template 形式で、`.Event`・`.WebhookID`・`.Feed`・`.Article` と、値をJSONとしてエスケープする `json` 関数を使用できます。
+//	@Description	secret を省略した場合は自動生成され、このレスポンスでのみ返されます
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			webhook	body		model.Webhook			true	"Webhook 情報"
+//	@Success		201		{object}	model.Webhook			"作成された Webhook"
+//	@Failure		400		{object}	map[string]interface{}	"リクエストボディの形式が不正、またはフィード・フォルダが存在しない"
+//	@Failure		500		{object}	map[string]string		"サーバー内部エラー"
+//	@Router			/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	var webhook model.Webhook
+	if err := c.ShouldBindJSON(&webhook); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	created, err := h.webhookService.CreateWebhook(user.ID, webhook)
+	if err != nil {
+		if respondWebhookValidationError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create webhook"})
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// UpdateWebhook は指定されたIDの Webhook を更新します。
+//
+//	@Summary		Webhook 更新
+//	@Description	指定されたIDの Webhook を更新します。secret を省略した場合は現在の鍵を維持します
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		string					true	"Webhook ID (UUID)"
+//	@Param			webhook	body		model.Webhook			true	"更新する Webhook 情報"
+//	@Success		200		{object}	model.Webhook			"更新された Webhook"
+//	@Failure		400		{object}	map[string]interface{}	"リクエストボディの形式が不正、またはフィード・フォルダが存在しない"
+//	@Failure		404		{object}	map[string]string		"Webhook が見つかりません"
+//	@Failure		500		{object}	map[string]string		"サーバー内部エラー"
+//	@Router			/webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	id := c.Param("id")
+	var webhook model.Webhook
+	if err := c.ShouldBindJSON(&webhook); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	updated, err := h.webhookService.UpdateWebhook(user.ID, id, webhook)
+	if err != nil {
+		if errors.Is(err, service.ErrWebhookNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+			return
+		}
+		if respondWebhookValidationError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update webhook"})
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteWebhook は指定されたIDの Webhook を削除します。配信ログも削除されます。
+//
+//	@Summary		Webhook 削除
+//	@Description	指定されたIDの Webhook と配信ログを削除します
+//	@Tags			webhooks
+//	@Security		BearerAuth
+//	@Param			id	path	string	true	"Webhook ID (UUID)"
+//	@Success		204	"削除成功"
+//	@Failure		404	{object}	map[string]string	"Webhook が見つかりません"
+//	@Failure		500	{object}	map[string]string	"サーバー内部エラー"
+//	@Router			/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	if err := h.webhookService.DeleteWebhook(user.ID, c.Param("id")); err != nil {
+		if errors.Is(err, service.ErrWebhookNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete webhook"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetWebhookDeliveries は指定されたIDの Webhook の配信ログを取得します。
+//
+//	@Summary		Webhook 配信ログ取得
+//	@Description	指定されたIDの Webhook の配信（送信待ち・再試行待ちを含む）を新しい順に最大100件取得します
+//	@Tags			webhooks
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		string					true	"Webhook ID (UUID)"
+//	@Success		200	{array}		model.WebhookDelivery	"配信ログ"
+//	@Failure		404	{object}	map[string]string		"Webhook が見つかりません"
+//	@Failure		500	{object}	map[string]string		"サーバー内部エラー"
+//	@Router			/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) GetWebhookDeliveries(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	deliveries, err := h.webhookService.GetWebhookDeliveries(user.ID, c.Param("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrWebhookNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get webhook deliveries"})
+		return
+	}
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// respondWebhookValidationError は Webhook の入力値に関するエラーを 400 として返します。
+// 該当するエラーでない場合は何もせず false を返します。
+func respondWebhookValidationError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrInvalidWebhookURL):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Webhook url must be http or https"})
+	case errors.Is(err, service.ErrInvalidWebhookTemplate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payload_template", "details": err.Error()})
+	case errors.Is(err, service.ErrFeedNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid feed_id"})
+	case errors.Is(err, service.ErrFolderNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid folder_id"})
+	default:
+		return false
+	}
+	return true
+}
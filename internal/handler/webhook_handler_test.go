@@ -0,0 +1,342 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"feedapp/internal/model"
+	"feedapp/internal/service"
+)
+
+// MockWebhookService は service.WebhookService のモック実装です。
+type MockWebhookService struct {
+	mock.Mock
+}
+
+func (m *MockWebhookService) GetAllWebhooks(userID string) ([]model.Webhook, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]model.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookService) GetWebhookByID(userID, id string) (model.Webhook, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).(model.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookService) CreateWebhook(userID string, webhook model.Webhook) (model.Webhook, error) {
+	args := m.Called(userID, webhook)
+	return args.Get(0).(model.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookService) UpdateWebhook(userID, id string, webhook model.Webhook) (model.Webhook, error) {
+	args := m.Called(userID, id, webhook)
+	return args.Get(0).(model.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookService) DeleteWebhook(userID, id string) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookService) GetWebhookDeliveries(userID, id string) ([]model.WebhookDelivery, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).([]model.WebhookDelivery), args.Error(1)
+}
+
+// newWebhookContext はログイン済みのテスト用コンテキストを作成します。body が空の場合はリクエストボディを設定しません。
+func newWebhookContext(method, id, body string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set(userContextKey, testUser)
+	if id != "" {
+		c.Params = gin.Params{{Key: "id", Value: id}}
+	}
+	c.Request = httptest.NewRequest(method, "/", bytes.NewBufferString(body))
+	if body != "" {
+		c.Request.Header.Set("Content-Type", "application/json")
+	}
+	return c, w
+}
+
+func TestWebhookHandler_GetAllWebhooks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockWebhookService)
+	handler := NewWebhookHandler(mockService)
+
+	// 正常系: Webhook 一覧を返す
+	t.Run("should return all webhooks", func(t *testing.T) {
+		expected := []model.Webhook{
+			{ID: "1", Name: "Slack", URL: "https://hooks.slack.com/services/T000/B000/XXX"},
+			{ID: "2", Name: "Discord", URL: "https://discord.com/api/webhooks/1/abc", Keyword: "go"},
+		}
+		mockService.On("GetAllWebhooks", testUser.ID).Return(expected, nil).Once()
+
+		c, w := newWebhookContext(http.MethodGet, "", "")
+		handler.GetAllWebhooks(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var actual []model.Webhook
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &actual))
+		assert.Equal(t, expected, actual)
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: サービスエラー
+	t.Run("should return 500 if service error", func(t *testing.T) {
+		mockService.On("GetAllWebhooks", testUser.ID).Return([]model.Webhook{}, assert.AnError).Once()
+
+		c, w := newWebhookContext(http.MethodGet, "", "")
+		handler.GetAllWebhooks(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "Failed to get webhooks")
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestWebhookHandler_GetWebhookByID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockWebhookService)
+	handler := NewWebhookHandler(mockService)
+
+	// 正常系: Webhook を返す
+	t.Run("should return webhook by ID", func(t *testing.T) {
+		expected := model.Webhook{ID: "1", Name: "Slack", URL: "https://example.com/hook"}
+		mockService.On("GetWebhookByID", testUser.ID, "1").Return(expected, nil).Once()
+
+		c, w := newWebhookContext(http.MethodGet, "1", "")
+		handler.GetWebhookByID(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var actual model.Webhook
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &actual))
+		assert.Equal(t, expected, actual)
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 他のユーザーの Webhook または存在しない Webhook
+	t.Run("should return 404 if webhook not found", func(t *testing.T) {
+		mockService.On("GetWebhookByID", testUser.ID, "other").Return(model.Webhook{}, service.ErrWebhookNotFound).Once()
+
+		c, w := newWebhookContext(http.MethodGet, "other", "")
+		handler.GetWebhookByID(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Webhook not found")
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestWebhookHandler_CreateWebhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// 異常系: 必須項目の不足・URL形式の不正はサービスを呼び出さずに 400 を返す
+	t.Run("should return 400 if invalid request body", func(t *testing.T) {
+		mockService := new(MockWebhookService)
+		handler := NewWebhookHandler(mockService)
+		for _, body := range []string{
+			`{"url":"https://example.com/hook"}`,
+			`{"name":"Slack","url":"not a url"}`,
+		} {
+			c, w := newWebhookContext(http.MethodPost, "", body)
+			handler.CreateWebhook(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, body)
+			assert.Contains(t, w.Body.String(), "Invalid input")
+		}
+		mockService.AssertNotCalled(t, "CreateWebhook", mock.Anything, mock.Anything)
+	})
+
+	mockService := new(MockWebhookService)
+	handler := NewWebhookHandler(mockService)
+
+	// 正常系: 作成した Webhook を署名の鍵とともに返す
+	t.Run("should create webhook successfully", func(t *testing.T) {
+		input := model.Webhook{
+			Name:            "Slack",
+			URL:             "https://hooks.slack.com/services/T000/B000/XXX",
+			FeedID:          "feed-1",
+			Keyword:         "golang",
+			PayloadTemplate: `{"text": {{json .Article.Title}}}`,
+		}
+		created := input
+		created.ID = "1"
+		created.Secret = "generated-secret"
+		mockService.On("CreateWebhook", testUser.ID, input).Return(created, nil).Once()
+
+		body, _ := json.Marshal(input)
+		c, w := newWebhookContext(http.MethodPost, "", string(body))
+		handler.CreateWebhook(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var actual model.Webhook
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &actual))
+		assert.Equal(t, created, actual)
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 入力値の検証エラーは 400 を返す
+	validationErrors := []struct {
+		name    string
+		err     error
+		message string
+	}{
+		{"unsupported url scheme", service.ErrInvalidWebhookURL, "Webhook url must be http or https"},
+		{"invalid template", fmt.Errorf("%w: template output is not valid JSON", service.ErrInvalidWebhookTemplate), "Invalid payload_template"},
+		{"unknown feed", service.ErrFeedNotFound, "Invalid feed_id"},
+		{"unknown folder", service.ErrFolderNotFound, "Invalid folder_id"},
+	}
+	for _, tt := range validationErrors {
+		t.Run("should return 400 if "+tt.name, func(t *testing.T) {
+			input := model.Webhook{Name: tt.name, URL: "https://example.com/hook"}
+			mockService.On("CreateWebhook", testUser.ID, input).Return(model.Webhook{}, tt.err).Once()
+
+			body, _ := json.Marshal(input)
+			c, w := newWebhookContext(http.MethodPost, "", string(body))
+			handler.CreateWebhook(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), tt.message)
+			mockService.AssertExpectations(t)
+		})
+	}
+
+	// 異常系: サービスエラー
+	t.Run("should return 500 if service error", func(t *testing.T) {
+		input := model.Webhook{Name: "Error", URL: "https://example.com/hook"}
+		mockService.On("CreateWebhook", testUser.ID, input).Return(model.Webhook{}, assert.AnError).Once()
+
+		body, _ := json.Marshal(input)
+		c, w := newWebhookContext(http.MethodPost, "", string(body))
+		handler.CreateWebhook(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "Failed to create webhook")
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestWebhookHandler_UpdateWebhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockWebhookService)
+	handler := NewWebhookHandler(mockService)
+
+	// 正常系: 更新した Webhook を返す
+	t.Run("should update webhook successfully", func(t *testing.T) {
+		input := model.Webhook{Name: "Renamed", URL: "https://example.com/hook", FolderID: "folder-1"}
+		updated := input
+		updated.ID = "1"
+		mockService.On("UpdateWebhook", testUser.ID, "1", input).Return(updated, nil).Once()
+
+		body, _ := json.Marshal(input)
+		c, w := newWebhookContext(http.MethodPut, "1", string(body))
+		handler.UpdateWebhook(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var actual model.Webhook
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &actual))
+		assert.Equal(t, updated, actual)
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: Webhook が見つからない場合
+	t.Run("should return 404 if webhook not found", func(t *testing.T) {
+		input := model.Webhook{Name: "Renamed", URL: "https://example.com/hook"}
+		mockService.On("UpdateWebhook", testUser.ID, "nonexistent", input).Return(model.Webhook{}, service.ErrWebhookNotFound).Once()
+
+		body, _ := json.Marshal(input)
+		c, w := newWebhookContext(http.MethodPut, "nonexistent", string(body))
+		handler.UpdateWebhook(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Webhook not found")
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: フォルダが見つからない場合
+	t.Run("should return 400 if folder not found", func(t *testing.T) {
+		input := model.Webhook{Name: "Renamed", URL: "https://example.com/hook", FolderID: "other"}
+		mockService.On("UpdateWebhook", testUser.ID, "1", input).Return(model.Webhook{}, service.ErrFolderNotFound).Once()
+
+		body, _ := json.Marshal(input)
+		c, w := newWebhookContext(http.MethodPut, "1", string(body))
+		handler.UpdateWebhook(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid folder_id")
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestWebhookHandler_DeleteWebhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockWebhookService)
+	handler := NewWebhookHandler(mockService)
+
+	// 正常系: 削除成功
+	t.Run("should delete webhook successfully", func(t *testing.T) {
+		mockService.On("DeleteWebhook", testUser.ID, "1").Return(nil).Once()
+
+		c, _ := newWebhookContext(http.MethodDelete, "1", "")
+		handler.DeleteWebhook(c)
+
+		assert.Equal(t, http.StatusNoContent, c.Writer.Status())
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: Webhook が見つからない場合
+	t.Run("should return 404 if webhook not found", func(t *testing.T) {
+		mockService.On("DeleteWebhook", testUser.ID, "nonexistent").Return(service.ErrWebhookNotFound).Once()
+
+		c, w := newWebhookContext(http.MethodDelete, "nonexistent", "")
+		handler.DeleteWebhook(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Webhook not found")
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestWebhookHandler_GetWebhookDeliveries(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockWebhookService)
+	handler := NewWebhookHandler(mockService)
+
+	// 正常系: 配信ログを返す
+	t.Run("should return deliveries", func(t *testing.T) {
+		expected := []model.WebhookDelivery{
+			{ID: "d2", WebhookID: "1", Event: model.WebhookEventArticleCreated, Payload: `{}`, Status: model.WebhookDeliveryPending, Attempts: 1, ResponseStatus: 503, Error: "unexpected status 503"},
+			{ID: "d1", WebhookID: "1", Event: model.WebhookEventArticleCreated, Payload: `{}`, Status: model.WebhookDeliverySucceeded, Attempts: 1, ResponseStatus: 200},
+		}
+		mockService.On("GetWebhookDeliveries", testUser.ID, "1").Return(expected, nil).Once()
+
+		c, w := newWebhookContext(http.MethodGet, "1", "")
+		handler.GetWebhookDeliveries(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var actual []model.WebhookDelivery
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &actual))
+		assert.Equal(t, expected, actual)
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: Webhook が見つからない場合
+	t.Run("should return 404 if webhook not found", func(t *testing.T) {
+		mockService.On("GetWebhookDeliveries", testUser.ID, "other").Return([]model.WebhookDelivery(nil), service.ErrWebhookNotFound).Once()
+
+		c, w := newWebhookContext(http.MethodGet, "other", "")
+		handler.GetWebhookDeliveries(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Webhook not found")
+		mockService.AssertExpectations(t)
+	})
+}
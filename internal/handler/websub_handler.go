@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"feedapp/internal/fetcher"
+	"feedapp/internal/service"
+	"feedapp/internal/websub"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebSubHandler は WebSub のハブからのコールバックを処理します。
+//
+// ハブからアクセスされるため認証は行わず、意図の確認はトピック、更新のプッシュは
+// 購読時に渡した鍵による署名（X-Hub-Signature）で検証します。
+//
+// サポートするエンドポイント:
+//   - GET /websub/callback/:feed_id - 購読・購読解除の意図の確認（hub.challenge を返す）
+//   - POST /websub/callback/:feed_id - 更新されたフィードの受信と取り込み
+type WebSubHandler struct {
+	websubService  service.WebSubService
+	refreshService service.RefreshService
+}
+
+// NewWebSubHandler は新しい WebSubHandler インスタンスを作成します。
+func NewWebSubHandler(websubService service.WebSubService, refreshService service.RefreshService) *WebSubHandler {
+	return &WebSubHandler{
+		websubService:  websubService,
+		refreshService: refreshService,
+	}
+}
+
+// Verify はハブからの意図の確認に応答します。
+// 購読を承認する場合は hub.challenge をそのまま text/plain で返し、拒否する場合は 404 を返します。
+func (h *WebSubHandler) Verify(c *gin.Context) {
+	leaseSeconds, _ := strconv.Atoi(c.Query("hub.lease_seconds"))
+	challenge, err := h.websubService.VerifyIntent(c.Param("feed_id"), service.WebSubVerification{
+		Mode:         c.Query("hub.mode"),
+		Topic:        c.Query("hub.topic"),
+		Challenge:    c.Query("hub.challenge"),
+		LeaseSeconds: leaseSeconds,
+		Reason:       c.Query("hub.reason"),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidWebSubVerification):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid verification request"})
+		case errors.Is(err, service.ErrWebSubNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Subscription not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.String(http.StatusOK, challenge)
+}
+
+// Receive はハブからプッシュされたフィードの内容を取り込みます。
+//
+// 署名が不正な内容は、仕様に従い 2xx を返したうえで取り込まずに破棄します。
+func (h *WebSubHandler) Receive(c *gin.Context) {
+	feedID := c.Param("feed_id")
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, fetcher.DefaultMaxBodySize))
+	if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Request body too large"})
+		return
+	}
+
+	if err := h.websubService.VerifyContent(feedID, c.GetHeader(websub.SignatureHeader), body); err != nil {
+		switch {
+		case errors.Is(err, service.ErrWebSubNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Subscription not found"})
+		case errors.Is(err, service.ErrInvalidWebSubSignature):
+			log.Printf("Ignored websub content for feed %s: %v", feedID, err)
+			c.Status(http.StatusAccepted)
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	created, err := h.refreshService.IngestContent(c.Request.Context(), feedID, body)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrFeedNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Feed not found"})
+		case errors.Is(err, service.ErrInvalidFeedContent):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid feed content"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	log.Printf("Received websub content for feed %s: %d new articles", feedID, created)
+	c.Status(http.StatusNoContent)
+}
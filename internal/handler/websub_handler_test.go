@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"feedapp/internal/model"
+	"feedapp/internal/service"
+	"feedapp/internal/websub"
+	"feedapp/internal/websub/websubtest"
+)
+
+// MockWebSubService は service.WebSubService のモック実装です。
+type MockWebSubService struct {
+	mock.Mock
+}
+
+func (m *MockWebSubService) Discover(ctx context.Context, feed model.Feed, hub, topic string) {
+	m.Called(ctx, feed, hub, topic)
+}
+
+// VerifyIntent は戻り値に func(service.WebSubVerification) string を設定した場合、その結果を challenge として返します。
+func (m *MockWebSubService) VerifyIntent(feedID string, verification service.WebSubVerification) (string, error) {
+	args := m.Called(feedID, verification)
+	if fn, ok := args.Get(0).(func(service.WebSubVerification) string); ok {
+		return fn(verification), args.Error(1)
+	}
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockWebSubService) VerifyContent(feedID, signature string, body []byte) error {
+	args := m.Called(feedID, signature, body)
+	return args.Error(0)
+}
+
+func (m *MockWebSubService) ActiveFeedIDs() (map[string]bool, error) {
+	args := m.Called()
+	return args.Get(0).(map[string]bool), args.Error(1)
+}
+
+func (m *MockWebSubService) Run(ctx context.Context, interval time.Duration) {
+	m.Called(ctx, interval)
+}
+
+// MockRefreshService は service.RefreshService のモック実装です。
+type MockRefreshService struct {
+	mock.Mock
+}
+
+func (m *MockRefreshService) RefreshFeed(ctx context.Context, id string) (int, error) {
+	args := m.Called(ctx, id)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRefreshService) RefreshDueFeeds(ctx context.Context) {
+	m.Called(ctx)
+}
+
+func (m *MockRefreshService) IngestContent(ctx context.Context, id string, data []byte) (int, error) {
+	args := m.Called(ctx, id, data)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRefreshService) Run(ctx context.Context, interval time.Duration) {
+	m.Called(ctx, interval)
+}
+
+// newWebSubServer はコールバックのエンドポイントを提供するテスト用サーバーを起動します。
+func newWebSubServer(t *testing.T, websubService service.WebSubService, refreshService service.RefreshService) *httptest.Server {
+	gin.SetMode(gin.TestMode)
+	handler := NewWebSubHandler(websubService, refreshService)
+	r := gin.New()
+	r.GET("/websub/callback/:feed_id", handler.Verify)
+	r.POST("/websub/callback/:feed_id", handler.Receive)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server
+}
+
+const websubTopic = "https://example.com/feed.xml"
+
+var websubContent = []byte(`<?xml version="1.0"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Pushed</title>
+  <entry><id>tag:example.com,2025:1</id><title>New</title><link href="https://example.com/1"/></entry>
+</feed>`)
+
+func TestWebSubHandler_Verify(t *testing.T) {
+	hub := websubtest.NewHub()
+	defer hub.Close()
+
+	// 正常系: 購読を承認して hub.challenge を返す
+	t.Run("should echo challenge for confirmed subscription", func(t *testing.T) {
+		mockService := new(MockWebSubService)
+		server := newWebSubServer(t, mockService, new(MockRefreshService))
+		mockService.On("VerifyIntent", "feed-1", mock.MatchedBy(func(v service.WebSubVerification) bool {
+			return v.Mode == websub.ModeSubscribe && v.Topic == websubTopic && v.LeaseSeconds == 3600 && v.Challenge != ""
+		})).Return(func(v service.WebSubVerification) string { return v.Challenge }, nil).Once()
+
+		confirmed, err := hub.Verify(websubtest.Subscription{Mode: websub.ModeSubscribe, Topic: websubTopic, Callback: server.URL + "/websub/callback/feed-1", LeaseSeconds: 3600})
+		require.NoError(t, err)
+		assert.True(t, confirmed)
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: 購読していないトピックは 404 で拒否する
+	t.Run("should reject unknown subscription", func(t *testing.T) {
+		mockService := new(MockWebSubService)
+		server := newWebSubServer(t, mockService, new(MockRefreshService))
+		mockService.On("VerifyIntent", "feed-1", mock.Anything).Return("", service.ErrWebSubNotFound).Once()
+
+		confirmed, err := hub.Verify(websubtest.Subscription{Mode: websub.ModeSubscribe, Topic: "https://other.example.com/", Callback: server.URL + "/websub/callback/feed-1"})
+		require.NoError(t, err)
+		assert.False(t, confirmed)
+		mockService.AssertExpectations(t)
+	})
+
+	// 異常系: hub.mode や hub.challenge が不正な場合は 400 を返す
+	t.Run("should return 400 for invalid request", func(t *testing.T) {
+		mockService := new(MockWebSubService)
+		server := newWebSubServer(t, mockService, new(MockRefreshService))
+		mockService.On("VerifyIntent", "feed-1", service.WebSubVerification{Mode: "bogus"}).Return("", service.ErrInvalidWebSubVerification).Once()
+
+		resp, err := http.Get(server.URL + "/websub/callback/feed-1?hub.mode=bogus")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestWebSubHandler_Receive(t *testing.T) {
+	hub := websubtest.NewHub()
+	defer hub.Close()
+
+	// 異常系: 署名が不正な内容は 2xx を返して取り込まない
+	t.Run("should ignore content with invalid signature", func(t *testing.T) {
+		mockService := new(MockWebSubService)
+		mockRefresh := new(MockRefreshService)
+		server := newWebSubServer(t, mockService, mockRefresh)
+		sub := websubtest.Subscription{Topic: websubTopic, Callback: server.URL + "/websub/callback/feed-1", Secret: "wrong"}
+		mockService.On("VerifyContent", "feed-1", websub.Sign("sha256", "wrong", websubContent), websubContent).
+			Return(service.ErrInvalidWebSubSignature).Once()
+
+		status, err := hub.Publish(sub, "application/atom+xml", websubContent)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusAccepted, status)
+		mockRefresh.AssertNotCalled(t, "IngestContent", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	// 正常系: 署名を検証してから取り込む
+	t.Run("should ingest signed content", func(t *testing.T) {
+		mockService := new(MockWebSubService)
+		mockRefresh := new(MockRefreshService)
+		server := newWebSubServer(t, mockService, mockRefresh)
+		sub := websubtest.Subscription{Topic: websubTopic, Callback: server.URL + "/websub/callback/feed-1", Secret: "secret-1"}
+		mockService.On("VerifyContent", "feed-1", websub.Sign("sha256", "secret-1", websubContent), websubContent).Return(nil).Once()
+		mockRefresh.On("IngestContent", mock.Anything, "feed-1", websubContent).Return(1, nil).Once()
+
+		status, err := hub.Publish(sub, "application/atom+xml", websubContent)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, status)
+		mockService.AssertExpectations(t)
+		mockRefresh.AssertExpectations(t)
+	})
+
+	// 異常系: 購読がないフィードは 404 を返す
+	t.Run("should return 404 for unknown subscription", func(t *testing.T) {
+		mockService := new(MockWebSubService)
+		server := newWebSubServer(t, mockService, new(MockRefreshService))
+		sub := websubtest.Subscription{Topic: websubTopic, Callback: server.URL + "/websub/callback/unknown"}
+		mockService.On("VerifyContent", "unknown", "", websubContent).Return(service.ErrWebSubNotFound).Once()
+
+		status, err := hub.Publish(sub, "application/atom+xml", websubContent)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	// 異常系: フィードとして解析できない内容は 400 を返す
+	t.Run("should return 400 for invalid content", func(t *testing.T) {
+		mockService := new(MockWebSubService)
+		mockRefresh := new(MockRefreshService)
+		server := newWebSubServer(t, mockService, mockRefresh)
+		body := []byte("not a feed")
+		sub := websubtest.Subscription{Topic: websubTopic, Callback: server.URL + "/websub/callback/feed-1", Secret: "secret-1"}
+		mockService.On("VerifyContent", "feed-1", mock.Anything, body).Return(nil).Once()
+		mockRefresh.On("IngestContent", mock.Anything, "feed-1", body).Return(0, service.ErrInvalidFeedContent).Once()
+
+		status, err := hub.Publish(sub, "text/plain", body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
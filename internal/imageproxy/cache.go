@@ -0,0 +1,191 @@
+package imageproxy
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Cache は取得した画像をディスクに保存する、合計サイズに上限のある LRU キャッシュです。
+//
+// ファイルはURLの SHA-256 をファイル名とし、先頭行に Content-Type、続けて画像の内容を保存します。
+// 参照順はファイルの更新日時に記録するため、再起動後も引き継がれます。
+type Cache struct {
+	dir      string
+	maxBytes int64
+
+	mu      sync.Mutex
+	size    int64
+	order   *list.List               // 先頭が最近参照したエントリ
+	entries map[string]*list.Element // キーとエントリ
+}
+
+// cacheEntry はキャッシュ済みのファイルです。
+type cacheEntry struct {
+	key  string
+	size int64
+}
+
+// NewCache は dir に合計 maxBytes まで画像を保存する Cache を作成します。
+// dir に保存済みのファイルは更新日時の新しい順に引き継ぎます。
+func NewCache(dir string, maxBytes int64) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create image cache directory: %w", err)
+	}
+	c := &Cache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  map[string]*list.Element{},
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image cache directory: %w", err)
+	}
+	type existing struct {
+		key     string
+		size    int64
+		modTime time.Time
+	}
+	var found []existing
+	for _, f := range files {
+		info, err := f.Info()
+		if err != nil || !info.Mode().IsRegular() || len(f.Name()) != sha256.Size*2 {
+			// 書き込み途中の一時ファイルなどは削除する
+			os.Remove(filepath.Join(dir, f.Name()))
+			continue
+		}
+		found = append(found, existing{key: f.Name(), size: info.Size(), modTime: info.ModTime()})
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].modTime.After(found[j].modTime) })
+	for _, f := range found {
+		c.entries[f.key] = c.order.PushBack(&cacheEntry{key: f.key, size: f.size})
+		c.size += f.size
+	}
+	c.mu.Lock()
+	c.evict()
+	c.mu.Unlock()
+	return c, nil
+}
+
+// Get はURLに対応する画像を取得します。
+func (c *Cache) Get(rawURL string) (contentType string, data []byte, ok bool) {
+	key := cacheKey(rawURL)
+	c.mu.Lock()
+	elem, ok := c.entries[key]
+	if ok {
+		c.order.MoveToFront(elem)
+	}
+	c.mu.Unlock()
+	if !ok {
+		return "", nil, false
+	}
+
+	path := filepath.Join(c.dir, key)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		c.remove(key)
+		return "", nil, false
+	}
+	header, data, found := bytes.Cut(content, []byte("\n"))
+	if !found {
+		c.remove(key)
+		return "", nil, false
+	}
+	now := time.Now()
+	os.Chtimes(path, now, now)
+	return string(header), data, true
+}
+
+// Put は画像を保存し、合計サイズが上限を超えた場合は参照の古いものから削除します。
+// 上限より大きい画像は保存しません。
+func (c *Cache) Put(rawURL, contentType string, data []byte) error {
+	size := int64(len(contentType) + 1 + len(data))
+	if size > c.maxBytes {
+		return nil
+	}
+	key := cacheKey(rawURL)
+	path := filepath.Join(c.dir, key)
+
+	tmp, err := os.CreateTemp(c.dir, key+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create image cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(contentType + "\n"); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write image cache file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write image cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write image cache file: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store image cache file: %w", err)
+	}
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		c.size -= entry.size
+		entry.size = size
+		c.order.MoveToFront(elem)
+	} else {
+		c.entries[key] = c.order.PushFront(&cacheEntry{key: key, size: size})
+	}
+	c.size += size
+	c.evict()
+	return nil
+}
+
+// Size はキャッシュ済みの画像の合計サイズ（バイト）を返します。
+func (c *Cache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// evict は合計サイズが上限以下になるまで参照の古いものから削除します。c.mu を保持して呼び出します。
+func (c *Cache) evict() {
+	for c.size > c.maxBytes {
+		elem := c.order.Back()
+		if elem == nil {
+			return
+		}
+		entry := elem.Value.(*cacheEntry)
+		c.order.Remove(elem)
+		delete(c.entries, entry.key)
+		c.size -= entry.size
+		os.Remove(filepath.Join(c.dir, entry.key))
+	}
+}
+
+// remove は読み出せなくなったエントリを削除します。
+func (c *Cache) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.size -= elem.Value.(*cacheEntry).size
+		c.order.Remove(elem)
+		delete(c.entries, key)
+	}
+	os.Remove(filepath.Join(c.dir, key))
+}
+
+// cacheKey はURLに対応するファイル名を返します。
+func cacheKey(rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return hex.EncodeToString(sum[:])
+}
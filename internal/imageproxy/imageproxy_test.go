@@ -0,0 +1,159 @@
+package imageproxy
+
+import (
+	"context"
+	"encoding/base64"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"feedapp/internal/fetcher"
+)
+
+// pngPixel は 1x1 の PNG 画像です。
+var pngPixel, _ = base64.StdEncoding.DecodeString("iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg==")
+
+func TestSigner(t *testing.T) {
+	signer := NewSigner([]byte("secret"), "https://feeds.example.com/")
+
+	sig := signer.Sign("http://example.com/a.png")
+	assert.True(t, signer.Verify("http://example.com/a.png", sig))
+	assert.False(t, signer.Verify("http://example.com/b.png", sig))
+	assert.False(t, signer.Verify("http://example.com/a.png", "not-hex"))
+	assert.False(t, NewSigner([]byte("other"), "").Verify("http://example.com/a.png", sig))
+
+	proxied, err := url.Parse(signer.URL("http://example.com/a.png"))
+	require.NoError(t, err)
+	assert.Equal(t, "feeds.example.com", proxied.Host)
+	assert.Equal(t, Path, proxied.Path)
+	assert.Equal(t, "http://example.com/a.png", proxied.Query().Get("url"))
+	assert.Equal(t, sig, proxied.Query().Get("sig"))
+
+	// http・https 以外のURLは書き換えない
+	assert.Equal(t, "data:image/gif;base64,R0lGOD", signer.URL("data:image/gif;base64,R0lGOD"))
+	assert.Equal(t, "/relative.png", signer.URL("/relative.png"))
+}
+
+func TestSigner_RewriteHTML(t *testing.T) {
+	signer := NewSigner([]byte("secret"), "")
+
+	content := `<p>Text <img src="http://example.com/a.png" srcset="http://example.com/a.png 1x, https://example.com/a@2x.png 2x" alt="a"></p><a href="http://example.com/">link</a>`
+	rewritten := signer.RewriteHTML(content)
+
+	assert.Contains(t, rewritten, `src="`+html.EscapeString(signer.URL("http://example.com/a.png"))+`"`)
+	assert.Contains(t, rewritten, html.EscapeString(signer.URL("https://example.com/a@2x.png"))+` 2x`)
+	assert.Contains(t, rewritten, `<a href="http://example.com/">link</a>`)
+	assert.NotContains(t, rewritten, `src="http://example.com/a.png"`)
+
+	// 画像がない場合はそのまま返す
+	assert.Equal(t, "<p>No images</p>", signer.RewriteHTML("<p>No images</p>"))
+}
+
+func TestCache(t *testing.T) {
+	// 1件あたり Content-Type の行（10バイト）と内容（10バイト）の20バイト
+	dir := t.TempDir()
+	cache, err := NewCache(dir, 50)
+	require.NoError(t, err)
+
+	require.NoError(t, cache.Put("http://example.com/1", "image/png", []byte("0123456789")))
+	require.NoError(t, cache.Put("http://example.com/2", "image/png", []byte("abcdefghij")))
+
+	contentType, data, ok := cache.Get("http://example.com/1")
+	require.True(t, ok)
+	assert.Equal(t, "image/png", contentType)
+	assert.Equal(t, "0123456789", string(data))
+
+	// 上限を超えた場合は参照の古いもの（2）から削除する
+	require.NoError(t, cache.Put("http://example.com/3", "image/gif", []byte("ABCDEFGHIJ")))
+	_, _, ok = cache.Get("http://example.com/2")
+	assert.False(t, ok)
+	_, _, ok = cache.Get("http://example.com/1")
+	assert.True(t, ok)
+	assert.Equal(t, int64(40), cache.Size())
+
+	// 上限より大きい画像は保存しない
+	require.NoError(t, cache.Put("http://example.com/large", "image/png", []byte(strings.Repeat("x", 41))))
+	_, _, ok = cache.Get("http://example.com/large")
+	assert.False(t, ok)
+
+	// 保存済みのファイルは再作成したキャッシュに引き継ぐ
+	reopened, err := NewCache(dir, 50)
+	require.NoError(t, err)
+	_, data, ok = reopened.Get("http://example.com/3")
+	require.True(t, ok)
+	assert.Equal(t, "ABCDEFGHIJ", string(data))
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+}
+
+func TestProxy_Get(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/image.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(pngPixel)
+		case "/image.svg":
+			w.Header().Set("Content-Type", "image/svg+xml")
+			w.Write([]byte(`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`))
+		case "/disguised.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("<html><body>not an image</body></html>"))
+		case "/large.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(append(pngPixel, make([]byte, 1024)...))
+		}
+	}))
+	defer server.Close()
+
+	cache, err := NewCache(t.TempDir(), DefaultCacheSize)
+	require.NoError(t, err)
+	proxy := New(fetcher.NewClient(fetcher.WithMaxBodySize(512)), cache)
+
+	// 正常系: 取得した画像をキャッシュし、2回目は配信元に問い合わせない
+	t.Run("should fetch and cache image", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			image, err := proxy.Get(context.Background(), server.URL+"/image.png")
+			require.NoError(t, err)
+			assert.Equal(t, "image/png", image.ContentType)
+			assert.Equal(t, pngPixel, image.Data)
+		}
+		assert.Equal(t, 1, requests)
+	})
+
+	// 異常系: SVG と、内容が画像でないものは拒否する
+	t.Run("should reject unsupported types", func(t *testing.T) {
+		_, err := proxy.Get(context.Background(), server.URL+"/image.svg")
+		assert.ErrorIs(t, err, ErrUnsupportedType)
+		_, err = proxy.Get(context.Background(), server.URL+"/disguised.png")
+		assert.ErrorIs(t, err, ErrUnsupportedType)
+	})
+
+	// 異常系: 最大サイズを超える画像
+	t.Run("should reject images larger than the limit", func(t *testing.T) {
+		_, err := proxy.Get(context.Background(), server.URL+"/large.png")
+		assert.ErrorIs(t, err, fetcher.ErrBodyTooLarge)
+	})
+
+	// 異常系: http・https 以外のURL
+	t.Run("should reject invalid urls", func(t *testing.T) {
+		_, err := proxy.Get(context.Background(), "file:///etc/passwd")
+		assert.ErrorIs(t, err, ErrInvalidURL)
+	})
+
+	// 異常系: プライベートアドレスへの接続を拒否するクライアントの場合
+	t.Run("should refuse loopback addresses", func(t *testing.T) {
+		publicOnly := New(fetcher.NewClient(fetcher.WithPublicAddressesOnly()), cache)
+		_, err := publicOnly.Get(context.Background(), server.URL+"/image.png?uncached")
+		assert.ErrorIs(t, err, fetcher.ErrAddressNotAllowed)
+	})
+}
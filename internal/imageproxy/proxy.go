@@ -0,0 +1,105 @@
+package imageproxy
+
+import (
+	"context"
+	"errors"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"feedapp/internal/fetcher"
+)
+
+const (
+	// DefaultMaxSize は1枚の画像のデフォルトの最大サイズ（バイト）です。
+	DefaultMaxSize = 5 << 20
+	// DefaultCacheSize はキャッシュのデフォルトの合計サイズ（バイト）です。
+	DefaultCacheSize = 512 << 20
+)
+
+var (
+	// ErrInvalidURL はURLが http・https の絶対URLでない場合に返されます。
+	ErrInvalidURL = errors.New("invalid image URL")
+	// ErrUnsupportedType は取得した内容が配信を許可する画像形式でない場合に返されます。
+	ErrUnsupportedType = errors.New("unsupported image type")
+)
+
+// allowedTypes は配信を許可する画像のメディアタイプです。
+// スクリプトを含められる SVG は、同じオリジンで配信すると危険なため許可しません。
+var allowedTypes = map[string]bool{
+	"image/jpeg":               true,
+	"image/png":                true,
+	"image/gif":                true,
+	"image/webp":               true,
+	"image/avif":               true,
+	"image/bmp":                true,
+	"image/x-icon":             true,
+	"image/vnd.microsoft.icon": true,
+}
+
+// Image はプロキシが配信する画像です。
+type Image struct {
+	ContentType string
+	Data        []byte
+}
+
+// Proxy は画像を取得し、キャッシュに保存します。
+type Proxy struct {
+	client *fetcher.Client
+	cache  *Cache
+}
+
+// New は client で画像を取得し、cache に保存する Proxy を作成します。
+// 画像の最大サイズと接続先の制限は client の設定で行います。
+func New(client *fetcher.Client, cache *Cache) *Proxy {
+	return &Proxy{client: client, cache: cache}
+}
+
+// Get は画像をキャッシュまたは配信元から取得します。
+//
+// Content-Type が許可する画像形式でない場合や、内容がHTMLなど画像以外と判定される場合は
+// ErrUnsupportedType を返します。
+func (p *Proxy) Get(ctx context.Context, rawURL string) (Image, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return Image{}, ErrInvalidURL
+	}
+	if contentType, data, ok := p.cache.Get(rawURL); ok {
+		return Image{ContentType: contentType, Data: data}, nil
+	}
+
+	resp, err := p.client.Get(ctx, rawURL)
+	if err != nil {
+		return Image{}, err
+	}
+	contentType, ok := imageType(resp)
+	if !ok {
+		return Image{}, ErrUnsupportedType
+	}
+	if err := p.cache.Put(rawURL, contentType, resp.Body); err != nil {
+		// キャッシュに保存できなくても取得した画像は配信する
+		log.Printf("Failed to cache image %q: %v", rawURL, err)
+	}
+	return Image{ContentType: contentType, Data: resp.Body}, nil
+}
+
+// imageType はレスポンスの Content-Type を検証し、配信する Content-Type を返します。
+// 内容から判定した形式が画像でない場合は、Content-Type に関わらず拒否します。
+func imageType(resp *fetcher.Response) (string, bool) {
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return "", false
+	}
+	mediaType = strings.ToLower(mediaType)
+	if !allowedTypes[mediaType] {
+		return "", false
+	}
+	// AVIF など http.DetectContentType が判定できない形式は application/octet-stream となる
+	sniffed := http.DetectContentType(resp.Body)
+	if !strings.HasPrefix(sniffed, "image/") && sniffed != "application/octet-stream" {
+		return "", false
+	}
+	return mediaType, true
+}
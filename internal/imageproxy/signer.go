@@ -0,0 +1,115 @@
+// Package imageproxy は記事中の画像をサーバー経由で配信する画像プロキシを提供します。
+//
+// 記事の本文に埋め込まれた外部の画像を読者のブラウザが直接取得すると、
+// 読者のIPアドレスが画像の配信元に知られ、HTTPS のページでは HTTP の画像が
+// 混在コンテンツとしてブロックされます。本文の画像URLを HMAC で署名した
+// プロキシのURLに書き換え、サーバーが画像を取得してディスクにキャッシュします。
+package imageproxy
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Path は画像プロキシのエンドポイントのパスです。
+const Path = "/api/v1/proxy/image"
+
+// Signer は画像URLの署名と、プロキシのURLへの書き換えを行います。
+type Signer struct {
+	key     []byte
+	baseURL string
+}
+
+// NewSigner は key で署名する Signer を作成します。
+// baseURL はプロキシのURLの前に付けるこのサーバーのURL（例: https://feeds.example.com）で、
+// 空の場合はパスのみのURLとなります。
+func NewSigner(key []byte, baseURL string) *Signer {
+	return &Signer{key: key, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// Sign は画像URLの署名（HMAC-SHA256 の16進表記）を返します。
+func (s *Signer) Sign(rawURL string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(rawURL))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify は署名が画像URLに対するものかを検証します。
+func (s *Signer) Verify(rawURL, sig string) bool {
+	expected, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(rawURL))
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// URL は画像URLをプロキシのURLに変換します。http・https 以外のURLはそのまま返します。
+func (s *Signer) URL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return rawURL
+	}
+	query := url.Values{"url": {rawURL}, "sig": {s.Sign(rawURL)}}
+	return s.baseURL + Path + "?" + query.Encode()
+}
+
+// RewriteHTML はHTML断片の img 要素の src・srcset をプロキシのURLに書き換えます。
+// 解析できない場合は元のHTMLを返します。
+func (s *Signer) RewriteHTML(content string) string {
+	if !strings.Contains(content, "<img") && !strings.Contains(content, "<IMG") {
+		return content
+	}
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(content), body)
+	if err != nil {
+		return content
+	}
+	var b bytes.Buffer
+	for _, n := range nodes {
+		s.rewriteNode(n)
+		if err := html.Render(&b, n); err != nil {
+			return content
+		}
+	}
+	return b.String()
+}
+
+// rewriteNode は要素を再帰的に処理し、img 要素の画像URLを書き換えます。
+func (s *Signer) rewriteNode(n *html.Node) {
+	if n.Type == html.ElementNode && n.DataAtom == atom.Img {
+		for i, a := range n.Attr {
+			switch a.Key {
+			case "src":
+				n.Attr[i].Val = s.URL(strings.TrimSpace(a.Val))
+			case "srcset":
+				n.Attr[i].Val = s.rewriteSrcset(a.Val)
+			}
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		s.rewriteNode(c)
+	}
+}
+
+// rewriteSrcset は srcset の各候補のURLを書き換えます。
+func (s *Signer) rewriteSrcset(srcset string) string {
+	candidates := strings.Split(srcset, ",")
+	for i, candidate := range candidates {
+		fields := strings.Fields(candidate)
+		if len(fields) == 0 {
+			continue
+		}
+		fields[0] = s.URL(fields[0])
+		candidates[i] = strings.Join(fields, " ")
+	}
+	return strings.Join(candidates, ", ")
+}
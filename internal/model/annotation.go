@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+// 注釈の色です。
+const (
+	AnnotationColorYellow = "yellow"
+	AnnotationColorGreen  = "green"
+	AnnotationColorBlue   = "blue"
+	AnnotationColorPink   = "pink"
+	AnnotationColorPurple = "purple"
+)
+
+// TextQuoteSelectorType は W3C Web Annotation の TextQuoteSelector を表す selector の type です。
+const TextQuoteSelectorType = "TextQuoteSelector"
+
+// TextQuoteSelector は注釈を付けた本文中の箇所を、引用した文字列とその前後の文字列で特定します。
+//
+// W3C Web Annotation Data Model の TextQuoteSelector と同じ形式です。
+// 本文が再抽出などで変わっても、前後の文字列から元の箇所を探せます。
+//
+// JSON tags:
+//   - type: 常に "TextQuoteSelector"
+//   - exact: 引用した文字列（必須）
+//   - prefix: 引用の直前の文字列（省略可能）
+//   - suffix: 引用の直後の文字列（省略可能）
+type TextQuoteSelector struct {
+	Type   string `json:"type"`                                // セレクターの種別
+	Exact  string `json:"exact" binding:"required,max=10000"`  // 引用した文字列（必須）
+	Prefix string `json:"prefix,omitempty" binding:"max=1000"` // 引用の直前の文字列
+	Suffix string `json:"suffix,omitempty" binding:"max=1000"` // 引用の直後の文字列
+}
+
+// Annotation はユーザーが記事の本文に付けた注釈（ハイライトとメモ）のデータモデルです。
+//
+// 注釈はユーザーごとに管理し、他のユーザーからは参照できません。
+//
+// JSON tags:
+//   - id: 注釈の一意識別子（UUID形式）
+//   - article_id: 注釈を付けた記事のID
+//   - article_title: 記事のタイトル（レスポンスのみ）
+//   - article_url: 記事の元URL（レスポンスのみ）
+//   - selector: 注釈を付けた本文中の箇所（必須）
+//   - body: メモ（省略可能）
+//   - color: ハイライトの色（"yellow", "green", "blue", "pink", "purple"。省略時は "yellow"）
+//   - created_at: 注釈の作成日時
+//   - updated_at: 注釈の最終更新日時
+type Annotation struct {
+	ID           string            `json:"id"`                                                                      // 注釈の一意識別子
+	UserID       string            `json:"-"`                                                                       // 所有者のユーザーID
+	ArticleID    string            `json:"article_id"`                                                              // 記事ID
+	ArticleTitle string            `json:"article_title,omitempty"`                                                 // 記事のタイトル
+	ArticleURL   string            `json:"article_url,omitempty"`                                                   // 記事の元URL
+	Selector     TextQuoteSelector `json:"selector"`                                                                // 注釈を付けた箇所（必須）
+	Body         string            `json:"body,omitempty" binding:"max=100000"`                                     // メモ
+	Color        string            `json:"color,omitempty" binding:"omitempty,oneof=yellow green blue pink purple"` // ハイライトの色
+	CreatedAt    time.Time         `json:"created_at"`                                                              // 作成日時
+	UpdatedAt    time.Time         `json:"updated_at"`                                                              // 最終更新日時
+}
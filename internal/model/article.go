@@ -6,7 +6,8 @@ import "time"
 //
 // 記事はフィードから取得される個々のコンテンツを表現します。
 // 各記事は読了状態と「後で見る」状態を管理し、ユーザーの読書習慣を
-// サポートします。記事は削除されることなく永続的に保存されます。
+// サポートします。記事は保持設定（全体設定またはフィードごとの retention_days・max_articles）に従って
+// 古いものから削除されますが、「後で見る」・タグ・注釈が付いた記事は削除されません。
 //
 // 状態管理:
 //   - 未読 + 通常
@@ -17,21 +18,103 @@ import "time"
 // JSON tags:
 //   - id: 記事の一意識別子（UUID形式）
 //   - feed_id: 記事が属するフィードのID
+//   - guid: フィード内でのエントリー識別子（RSSのguid、Atomのid）
 //   - title: 記事のタイトル
-//   - content: 記事の本文（省略可能）
+//   - author: 記事の著者（省略可能）
+//   - summary: 記事の要約（省略可能、RSSのdescription、Atomのsummary）
+//   - content: 記事の本文（省略可能、本文抽出を行った場合は記事ページから抽出した本文）
 //   - url: 記事の元URLリンク
+//   - image_url: 記事のサムネイル画像URL（省略可能）
+//   - categories: 記事のカテゴリ一覧（省略可能）
+//   - enclosures: 記事の添付メディア一覧（省略可能、ポッドキャストや動画）
+//   - episode: ポッドキャストのエピソード番号（省略可能）
 //   - published_at: 記事の公開日時
 //   - is_read: 既読フラグ
 //   - is_later: 後で見るフラグ
+//   - playback_position_seconds: 添付メディアの再生位置（秒）
+//   - played: 添付メディアの再生済みフラグ
+//   - downloaded: 添付メディアのダウンロード済みフラグ
+//   - tags: ユーザーが記事に付けたタグ一覧（省略可能）
+//   - snapshot_status: 「後で見る」に追加したときに保存する元ページのスナップショットの取得状況（pending・ready・failed、未取得の場合は省略）
+//   - extracted_at: 記事ページから本文を抽出した日時（未抽出の場合は省略）
 //   - created_at: 記事の作成日時
+//
+// content と summary はサニタイズ済みのHTMLです。サニタイズ前の元HTMLは
+// RawContent / RawSummary に保持され、APIレスポンスには含まれません。
+//
+// 認証情報を設定したフィードの記事は IsPrivate となり、同じURLの記事が他のフィードにあっても
+// 統合せずにフィードごとに保持します。
 type Article struct {
 	ID         string    `json:"id"`                   // 記事の一意識別子
 	FeedID     string    `json:"feed_id"`              // 所属フィードID
+	GUID       string    `json:"guid,omitempty"`       // feed_id のフィードでのエントリー識別子
 	Title      string    `json:"title"`                // 記事タイトル
+	Author     string    `json:"author,omitempty"`     // 著者
+	Summary    string    `json:"summary,omitempty"`    // 要約
 	Content    string    `json:"content,omitempty"`    // 記事本文（省略可能）
 	URL        string    `json:"url"`                  // 記事の元URL
+	ImageURL   string    `json:"image_url,omitempty"`  // サムネイル画像URL
+	Categories []string  `json:"categories,omitempty"` // カテゴリ一覧
+	Enclosures []Enclosure `json:"enclosures,omitempty"` // 添付メディア一覧
+	Episode    int       `json:"episode,omitempty"`    // エピソード番号
 	PublishedAt time.Time `json:"published_at"`        // 公開日時
 	IsRead     bool      `json:"is_read"`              // 既読フラグ
 	IsLater    bool      `json:"is_later"`             // 後で見るフラグ
+	PlaybackPosition int `json:"playback_position_seconds"` // 再生位置（秒）
+	Played     bool      `json:"played"`               // 再生済みフラグ
+	Downloaded bool      `json:"downloaded"`           // ダウンロード済みフラグ
+	Tags       []string  `json:"tags,omitempty"`       // タグ一覧
+	SnapshotStatus string `json:"snapshot_status,omitempty"` // 元ページのスナップショットの取得状況（SnapshotStatus* 定数）
+	ExtractedAt *time.Time `json:"extracted_at,omitempty"` // 本文抽出日時
 	CreatedAt  time.Time `json:"created_at"`           // 作成日時
+
+	RawContent       string `json:"-"` // サニタイズ前の本文
+	RawSummary       string `json:"-"` // サニタイズ前の要約
+	SanitizerVersion int    `json:"-"` // 適用済みのサニタイズルールのバージョン
+	ItemID           int64  `json:"-"` // 整数IDを必要とするクライアント向けの連番（取り込み順）
+	IsPrivate        bool   `json:"-"` // 認証情報を設定したフィードの記事（他のフィードと共有しない）
+}
+
+// Enclosure は記事に添付されたメディアファイルを表します。
+//
+// RSS の <enclosure>、Atom の <link rel="enclosure">、Media RSS の
+// <media:content> から取得され、ポッドキャストの音声や動画の再生に使用されます。
+//
+// JSON tags:
+//   - url: メディアファイルのURL
+//   - mime_type: MIMEタイプ（例: "audio/mpeg"）
+//   - length: ファイルサイズ（バイト、不明な場合は0）
+//   - duration: 再生時間（秒、不明な場合は0）
+type Enclosure struct {
+	URL      string `json:"url"`                 // メディアURL
+	MimeType string `json:"mime_type,omitempty"` // MIMEタイプ
+	Length   int64  `json:"length,omitempty"`    // ファイルサイズ（バイト）
+	Duration int    `json:"duration,omitempty"`  // 再生時間（秒）
+}
+
+// 添付メディアの種別を表す定数です。ArticleFilter.EnclosureType に使用します。
+const (
+	EnclosureTypeAny   = "any"   // 種別を問わず添付メディアあり
+	EnclosureTypeAudio = "audio" // 音声（audio/*）
+	EnclosureTypeVideo = "video" // 動画（video/*）
+	EnclosureTypeImage = "image" // 画像（image/*）
+)
+
+// ArticleFilter は記事一覧の絞り込み条件です。ゼロ値は絞り込みなしを表します。
+//
+// 記事は連番（Article.ItemID）の降順、OldestFirst の場合は昇順で返されます。
+// Cursor には前のページの最後の記事の連番を指定し、続きの記事を取得します。
+type ArticleFilter struct {
+	EnclosureType string    // 添付メディアの種別（EnclosureType* 定数のいずれか）
+	FeedID        string    // 指定フィードが配信した記事
+	FolderID      string    // 指定フォルダのフィードが配信した記事
+	IsRead        *bool     // 既読状態
+	IsLater       *bool     // 後で見る状態
+	ItemIDs       []int64   // 連番のいずれかに一致する記事
+	Since         time.Time // 作成日時がこの日時以降の記事
+	Until         time.Time // 作成日時がこの日時より前の記事
+	Tag           string    // 指定タグが付いた記事
+	OldestFirst   bool      // 古い順に返す
+	Cursor        int64     // この連番の記事より後（並び順で）の記事
+	Limit         int       // 最大件数（0は無制限）
 }
@@ -8,6 +8,12 @@ import "time"
 // 各フィードは特定のURLから定期的に記事を取得し、指定されたフォルダに分類されます。
 // プラグインシステムにより、RSS以外の様々なソースにも対応可能です。
 //
+// APIではユーザーの購読として扱い、name・folder_id・created_at は購読ごとの値となります。
+// url・plugin_type・update_interval・fetch_full_content・retention_days・max_articles・
+// rate_limit・max_connections・respect_robots・auth・proxy は同じフィードを購読するすべてのユーザーで共有されます。
+// 更新時に省略した共有の設定項目は保存済みの値を維持し、null を指定した項目（ClearedFields）は全体設定に戻します。
+// 共有の設定項目は、認証情報の削除を除いて購読者が1人のフィードでのみ変更できます。
+//
 // JSON tags:
 //   - id: フィードの一意識別子（UUID形式）
 //   - name: フィードの表示名（必須フィールド）
@@ -15,6 +21,15 @@ import "time"
 //   - plugin_type: 使用するプラグインの種別（例: "rss", "custom"）
 //   - folder_id: 所属するフォルダのID（省略可能）
 //   - update_interval: 更新間隔（分単位）
+//   - fetch_full_content: 新着記事の本文を記事ページから抽出するかどうか
+//   - retention_days: 既読記事を保持する日数（省略時は全体設定、0は無期限。購読者が1人のフィードでのみ変更可能）
+//   - max_articles: フィードごとに保持する記事の上限件数（省略時は全体設定、0は無制限。購読者が1人のフィードでのみ変更可能）
+//   - rate_limit: 取得先のホストへの1秒あたりのリクエスト数（全体設定より少ない場合のみ適用。省略時と0は全体設定のみ）
+//   - max_connections: 取得先のホストへの同時リクエスト数（全体設定より少ない場合のみ適用。省略時と0は全体設定のみ）
+//   - respect_robots: 取得前に robots.txt を確認するかどうか（省略時はプラグイン種別ごとの全体設定）
+//   - auth: 取得時の認証情報とリクエストヘッダー（応答ではユーザー名以外を伏せ字にします）
+//   - proxy: 取得時に使用するプロキシのURL（http・https・socks5。省略時は全体設定。応答ではパスワードを伏せ字にします）
+//   - parse_warning: 直近の取得で解析時に回復した問題（文字コードの誤りや不正なXMLなど。読み取り専用）
 //   - last_updated: 最後に更新された日時
 //   - created_at: フィードの作成日時
 type Feed struct {
@@ -24,6 +39,18 @@ type Feed struct {
 	PluginType     string    `json:"plugin_type" binding:"required"` // プラグイン種別（必須）
 	FolderID       string    `json:"folder_id,omitempty"`       // 所属フォルダID
 	UpdateInterval int       `json:"update_interval"`           // 更新間隔（分）
+	FetchFullContent bool    `json:"fetch_full_content"`        // 本文抽出フラグ
+	RetentionDays  *int      `json:"retention_days,omitempty" binding:"omitempty,min=0"` // 既読記事の保持日数（nil は全体設定）
+	MaxArticles    *int      `json:"max_articles,omitempty" binding:"omitempty,min=0"`   // 保持する記事の上限件数（nil は全体設定）
+	RateLimit      *float64  `json:"rate_limit,omitempty" binding:"omitempty,min=0"`     // ホストへの1秒あたりのリクエスト数（nil は全体設定）
+	MaxConnections *int      `json:"max_connections,omitempty" binding:"omitempty,min=0"` // ホストへの同時リクエスト数（nil は全体設定）
+	RespectRobots  *bool     `json:"respect_robots,omitempty"`                           // robots.txt を確認するかどうか（nil は全体設定）
+	Auth           *FeedAuth `json:"auth,omitempty"`                                     // 取得時の認証情報（nil は認証なし）
+	Proxy          string    `json:"proxy,omitempty"`                                    // 取得時のプロキシのURL（空は全体設定）
+	ParseWarning   string    `json:"parse_warning,omitempty"`                            // 直近の取得での解析時の警告（空は問題なし）
 	LastUpdated    time.Time `json:"last_updated,omitempty"`    // 最終更新日時
 	CreatedAt      time.Time `json:"created_at"`                // 作成日時
+	IntID          int64     `json:"-"`                         // 整数IDを必要とするクライアント向けの連番
+	ClearedFields  []string  `json:"-"`                         // 更新時に null を指定した項目のJSON名（全体設定に戻す）
+	OmittedFields  []string  `json:"-"`                         // 更新時に省略した update_interval・fetch_full_content のJSON名（保存済みの値を維持する）
 }
@@ -0,0 +1,110 @@
+package model
+
+import (
+	"encoding/base64"
+	"net/http"
+)
+
+// RedactedSecret は API の応答で秘密情報の代わりに返す値です。
+// 更新時にこの値を指定した項目は、保存済みの値を維持します。
+const RedactedSecret = "********"
+
+// FeedAuth はフィードの取得に使用する認証情報とリクエストヘッダーです。
+//
+// 認証情報は暗号化して保存し、API の応答ではユーザー名以外の値を RedactedSecret に置き換えます。
+// 認証情報を設定したフィードは、同じURLでも他のユーザーとは共有しません。
+//
+// JSON tags:
+//   - username: Basic 認証のユーザー名
+//   - password: Basic 認証のパスワード
+//   - token: Bearer トークン（username とは同時に指定できません）
+//   - cookie: Cookie ヘッダーの値
+//   - headers: 任意のリクエストヘッダー（ヘッダー名と値）
+type FeedAuth struct {
+	Username string            `json:"username,omitempty"`
+	Password string            `json:"password,omitempty"`
+	Token    string            `json:"token,omitempty"`
+	Cookie   string            `json:"cookie,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+}
+
+// IsZero は認証情報とヘッダーが何も設定されていないかを返します。
+func (a *FeedAuth) IsZero() bool {
+	return a == nil || (a.Username == "" && a.Password == "" && a.Token == "" && a.Cookie == "" && len(a.Headers) == 0)
+}
+
+// Header はフィードの取得時に付与するリクエストヘッダーを返します。
+func (a *FeedAuth) Header() http.Header {
+	header := http.Header{}
+	if a.IsZero() {
+		return header
+	}
+	for name, value := range a.Headers {
+		header.Set(name, value)
+	}
+	switch {
+	case a.Username != "" || a.Password != "":
+		header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(a.Username+":"+a.Password)))
+	case a.Token != "":
+		header.Set("Authorization", "Bearer "+a.Token)
+	}
+	if a.Cookie != "" {
+		header.Set("Cookie", a.Cookie)
+	}
+	return header
+}
+
+// Redacted はユーザー名以外の値を RedactedSecret に置き換えた認証情報を返します。
+func (a *FeedAuth) Redacted() *FeedAuth {
+	if a.IsZero() {
+		return nil
+	}
+	redacted := &FeedAuth{Username: a.Username}
+	if a.Password != "" {
+		redacted.Password = RedactedSecret
+	}
+	if a.Token != "" {
+		redacted.Token = RedactedSecret
+	}
+	if a.Cookie != "" {
+		redacted.Cookie = RedactedSecret
+	}
+	if len(a.Headers) > 0 {
+		redacted.Headers = make(map[string]string, len(a.Headers))
+		for name := range a.Headers {
+			redacted.Headers[name] = RedactedSecret
+		}
+	}
+	return redacted
+}
+
+// Restore は RedactedSecret を指定した項目を current の値に戻した認証情報を返します。
+// ヘッダーは同じ名前のヘッダーの値に戻します。
+func (a *FeedAuth) Restore(current *FeedAuth) *FeedAuth {
+	if a == nil {
+		return nil
+	}
+	if current == nil {
+		current = &FeedAuth{}
+	}
+	restored := *a
+	if restored.Password == RedactedSecret {
+		restored.Password = current.Password
+	}
+	if restored.Token == RedactedSecret {
+		restored.Token = current.Token
+	}
+	if restored.Cookie == RedactedSecret {
+		restored.Cookie = current.Cookie
+	}
+	if len(a.Headers) > 0 {
+		restored.Headers = make(map[string]string, len(a.Headers))
+		for name, value := range a.Headers {
+			if value == RedactedSecret {
+				value = current.Headers[name]
+			}
+			restored.Headers[name] = value
+		}
+	}
+	return &restored
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeedAuth_Header(t *testing.T) {
+	basic := &FeedAuth{Username: "user", Password: "pass", Cookie: "session=abc", Headers: map[string]string{"X-Api-Key": "key"}}
+	header := basic.Header()
+	assert.Equal(t, "Basic dXNlcjpwYXNz", header.Get("Authorization"))
+	assert.Equal(t, "session=abc", header.Get("Cookie"))
+	assert.Equal(t, "key", header.Get("X-Api-Key"))
+
+	bearer := &FeedAuth{Token: "token", Headers: map[string]string{"Authorization": "ignored"}}
+	assert.Equal(t, "Bearer token", bearer.Header().Get("Authorization"))
+
+	var none *FeedAuth
+	assert.Empty(t, none.Header())
+}
+
+func TestFeedAuth_RedactedAndRestore(t *testing.T) {
+	current := &FeedAuth{Username: "user", Password: "pass", Cookie: "session=abc", Headers: map[string]string{"X-Api-Key": "key"}}
+
+	redacted := current.Redacted()
+	assert.Equal(t, &FeedAuth{Username: "user", Password: RedactedSecret, Cookie: RedactedSecret, Headers: map[string]string{"X-Api-Key": RedactedSecret}}, redacted)
+	assert.Nil(t, (&FeedAuth{}).Redacted())
+
+	// 伏せ字のまま送り返した項目は保存済みの値に戻す
+	assert.Equal(t, current, redacted.Restore(current))
+
+	// 変更した項目は新しい値を使用する
+	updated := &FeedAuth{Username: "user", Password: "new", Headers: map[string]string{"X-Api-Key": RedactedSecret, "X-Other": "1"}}
+	assert.Equal(t, &FeedAuth{Username: "user", Password: "new", Headers: map[string]string{"X-Api-Key": "key", "X-Other": "1"}}, updated.Restore(current))
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// FeedIcon はフィードのサイトのアイコン（ファビコン）です。
+//
+// アイコンはフィードごとに1件で、すべての購読者に共有されます。
+// Hash は内容の SHA-256 で、配信時の ETag に使用します。
+type FeedIcon struct {
+	FeedID    string    // フィードID
+	URL       string    // 取得元のURL
+	MimeType  string    // 画像のメディアタイプ
+	Hash      string    // 内容の SHA-256（16進表記）
+	Data      []byte    // 画像の内容
+	FetchedAt time.Time // 取得した日時
+}
@@ -0,0 +1,21 @@
+package model
+
+import "slices"
+
+// FetchPolicy はフィードの取得先のサイトへの配慮に関する全体の既定値です。
+// フィードの rate_limit・max_connections・respect_robots が未設定の場合に使用します。
+type FetchPolicy struct {
+	RequestsPerSecond    float64  // ホストごとの1秒あたりのリクエスト数（0 は無制限）
+	Burst                int      // ホストごとに間隔を空けずに発行できるリクエスト数
+	MaxConcurrentPerHost int      // ホストごとの同時リクエスト数（0 は無制限）
+	MaxConcurrentFeeds   int      // 定期取得で同時に取得するフィード数（0 以下の場合は既定値）
+	RobotsPluginTypes    []string // robots.txt を確認するプラグイン種別
+}
+
+// RespectsRobots はフィードの取得前に robots.txt を確認するかを返します。
+func (p FetchPolicy) RespectsRobots(feed Feed) bool {
+	if feed.RespectRobots != nil {
+		return *feed.RespectRobots
+	}
+	return slices.Contains(p.RobotsPluginTypes, feed.PluginType)
+}
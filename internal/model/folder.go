@@ -19,13 +19,14 @@ import (
 // JSON tags:
 //   - id: フォルダの一意識別子（UUID形式）
 //   - name: フォルダの名前（必須フィールド）
-//   - user_id: 所有者のユーザーID（将来のマルチユーザー対応用、現在は未使用）
+//   - user_id: 所有者のユーザーID
 //   - created_at: フォルダの作成日時
 type Folder struct {
 	ID        string    `json:"id"`                   // フォルダの一意識別子
 	Name      string    `json:"name" binding:"required"` // フォルダ名（必須）
-	UserID    string    `json:"user_id,omitempty"`    // 将来のマルチユーザー対応用
+	UserID    string    `json:"user_id,omitempty"`    // 所有者のユーザーID
 	CreatedAt time.Time `json:"created_at"`           // 作成日時
+	IntID     int64     `json:"-"`                    // 整数IDを必要とするクライアント向けの連番
 }
 
 // GenerateUUID は新しいUUIDを生成して文字列で返します。
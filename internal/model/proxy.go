@@ -0,0 +1,39 @@
+package model
+
+import (
+	"net/url"
+	"strings"
+)
+
+// RedactProxyURL はプロキシのURLのパスワードを RedactedSecret に置き換えます。
+func RedactProxyURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return raw
+	}
+	if _, ok := u.User.Password(); !ok {
+		return raw
+	}
+	// url.UserPassword は "*" をエスケープするため、伏せ字はそのまま埋め込む
+	userinfo := url.User(u.User.Username()).String() + ":" + RedactedSecret + "@"
+	u.User = nil
+	return strings.Replace(u.String(), "://", "://"+userinfo, 1)
+}
+
+// RestoreProxyURL はプロキシのURLのパスワードが RedactedSecret の場合に、current のパスワードに戻します。
+// ユーザー名・ホスト・ポートが current と異なる場合は戻しません。
+func RestoreProxyURL(raw, current string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return raw
+	}
+	if password, ok := u.User.Password(); !ok || password != RedactedSecret {
+		return raw
+	}
+	c, err := url.Parse(current)
+	if err != nil || c.User == nil || c.Host != u.Host || c.User.Username() != u.User.Username() {
+		return raw
+	}
+	u.User = c.User
+	return u.String()
+}
@@ -0,0 +1,26 @@
+package model
+
+// RetentionPolicy は記事の保持設定の全体の既定値です。
+// フィードの retention_days・max_articles が未設定の場合に使用し、0 は無期限・無制限を表します。
+type RetentionPolicy struct {
+	Days               int // 既読記事を保持する日数
+	MaxArticlesPerFeed int // フィードごとに保持する記事の上限件数
+}
+
+// PurgeResult は保持設定による記事の削除結果です。
+//
+// JSON tags:
+//   - dry_run: 削除せずに対象の件数のみを集計したかどうか
+//   - deleted: 削除した（dry_run の場合は削除対象の）記事の件数
+//   - feeds: フィードごとの件数（件数が0のフィードは含みません）
+type PurgeResult struct {
+	DryRun  bool              `json:"dry_run"`
+	Deleted int               `json:"deleted"`
+	Feeds   []FeedPurgeResult `json:"feeds"`
+}
+
+// FeedPurgeResult はフィードごとの記事の削除件数です。
+type FeedPurgeResult struct {
+	FeedID  string `json:"feed_id"`
+	Deleted int    `json:"deleted"`
+}
@@ -0,0 +1,76 @@
+package model
+
+import "time"
+
+// ルールの条件で参照する記事のフィールドです。
+const (
+	RuleFieldTitle   = "title"   // タイトル
+	RuleFieldContent = "content" // 本文（HTML。本文が空の場合は要約）
+	RuleFieldAuthor  = "author"  // 著者
+	RuleFieldURL     = "url"     // 記事のURL
+)
+
+// ルールの条件の比較方法です。
+const (
+	RuleOperatorContains = "contains" // 部分一致
+	RuleOperatorRegex    = "regex"    // 正規表現（Go の regexp/syntax）
+)
+
+// ルールの条件に一致した記事に対するアクションです。
+const (
+	RuleActionMarkRead  = "mark_read"  // 既読にする
+	RuleActionMarkLater = "mark_later" // 後で見るに追加する
+	RuleActionSkip      = "skip"       // 取り込まない（一覧に表示しない）
+	RuleActionAddTag    = "add_tag"    // タグを付ける
+)
+
+// RuleCondition はルールの1件の条件です。
+//
+// JSON tags:
+//   - field: 対象のフィールド（"title", "content", "author", "url"）
+//   - operator: 比較方法（"contains", "regex"）
+//   - value: 比較する文字列または正規表現
+//   - ignore_case: 大文字小文字を区別しないか
+type RuleCondition struct {
+	Field      string `json:"field" binding:"required,oneof=title content author url"` // 対象のフィールド
+	Operator   string `json:"operator" binding:"required,oneof=contains regex"`        // 比較方法
+	Value      string `json:"value" binding:"required,max=1000"`                       // 比較する値
+	IgnoreCase bool   `json:"ignore_case"`                                             // 大文字小文字を区別しない
+}
+
+// Rule は取り込む記事を条件で自動的に処理するルールのデータモデルです。
+//
+// 記事の取り込み時に、購読者ごとに適用範囲（すべてのフィード・フォルダ・フィード）と
+// すべての条件に一致するルールのアクションを実行します。フィードは購読者全員で共有するため、
+// skip はそのユーザーの一覧から記事を除外し、購読者全員が skip した記事のみ作成しません。
+//
+// JSON tags:
+//   - id: ルールの一意識別子（UUID形式）
+//   - name: ルールの表示名（必須）
+//   - folder_id: 適用するフォルダID（省略可能。feed_id と同時には指定できない）
+//   - feed_id: 適用するフィードID（省略可能。いずれも省略した場合はすべてのフィードに適用）
+//   - conditions: 条件の一覧（1件以上、すべてに一致した記事にアクションを実行）
+//   - action: アクション（"mark_read", "mark_later", "skip", "add_tag"）
+//   - tag: add_tag で付けるタグ名（add_tag の場合は必須）
+//   - created_at: ルールの作成日時
+type Rule struct {
+	ID         string          `json:"id"`                                                                // ルールの一意識別子
+	UserID     string          `json:"-"`                                                                 // 所有者のユーザーID
+	Name       string          `json:"name" binding:"required,max=255"`                                   // 表示名（必須）
+	FolderID   string          `json:"folder_id,omitempty"`                                               // 適用するフォルダID
+	FeedID     string          `json:"feed_id,omitempty"`                                                 // 適用するフィードID
+	Conditions []RuleCondition `json:"conditions" binding:"required,min=1,dive"`                          // 条件（必須）
+	Action     string          `json:"action" binding:"required,oneof=mark_read mark_later skip add_tag"` // アクション（必須）
+	Tag        string          `json:"tag,omitempty" binding:"max=64"`                                    // 付けるタグ名
+	CreatedAt  time.Time       `json:"created_at"`                                                        // 作成日時
+}
+
+// RuleApplyResult は既存の記事へのルールの適用結果です。
+//
+// JSON tags:
+//   - matched: 条件に一致した記事の件数
+//   - dry_run: 件数の確認のみで、アクションを実行していない場合に true
+type RuleApplyResult struct {
+	Matched int  `json:"matched"` // 条件に一致した記事の件数
+	DryRun  bool `json:"dry_run"` // 件数の確認のみか
+}
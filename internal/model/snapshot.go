@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// スナップショットの取得状況を表す定数です。Article.SnapshotStatus に使用します。
+const (
+	SnapshotStatusPending = "pending" // 取得待ち
+	SnapshotStatusReady   = "ready"   // 取得済み
+	SnapshotStatusFailed  = "failed"  // 取得失敗
+)
+
+// ArticleSnapshot は記事の元ページのスナップショットです。
+//
+// スナップショットは画像とスタイルシートを埋め込んだ単一のHTMLで、記事ごとに1件保存します。
+// HTML本体は内容のハッシュ（SHA-256）をキーとしてディスク上のブロブストアに保存します。
+type ArticleSnapshot struct {
+	ArticleID   string     // 記事ID
+	URL         string     // 取得元の記事URL
+	Status      string     // 取得状況（SnapshotStatus* 定数）
+	Hash        string     // ブロブストアのキー（取得済みの場合のみ）
+	Size        int64      // HTMLのサイズ（バイト）
+	Error       string     // 取得に失敗した理由
+	RequestedAt time.Time  // 取得を要求した日時
+	CapturedAt  *time.Time // 取得した日時
+}
@@ -0,0 +1,19 @@
+package model
+
+// MaxTagNameLength はタグ名の最大文字数です。
+const MaxTagNameLength = 64
+
+// Tag はユーザーが記事に付けるタグと、タグが付いた記事の件数です。
+//
+// タグはユーザーごとに管理し、記事にタグを付けたときに作成され、
+// タグが付いた記事がなくなると削除されます。
+//
+// JSON tags:
+//   - name: タグ名
+//   - count: タグが付いた記事の件数
+//   - unread_count: タグが付いた未読の記事の件数
+type Tag struct {
+	Name        string `json:"name"`         // タグ名
+	Count       int    `json:"count"`        // 記事の件数
+	UnreadCount int    `json:"unread_count"` // 未読の記事の件数
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"net/url"
+	"strings"
+)
+
+// NormalizeURL は記事の重複判定に使用するためにURLを正規化します。
+//
+// 同一の記事が異なるフィードから微妙に異なるURLで配信されることがあるため、
+// 以下の規則で比較用のURLを生成します。
+//   - スキームとホスト名を小文字化し、http を https に統一
+//   - デフォルトポート（:80, :443）を除去
+//   - utm_* で始まるトラッキング用クエリパラメータを除去
+//   - 残りのクエリパラメータをキー順に並べ替え
+//   - フラグメント（#以降）を除去
+//   - パス末尾のスラッシュを除去
+//
+// 解析できないURLは前後の空白のみ除去して返します。
+//
+// Parameters:
+//   - raw: 正規化するURL文字列
+//
+// Returns:
+//   - string: 正規化されたURL文字列
+func NormalizeURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme == "http" {
+		u.Scheme = "https"
+	}
+	host := strings.ToLower(u.Hostname())
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host = host + ":" + port
+	}
+	u.Host = host
+
+	query := u.Query()
+	for key := range query {
+		if strings.HasPrefix(strings.ToLower(key), "utm_") {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode()
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = ""
+
+	return u.String()
+}
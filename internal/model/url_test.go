@@ -0,0 +1,33 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"http is upgraded to https", "http://example.com/a", "https://example.com/a"},
+		{"host is lower-cased", "https://EXAMPLE.com/Path", "https://example.com/Path"},
+		{"utm parameters are removed", "https://example.com/a?utm_source=rss&utm_medium=feed&id=1", "https://example.com/a?id=1"},
+		{"query parameters are sorted", "https://example.com/a?b=2&a=1", "https://example.com/a?a=1&b=2"},
+		{"fragment is removed", "https://example.com/a#comments", "https://example.com/a"},
+		{"trailing slash is removed", "https://example.com/a/", "https://example.com/a"},
+		{"root trailing slash is removed", "https://example.com/", "https://example.com"},
+		{"default port is removed", "http://example.com:80/a", "https://example.com/a"},
+		{"non-default port is kept", "https://example.com:8443/a", "https://example.com:8443/a"},
+		{"surrounding spaces are trimmed", "  https://example.com/a  ", "https://example.com/a"},
+		{"relative url is returned as is", "/relative/path", "/relative/path"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeURL(tt.input))
+		})
+	}
+}
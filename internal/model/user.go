@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// User はユーザーのデータモデルです。
+//
+// ユーザーはメールアドレスとパスワードでログインし、ブラウザではセッション
+// Cookie、スクリプトからは個人APIトークンで認証されます。
+//
+// JSON tags:
+//   - id: ユーザーの一意識別子（UUID形式）
+//   - email: メールアドレス（ログインID）
+//   - name: 表示名
+//   - fever_enabled: Fever API のパスワードが設定されているか
+//   - created_at: ユーザーの作成日時
+type User struct {
+	ID           string    `json:"id"`            // ユーザーの一意識別子
+	Email        string    `json:"email"`         // メールアドレス
+	Name         string    `json:"name"`          // 表示名
+	PasswordHash string    `json:"-"`             // bcrypt ハッシュ（レスポンスには含めない）
+	FeverEnabled bool      `json:"fever_enabled"` // Fever API のパスワード設定済みフラグ
+	CreatedAt    time.Time `json:"created_at"`    // 作成日時
+}
+
+// Session はブラウザのログインセッションです。
+// トークン自体は Cookie にのみ保持され、データベースにはハッシュのみを保存します。
+type Session struct {
+	TokenHash string    // セッショントークンの SHA-256 ハッシュ
+	UserID    string    // ユーザーID
+	ExpiresAt time.Time // 有効期限
+	CreatedAt time.Time // 作成日時
+}
+
+// APIToken はスクリプトから API を利用するための個人APIトークンです。
+//
+// JSON tags:
+//   - id: トークンの一意識別子（UUID形式）
+//   - name: トークンの用途を表す名前
+//   - token: トークン文字列（作成時のレスポンスにのみ含まれる）
+//   - last_used_at: 最終使用日時（未使用の場合は省略）
+//   - created_at: トークンの作成日時
+type APIToken struct {
+	ID         string     `json:"id"`                     // トークンの一意識別子
+	UserID     string     `json:"-"`                      // 所有者のユーザーID
+	Name       string     `json:"name"`                   // トークン名
+	Token      string     `json:"token,omitempty"`        // トークン文字列（作成時のみ）
+	TokenHash  string     `json:"-"`                      // トークンの SHA-256 ハッシュ
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"` // 最終使用日時
+	CreatedAt  time.Time  `json:"created_at"`             // 作成日時
+}
@@ -0,0 +1,74 @@
+package model
+
+import "time"
+
+// Webhook は新着記事を外部サービスへ通知する Webhook のデータモデルです。
+//
+// フィードから新しい記事が取り込まれると、条件に一致する Webhook の URL へ
+// HMAC-SHA256 で署名した JSON を POST します。folder_id・feed_id・keyword は
+// 省略可能な絞り込み条件で、設定したものすべてに一致する記事のみ通知します。
+//
+// JSON tags:
+//   - id: Webhook の一意識別子（UUID形式）
+//   - name: Webhook の表示名（必須）
+//   - url: 通知先のURL（必須）
+//   - folder_id: 通知対象のフォルダID（省略可能）
+//   - feed_id: 通知対象のフィードID（省略可能）
+//   - keyword: タイトル・要約・本文に含まれる必要があるキーワード（省略可能、大文字小文字を区別しない）
+//   - payload_template: 送信するJSONのテンプレート（省略可能、text/template 形式）
+//   - secret: 署名の鍵（作成時のレスポンスにのみ含まれる。作成時に省略した場合は自動生成）
+//   - created_at: Webhook の作成日時
+type Webhook struct {
+	ID              string    `json:"id"`                                 // Webhook の一意識別子
+	UserID          string    `json:"-"`                                  // 所有者のユーザーID
+	Name            string    `json:"name" binding:"required,max=255"`    // 表示名（必須）
+	URL             string    `json:"url" binding:"required,url"`         // 通知先URL（必須、URL形式）
+	FolderID        string    `json:"folder_id,omitempty"`                // 通知対象のフォルダID
+	FeedID          string    `json:"feed_id,omitempty"`                  // 通知対象のフィードID
+	Keyword         string    `json:"keyword,omitempty"`                  // 絞り込みキーワード
+	PayloadTemplate string    `json:"payload_template,omitempty"`         // ペイロードのテンプレート
+	Secret          string    `json:"secret,omitempty" binding:"max=255"` // 署名の鍵（作成時のみ）
+	CreatedAt       time.Time `json:"created_at"`                         // 作成日時
+}
+
+// Webhook の配信状態です。
+const (
+	WebhookDeliveryPending   = "pending"   // 送信待ち（再試行待ちを含む）
+	WebhookDeliverySucceeded = "succeeded" // 送信成功
+	WebhookDeliveryFailed    = "failed"    // 再試行の上限に達して失敗
+)
+
+// WebhookEventArticleCreated は新しい記事が取り込まれたことを表すイベント名です。
+const WebhookEventArticleCreated = "article.created"
+
+// WebhookDelivery は Webhook の1回分の配信とその結果です。
+//
+// 配信は status が pending の間、バックグラウンドで再試行されます。
+//
+// JSON tags:
+//   - id: 配信の一意識別子（UUID形式、X-FeedApp-Delivery ヘッダーの値）
+//   - webhook_id: 配信元の Webhook のID
+//   - article_id: 通知した記事のID（記事が削除された場合は省略）
+//   - event: イベント名（例: "article.created"）
+//   - payload: 送信したJSON
+//   - status: 配信状態（"pending", "succeeded", "failed"）
+//   - attempts: 送信を試みた回数
+//   - response_status: 最後の送信で返されたHTTPステータス（応答がない場合は省略）
+//   - error: 最後の送信のエラー内容（成功した場合は省略）
+//   - next_attempt_at: 次に送信を試みる日時（pending の場合のみ）
+//   - last_attempt_at: 最後に送信を試みた日時（未送信の場合は省略）
+//   - created_at: 配信の作成日時
+type WebhookDelivery struct {
+	ID             string     `json:"id"`                        // 配信の一意識別子
+	WebhookID      string     `json:"webhook_id"`                // Webhook ID
+	ArticleID      string     `json:"article_id,omitempty"`      // 記事ID
+	Event          string     `json:"event"`                     // イベント名
+	Payload        string     `json:"payload"`                   // 送信したJSON
+	Status         string     `json:"status"`                    // 配信状態
+	Attempts       int        `json:"attempts"`                  // 送信回数
+	ResponseStatus int        `json:"response_status,omitempty"` // 最後のHTTPステータス
+	Error          string     `json:"error,omitempty"`           // 最後のエラー
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"` // 次回送信日時
+	LastAttemptAt  *time.Time `json:"last_attempt_at,omitempty"` // 最終送信日時
+	CreatedAt      time.Time  `json:"created_at"`                // 作成日時
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// WebSub の購読状態です。
+const (
+	WebSubPending = "pending" // ハブへ購読を要求し、意図の確認を待っている
+	WebSubActive  = "active"  // 購読が成立し、ハブから更新がプッシュされる
+	WebSubFailed  = "failed"  // ハブへの要求に失敗した、または拒否された
+)
+
+// WebSubSubscription はフィードの WebSub ハブへの購読です。
+//
+// フィードはユーザー間で共有するため、購読もフィードごとに1件となります。
+// 購読が active で期限内の間は、フィードの定期取得の間隔を延ばしてプッシュされた内容を取り込みます。
+type WebSubSubscription struct {
+	FeedID         string     // フィードID
+	Hub            string     // ハブのURL
+	Topic          string     // 購読したトピック（フィードの rel="self" のURL）
+	Secret         string     // 署名の鍵（hub.secret）
+	State          string     // 購読状態（pending / active / failed）
+	LeaseExpiresAt *time.Time // 購読の有効期限（active の場合のみ）
+	LastError      string     // 最後のエラーの内容
+	UpdatedAt      time.Time  // 最後にハブへ要求した、または状態が変化した日時
+}
@@ -0,0 +1,362 @@
+// Package oidc は OpenID Connect の認可コードフロー（PKCE 付き）によるログインを提供します。
+//
+// プロバイダーの設定はディスカバリー（/.well-known/openid-configuration）から取得し、
+// IDトークンは JWKS の公開鍵で署名を検証します。対応する署名アルゴリズムは RS256 と ES256 です。
+// Google のほか、ディスカバリーに対応した任意のプロバイダーで利用できます。
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrInvalidToken はIDトークンの形式・署名・クレームが不正な場合に返されます。
+	ErrInvalidToken = errors.New("invalid id token")
+	// ErrExchangeFailed はトークンエンドポイントで認可コードを交換できなかった場合に返されます。
+	ErrExchangeFailed = errors.New("failed to exchange authorization code")
+)
+
+const (
+	// defaultTimeout はプロバイダーへのリクエストのタイムアウトです。
+	defaultTimeout = 10 * time.Second
+	// maxResponseSize はプロバイダーからのレスポンスの最大サイズです。
+	maxResponseSize = 1 << 20
+	// clockSkew は exp / iat の検証で許容する時刻のずれです。
+	clockSkew = time.Minute
+)
+
+// DefaultScopes は Config.Scopes が未指定の場合に要求するスコープです。
+var DefaultScopes = []string{"openid", "email", "profile"}
+
+// Config はプロバイダーの接続設定です。
+type Config struct {
+	Issuer       string   // 発行者URL（例: https://accounts.google.com）
+	ClientID     string   // クライアントID
+	ClientSecret string   // クライアントシークレット
+	RedirectURL  string   // コールバックURL
+	Scopes       []string // 要求するスコープ（未指定の場合は DefaultScopes）
+}
+
+// Claims はIDトークンから取り出したユーザー情報です。
+type Claims struct {
+	Issuer        string `json:"iss"`
+	Subject       string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+}
+
+// metadata はディスカバリーで取得するプロバイダーの設定です。
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider は OpenID Connect プロバイダーとのやり取りを行います。
+// ディスカバリーと公開鍵の取得は初回利用時に行い、結果をキャッシュします。
+type Provider struct {
+	config Config
+	client *http.Client
+	now    func() time.Time
+
+	mu   sync.Mutex
+	meta *metadata
+	keys map[string]any // kid ごとの公開鍵（*rsa.PublicKey または *ecdsa.PublicKey）
+}
+
+// New は新しい Provider を作成します。client が nil の場合は既定のタイムアウトを持つクライアントを使用します。
+func New(config Config, client *http.Client) *Provider {
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = DefaultScopes
+	}
+	config.Issuer = strings.TrimSuffix(config.Issuer, "/")
+	return &Provider{
+		config: config,
+		client: client,
+		now:    time.Now,
+	}
+}
+
+// AuthCodeURL はユーザーをリダイレクトする認可エンドポイントのURLを返します。
+// codeVerifier から S256 方式のコードチャレンジを計算して付与します。
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.config.ClientID},
+		"redirect_uri":          {p.config.RedirectURL},
+		"scope":                 {strings.Join(p.config.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {CodeChallenge(codeVerifier)},
+		"code_challenge_method": {"S256"},
+	}
+	separator := "?"
+	if strings.Contains(meta.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return meta.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+// Exchange は認可コードをトークンに交換し、IDトークンを検証してクレームを返します。
+// nonce は AuthCodeURL に渡した値と一致する必要があります。
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (Claims, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"client_id":     {p.config.ClientID},
+		"code_verifier": {codeVerifier},
+	}
+	if p.config.ClientSecret != "" {
+		form.Set("client_secret", p.config.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Claims{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var token struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := p.doJSON(req, &token); err != nil {
+		return Claims{}, fmt.Errorf("%w: %v", ErrExchangeFailed, err)
+	}
+	if token.IDToken == "" {
+		return Claims{}, fmt.Errorf("%w: id_token is missing", ErrExchangeFailed)
+	}
+	return p.Verify(ctx, token.IDToken, nonce)
+}
+
+// Verify はIDトークンの署名と iss / aud / exp / nonce を検証し、クレームを返します。
+func (p *Provider) Verify(ctx context.Context, rawIDToken, nonce string) (Claims, error) {
+	parts := strings.Split(rawIDToken, ".")
+	if len(parts) != 3 {
+		return Claims{}, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return Claims{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Claims{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	key, err := p.key(ctx, header.Kid)
+	if err != nil {
+		return Claims{}, err
+	}
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return Claims{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	var claims struct {
+		Claims
+		Audience audience `json:"aud"`
+		Expiry   int64    `json:"exp"`
+		IssuedAt int64    `json:"iat"`
+		Nonce    string   `json:"nonce"`
+	}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return Claims{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	now := p.now()
+	switch {
+	case !p.validIssuer(claims.Issuer):
+		return Claims{}, fmt.Errorf("%w: unexpected issuer %q", ErrInvalidToken, claims.Issuer)
+	case !claims.Audience.contains(p.config.ClientID):
+		return Claims{}, fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	case claims.Expiry == 0 || now.After(time.Unix(claims.Expiry, 0).Add(clockSkew)):
+		return Claims{}, fmt.Errorf("%w: token expired", ErrInvalidToken)
+	case claims.IssuedAt != 0 && time.Unix(claims.IssuedAt, 0).After(now.Add(clockSkew)):
+		return Claims{}, fmt.Errorf("%w: token issued in the future", ErrInvalidToken)
+	case claims.Nonce != nonce:
+		return Claims{}, fmt.Errorf("%w: nonce mismatch", ErrInvalidToken)
+	case claims.Subject == "":
+		return Claims{}, fmt.Errorf("%w: sub is missing", ErrInvalidToken)
+	}
+	// プロバイダーごとの表記の違いに依存しないよう、発行者は設定値に統一する
+	claims.Claims.Issuer = p.config.Issuer
+	return claims.Claims, nil
+}
+
+// Issuer は設定された発行者URLを返します。
+func (p *Provider) Issuer() string {
+	return p.config.Issuer
+}
+
+// validIssuer はIDトークンの iss が設定された発行者と一致するかを判定します。
+// Google はスキームを省略した "accounts.google.com" を発行する場合があるため、これも許容します。
+func (p *Provider) validIssuer(iss string) bool {
+	if iss == p.config.Issuer {
+		return true
+	}
+	return p.config.Issuer == "https://accounts.google.com" && iss == "accounts.google.com"
+}
+
+// discover はプロバイダーの設定を取得します。取得済みの場合はキャッシュを返します。
+func (p *Provider) discover(ctx context.Context) (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.meta != nil {
+		return p.meta, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.config.Issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	var meta metadata
+	if err := p.doJSON(req, &meta); err != nil {
+		return nil, fmt.Errorf("failed to discover oidc provider: %w", err)
+	}
+	if strings.TrimSuffix(meta.Issuer, "/") != p.config.Issuer {
+		return nil, fmt.Errorf("oidc issuer mismatch: expected %q, got %q", p.config.Issuer, meta.Issuer)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, errors.New("oidc discovery document is missing required endpoints")
+	}
+	p.meta = &meta
+	return p.meta, nil
+}
+
+// key は kid に対応する公開鍵を返します。
+// 見つからない場合は鍵のローテーションに備えて JWKS を取得し直します。
+func (p *Provider) key(ctx context.Context, kid string) (any, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, meta.JWKSURI, nil)
+	if err != nil {
+		return nil, err
+	}
+	var set jwks
+	if err := p.doJSON(req, &set); err != nil {
+		return nil, fmt.Errorf("failed to get oidc signing keys: %w", err)
+	}
+	p.keys = set.publicKeys()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: unknown signing key %q", ErrInvalidToken, kid)
+}
+
+// lookupKey はキャッシュから公開鍵を探します。kid が空の場合は鍵が1つだけのときに限りそれを返します。
+func (p *Provider) lookupKey(kid string) (any, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+// doJSON はリクエストを送信し、JSONレスポンスを v にデコードします。
+func (p *Provider) doJSON(req *http.Request, v any) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s: %s", resp.StatusCode, req.URL, strings.TrimSpace(string(body)))
+	}
+	return json.Unmarshal(body, v)
+}
+
+// GenerateRandom は state・nonce・コードベリファイアに使用するランダム文字列を生成します。
+func GenerateRandom() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CodeChallenge は PKCE の S256 方式でコードベリファイアからコードチャレンジを計算します。
+func CodeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// audience は文字列または文字列の配列で表される aud クレームです。
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+	*a = multiple
+	return nil
+}
+
+func (a audience) contains(clientID string) bool {
+	for _, aud := range a {
+		if aud == clientID {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeSegment は base64url エンコードされた JWT のセグメントをデコードします。
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
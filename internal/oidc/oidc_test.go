@@ -0,0 +1,141 @@
+package oidc_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"feedapp/internal/oidc"
+	"feedapp/internal/oidc/oidctest"
+)
+
+const redirectURL = "http://localhost:8080/api/v1/auth/oidc/callback"
+
+func newProvider(server *oidctest.Server) *oidc.Provider {
+	return oidc.New(oidc.Config{
+		Issuer:       server.URL,
+		ClientID:     server.ClientID,
+		ClientSecret: server.ClientSecret,
+		RedirectURL:  redirectURL,
+	}, nil)
+}
+
+func TestProvider_AuthorizationCodeFlow(t *testing.T) {
+	server := oidctest.NewServer("client-1", "secret-1")
+	defer server.Close()
+	provider := newProvider(server)
+	ctx := context.Background()
+
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", "verifier-1")
+	require.NoError(t, err)
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "S256", parsed.Query().Get("code_challenge_method"))
+	assert.Equal(t, oidc.CodeChallenge("verifier-1"), parsed.Query().Get("code_challenge"))
+	assert.Equal(t, "openid email profile", parsed.Query().Get("scope"))
+
+	callback, err := server.Authorize(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "state-1", callback.Query().Get("state"))
+
+	claims, err := provider.Exchange(ctx, callback.Query().Get("code"), "verifier-1", "nonce-1")
+	require.NoError(t, err)
+	assert.Equal(t, oidc.Claims{
+		Issuer:        server.URL,
+		Subject:       "subject-1",
+		Email:         "user@example.com",
+		EmailVerified: true,
+		Name:          "Test User",
+	}, claims)
+}
+
+func TestProvider_Exchange_rejectsWrongVerifierAndNonce(t *testing.T) {
+	server := oidctest.NewServer("client-1", "secret-1")
+	defer server.Close()
+	provider := newProvider(server)
+	ctx := context.Background()
+
+	t.Run("code verifier mismatch", func(t *testing.T) {
+		authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", "verifier")
+		require.NoError(t, err)
+		callback, err := server.Authorize(authURL)
+		require.NoError(t, err)
+
+		_, err = provider.Exchange(ctx, callback.Query().Get("code"), "other-verifier", "nonce")
+		assert.ErrorIs(t, err, oidc.ErrExchangeFailed)
+	})
+
+	t.Run("nonce mismatch", func(t *testing.T) {
+		authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", "verifier")
+		require.NoError(t, err)
+		callback, err := server.Authorize(authURL)
+		require.NoError(t, err)
+
+		_, err = provider.Exchange(ctx, callback.Query().Get("code"), "verifier", "other-nonce")
+		assert.ErrorIs(t, err, oidc.ErrInvalidToken)
+	})
+
+	t.Run("wrong client secret", func(t *testing.T) {
+		badProvider := oidc.New(oidc.Config{Issuer: server.URL, ClientID: "client-1", ClientSecret: "wrong", RedirectURL: redirectURL}, nil)
+		authURL, err := badProvider.AuthCodeURL(ctx, "state", "nonce", "verifier")
+		require.NoError(t, err)
+		callback, err := server.Authorize(authURL)
+		require.NoError(t, err)
+
+		_, err = badProvider.Exchange(ctx, callback.Query().Get("code"), "verifier", "nonce")
+		assert.ErrorIs(t, err, oidc.ErrExchangeFailed)
+	})
+}
+
+func TestProvider_Verify(t *testing.T) {
+	server := oidctest.NewServer("client-1", "secret-1")
+	defer server.Close()
+	provider := newProvider(server)
+	ctx := context.Background()
+
+	valid := func() map[string]any {
+		return map[string]any{
+			"iss":   server.URL,
+			"sub":   "subject-1",
+			"aud":   []string{"client-1", "other"},
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"iat":   time.Now().Unix(),
+			"nonce": "nonce",
+		}
+	}
+
+	claims, err := provider.Verify(ctx, server.SignIDToken(valid()), "nonce")
+	require.NoError(t, err)
+	assert.Equal(t, "subject-1", claims.Subject)
+
+	tests := []struct {
+		name   string
+		modify func(map[string]any)
+	}{
+		{"wrong issuer", func(c map[string]any) { c["iss"] = "https://evil.example.com" }},
+		{"wrong audience", func(c map[string]any) { c["aud"] = "other-client" }},
+		{"expired", func(c map[string]any) { c["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{"missing subject", func(c map[string]any) { delete(c, "sub") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := valid()
+			tt.modify(claims)
+			_, err := provider.Verify(ctx, server.SignIDToken(claims), "nonce")
+			assert.ErrorIs(t, err, oidc.ErrInvalidToken)
+		})
+	}
+
+	t.Run("tampered payload", func(t *testing.T) {
+		token := server.SignIDToken(valid())
+		other := server.SignIDToken(map[string]any{"iss": server.URL, "sub": "attacker", "aud": "client-1", "exp": time.Now().Add(time.Hour).Unix(), "nonce": "nonce"})
+		tampered := token[:strings.Index(token, ".")] + other[strings.Index(other, "."):strings.LastIndex(other, ".")] + token[strings.LastIndex(token, "."):]
+		_, err := provider.Verify(ctx, tampered, "nonce")
+		assert.ErrorIs(t, err, oidc.ErrInvalidToken)
+	})
+}
@@ -0,0 +1,166 @@
+package plugin
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// guessedCharsets は宣言された文字コードで変換できない場合に試す文字コードです。
+// EUC-JP のバイト列は Shift_JIS の半角カナとしても変換できてしまうため、EUC-JP を先に試します。
+var guessedCharsets = []string{"utf-8", "euc-jp", "shift_jis"}
+
+// xmlDeclEncoding は XML 宣言の encoding 属性に一致します。
+var xmlDeclEncoding = regexp.MustCompile(`^<\?xml[^>]*?\sencoding\s*=\s*["']([A-Za-z0-9._:-]+)["']`)
+
+// decodeCharset はフィードのバイト列を UTF-8 に変換し、変換結果と警告を返します。
+//
+// 文字コードは BOM、XML 宣言の encoding、HTTP の Content-Type の charset の順に判定します。
+// 宣言された文字コードで正しく変換できない場合（宣言の誤り）は UTF-8・EUC-JP・Shift_JIS を順に試し、
+// いずれでも変換できない場合は不正なバイト列を U+FFFD に置き換えます。
+// 宣言と異なる文字コードで変換した場合や置き換えた場合は警告を返します。
+func decodeCharset(data []byte, contentType string) ([]byte, []string) {
+	if hasBOM(data) {
+		// BOM は宣言より優先する
+		decoded, _, err := transform.Bytes(unicode.BOMOverride(encoding.Nop.NewDecoder()), data)
+		if err == nil {
+			return decoded, nil
+		}
+	}
+
+	var warnings []string
+	var labels []string
+	if label := declaredEncoding(data); label != "" {
+		labels = append(labels, label)
+	}
+	if charset := contentTypeCharset(contentType); charset != "" {
+		labels = append(labels, charset)
+	}
+	labels = append(labels, guessedCharsets...)
+
+	numDeclared := len(labels) - len(guessedCharsets)
+	tried := map[string]bool{}
+	first, declared := "", false
+	for i, label := range labels {
+		enc, name, err := lookupCharset(label)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("unknown charset %q", label))
+			continue
+		}
+		if tried[name] {
+			continue
+		}
+		tried[name] = true
+		if first == "" {
+			first, declared = name, i < numDeclared
+		}
+		decoded, ok := decodeStrict(enc, name, data)
+		if !ok {
+			continue
+		}
+		if declared && name != first {
+			warnings = append(warnings, fmt.Sprintf("charset %s does not match the content, decoded as %s", first, name))
+		}
+		return decoded, warnings
+	}
+
+	// いずれの文字コードでも変換できない場合は、最初の候補で不正なバイト列を置き換えて変換する
+	enc, _, _ := lookupCharset(first)
+	decoded, _, err := transform.Bytes(enc.NewDecoder(), data)
+	if err != nil {
+		decoded = bytes.ToValidUTF8(data, []byte(string(utf8.RuneError)))
+	}
+	warnings = append(warnings, fmt.Sprintf("invalid byte sequences for charset %s were replaced", first))
+	return decoded, warnings
+}
+
+// hasBOM はバイト列が UTF-8 または UTF-16 の BOM で始まるかを返します。
+func hasBOM(data []byte) bool {
+	return bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}) ||
+		bytes.HasPrefix(data, []byte{0xFE, 0xFF}) ||
+		bytes.HasPrefix(data, []byte{0xFF, 0xFE})
+}
+
+// declaredEncoding は XML 宣言の encoding 属性の値を返します。宣言がない場合は空文字列です。
+func declaredEncoding(data []byte) string {
+	head := bytes.TrimLeft(data[:min(len(data), 1024)], " \t\r\n")
+	if m := xmlDeclEncoding.FindSubmatch(head); m != nil {
+		return string(m[1])
+	}
+	return ""
+}
+
+// contentTypeCharset は Content-Type ヘッダーの charset パラメーターを返します。
+func contentTypeCharset(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(params["charset"])
+}
+
+// lookupCharset は文字コードの名前（別名を含む）から encoding.Encoding と正規化した名前を返します。
+func lookupCharset(label string) (encoding.Encoding, string, error) {
+	enc, err := htmlindex.Get(strings.TrimSpace(label))
+	if err != nil {
+		return nil, "", err
+	}
+	name, err := htmlindex.Name(enc)
+	if err != nil {
+		return nil, "", err
+	}
+	return enc, name, nil
+}
+
+// decodeStrict はバイト列を文字コード enc で変換し、不正なバイト列を含まず、
+// その文字コードとして自然な内容であれば true を返します。
+func decodeStrict(enc encoding.Encoding, name string, data []byte) ([]byte, bool) {
+	if name == "utf-8" {
+		return data, utf8.Valid(data)
+	}
+	decoded, _, err := transform.Bytes(enc.NewDecoder(), data)
+	if err != nil || bytes.ContainsRune(decoded, utf8.RuneError) {
+		return nil, false
+	}
+	if name == "windows-1252" && !isASCII(data) && utf8.Valid(data) {
+		// ISO-8859-1 はサーバーの既定値として誤って付与されることが多いため、UTF-8 として正しい場合は UTF-8 を優先する
+		return nil, false
+	}
+	return decoded, plausibleText(decoded)
+}
+
+// plausibleText は変換結果が日本語の文章として不自然でないかを返します。
+// 半角カナが全角文字より多い場合は、別の文字コードを誤って変換したものとみなします。
+func plausibleText(decoded []byte) bool {
+	halfwidth, wide := 0, 0
+	for _, r := range string(decoded) {
+		switch {
+		case r >= 0xFF61 && r <= 0xFF9F:
+			halfwidth++
+		case r >= 0x3000:
+			wide++
+		}
+	}
+	return halfwidth <= wide
+}
+
+// isASCII はバイト列が ASCII の文字のみで構成されているかを返します。
+func isASCII(data []byte) bool {
+	for _, b := range data {
+		if b >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
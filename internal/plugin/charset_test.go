@@ -0,0 +1,57 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeCharset(t *testing.T) {
+	t.Run("UTF-8 without declaration", func(t *testing.T) {
+		decoded, warnings := decodeCharset([]byte(`<rss><channel><title>日本語</title></channel></rss>`), "")
+		assert.Equal(t, `<rss><channel><title>日本語</title></channel></rss>`, string(decoded))
+		assert.Empty(t, warnings)
+	})
+
+	t.Run("Content-Type charset is used without declaration", func(t *testing.T) {
+		// "テスト" の Shift_JIS
+		decoded, warnings := decodeCharset([]byte("<title>\x83\x65\x83\x58\x83\x67</title>"), "text/xml; charset=Shift_JIS")
+		assert.Equal(t, "<title>テスト</title>", string(decoded))
+		assert.Empty(t, warnings)
+	})
+
+	t.Run("ISO-8859-1 header is ignored for UTF-8 content", func(t *testing.T) {
+		decoded, warnings := decodeCharset([]byte("<title>Café</title>"), "text/xml; charset=ISO-8859-1")
+		assert.Equal(t, "<title>Café</title>", string(decoded))
+		assert.Equal(t, []string{"charset windows-1252 does not match the content, decoded as utf-8"}, warnings)
+	})
+
+	t.Run("ASCII content with ISO-8859-1 header", func(t *testing.T) {
+		decoded, warnings := decodeCharset([]byte("<title>plain</title>"), "text/xml; charset=ISO-8859-1")
+		assert.Equal(t, "<title>plain</title>", string(decoded))
+		assert.Empty(t, warnings)
+	})
+
+	t.Run("unknown charset", func(t *testing.T) {
+		decoded, warnings := decodeCharset([]byte(`<?xml version="1.0" encoding="x-unknown"?><title>ok</title>`), "")
+		assert.Contains(t, string(decoded), "<title>ok</title>")
+		assert.Equal(t, []string{`unknown charset "x-unknown"`}, warnings)
+	})
+
+	t.Run("undecodable bytes are replaced", func(t *testing.T) {
+		decoded, warnings := decodeCharset([]byte("<title>\x80\xff broken</title>"), "")
+		assert.Equal(t, "<title>\uFFFD\uFFFD broken</title>", string(decoded))
+		assert.Equal(t, []string{"invalid byte sequences for charset utf-8 were replaced"}, warnings)
+	})
+}
+
+func TestStripInvalidXMLChars(t *testing.T) {
+	cleaned, removed := stripInvalidXMLChars([]byte("a\x00b\tc\x1fd\ne\uFFFEf"))
+	assert.Equal(t, "ab\tcd\nef", string(cleaned))
+	assert.Equal(t, 3, removed)
+
+	data := []byte("valid\r\ntext")
+	cleaned, removed = stripInvalidXMLChars(data)
+	assert.Equal(t, data, cleaned)
+	assert.Zero(t, removed)
+}
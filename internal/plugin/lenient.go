@@ -0,0 +1,75 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// decodeDocument は UTF-8 に変換済みのXMLを feedDocument に解析します。
+//
+// strict が false の場合は、よくある不正なXMLを許容する寛容なモードで解析します。
+//   - エスケープされていない & や未定義の実体参照はそのまま文字として扱う
+//   - &nbsp; などの HTML の実体参照を文字に変換する
+//   - 閉じられていない要素は親要素の終了時に閉じる
+func decodeDocument(data []byte, strict bool) (feedDocument, error) {
+	var doc feedDocument
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	// 文字コードは decodeCharset で UTF-8 に変換済みのため、XML 宣言の encoding は無視する
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	if !strict {
+		decoder.Strict = false
+		decoder.Entity = xml.HTMLEntity
+	}
+	err := decoder.Decode(&doc)
+	return doc, err
+}
+
+// repairXML は寛容なモードでも解析できない不正なXMLを修復し、修復内容を警告として返します。
+//
+//   - XML 宣言の前に出力されたエラーメッセージなどを取り除く
+//   - XML 1.0 で使用できない制御文字と不正なバイト列を取り除く
+func repairXML(data []byte) ([]byte, []string) {
+	var warnings []string
+	if i := bytes.Index(data, []byte("<?xml")); i > 0 && len(bytes.TrimSpace(data[:i])) > 0 {
+		data = data[i:]
+		warnings = append(warnings, fmt.Sprintf("removed %d bytes before the XML declaration", i))
+	}
+	if repaired, removed := stripInvalidXMLChars(data); removed > 0 {
+		data = repaired
+		warnings = append(warnings, fmt.Sprintf("removed %d invalid characters", removed))
+	}
+	return data, warnings
+}
+
+// stripInvalidXMLChars は XML 1.0 で使用できない文字（タブ・改行以外の制御文字など）を取り除き、
+// 取り除いた文字数を返します。
+func stripInvalidXMLChars(data []byte) ([]byte, int) {
+	cleaned := make([]byte, 0, len(data))
+	removed := 0
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		if (r == utf8.RuneError && size == 1) || !isXMLChar(r) {
+			removed++
+		} else {
+			cleaned = append(cleaned, data[i:i+size]...)
+		}
+		i += size
+	}
+	if removed == 0 {
+		return data, 0
+	}
+	return cleaned, removed
+}
+
+// isXMLChar は XML 1.0 の Char 生成規則で許可される文字かを返します。
+func isXMLChar(r rune) bool {
+	return r == '\t' || r == '\n' || r == '\r' ||
+		(r >= 0x20 && r <= 0xD7FF) ||
+		(r >= 0xE000 && r <= 0xFFFD) ||
+		(r >= 0x10000 && r <= 0x10FFFF)
+}
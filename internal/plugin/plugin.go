@@ -0,0 +1,58 @@
+// Package plugin はフィード取得プラグインの仕組みを提供します。
+//
+// 各プラグインはフィードの plugin_type に対応付けられ、フィードのURLから
+// 記事を取得して model.Article 形式に変換する責務を持ちます。
+// デフォルトでは RSS/Atom に対応する RSSPlugin が "rss" として登録されます。
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"feedapp/internal/model"
+)
+
+var ErrPluginNotFound = errors.New("plugin not found")
+
+// Result はプラグインによる1回の取得結果を表します。
+type Result struct {
+	Title string          // フィード自体のタイトル
+	Items []model.Article // 取得したエントリー（ID・FeedID・状態は未設定）
+	Hub   string          // フィードが通知する WebSub ハブのURL（rel="hub"、未対応の場合は空）
+	Self  string          // フィード自身の正規URL（rel="self"、WebSub のトピックとして使用する）
+	Link  string          // フィードのサイトのURL（RSS の <link>、Atom の rel="alternate"）
+	Icon  string          // フィードが指定するアイコンのURL（Atom の <icon>）
+
+	Warnings []string // 解析時に回復した問題（文字コードの誤りや不正なXMLなど）
+}
+
+// Plugin はフィード取得プラグインのインターフェースです。
+type Plugin interface {
+	Fetch(ctx context.Context, feed model.Feed) (*Result, error)
+}
+
+// Registry は plugin_type とプラグインの対応を管理します。
+type Registry struct {
+	plugins map[string]Plugin
+}
+
+// NewRegistry は空の Registry を作成します。
+func NewRegistry() *Registry {
+	return &Registry{plugins: make(map[string]Plugin)}
+}
+
+// Register は plugin_type に対応するプラグインを登録します。
+// 同じ plugin_type が既に登録されている場合は上書きします。
+func (r *Registry) Register(pluginType string, p Plugin) {
+	r.plugins[pluginType] = p
+}
+
+// Get は plugin_type に対応するプラグインを返します。
+func (r *Registry) Get(pluginType string) (Plugin, error) {
+	p, ok := r.plugins[pluginType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrPluginNotFound, pluginType)
+	}
+	return p, nil
+}
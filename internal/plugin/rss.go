@@ -0,0 +1,534 @@
+package plugin
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"feedapp/internal/fetcher"
+	"feedapp/internal/model"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported feed format")
+
+// RSSPlugin は RSS 2.0 / RSS 1.0 (RDF) / Atom フィードを取得するプラグインです。
+type RSSPlugin struct {
+	client *fetcher.Client
+}
+
+// NewRSSPlugin は新しい RSSPlugin インスタンスを作成します。
+func NewRSSPlugin(client *fetcher.Client) *RSSPlugin {
+	return &RSSPlugin{client: client}
+}
+
+// Fetch はフィードのURLを取得し、エントリーを解析して返します。
+//
+// WebSub のハブとトピックは HTTP の Link ヘッダーを優先し、ヘッダーにない場合は
+// フィード内のリンク要素を使用します。文字コードの判定には Content-Type ヘッダーも使用します。
+func (p *RSSPlugin) Fetch(ctx context.Context, feed model.Feed) (*Result, error) {
+	resp, err := p.client.Get(ctx, feed.URL)
+	if err != nil {
+		return nil, err
+	}
+	result, err := ParseFeedContent(resp.Body, resp.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+	if hub, self := parseLinkHeader(resp.Header.Values("Link")); hub != "" {
+		result.Hub = hub
+		if self != "" {
+			result.Self = self
+		}
+	}
+	return result, nil
+}
+
+// feedDocument は RSS/RDF/Atom のいずれのルート要素も受け取れる構造体です。
+type feedDocument struct {
+	XMLName xml.Name
+	Channel rssChannel  `xml:"channel"`
+	Items   []rssItem   `xml:"item"`  // RSS 1.0 ではルート直下に item が並ぶ
+	Title   atomText    `xml:"title"` // Atom
+	Links   []atomLink  `xml:"link"`  // Atom
+	Icon    string      `xml:"icon"`  // Atom
+	Entries []atomEntry `xml:"entry"` // Atom
+}
+
+type rssChannel struct {
+	Title     string     `xml:"title"`
+	Items     []rssItem  `xml:"item"`
+	AtomLinks []atomLink `xml:"http://www.w3.org/2005/Atom link"` // <atom:link rel="hub"> など
+	Link      string     `xml:"link"`                             // 名前空間付きの atom:link より後に宣言する
+}
+
+type rssItem struct {
+	Title           string           `xml:"title"`
+	Link            string           `xml:"link"`
+	GUID            string           `xml:"guid"`
+	Author          string           `xml:"author"`
+	DCCreator       string           `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Categories      []string         `xml:"category"`
+	DCSubjects      []string         `xml:"http://purl.org/dc/elements/1.1/ subject"`
+	Description     string           `xml:"description"`
+	ContentEncoded  string           `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	PubDate         string           `xml:"pubDate"`
+	DCDate          string           `xml:"http://purl.org/dc/elements/1.1/ date"`
+	About           string           `xml:"http://www.w3.org/1999/02/22-rdf-syntax-ns# about,attr"`
+	Enclosures      []rssEnclosure   `xml:"enclosure"`
+	MediaContents   []mediaContent   `xml:"http://search.yahoo.com/mrss/ content"`
+	MediaThumbnails []mediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	ITunesAuthor    string           `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd author"`
+	ITunesDuration  string           `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+	ITunesEpisode   string           `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd episode"`
+	ITunesImage     itunesImage      `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+}
+
+// itunesImage は iTunes 名前空間の <itunes:image href="..."> 要素です。
+type itunesImage struct {
+	Href string `xml:"href,attr"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length string `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// mediaContent は Media RSS の <media:content> 要素です。
+type mediaContent struct {
+	URL      string `xml:"url,attr"`
+	Type     string `xml:"type,attr"`
+	Medium   string `xml:"medium,attr"`
+	FileSize string `xml:"fileSize,attr"`
+	Duration string `xml:"duration,attr"`
+}
+
+// mediaThumbnail は Media RSS の <media:thumbnail> 要素です。
+type mediaThumbnail struct {
+	URL string `xml:"url,attr"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Body  string `xml:",chardata"`
+	Inner string `xml:",innerxml"`
+}
+
+// String は type 属性に応じてテキストを返します。
+func (t atomText) String() string {
+	if t.Type == "xhtml" {
+		return strings.TrimSpace(t.Inner)
+	}
+	return strings.TrimSpace(t.Body)
+}
+
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr"`
+}
+
+type atomEntry struct {
+	ID              string           `xml:"id"`
+	Title           atomText         `xml:"title"`
+	Authors         []atomPerson     `xml:"author"`
+	Categories      []atomCategory   `xml:"category"`
+	Links           []atomLink       `xml:"link"`
+	Content         atomText         `xml:"content"`
+	Summary         atomText         `xml:"summary"`
+	Published       string           `xml:"published"`
+	Updated         string           `xml:"updated"`
+	MediaContents   []mediaContent   `xml:"http://search.yahoo.com/mrss/ content"`
+	MediaThumbnails []mediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+}
+
+// ParseFeed は RSS 2.0 / RSS 1.0 (RDF) / Atom 形式のXMLを解析します。
+// 文字コードは BOM と XML 宣言から判定します。
+func ParseFeed(data []byte) (*Result, error) {
+	return ParseFeedContent(data, "")
+}
+
+// ParseFeedContent は Content-Type ヘッダーの charset も使用して文字コードを判定し、フィードを解析します。
+//
+// フィードは UTF-8 に変換してから解析します。XMLとして不正な場合は寛容なモードで解析し直し、
+// 解析できた場合は失敗とせずに、文字コードの誤りとあわせて Result.Warnings に記録します。
+func ParseFeedContent(data []byte, contentType string) (*Result, error) {
+	data, warnings := decodeCharset(data, contentType)
+	result, err := parseDocument(data, true)
+	if err != nil {
+		repaired, repairs := repairXML(data)
+		recovered, lenientErr := parseDocument(repaired, false)
+		if lenientErr != nil {
+			return nil, err
+		}
+		result = recovered
+		warnings = append(warnings, fmt.Sprintf("recovered from malformed XML: %v", err))
+		warnings = append(warnings, repairs...)
+	}
+	result.Warnings = warnings
+	return result, nil
+}
+
+// parseDocument はXMLを解析して Result に変換します。strict の意味は decodeDocument と同じです。
+func parseDocument(data []byte, strict bool) (*Result, error) {
+	doc, err := decodeDocument(data, strict)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse feed: %w", err)
+	}
+	return convertDocument(doc)
+}
+
+// convertDocument は解析したXMLのルート要素の種類に応じて Result に変換します。
+func convertDocument(doc feedDocument) (*Result, error) {
+	switch strings.ToLower(doc.XMLName.Local) {
+	case "rss":
+		hub, self := hubLinks(doc.Channel.AtomLinks)
+		return &Result{Title: strings.TrimSpace(doc.Channel.Title), Items: convertRSSItems(doc.Channel.Items), Hub: hub, Self: self, Link: strings.TrimSpace(doc.Channel.Link)}, nil
+	case "rdf":
+		hub, self := hubLinks(doc.Channel.AtomLinks)
+		return &Result{Title: strings.TrimSpace(doc.Channel.Title), Items: convertRSSItems(doc.Items), Hub: hub, Self: self, Link: strings.TrimSpace(doc.Channel.Link)}, nil
+	case "feed":
+		hub, self := hubLinks(doc.Links)
+		return &Result{Title: doc.Title.String(), Items: convertAtomEntries(doc.Entries), Hub: hub, Self: self, Link: alternateLink(doc.Links), Icon: strings.TrimSpace(doc.Icon)}, nil
+	default:
+		return nil, fmt.Errorf("%w: <%s>", ErrUnsupportedFormat, doc.XMLName.Local)
+	}
+}
+
+// hubLinks はフィードのリンク要素から WebSub のハブ（rel="hub"）と自身のURL（rel="self"）を返します。
+// ハブが複数ある場合は最初のものを使用します。
+func hubLinks(links []atomLink) (hub, self string) {
+	for _, l := range links {
+		switch strings.ToLower(strings.TrimSpace(l.Rel)) {
+		case "hub":
+			if hub == "" {
+				hub = strings.TrimSpace(l.Href)
+			}
+		case "self":
+			if self == "" {
+				self = strings.TrimSpace(l.Href)
+			}
+		}
+	}
+	return hub, self
+}
+
+// alternateLink は Atom のリンク要素からサイトのURL（rel="alternate" または rel 省略）を返します。
+func alternateLink(links []atomLink) string {
+	for _, l := range links {
+		rel := strings.ToLower(strings.TrimSpace(l.Rel))
+		if rel == "" || rel == "alternate" {
+			return strings.TrimSpace(l.Href)
+		}
+	}
+	return ""
+}
+
+// parseLinkHeader は HTTP の Link ヘッダー（RFC 8288）から WebSub のハブと自身のURLを返します。
+//
+// 例: Link: <https://hub.example.com/>; rel="hub", <https://example.com/feed.xml>; rel="self"
+func parseLinkHeader(values []string) (hub, self string) {
+	for _, value := range values {
+		for value != "" {
+			start := strings.Index(value, "<")
+			end := strings.Index(value, ">")
+			if start < 0 || end < start {
+				break
+			}
+			target := strings.TrimSpace(value[start+1 : end])
+			params := value[end+1:]
+			value = ""
+			if next := strings.Index(params, ","); next >= 0 {
+				params, value = params[:next], params[next+1:]
+			}
+			for _, param := range strings.Split(params, ";") {
+				name, rels, ok := strings.Cut(strings.TrimSpace(param), "=")
+				if !ok || !strings.EqualFold(strings.TrimSpace(name), "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(strings.TrimSpace(rels), `"`)) {
+					switch strings.ToLower(rel) {
+					case "hub":
+						if hub == "" {
+							hub = target
+						}
+					case "self":
+						if self == "" {
+							self = target
+						}
+					}
+				}
+			}
+		}
+	}
+	return hub, self
+}
+
+func convertRSSItems(rssItems []rssItem) []model.Article {
+	items := make([]model.Article, 0, len(rssItems))
+	for _, ri := range rssItems {
+		item := model.Article{
+			GUID:    strings.TrimSpace(ri.GUID),
+			Title:   strings.TrimSpace(ri.Title),
+			Author:  firstNonEmpty(ri.DCCreator, ri.ITunesAuthor, ri.Author),
+			Summary: strings.TrimSpace(ri.Description),
+			Content: strings.TrimSpace(ri.ContentEncoded),
+			URL:     strings.TrimSpace(ri.Link),
+		}
+		if item.GUID == "" {
+			item.GUID = strings.TrimSpace(ri.About)
+		}
+		if item.Content == "" {
+			item.Content = item.Summary
+		}
+		if item.URL == "" && strings.HasPrefix(item.GUID, "http") {
+			item.URL = item.GUID
+		}
+		item.Categories = uniqueStrings(append(ri.Categories, ri.DCSubjects...))
+		item.PublishedAt = parseDate(ri.PubDate)
+		if item.PublishedAt.IsZero() {
+			item.PublishedAt = parseDate(ri.DCDate)
+		}
+
+		var enclosures []model.Enclosure
+		for _, e := range ri.Enclosures {
+			enclosures = append(enclosures, model.Enclosure{URL: strings.TrimSpace(e.URL), MimeType: strings.TrimSpace(e.Type), Length: parseInt64(e.Length)})
+		}
+		item.Enclosures, item.ImageURL = mergeMedia(enclosures, ri.MediaContents, ri.MediaThumbnails)
+		applyITunes(&item, ri)
+		items = append(items, item)
+	}
+	return items
+}
+
+// applyITunes は iTunes 名前空間（ポッドキャスト向け拡張）の情報を記事に反映します。
+//
+// itunes:duration は再生時間が未設定の音声・動画の添付メディアに、
+// itunes:image はサムネイル画像が未設定の場合に使用します。
+func applyITunes(item *model.Article, ri rssItem) {
+	if duration := parseDuration(ri.ITunesDuration); duration > 0 {
+		for i, e := range item.Enclosures {
+			if e.Duration == 0 && !strings.HasPrefix(e.MimeType, "image/") {
+				item.Enclosures[i].Duration = duration
+			}
+		}
+	}
+	if item.ImageURL == "" {
+		item.ImageURL = strings.TrimSpace(ri.ITunesImage.Href)
+	}
+	item.Episode = int(parseInt64(ri.ITunesEpisode))
+}
+
+func convertAtomEntries(entries []atomEntry) []model.Article {
+	items := make([]model.Article, 0, len(entries))
+	for _, e := range entries {
+		item := model.Article{
+			GUID:    strings.TrimSpace(e.ID),
+			Title:   e.Title.String(),
+			Summary: e.Summary.String(),
+			Content: e.Content.String(),
+			URL:     atomAlternateLink(e.Links),
+		}
+		if len(e.Authors) > 0 {
+			item.Author = strings.TrimSpace(e.Authors[0].Name)
+		}
+		if item.Content == "" {
+			item.Content = item.Summary
+		}
+		var categories []string
+		for _, c := range e.Categories {
+			categories = append(categories, firstNonEmpty(c.Label, c.Term))
+		}
+		item.Categories = uniqueStrings(categories)
+		item.PublishedAt = parseDate(e.Published)
+		if item.PublishedAt.IsZero() {
+			item.PublishedAt = parseDate(e.Updated)
+		}
+
+		var enclosures []model.Enclosure
+		for _, l := range e.Links {
+			if l.Rel == "enclosure" {
+				enclosures = append(enclosures, model.Enclosure{URL: strings.TrimSpace(l.Href), MimeType: strings.TrimSpace(l.Type), Length: parseInt64(l.Length)})
+			}
+		}
+		item.Enclosures, item.ImageURL = mergeMedia(enclosures, e.MediaContents, e.MediaThumbnails)
+		items = append(items, item)
+	}
+	return items
+}
+
+// atomAlternateLink は rel="alternate"（省略時を含む）のリンクを返します。
+func atomAlternateLink(links []atomLink) string {
+	for _, l := range links {
+		if l.Rel == "" || l.Rel == "alternate" {
+			return strings.TrimSpace(l.Href)
+		}
+	}
+	return ""
+}
+
+// mergeMedia は enclosure と Media RSS の情報を統合し、添付メディア一覧とサムネイル画像URLを返します。
+//
+// 同じURLの <media:content> は enclosure の不足情報（MIMEタイプ・サイズ・再生時間）の補完に使用します。
+// サムネイルは <media:thumbnail>、画像の <media:content>、画像の enclosure の順に採用します。
+func mergeMedia(enclosures []model.Enclosure, contents []mediaContent, thumbnails []mediaThumbnail) ([]model.Enclosure, string) {
+	index := make(map[string]int, len(enclosures))
+	var merged []model.Enclosure
+	for _, e := range enclosures {
+		if e.URL == "" {
+			continue
+		}
+		if _, ok := index[e.URL]; ok {
+			continue
+		}
+		index[e.URL] = len(merged)
+		merged = append(merged, e)
+	}
+
+	imageURL := ""
+	for _, t := range thumbnails {
+		if u := strings.TrimSpace(t.URL); u != "" {
+			imageURL = u
+			break
+		}
+	}
+
+	for _, c := range contents {
+		u := strings.TrimSpace(c.URL)
+		if u == "" {
+			continue
+		}
+		if c.Medium == "image" || strings.HasPrefix(c.Type, "image/") {
+			if imageURL == "" {
+				imageURL = u
+			}
+			continue
+		}
+		enclosure := model.Enclosure{URL: u, MimeType: strings.TrimSpace(c.Type), Length: parseInt64(c.FileSize), Duration: int(parseInt64(c.Duration))}
+		if i, ok := index[u]; ok {
+			if merged[i].MimeType == "" {
+				merged[i].MimeType = enclosure.MimeType
+			}
+			if merged[i].Length == 0 {
+				merged[i].Length = enclosure.Length
+			}
+			if merged[i].Duration == 0 {
+				merged[i].Duration = enclosure.Duration
+			}
+			continue
+		}
+		index[u] = len(merged)
+		merged = append(merged, enclosure)
+	}
+
+	if imageURL == "" {
+		for _, e := range merged {
+			if strings.HasPrefix(e.MimeType, "image/") {
+				imageURL = e.URL
+				break
+			}
+		}
+	}
+	return merged, imageURL
+}
+
+// firstNonEmpty は前後の空白を除いて最初に空でない文字列を返します。
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// uniqueStrings は空白を除去し、空文字列と重複を取り除いたスライスを返します。
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var result []string
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		result = append(result, v)
+	}
+	return result
+}
+
+// parseInt64 は数値文字列を解析します。解析できない場合は0を返します。
+func parseInt64(value string) int64 {
+	n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// parseDuration は itunes:duration 形式（"HH:MM:SS"、"MM:SS"、秒数）の再生時間を秒に変換します。
+// 解析できない場合は0を返します。
+func parseDuration(value string) int {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	// 小数秒は切り捨てる
+	if i := strings.Index(value, "."); i >= 0 {
+		value = value[:i]
+	}
+	parts := strings.Split(value, ":")
+	if len(parts) > 3 {
+		return 0
+	}
+	seconds := 0
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0
+		}
+		seconds = seconds*60 + n
+	}
+	return seconds
+}
+
+// dateLayouts はフィードで使われる代表的な日時フォーマットです。
+var dateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC3339,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// parseDate は既知のフォーマットで日時を解析します。解析できない場合はゼロ値を返します。
+func parseDate(value string) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,386 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"feedapp/internal/model"
+)
+
+func TestParseFeed_RSS2(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/">
+  <channel>
+    <title>Example Blog</title>
+    <item>
+      <title>First post</title>
+      <link>https://example.com/first</link>
+      <guid isPermaLink="false">post-1</guid>
+      <description>Summary</description>
+      <content:encoded><![CDATA[<p>Full body</p>]]></content:encoded>
+      <pubDate>Sat, 28 Jun 2025 10:00:00 +0900</pubDate>
+    </item>
+    <item>
+      <title>Second post</title>
+      <guid>https://example.com/second</guid>
+      <description>Only description</description>
+    </item>
+  </channel>
+</rss>`)
+
+	result, err := ParseFeed(data)
+	require.NoError(t, err)
+	assert.Equal(t, "Example Blog", result.Title)
+	require.Len(t, result.Items, 2)
+
+	first := result.Items[0]
+	assert.Equal(t, "post-1", first.GUID)
+	assert.Equal(t, "First post", first.Title)
+	assert.Equal(t, "https://example.com/first", first.URL)
+	assert.Equal(t, "Summary", first.Summary)
+	assert.Equal(t, "<p>Full body</p>", first.Content)
+	assert.True(t, first.PublishedAt.Equal(time.Date(2025, 6, 28, 1, 0, 0, 0, time.UTC)))
+
+	// link が無い場合はパーマリンク形式の guid を URL として使用する
+	second := result.Items[1]
+	assert.Equal(t, "https://example.com/second", second.URL)
+	assert.Equal(t, "Only description", second.Summary)
+	assert.Equal(t, "Only description", second.Content)
+	assert.True(t, second.PublishedAt.IsZero())
+}
+
+func TestParseFeed_RDF(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/" xmlns:dc="http://purl.org/dc/elements/1.1/">
+  <channel rdf:about="https://example.jp/">
+    <title>RDF Site</title>
+  </channel>
+  <item rdf:about="https://example.jp/entry/1">
+    <title>エントリー1</title>
+    <link>https://example.jp/entry/1</link>
+    <description>本文</description>
+    <dc:date>2025-06-28T10:00:00+09:00</dc:date>
+  </item>
+</rdf:RDF>`)
+
+	result, err := ParseFeed(data)
+	require.NoError(t, err)
+	assert.Equal(t, "RDF Site", result.Title)
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, "https://example.jp/entry/1", result.Items[0].GUID)
+	assert.Equal(t, "エントリー1", result.Items[0].Title)
+	assert.False(t, result.Items[0].PublishedAt.IsZero())
+}
+
+func TestParseFeed_Atom(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Atom Feed</title>
+  <entry>
+    <id>tag:example.com,2025:1</id>
+    <title type="text">Atom entry</title>
+    <link rel="self" href="https://example.com/entry/1.atom"/>
+    <link href="https://example.com/entry/1"/>
+    <summary>Short</summary>
+    <updated>2025-06-28T10:00:00Z</updated>
+  </entry>
+</feed>`)
+
+	result, err := ParseFeed(data)
+	require.NoError(t, err)
+	assert.Equal(t, "Atom Feed", result.Title)
+	require.Len(t, result.Items, 1)
+
+	entry := result.Items[0]
+	assert.Equal(t, "tag:example.com,2025:1", entry.GUID)
+	assert.Equal(t, "https://example.com/entry/1", entry.URL)
+	assert.Equal(t, "Short", entry.Content)
+	assert.True(t, entry.PublishedAt.Equal(time.Date(2025, 6, 28, 10, 0, 0, 0, time.UTC)))
+}
+
+func TestParseFeed_Metadata(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:media="http://search.yahoo.com/mrss/">
+  <channel>
+    <title>Podcast</title>
+    <item>
+      <title>Episode 1</title>
+      <link>https://example.com/ep1</link>
+      <guid>ep-1</guid>
+      <dc:creator>Alice</dc:creator>
+      <category>Tech</category>
+      <category>Go</category>
+      <category>Tech</category>
+      <enclosure url="https://cdn.example.com/ep1.mp3" length="12345" type="audio/mpeg"/>
+      <media:content url="https://cdn.example.com/ep1.mp3" duration="1800"/>
+      <media:content url="https://cdn.example.com/ep1.mp4" type="video/mp4" fileSize="999"/>
+      <media:thumbnail url="https://cdn.example.com/ep1.jpg"/>
+    </item>
+  </channel>
+</rss>`)
+
+	result, err := ParseFeed(data)
+	require.NoError(t, err)
+	require.Len(t, result.Items, 1)
+
+	item := result.Items[0]
+	assert.Equal(t, "ep-1", item.GUID)
+	assert.Equal(t, "Alice", item.Author)
+	assert.Equal(t, []string{"Tech", "Go"}, item.Categories)
+	assert.Equal(t, "https://cdn.example.com/ep1.jpg", item.ImageURL)
+	assert.Equal(t, []model.Enclosure{
+		{URL: "https://cdn.example.com/ep1.mp3", MimeType: "audio/mpeg", Length: 12345, Duration: 1800},
+		{URL: "https://cdn.example.com/ep1.mp4", MimeType: "video/mp4", Length: 999},
+	}, item.Enclosures)
+}
+
+func TestParseFeed_ITunes(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+  <channel>
+    <title>Podcast</title>
+    <item>
+      <title>Episode 42</title>
+      <link>https://example.com/ep42</link>
+      <itunes:author>Carol</itunes:author>
+      <itunes:duration>1:02:03</itunes:duration>
+      <itunes:episode>42</itunes:episode>
+      <itunes:image href="https://cdn.example.com/ep42.jpg"/>
+      <enclosure url="https://cdn.example.com/ep42.m4a" length="1000" type="audio/x-m4a"/>
+    </item>
+  </channel>
+</rss>`)
+
+	result, err := ParseFeed(data)
+	require.NoError(t, err)
+	require.Len(t, result.Items, 1)
+
+	item := result.Items[0]
+	assert.Equal(t, "Carol", item.Author)
+	assert.Equal(t, 42, item.Episode)
+	assert.Equal(t, "https://cdn.example.com/ep42.jpg", item.ImageURL)
+	require.Len(t, item.Enclosures, 1)
+	assert.Equal(t, 3723, item.Enclosures[0].Duration)
+}
+
+func TestParseDuration(t *testing.T) {
+	assert.Equal(t, 3723, parseDuration("1:02:03"))
+	assert.Equal(t, 125, parseDuration("2:05"))
+	assert.Equal(t, 1800, parseDuration("1800"))
+	assert.Equal(t, 90, parseDuration("90.5"))
+	assert.Equal(t, 0, parseDuration(""))
+	assert.Equal(t, 0, parseDuration("unknown"))
+}
+
+func TestParseFeed_AtomMetadata(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Atom Feed</title>
+  <entry>
+    <id>tag:example.com,2025:2</id>
+    <title>Atom entry</title>
+    <author><name>Bob</name></author>
+    <category term="go" label="Go"/>
+    <category term="rss"/>
+    <link href="https://example.com/entry/2"/>
+    <link rel="enclosure" href="https://example.com/entry/2.png" type="image/png" length="100"/>
+    <summary>Short</summary>
+    <content type="html">&lt;p&gt;Long&lt;/p&gt;</content>
+  </entry>
+</feed>`)
+
+	result, err := ParseFeed(data)
+	require.NoError(t, err)
+	require.Len(t, result.Items, 1)
+
+	entry := result.Items[0]
+	assert.Equal(t, "Bob", entry.Author)
+	assert.Equal(t, []string{"Go", "rss"}, entry.Categories)
+	assert.Equal(t, "Short", entry.Summary)
+	assert.Equal(t, "<p>Long</p>", entry.Content)
+	assert.Equal(t, "https://example.com/entry/2.png", entry.ImageURL)
+	require.Len(t, entry.Enclosures, 1)
+	assert.Equal(t, int64(100), entry.Enclosures[0].Length)
+}
+
+func TestParseFeed_Unsupported(t *testing.T) {
+	_, err := ParseFeed([]byte(`<html><body>not a feed</body></html>`))
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+
+	_, err = ParseFeed([]byte(`not xml`))
+	assert.Error(t, err)
+}
+
+func TestParseFeed_WebSubHub(t *testing.T) {
+	t.Run("rss atom:link", func(t *testing.T) {
+		data := []byte(`<?xml version="1.0"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+  <channel>
+    <title>Hub Feed</title>
+    <link>https://example.com/</link>
+    <atom:link rel="hub" href="https://hub.example.com/"/>
+    <atom:link rel="self" href="https://example.com/feed.xml" type="application/rss+xml"/>
+  </channel>
+</rss>`)
+
+		result, err := ParseFeed(data)
+		require.NoError(t, err)
+		assert.Equal(t, "https://hub.example.com/", result.Hub)
+		assert.Equal(t, "https://example.com/feed.xml", result.Self)
+	})
+
+	t.Run("atom link", func(t *testing.T) {
+		data := []byte(`<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Atom Feed</title>
+  <link href="https://example.com/"/>
+  <link rel="self" href="https://example.com/atom.xml"/>
+  <link rel="hub" href="https://hub1.example.com/"/>
+  <link rel="hub" href="https://hub2.example.com/"/>
+  <entry>
+    <id>tag:example.com,2025:1</id>
+    <link rel="hub" href="https://entry-hub.example.com/"/>
+  </entry>
+</feed>`)
+
+		result, err := ParseFeed(data)
+		require.NoError(t, err)
+		assert.Equal(t, "https://hub1.example.com/", result.Hub)
+		assert.Equal(t, "https://example.com/atom.xml", result.Self)
+	})
+
+	t.Run("no hub", func(t *testing.T) {
+		result, err := ParseFeed([]byte(`<rss version="2.0"><channel><title>t</title><link>https://example.com/</link></channel></rss>`))
+		require.NoError(t, err)
+		assert.Empty(t, result.Hub)
+		assert.Empty(t, result.Self)
+	})
+}
+
+func TestParseFeed_SiteLinkAndIcon(t *testing.T) {
+	t.Run("rss", func(t *testing.T) {
+		data := []byte(`<?xml version="1.0"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+  <channel>
+    <title>RSS Feed</title>
+    <link>https://example.com/</link>
+    <atom:link rel="self" href="https://example.com/feed.xml"/>
+    <item><title>a</title><link>https://example.com/a</link></item>
+  </channel>
+</rss>`)
+
+		result, err := ParseFeed(data)
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/", result.Link)
+		assert.Empty(t, result.Icon)
+	})
+
+	t.Run("atom", func(t *testing.T) {
+		data := []byte(`<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Atom Feed</title>
+  <link rel="self" href="https://example.com/atom.xml"/>
+  <link rel="alternate" type="text/html" href="https://example.com/blog/"/>
+  <icon>/favicon.png</icon>
+  <entry>
+    <id>tag:example.com,2025:1</id>
+    <link href="https://example.com/blog/1"/>
+  </entry>
+</feed>`)
+
+		result, err := ParseFeed(data)
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/blog/", result.Link)
+		assert.Equal(t, "/favicon.png", result.Icon)
+	})
+}
+
+// TestParseFeedContent_Malformed は実際のサイトで見られる文字コードの誤りや不正なXMLを再現した
+// testdata/malformed のフィードを解析します。
+func TestParseFeedContent_Malformed(t *testing.T) {
+	tests := []struct {
+		file        string
+		contentType string
+		title       string
+		itemTitle   string
+		itemURL     string
+		warning     string // 期待する警告の一部（空の場合は警告なし）
+	}{
+		// 宣言が正しい場合は、誤った Content-Type の charset より XML 宣言を優先する
+		{file: "sjis_rss2.xml", contentType: "text/xml; charset=ISO-8859-1", title: "町内会だより", itemTitle: "夏祭りのお知らせ", itemURL: "http://www.example.jp/news/0701.html"},
+		{file: "eucjp_rdf.xml", contentType: "application/rdf+xml", title: "日記帳", itemTitle: "梅雨明けはまだ", itemURL: "http://diary.example.jp/20240701.html"},
+		{file: "sjis_declared_utf8.xml", contentType: "application/rss+xml", title: "商店街ニュース", itemTitle: "大売り出し開催中", itemURL: "http://shop.example.jp/sale",
+			warning: "charset utf-8 does not match the content, decoded as shift_jis"},
+		{file: "eucjp_no_declaration.xml", contentType: "text/xml; charset=Shift_JIS", title: "研究室の更新情報", itemTitle: "論文を公開しました", itemURL: "http://lab.example.ac.jp/papers/2024",
+			warning: "charset shift_jis does not match the content, decoded as euc-jp"},
+		// BOM は XML 宣言より優先する
+		{file: "utf8_bom_wrong_declaration.xml", contentType: "application/atom+xml", title: "開発ブログ", itemTitle: "リリースノート", itemURL: "https://dev.example.jp/release"},
+		{file: "utf16le_bom.xml", contentType: "text/xml", title: "お知らせ", itemTitle: "サーバーメンテナンス", itemURL: "https://status.example.jp/maintenance"},
+		{file: "unescaped_ampersand.xml", contentType: "application/rss+xml", title: "Tom & Jerry Fan Club", itemTitle: "Q&A session", itemURL: "https://fans.example.com/index.php?page=news&id=42",
+			warning: "recovered from malformed XML"},
+		{file: "html_entities.xml", contentType: "application/rss+xml", title: "Caf\u00e9\u00a0Blog", itemTitle: "New menu\u2026 \u00a9 2024", itemURL: "https://cafe.example.com/menu",
+			warning: "recovered from malformed XML"},
+		{file: "control_characters.xml", contentType: "application/rss+xml", title: "Forum Digest", itemTitle: "Weekly summary", itemURL: "https://forum.example.com/t/1",
+			warning: "removed 4 invalid characters"},
+		{file: "php_warning_prefix.xml", contentType: "text/html; charset=UTF-8", title: "Club News", itemTitle: "Season opener", itemURL: "https://club.example.com/news/1",
+			warning: "before the XML declaration"},
+		{file: "sjis_cms_entities.xml", contentType: "text/xml", title: "市役所\u00a0新着情報", itemTitle: "防災訓練のお知らせ&参加者募集", itemURL: "http://www.city.example.lg.jp/bousai.php?id=10&lang=ja",
+			warning: "recovered from malformed XML"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", "malformed", tt.file))
+			require.NoError(t, err)
+
+			result, err := ParseFeedContent(data, tt.contentType)
+			require.NoError(t, err)
+			assert.Equal(t, tt.title, result.Title)
+			require.NotEmpty(t, result.Items)
+			assert.Equal(t, tt.itemTitle, result.Items[0].Title)
+			assert.Equal(t, tt.itemURL, result.Items[0].URL)
+			if tt.warning == "" {
+				assert.Empty(t, result.Warnings)
+			} else {
+				assert.Contains(t, strings.Join(result.Warnings, "; "), tt.warning)
+			}
+		})
+	}
+
+	t.Run("truncated.xml", func(t *testing.T) {
+		data, err := os.ReadFile(filepath.Join("testdata", "malformed", "truncated.xml"))
+		require.NoError(t, err)
+
+		_, err = ParseFeedContent(data, "application/rss+xml")
+		assert.Error(t, err)
+	})
+}
+
+func TestParseFeedContent_WellFormedHasNoWarnings(t *testing.T) {
+	result, err := ParseFeedContent([]byte(`<?xml version="1.0" encoding="UTF-8"?><rss version="2.0"><channel><title>日本語</title></channel></rss>`), "application/rss+xml; charset=UTF-8")
+	require.NoError(t, err)
+	assert.Equal(t, "日本語", result.Title)
+	assert.Empty(t, result.Warnings)
+}
+
+func TestParseLinkHeader(t *testing.T) {
+	hub, self := parseLinkHeader([]string{
+		`<https://example.com/style.css>; rel="stylesheet", <https://hub.example.com/>; rel="hub"`,
+		`<https://example.com/feed.xml>; rel=self`,
+	})
+	assert.Equal(t, "https://hub.example.com/", hub)
+	assert.Equal(t, "https://example.com/feed.xml", self)
+
+	hub, self = parseLinkHeader([]string{`<https://hub.example.com/>; rel="hub self"`})
+	assert.Equal(t, "https://hub.example.com/", hub)
+	assert.Equal(t, "https://hub.example.com/", self)
+
+	hub, self = parseLinkHeader(nil)
+	assert.Empty(t, hub)
+	assert.Empty(t, self)
+}
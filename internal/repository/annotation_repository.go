@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"feedapp/internal/model"
+)
+
+// AnnotationRepository は記事の注釈のデータ永続化を定義するインターフェースです。
+//
+// すべてのメソッドは所有者で絞り込み、他のユーザーの注釈は ErrNotFound となります。
+// 記事が呼び出し元のユーザーから参照できるかは呼び出し元で確認します。
+type AnnotationRepository interface {
+	GetAll(userID string) ([]model.Annotation, error)
+	GetByArticleID(userID, articleID string) ([]model.Annotation, error)
+	GetByID(userID, articleID, id string) (model.Annotation, error)
+	Create(annotation model.Annotation) (model.Annotation, error)
+	Update(annotation model.Annotation) (model.Annotation, error)
+	Delete(userID, articleID, id string) error
+}
+
+// annotationRepository は AnnotationRepository インターフェースの実装です。
+type annotationRepository struct {
+	db *sql.DB
+}
+
+// NewAnnotationRepository は新しい annotationRepository インスタンスを作成します。
+func NewAnnotationRepository(db *sql.DB) AnnotationRepository {
+	return &annotationRepository{db: db}
+}
+
+// selectAnnotations は注釈を記事のタイトル・URLとともに取得するクエリの SELECT 句と FROM 句です。
+const selectAnnotations = `SELECT n.id, n.user_id, n.article_id, a.title, a.url,
+	n.selector_exact, n.selector_prefix, n.selector_suffix, n.body, n.color, n.created_at, n.updated_at
+	FROM annotations n JOIN articles a ON a.id = n.article_id`
+
+// scanAnnotation は selectAnnotations の順に並んだ行を model.Annotation に変換します。
+func scanAnnotation(row rowScanner) (model.Annotation, error) {
+	var annotation model.Annotation
+	if err := row.Scan(&annotation.ID, &annotation.UserID, &annotation.ArticleID, &annotation.ArticleTitle, &annotation.ArticleURL,
+		&annotation.Selector.Exact, &annotation.Selector.Prefix, &annotation.Selector.Suffix,
+		&annotation.Body, &annotation.Color, &annotation.CreatedAt, &annotation.UpdatedAt); err != nil {
+		return model.Annotation{}, err
+	}
+	annotation.Selector.Type = model.TextQuoteSelectorType
+	return annotation, nil
+}
+
+// queryAnnotations は複数の注釈を取得するクエリを実行します。
+func (r *annotationRepository) queryAnnotations(query string, args ...any) ([]model.Annotation, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	annotations := []model.Annotation{}
+	for rows.Next() {
+		annotation, err := scanAnnotation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan annotation row: %w", err)
+		}
+		annotations = append(annotations, annotation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return annotations, nil
+}
+
+// GetAll はユーザーのすべての注釈を新しい順に取得します。
+func (r *annotationRepository) GetAll(userID string) ([]model.Annotation, error) {
+	annotations, err := r.queryAnnotations(selectAnnotations+" WHERE n.user_id = $1 ORDER BY n.created_at DESC", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get annotations: %w", err)
+	}
+	return annotations, nil
+}
+
+// GetByArticleID は記事に付けたユーザーの注釈を作成順に取得します。
+func (r *annotationRepository) GetByArticleID(userID, articleID string) ([]model.Annotation, error) {
+	annotations, err := r.queryAnnotations(selectAnnotations+" WHERE n.user_id = $1 AND n.article_id = $2 ORDER BY n.created_at", userID, articleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get annotations by article ID: %w", err)
+	}
+	return annotations, nil
+}
+
+func (r *annotationRepository) GetByID(userID, articleID, id string) (model.Annotation, error) {
+	annotation, err := scanAnnotation(r.db.QueryRow(selectAnnotations+" WHERE n.id = $1 AND n.user_id = $2 AND n.article_id = $3", id, userID, articleID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Annotation{}, ErrNotFound
+		}
+		return model.Annotation{}, fmt.Errorf("failed to get annotation by ID: %w", err)
+	}
+	return annotation, nil
+}
+
+func (r *annotationRepository) Create(annotation model.Annotation) (model.Annotation, error) {
+	query := `INSERT INTO annotations (id, user_id, article_id, selector_exact, selector_prefix, selector_suffix, body, color, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	if _, err := r.db.Exec(query, annotation.ID, annotation.UserID, annotation.ArticleID, annotation.Selector.Exact, annotation.Selector.Prefix,
+		annotation.Selector.Suffix, annotation.Body, annotation.Color, annotation.CreatedAt, annotation.UpdatedAt); err != nil {
+		return model.Annotation{}, fmt.Errorf("failed to create annotation: %w", err)
+	}
+	return r.GetByID(annotation.UserID, annotation.ArticleID, annotation.ID)
+}
+
+// Update は注釈の箇所・メモ・色を更新します。所有者または記事が一致しない場合は ErrNotFound を返します。
+func (r *annotationRepository) Update(annotation model.Annotation) (model.Annotation, error) {
+	query := `UPDATE annotations SET selector_exact = $1, selector_prefix = $2, selector_suffix = $3, body = $4, color = $5, updated_at = $6
+		WHERE id = $7 AND user_id = $8 AND article_id = $9`
+	result, err := r.db.Exec(query, annotation.Selector.Exact, annotation.Selector.Prefix, annotation.Selector.Suffix, annotation.Body,
+		annotation.Color, annotation.UpdatedAt, annotation.ID, annotation.UserID, annotation.ArticleID)
+	if err != nil {
+		return model.Annotation{}, fmt.Errorf("failed to update annotation: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return model.Annotation{}, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return model.Annotation{}, ErrNotFound
+	}
+	return r.GetByID(annotation.UserID, annotation.ArticleID, annotation.ID)
+}
+
+func (r *annotationRepository) Delete(userID, articleID, id string) error {
+	result, err := r.db.Exec("DELETE FROM annotations WHERE id = $1 AND user_id = $2 AND article_id = $3", id, userID, articleID)
+	if err != nil {
+		return fmt.Errorf("failed to delete annotation: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
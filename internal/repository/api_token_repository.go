@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"feedapp/internal/model"
+)
+
+// APITokenRepository は個人APIトークンのデータ永続化を定義するインターフェースです。
+type APITokenRepository interface {
+	GetByUserID(userID string) ([]model.APIToken, error)
+	GetByTokenHash(tokenHash string) (model.APIToken, error)
+	Create(token model.APIToken) (model.APIToken, error)
+	Delete(userID, id string) error
+	UpdateLastUsed(id string, lastUsedAt time.Time) error
+}
+
+// apiTokenRepository は APITokenRepository インターフェースの実装です。
+type apiTokenRepository struct {
+	db *sql.DB
+}
+
+// NewAPITokenRepository は新しい apiTokenRepository インスタンスを作成します。
+func NewAPITokenRepository(db *sql.DB) APITokenRepository {
+	return &apiTokenRepository{db: db}
+}
+
+// apiTokenColumns はAPIトークンの取得時に使用するカラム一覧です。
+const apiTokenColumns = "id, user_id, name, token_hash, last_used_at, created_at"
+
+// scanAPIToken は apiTokenColumns の順に並んだ行を model.APIToken に変換します。
+func scanAPIToken(row rowScanner) (model.APIToken, error) {
+	var token model.APIToken
+	var lastUsedAt sql.NullTime
+	if err := row.Scan(&token.ID, &token.UserID, &token.Name, &token.TokenHash, &lastUsedAt, &token.CreatedAt); err != nil {
+		return model.APIToken{}, err
+	}
+	if lastUsedAt.Valid {
+		token.LastUsedAt = &lastUsedAt.Time
+	}
+	return token, nil
+}
+
+func (r *apiTokenRepository) GetByUserID(userID string) ([]model.APIToken, error) {
+	rows, err := r.db.Query("SELECT "+apiTokenColumns+" FROM api_tokens WHERE user_id = $1 ORDER BY created_at", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []model.APIToken
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api token row: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return tokens, nil
+}
+
+func (r *apiTokenRepository) GetByTokenHash(tokenHash string) (model.APIToken, error) {
+	token, err := scanAPIToken(r.db.QueryRow("SELECT "+apiTokenColumns+" FROM api_tokens WHERE token_hash = $1", tokenHash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.APIToken{}, ErrNotFound
+		}
+		return model.APIToken{}, fmt.Errorf("failed to get api token: %w", err)
+	}
+	return token, nil
+}
+
+func (r *apiTokenRepository) Create(token model.APIToken) (model.APIToken, error) {
+	query := `INSERT INTO api_tokens (id, user_id, name, token_hash, created_at) VALUES ($1, $2, $3, $4, $5) RETURNING ` + apiTokenColumns
+	createdToken, err := scanAPIToken(r.db.QueryRow(query, token.ID, token.UserID, token.Name, token.TokenHash, token.CreatedAt))
+	if err != nil {
+		return model.APIToken{}, fmt.Errorf("failed to create api token: %w", err)
+	}
+	return createdToken, nil
+}
+
+// Delete は指定ユーザーが所有するAPIトークンを削除します。
+// 他のユーザーのトークンを指定した場合は ErrNotFound を返します。
+func (r *apiTokenRepository) Delete(userID, id string) error {
+	result, err := r.db.Exec("DELETE FROM api_tokens WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete api token: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *apiTokenRepository) UpdateLastUsed(id string, lastUsedAt time.Time) error {
+	if _, err := r.db.Exec("UPDATE api_tokens SET last_used_at = $1 WHERE id = $2", lastUsedAt, id); err != nil {
+		return fmt.Errorf("failed to update api token last_used_at: %w", err)
+	}
+	return nil
+}
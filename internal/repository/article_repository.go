@@ -4,18 +4,73 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
 
 	"feedapp/internal/model"
 )
 
 // ArticleRepository は記事のデータ永続化を定義するインターフェースです。
+//
+// 記事本体は全ユーザーで共有し、既読・後で見る・再生状態はユーザーごとに
+// article_states テーブルへ保存します。ユーザーIDを受け取るメソッドは、
+// そのユーザーが購読しているフィードの記事のみを対象とし、それ以外の記事は
+// ErrNotFound となります。ユーザーIDを受け取らないメソッドは記事の取り込みで使用し、
+// 返される記事の状態は初期値となります。
 type ArticleRepository interface {
-	GetAll() ([]model.Article, error)
-	GetByID(id string) (model.Article, error)
+	GetAll(userID string, filter model.ArticleFilter) ([]model.Article, error)
+	GetByID(userID, id string) (model.Article, error)
+	GetItemIDs(userID string, filter model.ArticleFilter) ([]int64, error)
+	GetByNormalizedURL(normalizedURL string) (model.Article, error)
+	GetPrivateByNormalizedURL(feedID, normalizedURL string) (model.Article, error)
+	GetByFeedGUID(feedID, guid string) (model.Article, error)
+	IsPurged(normalizedURL string) (bool, error)
+	IsPrivatePurged(feedID, normalizedURL string) (bool, error)
+	GetByFeedID(userID, feedID string) ([]model.Article, error)
+	GetByFolderID(userID, folderID string) ([]model.Article, error)
 	Create(article model.Article) (model.Article, error)
-	Update(article model.Article) (model.Article, error)
+	UpdateState(userID string, article model.Article) (model.Article, error)
+	UpdateStates(userID string, ids []string, isRead, isLater *bool) error
+	MarkRead(userID string, filter model.ArticleFilter) (int64, error)
+	Hide(userID string, ids []string) error
 	Delete(id string) error
-	GetLaterArticles() ([]model.Article, error)
+	AddSource(articleID, feedID, guid string) (bool, error)
+	GetLaterArticles(userID string) ([]model.Article, error)
+	GetOutdatedSanitized(version, limit int) ([]model.Article, error)
+	UpdateSanitizedContent(article model.Article) error
+	UpdateExtractedContent(article model.Article) error
+	GetUnnormalized(limit int) ([]model.Article, error)
+	SetNormalizedURL(id, normalizedURL string) error
+}
+
+// selectArticles は記事を取得するクエリの SELECT 句と FROM 句です。
+//
+// プレースホルダー $1 にはユーザーID（取り込み時は NULL）を指定します。
+// 状態はユーザーの article_states から取得し、行が存在しない場合は初期値とします。
+// feed_id は、そのユーザーが購読しているフィードのうち最初に記事を配信したものを返します。
+// guid はその feed_id のフィードでのエントリー識別子（article_sources.guid）です。
+// タグはそのユーザーが付けたものを名前順に返します。
+// スナップショットの取得状況は記事ごとの値で、すべてのユーザーに共有されます。
+const selectArticles = `SELECT a.id, COALESCE(own.feed_id, a.feed_id),
+	CASE WHEN own.feed_id IS NOT NULL THEN own.guid ELSE (SELECT src.guid FROM article_sources src WHERE src.article_id = a.id AND src.feed_id = a.feed_id) END,
+	a.title, a.author, a.summary, a.content, a.summary_raw, a.content_raw, a.sanitizer_version, a.url, a.image_url, a.episode, a.published_at,
+	COALESCE(st.is_read, FALSE), COALESCE(st.is_later, FALSE), COALESCE(st.playback_position_seconds, 0), COALESCE(st.played, FALSE), COALESCE(st.downloaded, FALSE),
+	a.extracted_at, a.created_at, a.item_id,
+	ARRAY(SELECT t.name FROM article_tags atg JOIN tags t ON t.id = atg.tag_id WHERE atg.article_id = a.id AND t.user_id = $1 ORDER BY t.name),
+	(SELECT snap.status FROM article_snapshots snap WHERE snap.article_id = a.id), a.is_private
+	FROM articles a
+	LEFT JOIN LATERAL (SELECT src.feed_id, src.guid FROM article_sources src JOIN subscriptions sub ON sub.feed_id = src.feed_id WHERE src.article_id = a.id AND sub.user_id = $1 ORDER BY src.created_at LIMIT 1) own ON TRUE
+	LEFT JOIN article_states st ON st.article_id = a.id AND st.user_id = $1`
+
+// visibleToUser は $1 のユーザーが購読しているフィードの記事に絞り込む条件です。
+// ルールの skip でユーザーの一覧から除外した記事（article_states.is_hidden）は含みません。
+const visibleToUser = ` EXISTS (SELECT 1 FROM article_sources src JOIN subscriptions sub ON sub.feed_id = src.feed_id WHERE src.article_id = a.id AND sub.user_id = $1)
+	AND NOT EXISTS (SELECT 1 FROM article_states hidden WHERE hidden.article_id = a.id AND hidden.user_id = $1 AND hidden.is_hidden)`
+
+// rowScanner は *sql.Row と *sql.Rows の共通インターフェースです。
+type rowScanner interface {
+	Scan(dest ...any) error
 }
 
 // articleRepository は ArticleRepository インターフェースの実装です。
@@ -28,27 +83,49 @@ func NewArticleRepository(db *sql.DB) ArticleRepository {
 	return &articleRepository{db: db}
 }
 
-func (r *articleRepository) GetAll() ([]model.Article, error) {
-	rows, err := r.db.Query("SELECT id, feed_id, title, content, url, published_at, is_read, is_later, created_at FROM articles")
+// scanArticle は selectArticles の順に並んだ行を model.Article に変換します。
+func scanArticle(row rowScanner) (model.Article, error) {
+	var article model.Article
+	var guid, author, summary, content, rawSummary, rawContent, imageURL, snapshotStatus sql.NullString
+	var episode sql.NullInt64
+	var publishedAt, extractedAt sql.NullTime
+	if err := row.Scan(&article.ID, &article.FeedID, &guid, &article.Title, &author, &summary, &content, &rawSummary, &rawContent, &article.SanitizerVersion, &article.URL, &imageURL, &episode, &publishedAt, &article.IsRead, &article.IsLater, &article.PlaybackPosition, &article.Played, &article.Downloaded, &extractedAt, &article.CreatedAt, &article.ItemID, pq.Array(&article.Tags), &snapshotStatus, &article.IsPrivate); err != nil {
+		return model.Article{}, err
+	}
+	article.Episode = int(episode.Int64)
+	article.GUID = guid.String
+	article.Author = author.String
+	article.Summary = summary.String
+	article.RawSummary = rawSummary.String
+	article.RawContent = rawContent.String
+	article.ImageURL = imageURL.String
+	article.SnapshotStatus = snapshotStatus.String
+	if content.Valid {
+		article.Content = content.String
+	}
+	if publishedAt.Valid {
+		article.PublishedAt = publishedAt.Time
+	}
+	if extractedAt.Valid {
+		article.ExtractedAt = &extractedAt.Time
+	}
+	return article, nil
+}
+
+// queryArticles は複数の記事を取得するクエリを実行します。
+func (r *articleRepository) queryArticles(query string, args ...any) ([]model.Article, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get all articles: %w", err)
+		return nil, err
 	}
 	defer rows.Close()
 
 	var articles []model.Article
 	for rows.Next() {
-		var article model.Article
-		var content sql.NullString
-		var publishedAt sql.NullTime
-		if err := rows.Scan(&article.ID, &article.FeedID, &article.Title, &content, &article.URL, &publishedAt, &article.IsRead, &article.IsLater, &article.CreatedAt); err != nil {
+		article, err := scanArticle(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan article row: %w", err)
 		}
-		if content.Valid {
-			article.Content = content.String
-		}
-		if publishedAt.Valid {
-			article.PublishedAt = publishedAt.Time
-		}
 		articles = append(articles, article)
 	}
 
@@ -56,66 +133,386 @@ func (r *articleRepository) GetAll() ([]model.Article, error) {
 		return nil, fmt.Errorf("rows iteration error: %w", err)
 	}
 
+	if err := r.attachRelations(articles); err != nil {
+		return nil, err
+	}
 	return articles, nil
 }
 
-func (r *articleRepository) GetByID(id string) (model.Article, error) {
-	var article model.Article
-	var content sql.NullString
-	var publishedAt sql.NullTime
-	err := r.db.QueryRow("SELECT id, feed_id, title, content, url, published_at, is_read, is_later, created_at FROM articles WHERE id = $1", id).Scan(&article.ID, &article.FeedID, &article.Title, &content, &article.URL, &publishedAt, &article.IsRead, &article.IsLater, &article.CreatedAt)
+// getArticle は1件の記事を取得するクエリを実行し、関連データを読み込みます。
+func (r *articleRepository) getArticle(query string, args ...any) (model.Article, error) {
+	article, err := scanArticle(r.db.QueryRow(query, args...))
+	if err != nil {
+		return model.Article{}, err
+	}
+	articles := []model.Article{article}
+	if err := r.attachRelations(articles); err != nil {
+		return model.Article{}, err
+	}
+	return articles[0], nil
+}
+
+// attachRelations は記事のカテゴリと添付メディアをまとめて読み込みます。
+func (r *articleRepository) attachRelations(articles []model.Article) error {
+	if len(articles) == 0 {
+		return nil
+	}
+	ids := make([]string, len(articles))
+	index := make(map[string]int, len(articles))
+	for i, article := range articles {
+		ids[i] = article.ID
+		index[article.ID] = i
+	}
+
+	categoryRows, err := r.db.Query("SELECT article_id, name FROM article_categories WHERE article_id = ANY($1) ORDER BY article_id, position", pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get article categories: %w", err)
+	}
+	defer categoryRows.Close()
+	for categoryRows.Next() {
+		var articleID, name string
+		if err := categoryRows.Scan(&articleID, &name); err != nil {
+			return fmt.Errorf("failed to scan article category row: %w", err)
+		}
+		i := index[articleID]
+		articles[i].Categories = append(articles[i].Categories, name)
+	}
+	if err := categoryRows.Err(); err != nil {
+		return fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	enclosureRows, err := r.db.Query("SELECT article_id, url, mime_type, length, duration FROM article_enclosures WHERE article_id = ANY($1) ORDER BY article_id, position", pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get article enclosures: %w", err)
+	}
+	defer enclosureRows.Close()
+	for enclosureRows.Next() {
+		var articleID string
+		var enclosure model.Enclosure
+		var mimeType sql.NullString
+		var length, duration sql.NullInt64
+		if err := enclosureRows.Scan(&articleID, &enclosure.URL, &mimeType, &length, &duration); err != nil {
+			return fmt.Errorf("failed to scan article enclosure row: %w", err)
+		}
+		enclosure.MimeType = mimeType.String
+		enclosure.Length = length.Int64
+		enclosure.Duration = int(duration.Int64)
+		i := index[articleID]
+		articles[i].Enclosures = append(articles[i].Enclosures, enclosure)
+	}
+	if err := enclosureRows.Err(); err != nil {
+		return fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return nil
+}
+
+// enclosureMimePrefixes は添付メディア種別に対応するMIMEタイプの接頭辞です。
+var enclosureMimePrefixes = map[string]string{
+	model.EnclosureTypeAudio: "audio/",
+	model.EnclosureTypeVideo: "video/",
+	model.EnclosureTypeImage: "image/",
+}
+
+// GetAll はユーザーが購読しているフィードの記事のうち、絞り込み条件に一致するものを取得します。
+func (r *articleRepository) GetAll(userID string, filter model.ArticleFilter) ([]model.Article, error) {
+	conditions, args := filterConditions(filter, []any{userID})
+	query := selectArticles + " WHERE" + visibleToUser + conditions
+	if filter.OldestFirst {
+		query += " ORDER BY a.item_id"
+	} else {
+		query += " ORDER BY a.item_id DESC"
+	}
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	articles, err := r.queryArticles(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all articles: %w", err)
+	}
+	return articles, nil
+}
+
+// GetItemIDs は絞り込み条件に一致する記事の連番を GetAll と同じ順で取得します。
+// 未読記事の一覧など、記事の本文を必要としない同期処理で使用します。
+func (r *articleRepository) GetItemIDs(userID string, filter model.ArticleFilter) ([]int64, error) {
+	conditions, args := filterConditions(filter, []any{userID})
+	query := "SELECT a.item_id FROM articles a LEFT JOIN article_states st ON st.article_id = a.id AND st.user_id = $1 WHERE" + visibleToUser + conditions
+	if filter.OldestFirst {
+		query += " ORDER BY a.item_id"
+	} else {
+		query += " ORDER BY a.item_id DESC"
+	}
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get article item IDs: %w", err)
+	}
+	defer rows.Close()
+
+	itemIDs := []int64{}
+	for rows.Next() {
+		var itemID int64
+		if err := rows.Scan(&itemID); err != nil {
+			return nil, fmt.Errorf("failed to scan article item ID: %w", err)
+		}
+		itemIDs = append(itemIDs, itemID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return itemIDs, nil
+}
+
+// filterConditions は絞り込み条件を selectArticles に続ける AND 条件に変換します。
+// args には $1 のユーザーIDを含む引数を渡し、条件の引数を追加したものを返します。
+// 並び順と件数（OldestFirst・Limit）は含みません。
+func filterConditions(filter model.ArticleFilter, args []any) (string, []any) {
+	var conditions strings.Builder
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions.WriteString(" AND " + strings.ReplaceAll(condition, "$?", fmt.Sprintf("$%d", len(args))))
+	}
+
+	switch {
+	case filter.EnclosureType == model.EnclosureTypeAny:
+		conditions.WriteString(" AND EXISTS (SELECT 1 FROM article_enclosures e WHERE e.article_id = a.id)")
+	case enclosureMimePrefixes[filter.EnclosureType] != "":
+		add("EXISTS (SELECT 1 FROM article_enclosures e WHERE e.article_id = a.id AND e.mime_type LIKE $?)", enclosureMimePrefixes[filter.EnclosureType]+"%")
+	}
+	if filter.FeedID != "" {
+		add("a.id IN (SELECT article_id FROM article_sources WHERE feed_id = $?)", filter.FeedID)
+	}
+	if filter.FolderID != "" {
+		add("a.id IN (SELECT src.article_id FROM article_sources src JOIN subscriptions sub ON sub.feed_id = src.feed_id WHERE sub.user_id = $1 AND sub.folder_id = $?)", filter.FolderID)
+	}
+	if filter.Tag != "" {
+		add("a.id IN (SELECT atg.article_id FROM article_tags atg JOIN tags t ON t.id = atg.tag_id WHERE t.user_id = $1 AND t.name = $?)", filter.Tag)
+	}
+	if filter.IsRead != nil {
+		add("COALESCE(st.is_read, FALSE) = $?", *filter.IsRead)
+	}
+	if filter.IsLater != nil {
+		add("COALESCE(st.is_later, FALSE) = $?", *filter.IsLater)
+	}
+	if filter.ItemIDs != nil {
+		add("a.item_id = ANY($?)", pq.Array(filter.ItemIDs))
+	}
+	if !filter.Since.IsZero() {
+		add("a.created_at >= $?", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		add("a.created_at < $?", filter.Until)
+	}
+	if filter.Cursor > 0 {
+		if filter.OldestFirst {
+			add("a.item_id > $?", filter.Cursor)
+		} else {
+			add("a.item_id < $?", filter.Cursor)
+		}
+	}
+	return conditions.String(), args
+}
+
+func (r *articleRepository) GetByID(userID, id string) (model.Article, error) {
+	article, err := r.getArticle(selectArticles+" WHERE a.id = $2 AND"+visibleToUser, userID, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return model.Article{}, ErrNotFound
 		}
 		return model.Article{}, fmt.Errorf("failed to get article by ID: %w", err)
 	}
-	if content.Valid {
-		article.Content = content.String
+	return article, nil
+}
+
+// GetByNormalizedURL は正規化済みURLが一致する記事を取得します。
+// 認証情報を設定したフィードの記事（IsPrivate）は対象外です。
+func (r *articleRepository) GetByNormalizedURL(normalizedURL string) (model.Article, error) {
+	article, err := r.getArticle(selectArticles+" WHERE a.normalized_url = $2 AND NOT a.is_private", nil, normalizedURL)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Article{}, ErrNotFound
+		}
+		return model.Article{}, fmt.Errorf("failed to get article by URL: %w", err)
 	}
-	if publishedAt.Valid {
-		article.PublishedAt = publishedAt.Time
+	return article, nil
+}
+
+// GetPrivateByNormalizedURL は認証情報を設定したフィードの記事のうち、正規化済みURLが一致する記事を取得します。
+func (r *articleRepository) GetPrivateByNormalizedURL(feedID, normalizedURL string) (model.Article, error) {
+	article, err := r.getArticle(selectArticles+" WHERE a.feed_id = $2 AND a.normalized_url = $3 AND a.is_private", nil, feedID, normalizedURL)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Article{}, ErrNotFound
+		}
+		return model.Article{}, fmt.Errorf("failed to get article by URL: %w", err)
+	}
+	return article, nil
+}
+
+// IsPurged は正規化済みURLの記事が保持設定により削除済みかを返します。
+// 認証情報を設定したフィードの記事の削除は対象外です。
+func (r *articleRepository) IsPurged(normalizedURL string) (bool, error) {
+	var purged bool
+	if err := r.db.QueryRow("SELECT EXISTS (SELECT 1 FROM purged_articles WHERE normalized_url = $1 AND NOT is_private)", normalizedURL).Scan(&purged); err != nil {
+		return false, fmt.Errorf("failed to check purged article: %w", err)
+	}
+	return purged, nil
+}
+
+// IsPrivatePurged は認証情報を設定したフィードの記事のうち、正規化済みURLの記事が保持設定により削除済みかを返します。
+func (r *articleRepository) IsPrivatePurged(feedID, normalizedURL string) (bool, error) {
+	var purged bool
+	if err := r.db.QueryRow("SELECT EXISTS (SELECT 1 FROM purged_articles WHERE feed_id = $1 AND normalized_url = $2 AND is_private)", feedID, normalizedURL).Scan(&purged); err != nil {
+		return false, fmt.Errorf("failed to check purged article: %w", err)
+	}
+	return purged, nil
+}
+
+// GetByFeedGUID は指定フィード内でGUIDが一致する記事を取得します。
+func (r *articleRepository) GetByFeedGUID(feedID, guid string) (model.Article, error) {
+	query := selectArticles + " WHERE a.id = (SELECT article_id FROM article_sources WHERE feed_id = $2 AND guid = $3)"
+	article, err := r.getArticle(query, nil, feedID, guid)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Article{}, ErrNotFound
+		}
+		return model.Article{}, fmt.Errorf("failed to get article by GUID: %w", err)
 	}
 	return article, nil
 }
 
+// GetByFeedID は指定フィードが配信した記事のうち、ユーザーが参照できるものを取得します。
+func (r *articleRepository) GetByFeedID(userID, feedID string) ([]model.Article, error) {
+	query := selectArticles + " WHERE a.id IN (SELECT article_id FROM article_sources WHERE feed_id = $2) AND" + visibleToUser
+	articles, err := r.queryArticles(query, userID, feedID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get articles by feed ID: %w", err)
+	}
+	return articles, nil
+}
+
+// GetByFolderID は指定フォルダに分類した購読フィードのいずれかが配信した記事のうち、ユーザーが参照できるものを取得します。
+func (r *articleRepository) GetByFolderID(userID, folderID string) ([]model.Article, error) {
+	query := selectArticles + " WHERE" + visibleToUser + " AND a.id IN (SELECT src.article_id FROM article_sources src JOIN subscriptions sub ON sub.feed_id = src.feed_id WHERE sub.user_id = $1 AND sub.folder_id = $2)"
+	articles, err := r.queryArticles(query, userID, folderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get articles by folder ID: %w", err)
+	}
+	return articles, nil
+}
+
+// Create は記事を作成します。カテゴリと添付メディアも同一トランザクションで保存します。
+// 作成した記事の状態は初期値となります。
+// エントリー識別子（GUID）は保存しないため、AddSource でフィードごとに保存します。
+// IsPrivate の記事は、同じURLの記事が他のフィードにあっても別の記事として作成します。
 func (r *articleRepository) Create(article model.Article) (model.Article, error) {
-	query := `INSERT INTO articles (id, feed_id, title, content, url, published_at, is_read, is_later, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, feed_id, title, content, url, published_at, is_read, is_later, created_at`
-	var createdArticle model.Article
-	var content sql.NullString
-	var publishedAt sql.NullTime
-	err := r.db.QueryRow(query, article.ID, article.FeedID, article.Title, sql.NullString{String: article.Content, Valid: article.Content != ""}, article.URL, sql.NullTime{Time: article.PublishedAt, Valid: !article.PublishedAt.IsZero()}, article.IsRead, article.IsLater, article.CreatedAt).Scan(&createdArticle.ID, &createdArticle.FeedID, &createdArticle.Title, &content, &createdArticle.URL, &publishedAt, &createdArticle.IsRead, &createdArticle.IsLater, &createdArticle.CreatedAt)
+	tx, err := r.db.Begin()
 	if err != nil {
+		return model.Article{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO articles (id, feed_id, title, author, summary, content, summary_raw, content_raw, sanitizer_version, url, normalized_url, image_url, episode, published_at, extracted_at, created_at, is_private) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)`
+	if _, err := tx.Exec(query, article.ID, article.FeedID, article.Title, nullString(article.Author), nullString(article.Summary), nullString(article.Content), nullString(article.RawSummary), nullString(article.RawContent), article.SanitizerVersion, article.URL, model.NormalizeURL(article.URL), nullString(article.ImageURL), sql.NullInt64{Int64: int64(article.Episode), Valid: article.Episode > 0}, sql.NullTime{Time: article.PublishedAt, Valid: !article.PublishedAt.IsZero()}, article.ExtractedAt, article.CreatedAt, article.IsPrivate); err != nil {
 		return model.Article{}, fmt.Errorf("failed to create article: %w", err)
 	}
-	if content.Valid {
-		createdArticle.Content = content.String
+
+	for i, name := range article.Categories {
+		if _, err := tx.Exec("INSERT INTO article_categories (article_id, position, name) VALUES ($1, $2, $3)", article.ID, i, name); err != nil {
+			return model.Article{}, fmt.Errorf("failed to create article category: %w", err)
+		}
 	}
-	if publishedAt.Valid {
-		createdArticle.PublishedAt = publishedAt.Time
+	for i, enclosure := range article.Enclosures {
+		if _, err := tx.Exec("INSERT INTO article_enclosures (article_id, position, url, mime_type, length, duration) VALUES ($1, $2, $3, $4, $5, $6)",
+			article.ID, i, enclosure.URL, nullString(enclosure.MimeType), sql.NullInt64{Int64: enclosure.Length, Valid: enclosure.Length > 0}, sql.NullInt64{Int64: int64(enclosure.Duration), Valid: enclosure.Duration > 0}); err != nil {
+			return model.Article{}, fmt.Errorf("failed to create article enclosure: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Article{}, fmt.Errorf("failed to commit transaction: %w", err)
 	}
+	createdArticle := article
+	createdArticle.IsRead = false
+	createdArticle.IsLater = false
+	createdArticle.PlaybackPosition = 0
+	createdArticle.Played = false
+	createdArticle.Downloaded = false
 	return createdArticle, nil
 }
 
-func (r *articleRepository) Update(article model.Article) (model.Article, error) {
-	query := `UPDATE articles SET title = $1, content = $2, url = $3, published_at = $4, is_read = $5, is_later = $6 WHERE id = $7 RETURNING id, feed_id, title, content, url, published_at, is_read, is_later, created_at`
-	var updatedArticle model.Article
-	var content sql.NullString
-	var publishedAt sql.NullTime
-	err := r.db.QueryRow(query, article.Title, sql.NullString{String: article.Content, Valid: article.Content != ""}, article.URL, sql.NullTime{Time: article.PublishedAt, Valid: !article.PublishedAt.IsZero()}, article.IsRead, article.IsLater, article.ID).Scan(&updatedArticle.ID, &updatedArticle.FeedID, &updatedArticle.Title, &content, &updatedArticle.URL, &publishedAt, &updatedArticle.IsRead, &updatedArticle.IsLater, &updatedArticle.CreatedAt)
+// UpdateState はユーザーごとの記事の状態（既読・後で見る・再生状態）を保存します。
+// ユーザーが参照できない記事の場合は ErrNotFound を返します。
+func (r *articleRepository) UpdateState(userID string, article model.Article) (model.Article, error) {
+	query := `INSERT INTO article_states (user_id, article_id, is_read, is_later, playback_position_seconds, played, downloaded, updated_at)
+		SELECT $1, a.id, $3, $4, $5, $6, $7, CURRENT_TIMESTAMP FROM articles a WHERE a.id = $2 AND` + visibleToUser + `
+		ON CONFLICT (user_id, article_id) DO UPDATE SET is_read = EXCLUDED.is_read, is_later = EXCLUDED.is_later, playback_position_seconds = EXCLUDED.playback_position_seconds, played = EXCLUDED.played, downloaded = EXCLUDED.downloaded, updated_at = EXCLUDED.updated_at`
+	result, err := r.db.Exec(query, userID, article.ID, article.IsRead, article.IsLater, article.PlaybackPosition, article.Played, article.Downloaded)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return model.Article{}, ErrNotFound
-		}
-		return model.Article{}, fmt.Errorf("failed to update article: %w", err)
+		return model.Article{}, fmt.Errorf("failed to update article state: %w", err)
 	}
-	if content.Valid {
-		updatedArticle.Content = content.String
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return model.Article{}, fmt.Errorf("failed to get rows affected: %w", err)
 	}
-	if publishedAt.Valid {
-		updatedArticle.PublishedAt = publishedAt.Time
+	if rowsAffected == 0 {
+		return model.Article{}, ErrNotFound
 	}
-	return updatedArticle, nil
+	return r.GetByID(userID, article.ID)
+}
+
+// UpdateStates は複数の記事の既読・後で見る状態をまとめて更新します。
+// nil の状態は現在の値を維持し、ユーザーが参照できない記事は無視します。
+func (r *articleRepository) UpdateStates(userID string, ids []string, isRead, isLater *bool) error {
+	if len(ids) == 0 || (isRead == nil && isLater == nil) {
+		return nil
+	}
+	query := `INSERT INTO article_states (user_id, article_id, is_read, is_later, updated_at)
+		SELECT $1, a.id, COALESCE($3, FALSE), COALESCE($4, FALSE), CURRENT_TIMESTAMP FROM articles a WHERE a.id = ANY($2) AND` + visibleToUser + `
+		ON CONFLICT (user_id, article_id) DO UPDATE SET is_read = COALESCE($3, article_states.is_read), is_later = COALESCE($4, article_states.is_later), updated_at = EXCLUDED.updated_at`
+	if _, err := r.db.Exec(query, userID, pq.Array(ids), isRead, isLater); err != nil {
+		return fmt.Errorf("failed to update article states: %w", err)
+	}
+	return nil
+}
+
+// MarkRead は絞り込み条件に一致する未読記事をまとめて既読にし、更新した件数を返します。
+// 並び順と件数（OldestFirst・Limit）は無視します。
+func (r *articleRepository) MarkRead(userID string, filter model.ArticleFilter) (int64, error) {
+	conditions, args := filterConditions(filter, []any{userID})
+	query := `INSERT INTO article_states (user_id, article_id, is_read, updated_at)
+		SELECT $1, a.id, TRUE, CURRENT_TIMESTAMP FROM articles a LEFT JOIN article_states st ON st.article_id = a.id AND st.user_id = $1
+		WHERE COALESCE(st.is_read, FALSE) = FALSE AND` + visibleToUser + conditions + `
+		ON CONFLICT (user_id, article_id) DO UPDATE SET is_read = TRUE, updated_at = EXCLUDED.updated_at`
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark articles as read: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected, nil
+}
+
+// Hide は記事をユーザーの一覧から除外します。除外した記事はそのユーザーからは参照できなくなります。
+// ユーザーが参照できない記事は無視します。
+func (r *articleRepository) Hide(userID string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	query := `INSERT INTO article_states (user_id, article_id, is_hidden, updated_at)
+		SELECT $1, a.id, TRUE, CURRENT_TIMESTAMP FROM articles a WHERE a.id = ANY($2) AND` + visibleToUser + `
+		ON CONFLICT (user_id, article_id) DO UPDATE SET is_hidden = TRUE, updated_at = EXCLUDED.updated_at`
+	if _, err := r.db.Exec(query, userID, pq.Array(ids)); err != nil {
+		return fmt.Errorf("failed to hide articles: %w", err)
+	}
+	return nil
 }
 
 func (r *articleRepository) Delete(id string) error {
@@ -133,26 +530,106 @@ func (r *articleRepository) Delete(id string) error {
 	return nil
 }
 
-func (r *articleRepository) GetLaterArticles() ([]model.Article, error) {
-	rows, err := r.db.Query("SELECT id, feed_id, title, content, url, published_at, is_read, is_later, created_at FROM articles WHERE is_later = TRUE")
+// AddSource は記事とフィードの関連を登録し、新たに登録した場合に true を返します。
+// 既に関連が存在する場合は何もしません。GUIDが空の場合はNULLとして保存します。
+func (r *articleRepository) AddSource(articleID, feedID, guid string) (bool, error) {
+	query := `INSERT INTO article_sources (article_id, feed_id, guid) VALUES ($1, $2, $3) ON CONFLICT (article_id, feed_id) DO NOTHING`
+	result, err := r.db.Exec(query, articleID, feedID, sql.NullString{String: guid, Valid: guid != ""})
+	if err != nil {
+		return false, fmt.Errorf("failed to add article source: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// GetLaterArticles はユーザーが「後で見る」に設定した記事を取得します。
+func (r *articleRepository) GetLaterArticles(userID string) ([]model.Article, error) {
+	articles, err := r.queryArticles(selectArticles+" WHERE st.is_later = TRUE AND"+visibleToUser, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get later articles: %w", err)
 	}
+	return articles, nil
+}
+
+// GetOutdatedSanitized は適用済みのサニタイズルールが version より古い記事を最大 limit 件取得します。
+// 返される記事は再処理に必要な ID・URL・RawContent・RawSummary のみを持ちます。
+// 元HTMLが保存されていない既存記事は、現在の本文・要約を元HTMLとして扱います。
+func (r *articleRepository) GetOutdatedSanitized(version, limit int) ([]model.Article, error) {
+	rows, err := r.db.Query("SELECT id, url, COALESCE(content_raw, content, ''), COALESCE(summary_raw, summary, ''), sanitizer_version FROM articles WHERE sanitizer_version < $1 LIMIT $2", version, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outdated articles: %w", err)
+	}
 	defer rows.Close()
 
 	var articles []model.Article
 	for rows.Next() {
 		var article model.Article
-		var content sql.NullString
-		var publishedAt sql.NullTime
-		if err := rows.Scan(&article.ID, &article.FeedID, &article.Title, &content, &article.URL, &publishedAt, &article.IsRead, &article.IsLater, &article.CreatedAt); err != nil {
+		if err := rows.Scan(&article.ID, &article.URL, &article.RawContent, &article.RawSummary, &article.SanitizerVersion); err != nil {
 			return nil, fmt.Errorf("failed to scan article row: %w", err)
 		}
-		if content.Valid {
-			article.Content = content.String
-		}
-		if publishedAt.Valid {
-			article.PublishedAt = publishedAt.Time
+		articles = append(articles, article)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return articles, nil
+}
+
+// UpdateSanitizedContent は再サニタイズした本文・要約と適用したルールのバージョンを保存します。
+// 元HTMLが未保存の場合は、上書き前の本文・要約を元HTMLとして保存します。
+func (r *articleRepository) UpdateSanitizedContent(article model.Article) error {
+	query := `UPDATE articles SET content_raw = COALESCE(content_raw, content), summary_raw = COALESCE(summary_raw, summary), content = $1, summary = $2, sanitizer_version = $3 WHERE id = $4`
+	result, err := r.db.Exec(query, nullString(article.Content), nullString(article.Summary), article.SanitizerVersion, article.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update sanitized content: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// UpdateExtractedContent は記事ページから抽出した本文と抽出日時を保存します。
+// 要約が空の場合に備え、要約も合わせて更新します。
+func (r *articleRepository) UpdateExtractedContent(article model.Article) error {
+	query := `UPDATE articles SET content = $1, content_raw = $2, summary = $3, summary_raw = $4, sanitizer_version = $5, extracted_at = $6 WHERE id = $7`
+	result, err := r.db.Exec(query, nullString(article.Content), nullString(article.RawContent), nullString(article.Summary), nullString(article.RawSummary), article.SanitizerVersion, article.ExtractedAt, article.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update extracted content: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetUnnormalized は正規化URLが未設定の記事を最大 limit 件取得します。
+// 返される記事は ID と URL のみを持ちます。
+func (r *articleRepository) GetUnnormalized(limit int) ([]model.Article, error) {
+	rows, err := r.db.Query("SELECT id, url FROM articles WHERE normalized_url IS NULL LIMIT $1", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unnormalized articles: %w", err)
+	}
+	defer rows.Close()
+
+	var articles []model.Article
+	for rows.Next() {
+		var article model.Article
+		if err := rows.Scan(&article.ID, &article.URL); err != nil {
+			return nil, fmt.Errorf("failed to scan article row: %w", err)
 		}
 		articles = append(articles, article)
 	}
@@ -163,3 +640,87 @@ func (r *articleRepository) GetLaterArticles() ([]model.Article, error) {
 
 	return articles, nil
 }
+
+// SetNormalizedURL は記事の正規化URLを設定します。
+//
+// 正規化URLが同じ記事が既にある場合は、記事をその記事へ統合して削除します。
+// 認証情報を設定したフィードの記事（IsPrivate）は作成時に正規化URLを設定するため、統合の対象にも統合先にもなりません。
+// 統合では取得元フィード・ユーザーごとの状態・タグ・注釈を移し、
+// 統合先に同じユーザーの状態がある場合は統合先の状態を残します。
+func (r *articleRepository) SetNormalizedURL(id, normalizedURL string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var existingID string
+	err = tx.QueryRow("SELECT id FROM articles WHERE normalized_url = $1 AND id <> $2 AND NOT is_private FOR UPDATE", normalizedURL, id).Scan(&existingID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		if _, err := tx.Exec("UPDATE articles SET normalized_url = $1 WHERE id = $2", normalizedURL, id); err != nil {
+			return fmt.Errorf("failed to set normalized URL: %w", err)
+		}
+	case err != nil:
+		return fmt.Errorf("failed to get article by URL: %w", err)
+	default:
+		if err := mergeArticle(tx, id, existingID); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// mergeArticle は記事 id の取得元フィード・状態・タグ・注釈を記事 intoID へ移し、記事 id を削除します。
+func mergeArticle(tx *sql.Tx, id, intoID string) error {
+	statements := []string{
+		`INSERT INTO article_sources (article_id, feed_id, guid, created_at)
+			SELECT $2, feed_id, guid, created_at FROM article_sources WHERE article_id = $1 ON CONFLICT DO NOTHING`,
+		`INSERT INTO article_states (user_id, article_id, is_read, is_later, playback_position_seconds, played, downloaded, is_hidden, updated_at)
+			SELECT user_id, $2, is_read, is_later, playback_position_seconds, played, downloaded, is_hidden, updated_at FROM article_states WHERE article_id = $1
+			ON CONFLICT DO NOTHING`,
+		`INSERT INTO article_tags (tag_id, article_id, created_at)
+			SELECT tag_id, $2, created_at FROM article_tags WHERE article_id = $1 ON CONFLICT DO NOTHING`,
+		`UPDATE annotations SET article_id = $2 WHERE article_id = $1`,
+		`DELETE FROM articles WHERE id = $1`,
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement, id, intoID); err != nil {
+			return fmt.Errorf("failed to merge article: %w", err)
+		}
+	}
+	return nil
+}
+
+// nullInt は nil をNULLとして扱う sql.NullInt64 を返します。
+func nullInt(n *int) sql.NullInt64 {
+	if n == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(*n), Valid: true}
+}
+
+// nullFloat は nil をNULLとして扱う sql.NullFloat64 を返します。
+func nullFloat(f *float64) sql.NullFloat64 {
+	if f == nil {
+		return sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: *f, Valid: true}
+}
+
+// nullBool は nil をNULLとして扱う sql.NullBool を返します。
+func nullBool(b *bool) sql.NullBool {
+	if b == nil {
+		return sql.NullBool{}
+	}
+	return sql.NullBool{Bool: *b, Valid: true}
+}
+
+// nullString は空文字列をNULLとして扱う sql.NullString を返します。
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"feedapp/internal/model"
+)
+
+func TestArticleRepository_GetByFolderID(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewArticleRepository(db)
+
+	user := createTestUser(t, db)
+	folder, err := NewFolderRepository(db).Create(model.Folder{ID: model.GenerateUUID(), Name: "Folder " + model.GenerateUUID(), UserID: user.ID, CreatedAt: time.Now()})
+	require.NoError(t, err)
+	feed := createTestFeed(t, db, user.ID, folder.ID)
+	visible := createTestArticle(t, repo, feed.ID)
+	hidden := createTestArticle(t, repo, feed.ID)
+	// ルールの skip で一覧から除外した記事はフォルダの記事にも含めない
+	require.NoError(t, repo.Hide(user.ID, []string{hidden.ID}))
+
+	articles, err := repo.GetByFolderID(user.ID, folder.ID)
+	require.NoError(t, err)
+	require.Len(t, articles, 1)
+	assert.Equal(t, visible.ID, articles[0].ID)
+
+	// 他のユーザーのフォルダは参照できない
+	other := createTestUser(t, db)
+	articles, err = repo.GetByFolderID(other.ID, folder.ID)
+	require.NoError(t, err)
+	assert.Empty(t, articles)
+}
+
+func TestArticleRepository_GUID(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewArticleRepository(db)
+
+	userA := createTestUser(t, db)
+	userB := createTestUser(t, db)
+	feedA := createTestFeed(t, db, userA.ID, "")
+	feedB := createTestFeed(t, db, userB.ID, "")
+	article, err := repo.Create(model.Article{ID: model.GenerateUUID(), FeedID: feedA.ID, Title: "Article", URL: "https://example.com/articles/" + model.GenerateUUID(), CreatedAt: time.Now()})
+	require.NoError(t, err)
+	_, err = repo.AddSource(article.ID, feedA.ID, "guid-a")
+	require.NoError(t, err)
+	_, err = repo.AddSource(article.ID, feedB.ID, "guid-b")
+	require.NoError(t, err)
+
+	// エントリー識別子はユーザーが購読しているフィードでの値を返す
+	got, err := repo.GetByID(userA.ID, article.ID)
+	require.NoError(t, err)
+	assert.Equal(t, feedA.ID, got.FeedID)
+	assert.Equal(t, "guid-a", got.GUID)
+	got, err = repo.GetByID(userB.ID, article.ID)
+	require.NoError(t, err)
+	assert.Equal(t, feedB.ID, got.FeedID)
+	assert.Equal(t, "guid-b", got.GUID)
+
+	got, err = repo.GetByFeedGUID(feedB.ID, "guid-b")
+	require.NoError(t, err)
+	assert.Equal(t, article.ID, got.ID)
+}
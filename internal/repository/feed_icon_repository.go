@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"feedapp/internal/model"
+)
+
+// FeedIconRepository はフィードのアイコンのデータ永続化を定義するインターフェースです。
+//
+// アイコンはフィードごとに1件で、すべての購読者に共有されます。
+// アイコンが見つからなかったフィードも確認日時のみを記録し、GetCheckedAt で次回の確認時期を判定します。
+type FeedIconRepository interface {
+	GetByFeedID(feedID string) (model.FeedIcon, error)
+	GetByUserID(userID string) ([]model.FeedIcon, error)
+	GetCheckedAt(feedID string) (time.Time, error)
+	Save(icon model.FeedIcon, checkedAt time.Time) error
+	MarkChecked(feedID string, checkedAt time.Time) error
+}
+
+// feedIconRepository は FeedIconRepository インターフェースの実装です。
+type feedIconRepository struct {
+	db *sql.DB
+}
+
+// NewFeedIconRepository は新しい feedIconRepository インスタンスを作成します。
+func NewFeedIconRepository(db *sql.DB) FeedIconRepository {
+	return &feedIconRepository{db: db}
+}
+
+// selectFeedIcons はアイコンを取得するクエリの SELECT 句と FROM 句です。
+const selectFeedIcons = `SELECT icon.feed_id, icon.url, icon.mime_type, icon.hash, icon.data, icon.fetched_at FROM feed_icons icon`
+
+// scanFeedIcon は selectFeedIcons の順に並んだ行を model.FeedIcon に変換します。
+func scanFeedIcon(row rowScanner) (model.FeedIcon, error) {
+	var icon model.FeedIcon
+	if err := row.Scan(&icon.FeedID, &icon.URL, &icon.MimeType, &icon.Hash, &icon.Data, &icon.FetchedAt); err != nil {
+		return model.FeedIcon{}, err
+	}
+	return icon, nil
+}
+
+// GetByFeedID はフィードのアイコンを取得します。アイコンが見つかっていない場合は ErrNotFound を返します。
+func (r *feedIconRepository) GetByFeedID(feedID string) (model.FeedIcon, error) {
+	icon, err := scanFeedIcon(r.db.QueryRow(selectFeedIcons+" WHERE icon.feed_id = $1 AND icon.data IS NOT NULL", feedID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.FeedIcon{}, ErrNotFound
+		}
+		return model.FeedIcon{}, fmt.Errorf("failed to get feed icon: %w", err)
+	}
+	return icon, nil
+}
+
+// GetByUserID はユーザーが購読しているフィードのアイコンを取得します。
+func (r *feedIconRepository) GetByUserID(userID string) ([]model.FeedIcon, error) {
+	query := selectFeedIcons + ` JOIN subscriptions sub ON sub.feed_id = icon.feed_id
+		WHERE sub.user_id = $1 AND icon.data IS NOT NULL ORDER BY icon.feed_id`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed icons: %w", err)
+	}
+	defer rows.Close()
+
+	icons := []model.FeedIcon{}
+	for rows.Next() {
+		icon, err := scanFeedIcon(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan feed icon: %w", err)
+		}
+		icons = append(icons, icon)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate feed icons: %w", err)
+	}
+	return icons, nil
+}
+
+// GetCheckedAt はフィードのアイコンを最後に確認した日時を取得します。未確認の場合は ErrNotFound を返します。
+func (r *feedIconRepository) GetCheckedAt(feedID string) (time.Time, error) {
+	var checkedAt time.Time
+	if err := r.db.QueryRow("SELECT checked_at FROM feed_icons WHERE feed_id = $1", feedID).Scan(&checkedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, ErrNotFound
+		}
+		return time.Time{}, fmt.Errorf("failed to get feed icon check time: %w", err)
+	}
+	return checkedAt, nil
+}
+
+// Save はフィードのアイコンを保存し、確認日時を更新します。
+func (r *feedIconRepository) Save(icon model.FeedIcon, checkedAt time.Time) error {
+	query := `INSERT INTO feed_icons (feed_id, data, mime_type, hash, url, fetched_at, checked_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (feed_id) DO UPDATE SET data = EXCLUDED.data, mime_type = EXCLUDED.mime_type, hash = EXCLUDED.hash,
+			url = EXCLUDED.url, fetched_at = EXCLUDED.fetched_at, checked_at = EXCLUDED.checked_at`
+	if _, err := r.db.Exec(query, icon.FeedID, icon.Data, icon.MimeType, icon.Hash, icon.URL, icon.FetchedAt, checkedAt); err != nil {
+		return fmt.Errorf("failed to save feed icon: %w", err)
+	}
+	return nil
+}
+
+// MarkChecked はアイコンが見つからなかったフィードの確認日時を更新します。保存済みのアイコンは維持します。
+func (r *feedIconRepository) MarkChecked(feedID string, checkedAt time.Time) error {
+	query := `INSERT INTO feed_icons (feed_id, checked_at) VALUES ($1, $2)
+		ON CONFLICT (feed_id) DO UPDATE SET checked_at = EXCLUDED.checked_at`
+	if _, err := r.db.Exec(query, feedID, checkedAt); err != nil {
+		return fmt.Errorf("failed to update feed icon check time: %w", err)
+	}
+	return nil
+}
@@ -2,53 +2,178 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
 
 	"feedapp/internal/model"
+	"feedapp/internal/secretbox"
 )
 
 // FeedRepository はフィードのデータ永続化を定義するインターフェースです。
+//
+// フィード（feeds テーブル）は全ユーザーで共有する取得元で、ユーザーは
+// subscriptions テーブルを通じてフィードを購読します。ユーザーIDを受け取る
+// メソッドは購読を単位とした操作で、表示名とフォルダは購読ごとの値となります。
+// 購読していないフィードは ErrNotFound となります。
+// GetAllSources / GetSourceByID / UpdateLastUpdated / UpdateParseWarning / GetSubscriberIDs は定期取得で使用する取得元の操作です。
+// フィードの認証情報とプロキシは暗号化して保存し、取得時に復号します。暗号化キーが設定されていない場合、
+// 認証情報やプロキシを保存しようとすると secretbox.ErrNoKey となります。
 type FeedRepository interface {
-	GetAll() ([]model.Feed, error)
-	GetByID(id string) (model.Feed, error)
-	Create(feed model.Feed) (model.Feed, error)
-	Update(feed model.Feed) (model.Feed, error)
-	Delete(id string) error
+	GetAll(userID string) ([]model.Feed, error)
+	GetByID(userID, id string) (model.Feed, error)
+	Create(userID string, feed model.Feed) (model.Feed, error)
+	Update(userID string, feed model.Feed) (model.Feed, error)
+	Delete(userID, id string) error
+	GetAllSources() ([]model.Feed, error)
+	GetSourceByID(id string) (model.Feed, error)
+	UpdateLastUpdated(id string, lastUpdated time.Time) error
+	UpdateParseWarning(id, warning string) error
+	GetSubscriberIDs(id string) ([]string, error)
 }
 
 // feedRepository は FeedRepository インターフェースの実装です。
 type feedRepository struct {
-	db *sql.DB
+	db  *sql.DB
+	box *secretbox.Box
 }
 
 // NewFeedRepository は新しい feedRepository インスタンスを作成します。
-func NewFeedRepository(db *sql.DB) FeedRepository {
-	return &feedRepository{db: db}
+// box はフィードの認証情報の暗号化に使用し、nil の場合は認証情報を保存できません。
+func NewFeedRepository(db *sql.DB, box *secretbox.Box) FeedRepository {
+	return &feedRepository{db: db, box: box}
 }
 
-func (r *feedRepository) GetAll() ([]model.Feed, error) {
-	rows, err := r.db.Query("SELECT id, name, url, plugin_type, folder_id, update_interval, last_updated, created_at FROM feeds")
+// feedColumns は取得元としてのフィードの取得時に使用するカラム一覧です。
+const feedColumns = "id, name, url, plugin_type, folder_id, update_interval, fetch_full_content, retention_days, max_articles, rate_limit, max_connections, respect_robots, auth, proxy, parse_warning, last_updated, created_at, int_id"
+
+// subscriptionColumns は購読としてのフィードの取得時に使用するカラム一覧です。
+// 名前・フォルダ・作成日時は購読の値を使用します。
+const subscriptionColumns = "f.id, sub.name, f.url, f.plugin_type, sub.folder_id, f.update_interval, f.fetch_full_content, f.retention_days, f.max_articles, f.rate_limit, f.max_connections, f.respect_robots, f.auth, f.proxy, f.parse_warning, f.last_updated, sub.created_at, f.int_id"
+
+// subscriptionFrom は subscriptionColumns と組み合わせて使用する FROM 句です。
+const subscriptionFrom = " FROM subscriptions sub JOIN feeds f ON f.id = sub.feed_id"
+
+// scanFeed は feedColumns または subscriptionColumns の順に並んだ行を model.Feed に変換します。
+// 認証情報とプロキシは復号して設定します。
+func (r *feedRepository) scanFeed(row rowScanner) (model.Feed, error) {
+	var feed model.Feed
+	var folderID, parseWarning sql.NullString
+	var retentionDays, maxArticles, maxConnections sql.NullInt64
+	var rateLimit sql.NullFloat64
+	var respectRobots sql.NullBool
+	var auth, proxy []byte
+	var lastUpdated sql.NullTime
+	if err := row.Scan(&feed.ID, &feed.Name, &feed.URL, &feed.PluginType, &folderID, &feed.UpdateInterval, &feed.FetchFullContent,
+		&retentionDays, &maxArticles, &rateLimit, &maxConnections, &respectRobots, &auth, &proxy, &parseWarning, &lastUpdated, &feed.CreatedAt, &feed.IntID); err != nil {
+		return model.Feed{}, err
+	}
+	if folderID.Valid {
+		feed.FolderID = folderID.String
+	}
+	if retentionDays.Valid {
+		days := int(retentionDays.Int64)
+		feed.RetentionDays = &days
+	}
+	if maxArticles.Valid {
+		max := int(maxArticles.Int64)
+		feed.MaxArticles = &max
+	}
+	if rateLimit.Valid {
+		feed.RateLimit = &rateLimit.Float64
+	}
+	if maxConnections.Valid {
+		connections := int(maxConnections.Int64)
+		feed.MaxConnections = &connections
+	}
+	if respectRobots.Valid {
+		feed.RespectRobots = &respectRobots.Bool
+	}
+	if auth != nil {
+		opened, err := r.openAuth(feed.ID, auth)
+		if err != nil {
+			return model.Feed{}, err
+		}
+		feed.Auth = opened
+	}
+	if proxy != nil {
+		data, err := r.box.Open(proxy, proxyAdditionalData(feed.ID))
+		if err != nil {
+			return model.Feed{}, fmt.Errorf("failed to decrypt proxy of feed %s: %w", feed.ID, err)
+		}
+		feed.Proxy = string(data)
+	}
+	if parseWarning.Valid {
+		feed.ParseWarning = parseWarning.String
+	}
+	if lastUpdated.Valid {
+		feed.LastUpdated = lastUpdated.Time
+	}
+	return feed, nil
+}
+
+// sealAuth は認証情報をフィードIDを関連データとして暗号化します。認証情報がない場合は NULL とします。
+func (r *feedRepository) sealAuth(feedID string, auth *model.FeedAuth) (sql.Null[[]byte], error) {
+	if auth.IsZero() {
+		return sql.Null[[]byte]{}, nil
+	}
+	data, err := json.Marshal(auth)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get all feeds: %w", err)
+		return sql.Null[[]byte]{}, fmt.Errorf("failed to marshal feed auth: %w", err)
+	}
+	sealed, err := r.box.Seal(data, []byte(feedID))
+	if err != nil {
+		return sql.Null[[]byte]{}, fmt.Errorf("failed to encrypt feed auth: %w", err)
+	}
+	return sql.Null[[]byte]{V: sealed, Valid: true}, nil
+}
+
+// openAuth は暗号化した認証情報を復号します。
+func (r *feedRepository) openAuth(feedID string, sealed []byte) (*model.FeedAuth, error) {
+	data, err := r.box.Open(sealed, []byte(feedID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt auth of feed %s: %w", feedID, err)
+	}
+	var auth model.FeedAuth
+	if err := json.Unmarshal(data, &auth); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal auth of feed %s: %w", feedID, err)
+	}
+	return &auth, nil
+}
+
+// sealProxy はプロキシのURLを暗号化します。プロキシが未設定の場合は NULL とします。
+func (r *feedRepository) sealProxy(feedID, proxy string) (sql.Null[[]byte], error) {
+	if proxy == "" {
+		return sql.Null[[]byte]{}, nil
+	}
+	sealed, err := r.box.Seal([]byte(proxy), proxyAdditionalData(feedID))
+	if err != nil {
+		return sql.Null[[]byte]{}, fmt.Errorf("failed to encrypt feed proxy: %w", err)
+	}
+	return sql.Null[[]byte]{V: sealed, Valid: true}, nil
+}
+
+// proxyAdditionalData はプロキシの暗号化に使用する関連データです。
+// 認証情報の暗号文と入れ替えられないよう、フィードIDに列名を付けます。
+func proxyAdditionalData(feedID string) []byte {
+	return []byte(feedID + "/proxy")
+}
+
+// queryFeeds は複数のフィードを取得するクエリを実行します。
+func (r *feedRepository) queryFeeds(query string, args ...any) ([]model.Feed, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
 	}
 	defer rows.Close()
 
 	var feeds []model.Feed
 	for rows.Next() {
-		var feed model.Feed
-		var folderID sql.NullString
-		var lastUpdated sql.NullTime
-		if err := rows.Scan(&feed.ID, &feed.Name, &feed.URL, &feed.PluginType, &folderID, &feed.UpdateInterval, &lastUpdated, &feed.CreatedAt); err != nil {
+		feed, err := r.scanFeed(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan feed row: %w", err)
 		}
-		if folderID.Valid {
-			feed.FolderID = folderID.String
-		}
-		if lastUpdated.Valid {
-			feed.LastUpdated = lastUpdated.Time
-		}
 		feeds = append(feeds, feed)
 	}
 
@@ -59,66 +184,200 @@ func (r *feedRepository) GetAll() ([]model.Feed, error) {
 	return feeds, nil
 }
 
-func (r *feedRepository) GetByID(id string) (model.Feed, error) {
-	var feed model.Feed
-	var folderID sql.NullString
-	var lastUpdated sql.NullTime
-	err := r.db.QueryRow("SELECT id, name, url, plugin_type, folder_id, update_interval, last_updated, created_at FROM feeds WHERE id = $1", id).Scan(&feed.ID, &feed.Name, &feed.URL, &feed.PluginType, &folderID, &feed.UpdateInterval, &lastUpdated, &feed.CreatedAt)
+// GetAll はユーザーが購読しているすべてのフィードを取得します。
+func (r *feedRepository) GetAll(userID string) ([]model.Feed, error) {
+	feeds, err := r.queryFeeds("SELECT "+subscriptionColumns+subscriptionFrom+" WHERE sub.user_id = $1", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all feeds: %w", err)
+	}
+	return feeds, nil
+}
+
+// GetByID はユーザーが購読しているフィードを取得します。
+func (r *feedRepository) GetByID(userID, id string) (model.Feed, error) {
+	feed, err := r.scanFeed(r.db.QueryRow("SELECT "+subscriptionColumns+subscriptionFrom+" WHERE sub.user_id = $1 AND f.id = $2", userID, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return model.Feed{}, ErrNotFound
 		}
 		return model.Feed{}, fmt.Errorf("failed to get feed by ID: %w", err)
 	}
-	if folderID.Valid {
-		feed.FolderID = folderID.String
-	}
-	if lastUpdated.Valid {
-		feed.LastUpdated = lastUpdated.Time
-	}
 	return feed, nil
 }
 
-func (r *feedRepository) Create(feed model.Feed) (model.Feed, error) {
-		query := `INSERT INTO feeds (id, name, url, plugin_type, folder_id, update_interval, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, name, url, plugin_type, folder_id, update_interval, last_updated, created_at`
-	var createdFeed model.Feed
-	var folderID sql.NullString
-	var lastUpdated sql.NullTime
-	err := r.db.QueryRow(query, feed.ID, feed.Name, feed.URL, feed.PluginType, sql.NullString{String: feed.FolderID, Valid: feed.FolderID != ""}, feed.UpdateInterval, feed.CreatedAt).Scan(&createdFeed.ID, &createdFeed.Name, &createdFeed.URL, &createdFeed.PluginType, &folderID, &createdFeed.UpdateInterval, &lastUpdated, &createdFeed.CreatedAt)
+// Create はフィードを購読します。
+//
+// 同じURLのフィードが既に登録されている場合はそのフィードを共有し、新しい取得元は作成しません。
+// その場合、本文抽出はいずれかの購読者が有効にしていれば有効となり、更新間隔は短い方を採用します。
+// 保持設定・取得の制限は既存のフィードの値を維持します。
+// 認証情報やプロキシを設定したフィードは共有せず、常に新しい取得元を作成します。
+// 既に購読済みの場合は ErrAlreadyExists を返します。
+func (r *feedRepository) Create(userID string, feed model.Feed) (model.Feed, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Feed{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	auth, err := r.sealAuth(feed.ID, feed.Auth)
 	if err != nil {
+		return model.Feed{}, err
+	}
+	proxy, err := r.sealProxy(feed.ID, feed.Proxy)
+	if err != nil {
+		return model.Feed{}, err
+	}
+	var feedID string
+	query := `INSERT INTO feeds (id, name, url, plugin_type, update_interval, fetch_full_content, retention_days, max_articles, rate_limit, max_connections, respect_robots, auth, proxy, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		ON CONFLICT (url) WHERE auth IS NULL AND proxy IS NULL DO UPDATE SET fetch_full_content = feeds.fetch_full_content OR EXCLUDED.fetch_full_content, update_interval = LEAST(feeds.update_interval, NULLIF(EXCLUDED.update_interval, 0))
+		RETURNING id`
+	if err := tx.QueryRow(query, feed.ID, feed.Name, feed.URL, feed.PluginType, feed.UpdateInterval, feed.FetchFullContent,
+		nullInt(feed.RetentionDays), nullInt(feed.MaxArticles), nullFloat(feed.RateLimit), nullInt(feed.MaxConnections), nullBool(feed.RespectRobots), auth, proxy, feed.CreatedAt).Scan(&feedID); err != nil {
 		return model.Feed{}, fmt.Errorf("failed to create feed: %w", err)
 	}
-	if folderID.Valid {
-		createdFeed.FolderID = folderID.String
+
+	_, err = tx.Exec("INSERT INTO subscriptions (id, user_id, feed_id, name, folder_id, created_at) VALUES ($1, $2, $3, $4, $5, $6)",
+		model.GenerateUUID(), userID, feedID, feed.Name, nullString(feed.FolderID), feed.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return model.Feed{}, ErrAlreadyExists
+		}
+		return model.Feed{}, fmt.Errorf("failed to create subscription: %w", err)
 	}
-	if lastUpdated.Valid {
-		createdFeed.LastUpdated = lastUpdated.Time
+
+	createdFeed, err := r.scanFeed(tx.QueryRow("SELECT "+subscriptionColumns+subscriptionFrom+" WHERE sub.user_id = $1 AND f.id = $2", userID, feedID))
+	if err != nil {
+		return model.Feed{}, fmt.Errorf("failed to get created feed: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return model.Feed{}, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 	return createdFeed, nil
 }
 
-func (r *feedRepository) Update(feed model.Feed) (model.Feed, error) {
-	query := `UPDATE feeds SET name = $1, url = $2, plugin_type = $3, folder_id = $4, update_interval = $5, last_updated = $6 WHERE id = $7 RETURNING id, name, url, plugin_type, folder_id, update_interval, last_updated, created_at`
-	var updatedFeed model.Feed
-	var folderID string
-	var lastUpdated time.Time
-	err := r.db.QueryRow(query, updatedFeed.Name, updatedFeed.URL, updatedFeed.PluginType, updatedFeed.FolderID, updatedFeed.UpdateInterval, updatedFeed.LastUpdated, updatedFeed.ID).Scan(&updatedFeed.ID, &updatedFeed.Name, &updatedFeed.URL, &updatedFeed.PluginType, &folderID, &updatedFeed.UpdateInterval, &lastUpdated, &updatedFeed.CreatedAt)
+// Update は購読の名前・フォルダと、取得元の更新間隔・本文抽出設定・保持設定・取得の制限・認証情報・プロキシを更新します。
+// 購読の名前・フォルダ以外は同じフィードを購読しているすべてのユーザーに共有されます。
+// feed の値で共有の設定をすべて置き換えるため、省略された項目は呼び出し側で保存済みの値を設定する必要があります。
+// 認証情報やプロキシを削除したフィードと同じURLの共有のフィードが既にある場合は ErrAlreadyExists を返します。
+func (r *feedRepository) Update(userID string, feed model.Feed) (model.Feed, error) {
+	auth, err := r.sealAuth(feed.ID, feed.Auth)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return model.Feed{}, ErrNotFound
+		return model.Feed{}, err
+	}
+	proxy, err := r.sealProxy(feed.ID, feed.Proxy)
+	if err != nil {
+		return model.Feed{}, err
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Feed{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE subscriptions SET name = $1, folder_id = $2 WHERE user_id = $3 AND feed_id = $4", feed.Name, nullString(feed.FolderID), userID, feed.ID)
+	if err != nil {
+		return model.Feed{}, fmt.Errorf("failed to update subscription: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return model.Feed{}, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return model.Feed{}, ErrNotFound
+	}
+
+	if _, err := tx.Exec("UPDATE feeds SET update_interval = $1, fetch_full_content = $2, retention_days = $3, max_articles = $4, rate_limit = $5, max_connections = $6, respect_robots = $7, auth = $8, proxy = $9 WHERE id = $10",
+		feed.UpdateInterval, feed.FetchFullContent, nullInt(feed.RetentionDays), nullInt(feed.MaxArticles),
+		nullFloat(feed.RateLimit), nullInt(feed.MaxConnections), nullBool(feed.RespectRobots), auth, proxy, feed.ID); err != nil {
+		if isUniqueViolation(err) {
+			return model.Feed{}, ErrAlreadyExists
 		}
 		return model.Feed{}, fmt.Errorf("failed to update feed: %w", err)
 	}
-	updatedFeed.FolderID = folderID
-	updatedFeed.LastUpdated = lastUpdated
+
+	updatedFeed, err := r.scanFeed(tx.QueryRow("SELECT "+subscriptionColumns+subscriptionFrom+" WHERE sub.user_id = $1 AND f.id = $2", userID, feed.ID))
+	if err != nil {
+		return model.Feed{}, fmt.Errorf("failed to get updated feed: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return model.Feed{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return updatedFeed, nil
 }
 
-func (r *feedRepository) Delete(id string) error {
-	result, err := r.db.Exec("DELETE FROM feeds WHERE id = $1", id)
+// Delete はフィードの購読を解除します。
+// 購読者がいなくなったフィードは、取得元と記事もあわせて削除します。
+func (r *feedRepository) Delete(userID, id string) error {
+	tx, err := r.db.Begin()
 	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM subscriptions WHERE user_id = $1 AND feed_id = $2", userID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete subscription: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	if _, err := tx.Exec("DELETE FROM feeds WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM subscriptions WHERE feed_id = $1)", id); err != nil {
 		return fmt.Errorf("failed to delete feed: %w", err)
 	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetAllSources は定期取得の対象となるすべてのフィードを取得します。
+func (r *feedRepository) GetAllSources() ([]model.Feed, error) {
+	feeds, err := r.queryFeeds("SELECT " + feedColumns + " FROM feeds")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all feed sources: %w", err)
+	}
+	return feeds, nil
+}
+
+// GetSourceByID は購読者に関係なくフィードを取得します。
+func (r *feedRepository) GetSourceByID(id string) (model.Feed, error) {
+	feed, err := r.scanFeed(r.db.QueryRow("SELECT "+feedColumns+" FROM feeds WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Feed{}, ErrNotFound
+		}
+		return model.Feed{}, fmt.Errorf("failed to get feed by ID: %w", err)
+	}
+	return feed, nil
+}
+
+// UpdateLastUpdated はフィードの最終更新日時を更新します。
+func (r *feedRepository) UpdateLastUpdated(id string, lastUpdated time.Time) error {
+	result, err := r.db.Exec("UPDATE feeds SET last_updated = $1 WHERE id = $2", lastUpdated, id)
+	if err != nil {
+		return fmt.Errorf("failed to update feed last_updated: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// UpdateParseWarning はフィードの解析時の警告を更新します。空文字列の場合は警告を削除します。
+func (r *feedRepository) UpdateParseWarning(id, warning string) error {
+	result, err := r.db.Exec("UPDATE feeds SET parse_warning = $1 WHERE id = $2", nullString(warning), id)
+	if err != nil {
+		return fmt.Errorf("failed to update feed parse_warning: %w", err)
+	}
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("failed to get rows affected: %w", err)
@@ -128,3 +387,27 @@ func (r *feedRepository) Delete(id string) error {
 	}
 	return nil
 }
+
+// GetSubscriberIDs はフィードを購読しているユーザーのIDを取得します。
+func (r *feedRepository) GetSubscriberIDs(id string) ([]string, error) {
+	rows, err := r.db.Query("SELECT user_id FROM subscriptions WHERE feed_id = $1", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed subscribers: %w", err)
+	}
+	defer rows.Close()
+
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan subscriber row: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return userIDs, nil
+}
@@ -10,13 +10,17 @@ import (
 
 var ErrNotFound = errors.New("not found")
 
+// ErrAlreadyExists は一意制約に違反するレコードを作成しようとした場合に返されます。
+var ErrAlreadyExists = errors.New("already exists")
+
 // FolderRepository はフォルダのデータ永続化を定義するインターフェースです。
+// すべての操作は所有者のユーザーIDで絞り込まれ、他のユーザーのフォルダは ErrNotFound となります。
 type FolderRepository interface {
-	GetAll() ([]model.Folder, error)
-	GetByID(id string) (model.Folder, error)
+	GetAll(userID string) ([]model.Folder, error)
+	GetByID(userID, id string) (model.Folder, error)
 	Create(folder model.Folder) (model.Folder, error)
 	Update(folder model.Folder) (model.Folder, error)
-	Delete(id string) error
+	Delete(userID, id string) error
 }
 
 // folderRepository は FolderRepository インターフェースの実装です。
@@ -29,8 +33,19 @@ func NewFolderRepository(db *sql.DB) FolderRepository {
 	return &folderRepository{db: db}
 }
 
-func (r *folderRepository) GetAll() ([]model.Folder, error) {
-	rows, err := r.db.Query("SELECT id, name, user_id, created_at FROM folders")
+// scanFolder は id, name, user_id, created_at, int_id の順に並んだ行を model.Folder に変換します。
+func scanFolder(row rowScanner) (model.Folder, error) {
+	var folder model.Folder
+	var userID sql.NullString // user_idはNULL許容のためsql.NullStringを使用
+	if err := row.Scan(&folder.ID, &folder.Name, &userID, &folder.CreatedAt, &folder.IntID); err != nil {
+		return model.Folder{}, err
+	}
+	folder.UserID = userID.String
+	return folder, nil
+}
+
+func (r *folderRepository) GetAll(userID string) ([]model.Folder, error) {
+	rows, err := r.db.Query("SELECT id, name, user_id, created_at, int_id FROM folders WHERE user_id = $1", userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all folders: %w", err)
 	}
@@ -38,14 +53,10 @@ func (r *folderRepository) GetAll() ([]model.Folder, error) {
 
 	var folders []model.Folder
 	for rows.Next() {
-		var folder model.Folder
-		var userID sql.NullString // user_idはNULL許容のためsql.NullStringを使用
-		if err := rows.Scan(&folder.ID, &folder.Name, &userID, &folder.CreatedAt); err != nil {
+		folder, err := scanFolder(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan folder row: %w", err)
 		}
-		if userID.Valid {
-			folder.UserID = userID.String
-		}
 		folders = append(folders, folder)
 	}
 
@@ -56,53 +67,41 @@ func (r *folderRepository) GetAll() ([]model.Folder, error) {
 	return folders, nil
 }
 
-func (r *folderRepository) GetByID(id string) (model.Folder, error) {
-	var folder model.Folder
-	var userID sql.NullString
-	err := r.db.QueryRow("SELECT id, name, user_id, created_at FROM folders WHERE id = $1", id).Scan(&folder.ID, &folder.Name, &userID, &folder.CreatedAt)
+func (r *folderRepository) GetByID(userID, id string) (model.Folder, error) {
+	folder, err := scanFolder(r.db.QueryRow("SELECT id, name, user_id, created_at, int_id FROM folders WHERE id = $1 AND user_id = $2", id, userID))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return model.Folder{}, ErrNotFound
 		}
 		return model.Folder{}, fmt.Errorf("failed to get folder by ID: %w", err)
 	}
-	if userID.Valid {
-		folder.UserID = userID.String
-	}
 	return folder, nil
 }
 
 func (r *folderRepository) Create(folder model.Folder) (model.Folder, error) {
-		query := `INSERT INTO folders (id, name, user_id, created_at) VALUES ($1, $2, $3, $4) RETURNING id, name, user_id, created_at`
-	var createdFolder model.Folder
-	var userID sql.NullString
-	err := r.db.QueryRow(query, folder.ID, folder.Name, sql.NullString{String: folder.UserID, Valid: folder.UserID != ""}, folder.CreatedAt).Scan(&createdFolder.ID, &createdFolder.Name, &userID, &createdFolder.CreatedAt)
+	query := `INSERT INTO folders (id, name, user_id, created_at) VALUES ($1, $2, $3, $4) RETURNING id, name, user_id, created_at, int_id`
+	createdFolder, err := scanFolder(r.db.QueryRow(query, folder.ID, folder.Name, nullString(folder.UserID), folder.CreatedAt))
 	if err != nil {
 		return model.Folder{}, fmt.Errorf("failed to create folder: %w", err)
 	}
-	if userID.Valid {
-		createdFolder.UserID = userID.String
-	}
 	return createdFolder, nil
 }
 
+// Update はフォルダ名を更新します。folder.UserID が所有者と一致しない場合は ErrNotFound を返します。
 func (r *folderRepository) Update(folder model.Folder) (model.Folder, error) {
-	query := `UPDATE folders SET name = $1 WHERE id = $2 RETURNING id, name, user_id, created_at`
-	var updatedFolder model.Folder
-	var userID string
-	err := r.db.QueryRow(query, folder.Name, folder.ID).Scan(&updatedFolder.ID, &updatedFolder.Name, &userID, &updatedFolder.CreatedAt)
+	query := `UPDATE folders SET name = $1 WHERE id = $2 AND user_id = $3 RETURNING id, name, user_id, created_at, int_id`
+	updatedFolder, err := scanFolder(r.db.QueryRow(query, folder.Name, folder.ID, folder.UserID))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return model.Folder{}, ErrNotFound
 		}
 		return model.Folder{}, fmt.Errorf("failed to update folder: %w", err)
 	}
-	updatedFolder.UserID = userID
 	return updatedFolder, nil
 }
 
-func (r *folderRepository) Delete(id string) error {
-	result, err := r.db.Exec("DELETE FROM folders WHERE id = $1", id)
+func (r *folderRepository) Delete(userID, id string) error {
+	result, err := r.db.Exec("DELETE FROM folders WHERE id = $1 AND user_id = $2", id, userID)
 	if err != nil {
 		return fmt.Errorf("failed to delete folder: %w", err)
 	}
@@ -115,5 +114,3 @@ func (r *folderRepository) Delete(id string) error {
 	}
 	return nil
 }
-
-
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// migrationLockID はマイグレーションの実行中に取得する advisory lock のキーです。
+// 複数のサーバーが同時に起動しても、マイグレーションを重複して実行しないようにします。
+const migrationLockID = 20250706
+
+// untrackedMigrationsThrough は schema_migrations の導入以前に、起動のたびに実行していたマイグレーションの最後のファイルです。
+const untrackedMigrationsThrough = "20250706080000_unshare_proxied_feeds.sql"
+
+// untrackedMigrationsMarker は untrackedMigrationsThrough まで適用済みのデータベースにのみ存在するインデックスです。
+const untrackedMigrationsMarker = "idx_feeds_url_public"
+
+// Migrate は未適用のマイグレーションファイルを順に実行し、schema_migrations に記録します。
+//
+// 各ファイルは1回だけ、記録と同じトランザクションで実行します。適用済みのファイルは変更せず、
+// スキーマの修正は新しいファイルとして追加してください。
+// schema_migrations の導入以前のデータベースは、untrackedMigrationsThrough まで適用済みの場合のみ
+// 記録して実行しません。それ以前のデータベースには、従来どおりすべてのファイルを実行します。
+func Migrate(db *sql.DB, paths []string) error {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return fmt.Errorf("failed to lock migrations: %w", err)
+	}
+	defer conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", migrationLockID)
+
+	var tracked bool
+	if err := conn.QueryRowContext(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&tracked); err != nil {
+		return fmt.Errorf("failed to check schema_migrations: %w", err)
+	}
+	if !tracked {
+		if err := createSchemaMigrations(ctx, conn, paths); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range paths {
+		if err := applyMigration(ctx, conn, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createSchemaMigrations は schema_migrations を作成します。
+// untrackedMigrationsThrough まで適用済みのデータベースでは、それまでのファイルを適用済みとして記録します。
+func createSchemaMigrations(ctx context.Context, conn *sql.Conn, paths []string) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`CREATE TABLE schema_migrations (
+		version TEXT PRIMARY KEY,
+		applied_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	var applied bool
+	if err := tx.QueryRow("SELECT to_regclass($1) IS NOT NULL", untrackedMigrationsMarker).Scan(&applied); err != nil {
+		return fmt.Errorf("failed to check applied migrations: %w", err)
+	}
+	if applied {
+		for _, path := range paths {
+			version := filepath.Base(path)
+			if version > untrackedMigrationsThrough {
+				continue
+			}
+			if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES ($1)", version); err != nil {
+				return fmt.Errorf("failed to record %s: %w", path, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// applyMigration は未適用のマイグレーションファイルを実行し、schema_migrations に記録します。
+func applyMigration(ctx context.Context, conn *sql.Conn, path string) error {
+	version := filepath.Base(path)
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var applied bool
+	if err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", version).Scan(&applied); err != nil {
+		return fmt.Errorf("failed to check %s: %w", path, err)
+	}
+	if applied {
+		return nil
+	}
+
+	migrationSQL, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if _, err := tx.Exec(string(migrationSQL)); err != nil {
+		return fmt.Errorf("failed to execute %s: %w", path, err)
+	}
+	if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES ($1)", version); err != nil {
+		return fmt.Errorf("failed to record %s: %w", path, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit %s: %w", path, err)
+	}
+	log.Printf("%s executed successfully.", path)
+	return nil
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"feedapp/internal/model"
+)
+
+func TestMigrate_RunsOnce(t *testing.T) {
+	db := openTestDB(t)
+
+	table := "migrate_test_" + strings.ReplaceAll(model.GenerateUUID(), "-", "")
+	version := "29991231000000_" + table + ".sql"
+	path := filepath.Join(t.TempDir(), version)
+	require.NoError(t, os.WriteFile(path, []byte(
+		"CREATE TABLE IF NOT EXISTS "+table+" (id SERIAL PRIMARY KEY);\n"+
+			"INSERT INTO "+table+" DEFAULT VALUES;\n"), 0o644))
+	t.Cleanup(func() {
+		db.Exec("DROP TABLE IF EXISTS " + table)
+		db.Exec("DELETE FROM schema_migrations WHERE version = $1", version)
+	})
+
+	require.NoError(t, Migrate(db, []string{path}))
+	require.NoError(t, Migrate(db, []string{path}))
+
+	var runs int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM "+table).Scan(&runs))
+	assert.Equal(t, 1, runs)
+
+	var recorded bool
+	require.NoError(t, db.QueryRow("SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", version).Scan(&recorded))
+	assert.True(t, recorded)
+}
+
+func TestMigrate_RollsBackFailedMigration(t *testing.T) {
+	db := openTestDB(t)
+
+	table := "migrate_test_" + strings.ReplaceAll(model.GenerateUUID(), "-", "")
+	version := "29991231000000_" + table + ".sql"
+	path := filepath.Join(t.TempDir(), version)
+	require.NoError(t, os.WriteFile(path, []byte(
+		"CREATE TABLE "+table+" (id SERIAL PRIMARY KEY);\n"+
+			"SELECT no_such_column FROM "+table+";\n"), 0o644))
+	t.Cleanup(func() {
+		db.Exec("DROP TABLE IF EXISTS " + table)
+		db.Exec("DELETE FROM schema_migrations WHERE version = $1", version)
+	})
+
+	require.Error(t, Migrate(db, []string{path}))
+
+	// 失敗したファイルは記録せず、途中までの変更も残さない
+	var exists, recorded bool
+	require.NoError(t, db.QueryRow("SELECT to_regclass($1) IS NOT NULL", table).Scan(&exists))
+	assert.False(t, exists)
+	require.NoError(t, db.QueryRow("SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", version).Scan(&recorded))
+	assert.False(t, recorded)
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"feedapp/internal/model"
+)
+
+// openTestDB は TEST_DATABASE_URL のデータベースに接続し、未適用のマイグレーションを適用します。
+// TEST_DATABASE_URL が未設定の場合はテストをスキップします。
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	db, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	paths, err := filepath.Glob(filepath.Join("..", "..", "migrations", "*.sql"))
+	require.NoError(t, err)
+	sort.Strings(paths)
+	require.NoError(t, Migrate(db, paths))
+	return db
+}
+
+// createTestUser はテスト用のユーザーを作成します。
+func createTestUser(t *testing.T, db *sql.DB) model.User {
+	t.Helper()
+	id := model.GenerateUUID()
+	user, err := NewUserRepository(db).Create(model.User{ID: id, Email: id + "@example.com", Name: "Test User", CreatedAt: time.Now()})
+	require.NoError(t, err)
+	return user
+}
+
+// createTestFeed はユーザーが購読するテスト用のフィードを作成します。
+func createTestFeed(t *testing.T, db *sql.DB, userID, folderID string) model.Feed {
+	t.Helper()
+	id := model.GenerateUUID()
+	feed, err := NewFeedRepository(db, nil).Create(userID, model.Feed{ID: id, Name: "Test Feed", URL: "https://example.com/" + id, PluginType: "rss", FolderID: folderID, CreatedAt: time.Now()})
+	require.NoError(t, err)
+	return feed
+}
+
+// createTestArticle はフィードのテスト用の記事を作成します。
+func createTestArticle(t *testing.T, repo ArticleRepository, feedID string) model.Article {
+	t.Helper()
+	id := model.GenerateUUID()
+	article, err := repo.Create(model.Article{ID: id, FeedID: feedID, Title: "Article " + id, URL: "https://example.com/articles/" + id, CreatedAt: time.Now()})
+	require.NoError(t, err)
+	_, err = repo.AddSource(article.ID, feedID, "")
+	require.NoError(t, err)
+	return article
+}
+
+func TestMigrations_LegacyOwnership(t *testing.T) {
+	db := openTestDB(t)
+
+	// マルチユーザー化以前の状態のカラムは、マイグレーションで legacy_article_states に移して削除される
+	var legacyColumns int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = 'articles'
+		AND column_name IN ('is_read', 'is_later', 'playback_position_seconds', 'played', 'downloaded')`).Scan(&legacyColumns))
+	assert.Zero(t, legacyColumns)
+
+	createTestUser(t, db)
+
+	// 最初のユーザーの登録時に、所有者のないデータと移した状態は引き継がれる
+	var legacyStates int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM legacy_article_states").Scan(&legacyStates))
+	assert.Zero(t, legacyStates)
+
+	var unownedFolders int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM folders WHERE user_id IS NULL").Scan(&unownedFolders))
+	assert.Zero(t, unownedFolders)
+
+	// マイグレーションは1回だけ実行するため、テストデータを所有者のないデータとして再作成しない
+	db = openTestDB(t)
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM folders WHERE user_id IS NULL").Scan(&unownedFolders))
+	assert.Zero(t, unownedFolders)
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"feedapp/internal/model"
+)
+
+// RetentionRepository は保持設定による記事の削除を定義するインターフェースです。
+//
+// 削除した記事の正規化URLは purged_articles テーブルに記録し、
+// ArticleRepository.IsPurged で再登録の要否を判定します。
+type RetentionRepository interface {
+	Purge(userID string, policy model.RetentionPolicy, now time.Time, dryRun bool) (model.PurgeResult, error)
+	DeleteTombstones(before time.Time) (int64, error)
+}
+
+// retentionRepository は RetentionRepository インターフェースの実装です。
+type retentionRepository struct {
+	db *sql.DB
+}
+
+// NewRetentionRepository は新しい retentionRepository インスタンスを作成します。
+func NewRetentionRepository(db *sql.DB) RetentionRepository {
+	return &retentionRepository{db: db}
+}
+
+// purgeCandidates は削除対象の記事のIDと取得元フィードを取得するクエリです。
+// $1 は全体の保持日数、$2 はフィードごとの上限件数、$3 は基準日時、$4 は対象を購読中のフィードの記事に限るユーザーID（NULL の場合はすべての記事）です。
+//
+// 記事を取得したフィードごとに、上限件数を超えた古い記事か、保持日数を過ぎた記事かを判定し、
+// すべての取得元で対象となった記事のみを削除します。保持日数による削除は
+// すべての購読者が既読または非表示にした記事に限ります。
+// 正規化URLが未設定の記事（起動時の設定が終わっていない既存記事）は、再登録を防げないため対象外です。
+const purgeCandidates = `WITH ranked AS (
+	SELECT src.article_id, a.created_at,
+		ROW_NUMBER() OVER (PARTITION BY src.feed_id ORDER BY a.item_id DESC) AS position,
+		COALESCE(f.retention_days, $1) AS retention_days,
+		COALESCE(f.max_articles, $2) AS max_articles
+	FROM article_sources src JOIN articles a ON a.id = src.article_id JOIN feeds f ON f.id = src.feed_id
+), expired AS (
+	SELECT article_id,
+		bool_and(max_articles > 0 AND position > max_articles) AS over_limit,
+		bool_and((max_articles > 0 AND position > max_articles)
+			OR (retention_days > 0 AND created_at < $3::timestamptz - retention_days * INTERVAL '1 day')) AS outdated
+	FROM ranked GROUP BY article_id
+)
+SELECT a.id, a.feed_id FROM articles a JOIN expired e ON e.article_id = a.id
+WHERE a.normalized_url IS NOT NULL
+	AND ($4::uuid IS NULL OR EXISTS (SELECT 1 FROM article_sources own JOIN subscriptions sub ON sub.feed_id = own.feed_id
+		WHERE own.article_id = a.id AND sub.user_id = $4::uuid))
+	AND (e.over_limit OR (e.outdated AND NOT EXISTS (
+		SELECT 1 FROM article_sources s JOIN subscriptions sub ON sub.feed_id = s.feed_id
+		LEFT JOIN article_states st ON st.article_id = s.article_id AND st.user_id = sub.user_id
+		WHERE s.article_id = a.id AND NOT COALESCE(st.is_read OR st.is_hidden, FALSE))))
+	AND` + retainedExcluded
+
+// retainedExcluded は「後で見る」・タグ・注釈が付いた記事を除外する条件です。
+// 候補の取得から削除までの間に状態が変わった場合に備え、削除時にも再度確認します。
+const retainedExcluded = ` NOT EXISTS (SELECT 1 FROM article_states later WHERE later.article_id = a.id AND later.is_later)
+	AND NOT EXISTS (SELECT 1 FROM article_tags atg WHERE atg.article_id = a.id)
+	AND NOT EXISTS (SELECT 1 FROM annotations n WHERE n.article_id = a.id)`
+
+// Purge は保持設定に従って記事を削除し、フィードごとの件数を返します。
+// userID を指定した場合は、そのユーザーが購読しているフィードの記事のみを対象とします（空の場合はすべての記事）。
+// dryRun が true の場合は削除せずに対象の件数のみを集計します。
+// 削除した記事の正規化URLは purged_articles に記録します。
+func (r *retentionRepository) Purge(userID string, policy model.RetentionPolicy, now time.Time, dryRun bool) (model.PurgeResult, error) {
+	result := model.PurgeResult{DryRun: dryRun, Feeds: []model.FeedPurgeResult{}}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return result, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(purgeCandidates, policy.Days, policy.MaxArticlesPerFeed, now, nullString(userID))
+	if err != nil {
+		return result, fmt.Errorf("failed to get purge candidates: %w", err)
+	}
+	var ids []string
+	feedIndex := map[string]int{}
+	for rows.Next() {
+		var id string
+		var feedID sql.NullString
+		if err := rows.Scan(&id, &feedID); err != nil {
+			rows.Close()
+			return result, fmt.Errorf("failed to scan purge candidate row: %w", err)
+		}
+		ids = append(ids, id)
+		i, ok := feedIndex[feedID.String]
+		if !ok {
+			i = len(result.Feeds)
+			feedIndex[feedID.String] = i
+			result.Feeds = append(result.Feeds, model.FeedPurgeResult{FeedID: feedID.String})
+		}
+		result.Feeds[i].Deleted++
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return result, fmt.Errorf("rows iteration error: %w", err)
+	}
+	rows.Close()
+	result.Deleted = len(ids)
+	if dryRun || len(ids) == 0 {
+		return result, nil
+	}
+
+	// 認証情報を設定したフィードの記事は、フィードごとに記録する
+	tombstones := []string{
+		`INSERT INTO purged_articles (normalized_url, feed_id, purged_at)
+		SELECT a.normalized_url, a.feed_id, $2 FROM articles a WHERE a.id = ANY($1) AND NOT a.is_private AND` + retainedExcluded + `
+		ON CONFLICT (normalized_url) WHERE NOT is_private DO UPDATE SET purged_at = EXCLUDED.purged_at`,
+		`INSERT INTO purged_articles (normalized_url, feed_id, is_private, purged_at)
+		SELECT a.normalized_url, a.feed_id, TRUE, $2 FROM articles a WHERE a.id = ANY($1) AND a.is_private AND` + retainedExcluded + `
+		ON CONFLICT (feed_id, normalized_url) WHERE is_private DO UPDATE SET purged_at = EXCLUDED.purged_at`,
+	}
+	for _, tombstone := range tombstones {
+		if _, err := tx.Exec(tombstone, pq.Array(ids), now); err != nil {
+			return result, fmt.Errorf("failed to record purged articles: %w", err)
+		}
+	}
+	deleted, err := tx.Exec("DELETE FROM articles a WHERE a.id = ANY($1) AND"+retainedExcluded, pq.Array(ids))
+	if err != nil {
+		return result, fmt.Errorf("failed to purge articles: %w", err)
+	}
+	rowsAffected, err := deleted.RowsAffected()
+	if err != nil {
+		return result, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return result, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	result.Deleted = int(rowsAffected)
+	return result, nil
+}
+
+// DeleteTombstones は指定日時より前に記録した削除済み記事のURLを削除します。
+func (r *retentionRepository) DeleteTombstones(before time.Time) (int64, error) {
+	result, err := r.db.Exec("DELETE FROM purged_articles WHERE purged_at < $1", before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete purged article tombstones: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected, nil
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"feedapp/internal/model"
+)
+
+func TestRetentionRepository_PurgeScopedToSubscriptions(t *testing.T) {
+	db := openTestDB(t)
+	articleRepo := NewArticleRepository(db)
+	repo := NewRetentionRepository(db)
+
+	userA := createTestUser(t, db)
+	userB := createTestUser(t, db)
+	feedA := createTestFeed(t, db, userA.ID, "")
+	feedB := createTestFeed(t, db, userB.ID, "")
+	for _, feed := range []model.Feed{feedA, feedB} {
+		createTestArticle(t, articleRepo, feed.ID)
+		createTestArticle(t, articleRepo, feed.ID)
+	}
+
+	// 上限件数を超えた記事のうち、ユーザーAが購読しているフィードの記事のみが対象となる
+	result, err := repo.Purge(userA.ID, model.RetentionPolicy{MaxArticlesPerFeed: 1}, time.Now(), true)
+	require.NoError(t, err)
+	assert.Equal(t, []model.FeedPurgeResult{{FeedID: feedA.ID, Deleted: 1}}, result.Feeds)
+	assert.Equal(t, 1, result.Deleted)
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"feedapp/internal/model"
+)
+
+// RuleRepository はルールのデータ永続化を定義するインターフェースです。
+//
+// ユーザーIDを受け取るメソッドは所有者で絞り込み、他のユーザーのルールは ErrNotFound となります。
+// GetMatching は記事の取り込みで使用する操作です。
+type RuleRepository interface {
+	GetAll(userID string) ([]model.Rule, error)
+	GetByID(userID, id string) (model.Rule, error)
+	Create(rule model.Rule) (model.Rule, error)
+	Update(rule model.Rule) (model.Rule, error)
+	Delete(userID, id string) error
+	GetMatching(feedID string) ([]model.Rule, error)
+}
+
+// ruleRepository は RuleRepository インターフェースの実装です。
+type ruleRepository struct {
+	db *sql.DB
+}
+
+// NewRuleRepository は新しい ruleRepository インスタンスを作成します。
+func NewRuleRepository(db *sql.DB) RuleRepository {
+	return &ruleRepository{db: db}
+}
+
+// ruleColumns はルールの取得時に使用するカラム一覧です。
+const ruleColumns = "r.id, r.user_id, r.name, r.folder_id, r.feed_id, r.conditions, r.action, r.tag, r.created_at"
+
+// scanRule は ruleColumns の順に並んだ行を model.Rule に変換します。
+func scanRule(row rowScanner) (model.Rule, error) {
+	var rule model.Rule
+	var folderID, feedID sql.NullString
+	var conditions []byte
+	if err := row.Scan(&rule.ID, &rule.UserID, &rule.Name, &folderID, &feedID, &conditions, &rule.Action, &rule.Tag, &rule.CreatedAt); err != nil {
+		return model.Rule{}, err
+	}
+	if err := json.Unmarshal(conditions, &rule.Conditions); err != nil {
+		return model.Rule{}, fmt.Errorf("failed to decode rule conditions: %w", err)
+	}
+	rule.FolderID = folderID.String
+	rule.FeedID = feedID.String
+	return rule, nil
+}
+
+// queryRules は複数のルールを取得するクエリを実行します。
+func (r *ruleRepository) queryRules(query string, args ...any) ([]model.Rule, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []model.Rule
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rule row: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return rules, nil
+}
+
+func (r *ruleRepository) GetAll(userID string) ([]model.Rule, error) {
+	rules, err := r.queryRules("SELECT "+ruleColumns+" FROM rules r WHERE r.user_id = $1 ORDER BY r.created_at", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rules: %w", err)
+	}
+	return rules, nil
+}
+
+func (r *ruleRepository) GetByID(userID, id string) (model.Rule, error) {
+	rule, err := scanRule(r.db.QueryRow("SELECT "+ruleColumns+" FROM rules r WHERE r.id = $1 AND r.user_id = $2", id, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Rule{}, ErrNotFound
+		}
+		return model.Rule{}, fmt.Errorf("failed to get rule by ID: %w", err)
+	}
+	return rule, nil
+}
+
+func (r *ruleRepository) Create(rule model.Rule) (model.Rule, error) {
+	conditions, err := json.Marshal(rule.Conditions)
+	if err != nil {
+		return model.Rule{}, fmt.Errorf("failed to encode rule conditions: %w", err)
+	}
+	query := `INSERT INTO rules AS r (id, user_id, name, folder_id, feed_id, conditions, action, tag, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING ` + ruleColumns
+	created, err := scanRule(r.db.QueryRow(query, rule.ID, rule.UserID, rule.Name, nullString(rule.FolderID), nullString(rule.FeedID),
+		conditions, rule.Action, rule.Tag, rule.CreatedAt))
+	if err != nil {
+		return model.Rule{}, fmt.Errorf("failed to create rule: %w", err)
+	}
+	return created, nil
+}
+
+// Update はルールを更新します。rule.UserID が所有者と一致しない場合は ErrNotFound を返します。
+func (r *ruleRepository) Update(rule model.Rule) (model.Rule, error) {
+	conditions, err := json.Marshal(rule.Conditions)
+	if err != nil {
+		return model.Rule{}, fmt.Errorf("failed to encode rule conditions: %w", err)
+	}
+	query := `UPDATE rules AS r SET name = $1, folder_id = $2, feed_id = $3, conditions = $4, action = $5, tag = $6
+		WHERE r.id = $7 AND r.user_id = $8 RETURNING ` + ruleColumns
+	updated, err := scanRule(r.db.QueryRow(query, rule.Name, nullString(rule.FolderID), nullString(rule.FeedID), conditions,
+		rule.Action, rule.Tag, rule.ID, rule.UserID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Rule{}, ErrNotFound
+		}
+		return model.Rule{}, fmt.Errorf("failed to update rule: %w", err)
+	}
+	return updated, nil
+}
+
+func (r *ruleRepository) Delete(userID, id string) error {
+	result, err := r.db.Exec("DELETE FROM rules WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete rule: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetMatching は指定フィードの記事の取り込みで適用するルールを、作成順に取得します。
+//
+// 所有者がフィードを購読しており、適用範囲がすべてのフィード・そのフィード・
+// 所有者の購読のフォルダのいずれかであるルールを返します。
+func (r *ruleRepository) GetMatching(feedID string) ([]model.Rule, error) {
+	query := "SELECT " + ruleColumns + ` FROM rules r
+		JOIN subscriptions sub ON sub.user_id = r.user_id AND sub.feed_id = $1
+		WHERE (r.feed_id IS NULL OR r.feed_id = $1)
+		AND (r.folder_id IS NULL OR r.folder_id = sub.folder_id)
+		ORDER BY r.created_at`
+	rules, err := r.queryRules(query, feedID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get matching rules: %w", err)
+	}
+	return rules, nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"feedapp/internal/model"
+)
+
+// SessionRepository はログインセッションのデータ永続化を定義するインターフェースです。
+type SessionRepository interface {
+	Create(session model.Session) error
+	GetByTokenHash(tokenHash string) (model.Session, error)
+	Delete(tokenHash string) error
+	DeleteExpired(now time.Time) error
+}
+
+// sessionRepository は SessionRepository インターフェースの実装です。
+type sessionRepository struct {
+	db *sql.DB
+}
+
+// NewSessionRepository は新しい sessionRepository インスタンスを作成します。
+func NewSessionRepository(db *sql.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+func (r *sessionRepository) Create(session model.Session) error {
+	_, err := r.db.Exec("INSERT INTO sessions (token_hash, user_id, expires_at, created_at) VALUES ($1, $2, $3, $4)", session.TokenHash, session.UserID, session.ExpiresAt, session.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	return nil
+}
+
+// GetByTokenHash は有効期限内のセッションを取得します。期限切れのセッションは ErrNotFound となります。
+func (r *sessionRepository) GetByTokenHash(tokenHash string) (model.Session, error) {
+	var session model.Session
+	err := r.db.QueryRow("SELECT token_hash, user_id, expires_at, created_at FROM sessions WHERE token_hash = $1 AND expires_at > CURRENT_TIMESTAMP", tokenHash).Scan(&session.TokenHash, &session.UserID, &session.ExpiresAt, &session.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Session{}, ErrNotFound
+		}
+		return model.Session{}, fmt.Errorf("failed to get session: %w", err)
+	}
+	return session, nil
+}
+
+func (r *sessionRepository) Delete(tokenHash string) error {
+	if _, err := r.db.Exec("DELETE FROM sessions WHERE token_hash = $1", tokenHash); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired は有効期限切れのセッションを削除します。
+func (r *sessionRepository) DeleteExpired(now time.Time) error {
+	if _, err := r.db.Exec("DELETE FROM sessions WHERE expires_at <= $1", now); err != nil {
+		return fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"feedapp/internal/model"
+)
+
+// SnapshotRepository は記事の元ページのスナップショットの取得状況のデータ永続化を定義するインターフェースです。
+//
+// スナップショットは記事ごとに1件で、すべてのユーザーに共有されます。
+// 記事が呼び出し元のユーザーから参照できるかは呼び出し元で確認します。
+type SnapshotRepository interface {
+	Request(articleID string, requestedAt time.Time) (bool, error)
+	GetByArticleID(articleID string) (model.ArticleSnapshot, error)
+	GetPending(limit int) ([]model.ArticleSnapshot, error)
+	Complete(articleID, hash string, size int64, capturedAt time.Time) error
+	Fail(articleID, reason string) error
+}
+
+// snapshotRepository は SnapshotRepository インターフェースの実装です。
+type snapshotRepository struct {
+	db *sql.DB
+}
+
+// NewSnapshotRepository は新しい snapshotRepository インスタンスを作成します。
+func NewSnapshotRepository(db *sql.DB) SnapshotRepository {
+	return &snapshotRepository{db: db}
+}
+
+// selectSnapshots はスナップショットを取得元の記事URLとともに取得するクエリの SELECT 句と FROM 句です。
+const selectSnapshots = `SELECT snap.article_id, a.url, snap.status, snap.blob_hash, snap.size, snap.error, snap.requested_at, snap.captured_at
+	FROM article_snapshots snap JOIN articles a ON a.id = snap.article_id`
+
+// scanSnapshot は selectSnapshots の順に並んだ行を model.ArticleSnapshot に変換します。
+func scanSnapshot(row rowScanner) (model.ArticleSnapshot, error) {
+	var snapshot model.ArticleSnapshot
+	var hash sql.NullString
+	var capturedAt sql.NullTime
+	if err := row.Scan(&snapshot.ArticleID, &snapshot.URL, &snapshot.Status, &hash, &snapshot.Size, &snapshot.Error, &snapshot.RequestedAt, &capturedAt); err != nil {
+		return model.ArticleSnapshot{}, err
+	}
+	snapshot.Hash = hash.String
+	if capturedAt.Valid {
+		snapshot.CapturedAt = &capturedAt.Time
+	}
+	return snapshot, nil
+}
+
+// Request は記事のスナップショットの取得を要求します。
+// スナップショットが未取得または取得に失敗している場合のみ取得待ちにし、true を返します。
+func (r *snapshotRepository) Request(articleID string, requestedAt time.Time) (bool, error) {
+	query := `INSERT INTO article_snapshots (article_id, status, requested_at) VALUES ($1, $2, $3)
+		ON CONFLICT (article_id) DO UPDATE SET status = EXCLUDED.status, error = '', requested_at = EXCLUDED.requested_at
+		WHERE article_snapshots.status = $4`
+	result, err := r.db.Exec(query, articleID, model.SnapshotStatusPending, requestedAt, model.SnapshotStatusFailed)
+	if err != nil {
+		return false, fmt.Errorf("failed to request snapshot: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+func (r *snapshotRepository) GetByArticleID(articleID string) (model.ArticleSnapshot, error) {
+	snapshot, err := scanSnapshot(r.db.QueryRow(selectSnapshots+" WHERE snap.article_id = $1", articleID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.ArticleSnapshot{}, ErrNotFound
+		}
+		return model.ArticleSnapshot{}, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+// GetPending は取得待ちのスナップショットを要求の古い順に取得します。
+func (r *snapshotRepository) GetPending(limit int) ([]model.ArticleSnapshot, error) {
+	rows, err := r.db.Query(selectSnapshots+" WHERE snap.status = $1 ORDER BY snap.requested_at LIMIT $2", model.SnapshotStatusPending, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := []model.ArticleSnapshot{}
+	for rows.Next() {
+		snapshot, err := scanSnapshot(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan snapshot row: %w", err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return snapshots, nil
+}
+
+// Complete はスナップショットを取得済みにし、ブロブストアのキーとサイズを保存します。
+func (r *snapshotRepository) Complete(articleID, hash string, size int64, capturedAt time.Time) error {
+	query := "UPDATE article_snapshots SET status = $1, blob_hash = $2, size = $3, error = '', captured_at = $4 WHERE article_id = $5"
+	return r.update(query, model.SnapshotStatusReady, hash, size, capturedAt, articleID)
+}
+
+// Fail はスナップショットを取得失敗にし、理由を保存します。
+func (r *snapshotRepository) Fail(articleID, reason string) error {
+	return r.update("UPDATE article_snapshots SET status = $1, error = $2 WHERE article_id = $3", model.SnapshotStatusFailed, reason, articleID)
+}
+
+// update はスナップショットを更新します。対象が存在しない場合は ErrNotFound を返します。
+func (r *snapshotRepository) update(query string, args ...any) error {
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update snapshot: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
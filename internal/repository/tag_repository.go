@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"feedapp/internal/model"
+)
+
+// TagRepository はユーザーごとのタグと記事の関連のデータ永続化を定義するインターフェースです。
+//
+// 記事が呼び出し元のユーザーから参照できるかは呼び出し元で確認します。
+type TagRepository interface {
+	GetAll(userID string) ([]model.Tag, error)
+	AddToArticles(userID, name string, articleIDs []string) error
+	RemoveFromArticle(userID, name, articleID string) error
+}
+
+// tagRepository は TagRepository インターフェースの実装です。
+type tagRepository struct {
+	db *sql.DB
+}
+
+// NewTagRepository は新しい tagRepository インスタンスを作成します。
+func NewTagRepository(db *sql.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// AddToArticles は記事にタグを付けます。タグが存在しない場合は作成し、既に付いている記事は無視します。
+func (r *tagRepository) AddToArticles(userID, name string, articleIDs []string) error {
+	if len(articleIDs) == 0 {
+		return nil
+	}
+	query := `WITH tag AS (
+			INSERT INTO tags (id, user_id, name) VALUES ($1, $2, $3)
+			ON CONFLICT (user_id, name) DO UPDATE SET name = EXCLUDED.name RETURNING id
+		)
+		INSERT INTO article_tags (tag_id, article_id) SELECT tag.id, unnest($4::uuid[]) FROM tag
+		ON CONFLICT (tag_id, article_id) DO NOTHING`
+	if _, err := r.db.Exec(query, model.GenerateUUID(), userID, name, pq.Array(articleIDs)); err != nil {
+		return fmt.Errorf("failed to add tag to articles: %w", err)
+	}
+	return nil
+}
+
+// GetAll はユーザーのタグを、ユーザーが参照できる記事の件数とともに名前順に取得します。
+func (r *tagRepository) GetAll(userID string) ([]model.Tag, error) {
+	query := `SELECT t.name, COUNT(a.id), COUNT(a.id) FILTER (WHERE NOT COALESCE(st.is_read, FALSE))
+		FROM tags t
+		LEFT JOIN article_tags atg ON atg.tag_id = t.id
+		LEFT JOIN articles a ON a.id = atg.article_id AND` + visibleToUser + `
+		LEFT JOIN article_states st ON st.article_id = a.id AND st.user_id = $1
+		WHERE t.user_id = $1
+		GROUP BY t.id, t.name
+		ORDER BY t.name`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []model.Tag{}
+	for rows.Next() {
+		var tag model.Tag
+		if err := rows.Scan(&tag.Name, &tag.Count, &tag.UnreadCount); err != nil {
+			return nil, fmt.Errorf("failed to scan tag row: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return tags, nil
+}
+
+// RemoveFromArticle は記事からタグを外します。タグが付いていない場合は ErrNotFound を返します。
+// タグが付いた記事がなくなった場合はタグも削除します。
+func (r *tagRepository) RemoveFromArticle(userID, name, articleID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var tagID string
+	err = tx.QueryRow(`DELETE FROM article_tags atg USING tags t
+		WHERE atg.tag_id = t.id AND t.user_id = $1 AND t.name = $2 AND atg.article_id = $3
+		RETURNING t.id`, userID, name, articleID).Scan(&tagID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to remove tag from article: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM tags t WHERE t.id = $1 AND NOT EXISTS (SELECT 1 FROM article_tags atg WHERE atg.tag_id = t.id)", tagID); err != nil {
+		return fmt.Errorf("failed to delete unused tag: %w", err)
+	}
+	return tx.Commit()
+}
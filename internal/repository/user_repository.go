@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"feedapp/internal/model"
+)
+
+// UserRepository はユーザーのデータ永続化を定義するインターフェースです。
+type UserRepository interface {
+	GetByID(id string) (model.User, error)
+	GetByEmail(email string) (model.User, error)
+	Create(user model.User) (model.User, error)
+	GetByIdentity(issuer, subject string) (model.User, error)
+	CreateWithIdentity(user model.User, issuer, subject string) (model.User, error)
+	AddIdentity(userID, issuer, subject string) error
+	GetByFeverKeyHash(keyHash string) (model.User, error)
+	UpdateFeverKeyHash(userID, keyHash string) error
+}
+
+// userRepository は UserRepository インターフェースの実装です。
+type userRepository struct {
+	db *sql.DB
+}
+
+// NewUserRepository は新しい userRepository インスタンスを作成します。
+func NewUserRepository(db *sql.DB) UserRepository {
+	return &userRepository{db: db}
+}
+
+// userColumns はユーザーの取得時に使用するカラム一覧です。
+const userColumns = "id, email, name, password_hash, fever_key_hash IS NOT NULL, created_at"
+
+// firstUserLockID はユーザーの登録時に、最初のユーザーかどうかを判定する間取得する advisory lock のキーです。
+const firstUserLockID = 20250705140000
+
+// scanUser は userColumns の順に並んだ行を model.User に変換します。
+func scanUser(row rowScanner) (model.User, error) {
+	var user model.User
+	var passwordHash sql.NullString
+	if err := row.Scan(&user.ID, &user.Email, &user.Name, &passwordHash, &user.FeverEnabled, &user.CreatedAt); err != nil {
+		return model.User{}, err
+	}
+	user.PasswordHash = passwordHash.String
+	return user, nil
+}
+
+func (r *userRepository) GetByID(id string) (model.User, error) {
+	user, err := scanUser(r.db.QueryRow("SELECT "+userColumns+" FROM users WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.User{}, ErrNotFound
+		}
+		return model.User{}, fmt.Errorf("failed to get user by ID: %w", err)
+	}
+	return user, nil
+}
+
+// GetByEmail はメールアドレスでユーザーを取得します。メールアドレスの大文字・小文字は区別しません。
+func (r *userRepository) GetByEmail(email string) (model.User, error) {
+	user, err := scanUser(r.db.QueryRow("SELECT "+userColumns+" FROM users WHERE LOWER(email) = LOWER($1)", email))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.User{}, ErrNotFound
+		}
+		return model.User{}, fmt.Errorf("failed to get user by email: %w", err)
+	}
+	return user, nil
+}
+
+// Create はユーザーを作成します。メールアドレスが登録済みの場合は ErrAlreadyExists を返します。
+//
+// 最初に作成されたユーザーは、マルチユーザー対応以前から存在する所有者のない
+// フォルダ・フィード・記事の状態を引き継ぎます。所有者のないデータがあるサーバーでは、
+// 所有者が最初に登録するまで他のユーザーが登録できないようにする必要があります。
+func (r *userRepository) Create(user model.User) (model.User, error) {
+	return r.create(user, func(*sql.Tx, string) error { return nil })
+}
+
+// GetByIdentity は外部認証の発行者と sub クレームに対応するユーザーを取得します。
+func (r *userRepository) GetByIdentity(issuer, subject string) (model.User, error) {
+	query := "SELECT " + userColumns + " FROM users WHERE id = (SELECT user_id FROM user_identities WHERE issuer = $1 AND subject = $2)"
+	user, err := scanUser(r.db.QueryRow(query, issuer, subject))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.User{}, ErrNotFound
+		}
+		return model.User{}, fmt.Errorf("failed to get user by identity: %w", err)
+	}
+	return user, nil
+}
+
+// CreateWithIdentity は外部認証のアカウントに対応するユーザーを作成します。
+// メールアドレスが登録済みの場合は ErrAlreadyExists を返します。
+func (r *userRepository) CreateWithIdentity(user model.User, issuer, subject string) (model.User, error) {
+	return r.create(user, func(tx *sql.Tx, userID string) error {
+		return insertIdentity(tx, userID, issuer, subject)
+	})
+}
+
+// AddIdentity は既存のユーザーに外部認証のアカウントを関連付けます。
+// アカウントが別のユーザーに関連付け済みの場合は ErrAlreadyExists を返します。
+func (r *userRepository) AddIdentity(userID, issuer, subject string) error {
+	return insertIdentity(r.db, userID, issuer, subject)
+}
+
+// GetByFeverKeyHash は Fever API の api_key のハッシュでユーザーを取得します。
+func (r *userRepository) GetByFeverKeyHash(keyHash string) (model.User, error) {
+	user, err := scanUser(r.db.QueryRow("SELECT "+userColumns+" FROM users WHERE fever_key_hash = $1", keyHash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.User{}, ErrNotFound
+		}
+		return model.User{}, fmt.Errorf("failed to get user by fever key: %w", err)
+	}
+	return user, nil
+}
+
+// UpdateFeverKeyHash は Fever API の api_key のハッシュを保存します。空文字列の場合は Fever API を無効にします。
+func (r *userRepository) UpdateFeverKeyHash(userID, keyHash string) error {
+	result, err := r.db.Exec("UPDATE users SET fever_key_hash = $1 WHERE id = $2", nullString(keyHash), userID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrAlreadyExists
+		}
+		return fmt.Errorf("failed to update fever key: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// create はユーザーを作成し、同じトランザクション内で afterInsert を実行します。
+func (r *userRepository) create(user model.User, afterInsert func(tx *sql.Tx, userID string) error) (model.User, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.User{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO users (id, email, name, password_hash, created_at) VALUES ($1, $2, $3, $4, $5) RETURNING ` + userColumns
+	createdUser, err := scanUser(tx.QueryRow(query, user.ID, user.Email, user.Name, nullString(user.PasswordHash), user.CreatedAt))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return model.User{}, ErrAlreadyExists
+		}
+		return model.User{}, fmt.Errorf("failed to create user: %w", err)
+	}
+	if err := afterInsert(tx, createdUser.ID); err != nil {
+		return model.User{}, err
+	}
+
+	// 同時に登録した複数のユーザーがどちらも最初のユーザーとして引き継がないよう、
+	// ロックを取得してから数える（READ COMMITTED では、ロックの解放後に他のトランザクションの登録が見える）
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", firstUserLockID); err != nil {
+		return model.User{}, fmt.Errorf("failed to lock users: %w", err)
+	}
+	var count int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM users").Scan(&count); err != nil {
+		return model.User{}, fmt.Errorf("failed to count users: %w", err)
+	}
+	if count == 1 {
+		if err := adoptUnownedData(tx, createdUser.ID); err != nil {
+			return model.User{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.User{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return createdUser, nil
+}
+
+// adoptUnownedData は所有者のないフォルダ・フィード・記事の状態を指定ユーザーのものにします。
+// 記事の状態は、ユーザーの登録前にマイグレーションで legacy_article_states に移した
+// マルチユーザー化以前の状態を引き継ぎ、引き継いだものは削除します。
+func adoptUnownedData(tx *sql.Tx, userID string) error {
+	statements := []string{
+		`UPDATE folders SET user_id = $1 WHERE user_id IS NULL`,
+		`INSERT INTO subscriptions (id, user_id, feed_id, name, folder_id, created_at)
+			SELECT gen_random_uuid(), $1, id, name, folder_id, created_at FROM feeds
+			ON CONFLICT (user_id, feed_id) DO NOTHING`,
+		`INSERT INTO article_states (user_id, article_id, is_read, is_later, playback_position_seconds, played, downloaded)
+			SELECT $1, article_id, is_read, is_later, playback_position_seconds, played, downloaded FROM legacy_article_states
+			ON CONFLICT (user_id, article_id) DO NOTHING`,
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement, userID); err != nil {
+			return fmt.Errorf("failed to adopt unowned data: %w", err)
+		}
+	}
+	if _, err := tx.Exec("DELETE FROM legacy_article_states"); err != nil {
+		return fmt.Errorf("failed to delete legacy article states: %w", err)
+	}
+	return nil
+}
+
+// execer は *sql.DB と *sql.Tx に共通する Exec メソッドです。
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// insertIdentity はユーザーと外部認証のアカウントの関連を登録します。
+func insertIdentity(db execer, userID, issuer, subject string) error {
+	if _, err := db.Exec("INSERT INTO user_identities (issuer, subject, user_id) VALUES ($1, $2, $3)", issuer, subject, userID); err != nil {
+		if isUniqueViolation(err) {
+			return ErrAlreadyExists
+		}
+		return fmt.Errorf("failed to create user identity: %w", err)
+	}
+	return nil
+}
+
+// isUniqueViolation は一意制約違反のエラーかどうかを判定します。
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
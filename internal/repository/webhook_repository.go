@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"feedapp/internal/model"
+)
+
+// WebhookRepository は Webhook と配信ログのデータ永続化を定義するインターフェースです。
+//
+// ユーザーIDを受け取るメソッドは所有者で絞り込み、他のユーザーの Webhook は ErrNotFound となります。
+// GetMatching / GetTarget / GetDueDeliveries は記事の取り込みと配信キューで使用する操作です。
+type WebhookRepository interface {
+	GetAll(userID string) ([]model.Webhook, error)
+	GetByID(userID, id string) (model.Webhook, error)
+	Create(webhook model.Webhook) (model.Webhook, error)
+	Update(webhook model.Webhook) (model.Webhook, error)
+	Delete(userID, id string) error
+	GetMatching(feedID, articleID string) ([]model.Webhook, error)
+	GetTarget(id string) (model.Webhook, error)
+	CreateDelivery(delivery model.WebhookDelivery) (model.WebhookDelivery, error)
+	UpdateDelivery(delivery model.WebhookDelivery) error
+	GetDeliveries(webhookID string, limit int) ([]model.WebhookDelivery, error)
+	GetDueDeliveries(now time.Time, limit int) ([]model.WebhookDelivery, error)
+}
+
+// webhookRepository は WebhookRepository インターフェースの実装です。
+type webhookRepository struct {
+	db *sql.DB
+}
+
+// NewWebhookRepository は新しい webhookRepository インスタンスを作成します。
+func NewWebhookRepository(db *sql.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+// webhookColumns は Webhook の取得時に使用するカラム一覧です。
+const webhookColumns = "w.id, w.user_id, w.name, w.url, w.folder_id, w.feed_id, w.keyword, w.payload_template, w.secret, w.created_at"
+
+// webhookDeliveryColumns は配信の取得時に使用するカラム一覧です。
+const webhookDeliveryColumns = "id, webhook_id, article_id, event, payload, status, attempts, response_status, error, next_attempt_at, last_attempt_at, created_at"
+
+// scanWebhook は webhookColumns の順に並んだ行を model.Webhook に変換します。
+func scanWebhook(row rowScanner) (model.Webhook, error) {
+	var webhook model.Webhook
+	var folderID, feedID sql.NullString
+	if err := row.Scan(&webhook.ID, &webhook.UserID, &webhook.Name, &webhook.URL, &folderID, &feedID, &webhook.Keyword, &webhook.PayloadTemplate, &webhook.Secret, &webhook.CreatedAt); err != nil {
+		return model.Webhook{}, err
+	}
+	webhook.FolderID = folderID.String
+	webhook.FeedID = feedID.String
+	return webhook, nil
+}
+
+// scanWebhookDelivery は webhookDeliveryColumns の順に並んだ行を model.WebhookDelivery に変換します。
+func scanWebhookDelivery(row rowScanner) (model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	var articleID sql.NullString
+	var responseStatus sql.NullInt64
+	var nextAttemptAt, lastAttemptAt sql.NullTime
+	if err := row.Scan(&delivery.ID, &delivery.WebhookID, &articleID, &delivery.Event, &delivery.Payload, &delivery.Status, &delivery.Attempts, &responseStatus, &delivery.Error, &nextAttemptAt, &lastAttemptAt, &delivery.CreatedAt); err != nil {
+		return model.WebhookDelivery{}, err
+	}
+	delivery.ArticleID = articleID.String
+	delivery.ResponseStatus = int(responseStatus.Int64)
+	if nextAttemptAt.Valid {
+		delivery.NextAttemptAt = &nextAttemptAt.Time
+	}
+	if lastAttemptAt.Valid {
+		delivery.LastAttemptAt = &lastAttemptAt.Time
+	}
+	return delivery, nil
+}
+
+// queryWebhooks は複数の Webhook を取得するクエリを実行します。
+func (r *webhookRepository) queryWebhooks(query string, args ...any) ([]model.Webhook, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []model.Webhook
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook row: %w", err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// queryDeliveries は複数の配信を取得するクエリを実行します。
+func (r *webhookRepository) queryDeliveries(query string, args ...any) ([]model.WebhookDelivery, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []model.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery row: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (r *webhookRepository) GetAll(userID string) ([]model.Webhook, error) {
+	webhooks, err := r.queryWebhooks("SELECT "+webhookColumns+" FROM webhooks w WHERE w.user_id = $1 ORDER BY w.created_at", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+func (r *webhookRepository) GetByID(userID, id string) (model.Webhook, error) {
+	webhook, err := scanWebhook(r.db.QueryRow("SELECT "+webhookColumns+" FROM webhooks w WHERE w.id = $1 AND w.user_id = $2", id, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Webhook{}, ErrNotFound
+		}
+		return model.Webhook{}, fmt.Errorf("failed to get webhook by ID: %w", err)
+	}
+	return webhook, nil
+}
+
+func (r *webhookRepository) Create(webhook model.Webhook) (model.Webhook, error) {
+	query := `INSERT INTO webhooks AS w (id, user_id, name, url, folder_id, feed_id, keyword, payload_template, secret, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING ` + webhookColumns
+	created, err := scanWebhook(r.db.QueryRow(query, webhook.ID, webhook.UserID, webhook.Name, webhook.URL,
+		nullString(webhook.FolderID), nullString(webhook.FeedID), webhook.Keyword, webhook.PayloadTemplate, webhook.Secret, webhook.CreatedAt))
+	if err != nil {
+		return model.Webhook{}, fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return created, nil
+}
+
+// Update は Webhook を更新します。webhook.UserID が所有者と一致しない場合は ErrNotFound を返します。
+func (r *webhookRepository) Update(webhook model.Webhook) (model.Webhook, error) {
+	query := `UPDATE webhooks AS w SET name = $1, url = $2, folder_id = $3, feed_id = $4, keyword = $5, payload_template = $6, secret = $7
+		WHERE w.id = $8 AND w.user_id = $9 RETURNING ` + webhookColumns
+	updated, err := scanWebhook(r.db.QueryRow(query, webhook.Name, webhook.URL, nullString(webhook.FolderID), nullString(webhook.FeedID),
+		webhook.Keyword, webhook.PayloadTemplate, webhook.Secret, webhook.ID, webhook.UserID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Webhook{}, ErrNotFound
+		}
+		return model.Webhook{}, fmt.Errorf("failed to update webhook: %w", err)
+	}
+	return updated, nil
+}
+
+func (r *webhookRepository) Delete(userID, id string) error {
+	result, err := r.db.Exec("DELETE FROM webhooks WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetMatching は指定フィードの新着記事を通知する Webhook を取得します。
+//
+// 所有者がフィードを購読しており、フィード・フォルダの条件に一致する Webhook を返します。
+// フォルダの条件は所有者の購読のフォルダで判定します。所有者がルールで一覧から除外した記事と、
+// 別のフィードから取り込んだ際に配信済みの記事は通知しません。キーワードの条件は呼び出し元で判定します。
+func (r *webhookRepository) GetMatching(feedID, articleID string) ([]model.Webhook, error) {
+	query := "SELECT " + webhookColumns + ` FROM webhooks w
+		JOIN subscriptions sub ON sub.user_id = w.user_id AND sub.feed_id = $1
+		WHERE (w.feed_id IS NULL OR w.feed_id = $1)
+		AND (w.folder_id IS NULL OR w.folder_id = sub.folder_id)
+		AND NOT EXISTS (SELECT 1 FROM article_states st WHERE st.user_id = w.user_id AND st.article_id = $2 AND st.is_hidden)
+		AND NOT EXISTS (SELECT 1 FROM webhook_deliveries d WHERE d.webhook_id = w.id AND d.article_id = $2)`
+	webhooks, err := r.queryWebhooks(query, feedID, articleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get matching webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+// GetTarget は配信時に使用する Webhook を所有者で絞り込まずに取得します。
+func (r *webhookRepository) GetTarget(id string) (model.Webhook, error) {
+	webhook, err := scanWebhook(r.db.QueryRow("SELECT "+webhookColumns+" FROM webhooks w WHERE w.id = $1", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Webhook{}, ErrNotFound
+		}
+		return model.Webhook{}, fmt.Errorf("failed to get webhook target: %w", err)
+	}
+	return webhook, nil
+}
+
+func (r *webhookRepository) CreateDelivery(delivery model.WebhookDelivery) (model.WebhookDelivery, error) {
+	query := `INSERT INTO webhook_deliveries (id, webhook_id, article_id, event, payload, status, attempts, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING ` + webhookDeliveryColumns
+	created, err := scanWebhookDelivery(r.db.QueryRow(query, delivery.ID, delivery.WebhookID, nullString(delivery.ArticleID),
+		delivery.Event, delivery.Payload, delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.CreatedAt))
+	if err != nil {
+		return model.WebhookDelivery{}, fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+	return created, nil
+}
+
+// UpdateDelivery は送信結果（状態・送信回数・応答・次回送信日時）を更新します。
+func (r *webhookRepository) UpdateDelivery(delivery model.WebhookDelivery) error {
+	var responseStatus sql.NullInt64
+	if delivery.ResponseStatus != 0 {
+		responseStatus = sql.NullInt64{Int64: int64(delivery.ResponseStatus), Valid: true}
+	}
+	query := `UPDATE webhook_deliveries SET status = $1, attempts = $2, response_status = $3, error = $4,
+		next_attempt_at = $5, last_attempt_at = $6 WHERE id = $7`
+	if _, err := r.db.Exec(query, delivery.Status, delivery.Attempts, responseStatus, delivery.Error,
+		delivery.NextAttemptAt, delivery.LastAttemptAt, delivery.ID); err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// GetDeliveries は指定 Webhook の配信を新しい順に最大 limit 件取得します。
+func (r *webhookRepository) GetDeliveries(webhookID string, limit int) ([]model.WebhookDelivery, error) {
+	deliveries, err := r.queryDeliveries("SELECT "+webhookDeliveryColumns+" FROM webhook_deliveries WHERE webhook_id = $1 ORDER BY created_at DESC LIMIT $2", webhookID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// GetDueDeliveries は送信日時を過ぎた pending の配信を古い順に最大 limit 件取得します。
+func (r *webhookRepository) GetDueDeliveries(now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	query := "SELECT " + webhookDeliveryColumns + ` FROM webhook_deliveries
+		WHERE status = $1 AND next_attempt_at <= $2 ORDER BY next_attempt_at LIMIT $3`
+	deliveries, err := r.queryDeliveries(query, model.WebhookDeliveryPending, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"feedapp/internal/model"
+)
+
+// WebSubRepository はフィードの WebSub 購読のデータ永続化を定義するインターフェースです。
+type WebSubRepository interface {
+	GetByFeedID(feedID string) (model.WebSubSubscription, error)
+	Save(sub model.WebSubSubscription) error
+	GetActiveFeedIDs(now time.Time) ([]string, error)
+	GetExpiring(before time.Time) ([]model.WebSubSubscription, error)
+}
+
+// webSubRepository は WebSubRepository インターフェースの実装です。
+type webSubRepository struct {
+	db *sql.DB
+}
+
+// NewWebSubRepository は新しい webSubRepository インスタンスを作成します。
+func NewWebSubRepository(db *sql.DB) WebSubRepository {
+	return &webSubRepository{db: db}
+}
+
+// webSubColumns は購読の取得時に使用するカラム一覧です。
+const webSubColumns = "feed_id, hub, topic, secret, state, lease_expires_at, last_error, updated_at"
+
+// scanWebSubSubscription は webSubColumns の順に並んだ行を model.WebSubSubscription に変換します。
+func scanWebSubSubscription(row rowScanner) (model.WebSubSubscription, error) {
+	var sub model.WebSubSubscription
+	var leaseExpiresAt sql.NullTime
+	if err := row.Scan(&sub.FeedID, &sub.Hub, &sub.Topic, &sub.Secret, &sub.State, &leaseExpiresAt, &sub.LastError, &sub.UpdatedAt); err != nil {
+		return model.WebSubSubscription{}, err
+	}
+	if leaseExpiresAt.Valid {
+		sub.LeaseExpiresAt = &leaseExpiresAt.Time
+	}
+	return sub, nil
+}
+
+func (r *webSubRepository) GetByFeedID(feedID string) (model.WebSubSubscription, error) {
+	sub, err := scanWebSubSubscription(r.db.QueryRow("SELECT "+webSubColumns+" FROM websub_subscriptions WHERE feed_id = $1", feedID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.WebSubSubscription{}, ErrNotFound
+		}
+		return model.WebSubSubscription{}, fmt.Errorf("failed to get websub subscription: %w", err)
+	}
+	return sub, nil
+}
+
+// Save はフィードの購読を作成し、既に存在する場合は上書きします。
+func (r *webSubRepository) Save(sub model.WebSubSubscription) error {
+	query := `INSERT INTO websub_subscriptions (feed_id, hub, topic, secret, state, lease_expires_at, last_error, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (feed_id) DO UPDATE SET hub = EXCLUDED.hub, topic = EXCLUDED.topic, secret = EXCLUDED.secret, state = EXCLUDED.state,
+		lease_expires_at = EXCLUDED.lease_expires_at, last_error = EXCLUDED.last_error, updated_at = EXCLUDED.updated_at`
+	if _, err := r.db.Exec(query, sub.FeedID, sub.Hub, sub.Topic, sub.Secret, sub.State, sub.LeaseExpiresAt, sub.LastError, sub.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save websub subscription: %w", err)
+	}
+	return nil
+}
+
+// GetActiveFeedIDs は購読が成立しており、有効期限内のフィードのIDを取得します。
+func (r *webSubRepository) GetActiveFeedIDs(now time.Time) ([]string, error) {
+	rows, err := r.db.Query("SELECT feed_id FROM websub_subscriptions WHERE state = $1 AND lease_expires_at > $2", model.WebSubActive, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active websub subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var feedIDs []string
+	for rows.Next() {
+		var feedID string
+		if err := rows.Scan(&feedID); err != nil {
+			return nil, fmt.Errorf("failed to scan feed ID: %w", err)
+		}
+		feedIDs = append(feedIDs, feedID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return feedIDs, nil
+}
+
+// GetExpiring は有効期限が before 以前の成立済みの購読（期限切れを含む）を取得します。
+func (r *webSubRepository) GetExpiring(before time.Time) ([]model.WebSubSubscription, error) {
+	rows, err := r.db.Query("SELECT "+webSubColumns+" FROM websub_subscriptions WHERE state = $1 AND lease_expires_at <= $2 ORDER BY lease_expires_at", model.WebSubActive, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expiring websub subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []model.WebSubSubscription
+	for rows.Next() {
+		sub, err := scanWebSubSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan websub subscription: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return subs, nil
+}
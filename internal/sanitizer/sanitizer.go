@@ -0,0 +1,306 @@
+// Package sanitizer は記事本文のHTMLサニタイズ処理を提供します。
+//
+// 外部サイトから取得したHTMLをそのままフロントエンドで描画するとXSSの
+// 原因となるため、取り込み時に許可リスト方式で安全なタグと属性のみを残します。
+// ルールを変更した場合は Version を上げることで、保存済みの元HTMLから
+// 再サニタイズされます。
+package sanitizer
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Version はサニタイズルールのバージョンです。
+// ルールを変更した場合はインクリメントしてください。
+const Version = 1
+
+// allowedTags は出力に残すタグと、そのタグで許可する属性です。
+var allowedTags = map[atom.Atom][]string{
+	atom.A:          {"href"},
+	atom.Abbr:       nil,
+	atom.Audio:      {"src", "controls"},
+	atom.B:          nil,
+	atom.Blockquote: {"cite"},
+	atom.Br:         nil,
+	atom.Caption:    nil,
+	atom.Cite:       nil,
+	atom.Code:       nil,
+	atom.Dd:         nil,
+	atom.Del:        nil,
+	atom.Details:    nil,
+	atom.Div:        nil,
+	atom.Dl:         nil,
+	atom.Dt:         nil,
+	atom.Em:         nil,
+	atom.Figcaption: nil,
+	atom.Figure:     nil,
+	atom.H1:         nil,
+	atom.H2:         nil,
+	atom.H3:         nil,
+	atom.H4:         nil,
+	atom.H5:         nil,
+	atom.H6:         nil,
+	atom.Hr:         nil,
+	atom.I:          nil,
+	atom.Img:        {"src", "srcset", "alt", "width", "height"},
+	atom.Ins:        nil,
+	atom.Kbd:        nil,
+	atom.Li:         nil,
+	atom.Mark:       nil,
+	atom.Ol:         {"start"},
+	atom.P:          nil,
+	atom.Picture:    nil,
+	atom.Pre:        nil,
+	atom.Q:          {"cite"},
+	atom.S:          nil,
+	atom.Small:      nil,
+	atom.Source:     {"src", "srcset", "type", "media"},
+	atom.Span:       nil,
+	atom.Strong:     nil,
+	atom.Sub:        nil,
+	atom.Summary:    nil,
+	atom.Sup:        nil,
+	atom.Table:      nil,
+	atom.Tbody:      nil,
+	atom.Td:         {"colspan", "rowspan"},
+	atom.Tfoot:      nil,
+	atom.Th:         {"colspan", "rowspan", "scope"},
+	atom.Thead:      nil,
+	atom.Time:       {"datetime"},
+	atom.Tr:         nil,
+	atom.U:          nil,
+	atom.Ul:         nil,
+	atom.Video:      {"src", "poster", "controls", "width", "height"},
+}
+
+// globalAttrs はすべての許可タグで使用できる属性です。
+var globalAttrs = []string{"title", "lang", "dir"}
+
+// droppedTags は子要素を含めて完全に除去するタグです。
+// これ以外の許可されていないタグはタグのみ除去し、子要素は残します。
+var droppedTags = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Iframe:   true,
+	atom.Frame:    true,
+	atom.Frameset: true,
+	atom.Object:   true,
+	atom.Embed:    true,
+	atom.Applet:   true,
+	atom.Form:     true,
+	atom.Input:    true,
+	atom.Button:   true,
+	atom.Textarea: true,
+	atom.Select:   true,
+	atom.Noscript: true,
+	atom.Template: true,
+	atom.Head:     true,
+	atom.Title:    true,
+	atom.Meta:     true,
+	atom.Link:     true,
+	atom.Base:     true,
+	atom.Svg:      true,
+	atom.Math:     true,
+}
+
+// urlAttrs はURLとして検証・解決する属性です。
+var urlAttrs = map[string]bool{
+	"href":   true,
+	"src":    true,
+	"cite":   true,
+	"poster": true,
+}
+
+// Sanitize はHTML断片をサニタイズして返します。
+//
+// 許可リストにないタグ・属性（on* イベントハンドラーや style を含む）を除去し、
+// href/src などの相対URLを baseURL を基準に絶対URLへ解決します。
+// http/https 以外のスキーム（javascript: など）のURLは属性ごと除去し、
+// リンクには rel="noopener noreferrer" と target="_blank" を付与します。
+//
+// Parameters:
+//   - content: サニタイズするHTML断片
+//   - baseURL: 相対URLの解決に使用する記事のURL
+//
+// Returns:
+//   - string: サニタイズ済みのHTML
+func Sanitize(content, baseURL string) string {
+	if strings.TrimSpace(content) == "" {
+		return ""
+	}
+
+	base, err := url.Parse(baseURL)
+	if err != nil || !base.IsAbs() {
+		base = nil
+	}
+
+	fragmentContext := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	nodes, err := html.ParseFragment(strings.NewReader(content), fragmentContext)
+	if err != nil {
+		// 解析できないHTMLはテキストとしてエスケープして返す
+		return html.EscapeString(content)
+	}
+
+	root := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	for _, n := range nodes {
+		root.AppendChild(n)
+	}
+	sanitizeChildren(root, base)
+
+	var b strings.Builder
+	for c := root.FirstChild; c != nil; c = c.NextSibling {
+		if err := html.Render(&b, c); err != nil {
+			return ""
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// sanitizeChildren は n の子要素を再帰的にサニタイズします。
+func sanitizeChildren(n *html.Node, base *url.URL) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		switch c.Type {
+		case html.TextNode:
+			// テキストはそのまま残す
+		case html.ElementNode:
+			switch {
+			case droppedTags[c.DataAtom]:
+				n.RemoveChild(c)
+			case isAllowedTag(c.DataAtom):
+				sanitizeElement(c, base)
+				if c.DataAtom == atom.Img && getAttr(c, "src") == "" {
+					n.RemoveChild(c)
+					break
+				}
+				sanitizeChildren(c, base)
+			default:
+				// 許可されていないタグは子要素のみ残す
+				sanitizeChildren(c, base)
+				for gc := c.FirstChild; gc != nil; {
+					gcNext := gc.NextSibling
+					c.RemoveChild(gc)
+					n.InsertBefore(gc, c)
+					gc = gcNext
+				}
+				n.RemoveChild(c)
+			}
+		default:
+			// コメント・DOCTYPEなどは除去する
+			n.RemoveChild(c)
+		}
+		c = next
+	}
+}
+
+// isAllowedTag は出力に残すタグかどうかを判定します。
+func isAllowedTag(a atom.Atom) bool {
+	_, ok := allowedTags[a]
+	return ok
+}
+
+// sanitizeElement は許可タグの属性を許可リストで絞り込み、URLを解決します。
+func sanitizeElement(n *html.Node, base *url.URL) {
+	allowed := allowedTags[n.DataAtom]
+	var attrs []html.Attribute
+	for _, attr := range n.Attr {
+		key := strings.ToLower(attr.Key)
+		if attr.Namespace != "" || !(contains(allowed, key) || contains(globalAttrs, key)) {
+			continue
+		}
+		switch {
+		case urlAttrs[key]:
+			resolved, ok := resolveURL(attr.Val, base, key == "href")
+			if !ok {
+				continue
+			}
+			attr.Val = resolved
+		case key == "srcset":
+			srcset, ok := resolveSrcset(attr.Val, base)
+			if !ok {
+				continue
+			}
+			attr.Val = srcset
+		}
+		attr.Key = key
+		attrs = append(attrs, attr)
+	}
+
+	if n.DataAtom == atom.A {
+		attrs = append(attrs,
+			html.Attribute{Key: "rel", Val: "noopener noreferrer"},
+			html.Attribute{Key: "target", Val: "_blank"},
+		)
+	}
+	n.Attr = attrs
+}
+
+// resolveURL はURLを base を基準に解決し、安全なスキームかどうかを検証します。
+// allowMailto が true の場合は mailto: も許可します。
+func resolveURL(raw string, base *url.URL, allowMailto bool) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", false
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", false
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return u.String(), true
+	case "mailto":
+		return u.String(), allowMailto
+	case "":
+		// 基準URLがない場合の相対URLやページ内リンク（#...）
+		return u.String(), true
+	default:
+		return "", false
+	}
+}
+
+// resolveSrcset は srcset 属性の各候補URLを解決します。
+func resolveSrcset(value string, base *url.URL) (string, bool) {
+	var candidates []string
+	for _, candidate := range strings.Split(value, ",") {
+		fields := strings.Fields(candidate)
+		if len(fields) == 0 {
+			continue
+		}
+		resolved, ok := resolveURL(fields[0], base, false)
+		if !ok {
+			continue
+		}
+		fields[0] = resolved
+		candidates = append(candidates, strings.Join(fields, " "))
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	return strings.Join(candidates, ", "), true
+}
+
+func getAttr(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package sanitizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitize(t *testing.T) {
+	const base = "https://example.com/blog/post.html"
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "keeps safe markup",
+			input:    `<p>Hello <strong>world</strong></p>`,
+			expected: `<p>Hello <strong>world</strong></p>`,
+		},
+		{
+			name:     "removes script with its content",
+			input:    `<p>a</p><script>alert(1)</script><p>b</p>`,
+			expected: `<p>a</p><p>b</p>`,
+		},
+		{
+			name:     "removes event handlers and style",
+			input:    `<p onclick="alert(1)" style="color:red" title="t">x</p>`,
+			expected: `<p title="t">x</p>`,
+		},
+		{
+			name:     "removes javascript urls",
+			input:    `<a href="javascript:alert(1)">x</a>`,
+			expected: `<a rel="noopener noreferrer" target="_blank">x</a>`,
+		},
+		{
+			name:     "resolves relative links and adds rel",
+			input:    `<a href="../about">about</a>`,
+			expected: `<a href="https://example.com/about" rel="noopener noreferrer" target="_blank">about</a>`,
+		},
+		{
+			name:     "resolves relative image src and srcset",
+			input:    `<img src="img/a.png" srcset="img/a.png 1x, img/a@2x.png 2x" alt="a" onerror="x()">`,
+			expected: `<img src="https://example.com/blog/img/a.png" srcset="https://example.com/blog/img/a.png 1x, https://example.com/blog/img/a@2x.png 2x" alt="a"/>`,
+		},
+		{
+			name:     "drops images without safe src",
+			input:    `<img src="data:image/svg+xml;base64,AAAA"><p>x</p>`,
+			expected: `<p>x</p>`,
+		},
+		{
+			name:     "unwraps unknown tags",
+			input:    `<custom-tag><em>kept</em></custom-tag>`,
+			expected: `<em>kept</em>`,
+		},
+		{
+			name:     "removes iframes and comments",
+			input:    `<iframe src="https://evil.example"></iframe><!-- note --><p>x</p>`,
+			expected: `<p>x</p>`,
+		},
+		{
+			name:     "keeps mailto links",
+			input:    `<a href="mailto:me@example.com">mail</a>`,
+			expected: `<a href="mailto:me@example.com" rel="noopener noreferrer" target="_blank">mail</a>`,
+		},
+		{
+			name:     "escapes plain text",
+			input:    `1 < 2 & 3`,
+			expected: `1 &lt; 2 &amp; 3`,
+		},
+		{
+			name:     "returns empty for blank input",
+			input:    "   ",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Sanitize(tt.input, base))
+		})
+	}
+}
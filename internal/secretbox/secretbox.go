@@ -0,0 +1,77 @@
+// Package secretbox はフィードの認証情報など、データベースに保存する秘密情報の暗号化を提供します。
+//
+// 暗号化には AES-256-GCM を使用し、鍵は設定の暗号化キーから SHA-256 で導出します。
+// 暗号文はバージョン（1バイト）・ノンス・暗号化したデータの順に連結したものです。
+// 関連データ（例: フィードID）を指定すると、別の行へ暗号文を複製しても復号できません。
+package secretbox
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+// version は暗号文の形式のバージョンです。
+const version = 1
+
+var (
+	// ErrNoKey は暗号化キーが設定されていない場合に返されます。
+	ErrNoKey = errors.New("encryption key is not configured")
+	// ErrDecrypt は暗号文が不正な場合や、暗号化時と鍵・関連データが異なる場合に返されます。
+	ErrDecrypt = errors.New("failed to decrypt secret")
+)
+
+// Box は秘密情報の暗号化と復号を行います。
+// nil の Box は暗号化キーが設定されていないことを表し、すべての操作で ErrNoKey を返します。
+type Box struct {
+	aead cipher.AEAD
+}
+
+// New は key から導出した鍵で暗号化する Box を作成します。key が空の場合は nil を返します。
+func New(key string) (*Box, error) {
+	if key == "" {
+		return nil, nil
+	}
+	sum := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AEAD: %w", err)
+	}
+	return &Box{aead: aead}, nil
+}
+
+// Seal は plaintext を暗号化します。additionalData は復号時にも同じ値を指定する必要があります。
+func (b *Box) Seal(plaintext, additionalData []byte) ([]byte, error) {
+	if b == nil {
+		return nil, ErrNoKey
+	}
+	nonce := make([]byte, b.aead.NonceSize(), 1+b.aead.NonceSize()+len(plaintext)+b.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	out := append([]byte{version}, nonce...)
+	return b.aead.Seal(out, nonce, plaintext, additionalData), nil
+}
+
+// Open は Seal で暗号化したデータを復号します。
+func (b *Box) Open(ciphertext, additionalData []byte) ([]byte, error) {
+	if b == nil {
+		return nil, ErrNoKey
+	}
+	nonceSize := b.aead.NonceSize()
+	if len(ciphertext) < 1+nonceSize || ciphertext[0] != version {
+		return nil, ErrDecrypt
+	}
+	plaintext, err := b.aead.Open(nil, ciphertext[1:1+nonceSize], ciphertext[1+nonceSize:], additionalData)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,63 @@
+package secretbox
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBox_SealOpen(t *testing.T) {
+	box, err := New("test-encryption-key")
+	require.NoError(t, err)
+
+	sealed, err := box.Seal([]byte("secret"), []byte("feed-1"))
+	require.NoError(t, err)
+	assert.False(t, bytes.Contains(sealed, []byte("secret")))
+
+	opened, err := box.Open(sealed, []byte("feed-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(opened))
+
+	// 同じ平文でもノンスが異なるため暗号文は一致しない
+	again, err := box.Seal([]byte("secret"), []byte("feed-1"))
+	require.NoError(t, err)
+	assert.NotEqual(t, sealed, again)
+}
+
+func TestBox_OpenErrors(t *testing.T) {
+	box, err := New("test-encryption-key")
+	require.NoError(t, err)
+	sealed, err := box.Seal([]byte("secret"), []byte("feed-1"))
+	require.NoError(t, err)
+
+	// 関連データが異なる
+	_, err = box.Open(sealed, []byte("feed-2"))
+	assert.ErrorIs(t, err, ErrDecrypt)
+
+	// 鍵が異なる
+	other, err := New("other-encryption-key")
+	require.NoError(t, err)
+	_, err = other.Open(sealed, []byte("feed-1"))
+	assert.ErrorIs(t, err, ErrDecrypt)
+
+	// 改ざん・切り詰め
+	tampered := append([]byte(nil), sealed...)
+	tampered[len(tampered)-1] ^= 1
+	_, err = box.Open(tampered, []byte("feed-1"))
+	assert.ErrorIs(t, err, ErrDecrypt)
+	_, err = box.Open(sealed[:5], []byte("feed-1"))
+	assert.ErrorIs(t, err, ErrDecrypt)
+}
+
+func TestBox_NoKey(t *testing.T) {
+	box, err := New("")
+	require.NoError(t, err)
+	assert.Nil(t, box)
+
+	_, err = box.Seal([]byte("secret"), nil)
+	assert.ErrorIs(t, err, ErrNoKey)
+	_, err = box.Open([]byte("data"), nil)
+	assert.ErrorIs(t, err, ErrNoKey)
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"feedapp/internal/model"
+	"feedapp/internal/repository"
+)
+
+var ErrAnnotationNotFound = errors.New("annotation not found")
+
+// AnnotationService は記事の注釈に関するビジネスロジックを定義するインターフェースです。
+// 注釈はユーザーごとに管理し、ユーザーが参照できる記事にのみ付けられます。
+type AnnotationService interface {
+	GetAllAnnotations(userID string) ([]model.Annotation, error)
+	GetArticleAnnotations(userID, articleID string) ([]model.Annotation, error)
+	GetAnnotation(userID, articleID, id string) (model.Annotation, error)
+	CreateAnnotation(userID, articleID string, annotation model.Annotation) (model.Annotation, error)
+	UpdateAnnotation(userID, articleID, id string, annotation model.Annotation) (model.Annotation, error)
+	DeleteAnnotation(userID, articleID, id string) error
+	ExportArticleAnnotations(userID, articleID string) (string, error)
+}
+
+// annotationService は AnnotationService インターフェースの実装です。
+type annotationService struct {
+	annotationRepo repository.AnnotationRepository
+	articleRepo    repository.ArticleRepository
+}
+
+// NewAnnotationService は新しい annotationService インスタンスを作成します。
+func NewAnnotationService(annotationRepo repository.AnnotationRepository, articleRepo repository.ArticleRepository) AnnotationService {
+	return &annotationService{
+		annotationRepo: annotationRepo,
+		articleRepo:    articleRepo,
+	}
+}
+
+// GetAllAnnotations はユーザーがすべての記事に付けた注釈を新しい順に取得します。
+func (s *annotationService) GetAllAnnotations(userID string) ([]model.Annotation, error) {
+	return s.annotationRepo.GetAll(userID)
+}
+
+// GetArticleAnnotations は記事に付けた注釈を作成順に取得します。
+func (s *annotationService) GetArticleAnnotations(userID, articleID string) ([]model.Annotation, error) {
+	if _, err := s.getArticle(userID, articleID); err != nil {
+		return nil, err
+	}
+	return s.annotationRepo.GetByArticleID(userID, articleID)
+}
+
+func (s *annotationService) GetAnnotation(userID, articleID, id string) (model.Annotation, error) {
+	annotation, err := s.annotationRepo.GetByID(userID, articleID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.Annotation{}, ErrAnnotationNotFound
+		}
+		return model.Annotation{}, err
+	}
+	return annotation, nil
+}
+
+func (s *annotationService) CreateAnnotation(userID, articleID string, annotation model.Annotation) (model.Annotation, error) {
+	if _, err := s.getArticle(userID, articleID); err != nil {
+		return model.Annotation{}, err
+	}
+	now := time.Now()
+	annotation.ID = model.GenerateUUID()
+	annotation.UserID = userID
+	annotation.ArticleID = articleID
+	annotation.CreatedAt = now
+	annotation.UpdatedAt = now
+	normalizeAnnotation(&annotation)
+	return s.annotationRepo.Create(annotation)
+}
+
+func (s *annotationService) UpdateAnnotation(userID, articleID, id string, annotation model.Annotation) (model.Annotation, error) {
+	annotation.ID = id
+	annotation.UserID = userID
+	annotation.ArticleID = articleID
+	annotation.UpdatedAt = time.Now()
+	normalizeAnnotation(&annotation)
+	updated, err := s.annotationRepo.Update(annotation)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.Annotation{}, ErrAnnotationNotFound
+		}
+		return model.Annotation{}, err
+	}
+	return updated, nil
+}
+
+func (s *annotationService) DeleteAnnotation(userID, articleID, id string) error {
+	err := s.annotationRepo.Delete(userID, articleID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrAnnotationNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// ExportArticleAnnotations は記事に付けた注釈を Markdown として出力します。
+// 記事のタイトルを見出し、URLをリンクとし、注釈ごとに引用した文字列を引用ブロック、メモを本文として並べます。
+func (s *annotationService) ExportArticleAnnotations(userID, articleID string) (string, error) {
+	article, err := s.getArticle(userID, articleID)
+	if err != nil {
+		return "", err
+	}
+	annotations, err := s.annotationRepo.GetByArticleID(userID, articleID)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	title := strings.TrimSpace(article.Title)
+	if title == "" {
+		title = article.URL
+	}
+	fmt.Fprintf(&b, "# %s\n\n<%s>\n", markdownLine(title), article.URL)
+	for _, annotation := range annotations {
+		b.WriteString("\n")
+		for _, line := range strings.Split(strings.TrimSpace(annotation.Selector.Exact), "\n") {
+			fmt.Fprintf(&b, "> %s\n", strings.TrimRight(line, " \t\r"))
+		}
+		if body := strings.TrimSpace(annotation.Body); body != "" {
+			fmt.Fprintf(&b, "\n%s\n", body)
+		}
+	}
+	return b.String(), nil
+}
+
+// getArticle はユーザーが参照できる記事を取得します。
+func (s *annotationService) getArticle(userID, articleID string) (model.Article, error) {
+	article, err := s.articleRepo.GetByID(userID, articleID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.Article{}, ErrArticleNotFound
+		}
+		return model.Article{}, err
+	}
+	return article, nil
+}
+
+// normalizeAnnotation はセレクターの種別と色の既定値を設定します。
+func normalizeAnnotation(annotation *model.Annotation) {
+	annotation.Selector.Type = model.TextQuoteSelectorType
+	if annotation.Color == "" {
+		annotation.Color = model.AnnotationColorYellow
+	}
+}
+
+// markdownLine は見出しに使う文字列の改行を空白に置き換えます。
+func markdownLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
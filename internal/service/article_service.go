@@ -1,54 +1,148 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"feedapp/internal/events"
+	"feedapp/internal/extractor"
+	"feedapp/internal/imageproxy"
 	"feedapp/internal/model"
 	"feedapp/internal/repository"
+	"fmt"
+	"log"
 )
 
 var ErrArticleNotFound = errors.New("article not found")
 
+// ErrExtractionFailed は記事ページからの本文抽出に失敗した場合に返されます。
+var ErrExtractionFailed = errors.New("failed to extract article content")
+
 // ArticleService は記事関連のビジネスロジックを定義するインターフェースです。
+// 記事はユーザーが購読しているフィードのもののみ参照でき、既読・後で見る・再生状態はユーザーごとに管理します。
+// 既読・後で見る状態を変更すると、同じユーザーの他のクライアントへ article.updated イベントを発行します。
+// UpdateArticleStatus で「後で見る」に追加した記事は、元ページのスナップショットの取得を要求します。
+// 返す記事の本文・要約・画像URLの画像は、保存内容は変更せずに画像プロキシのURLへ書き換えます。
 type ArticleService interface {
-	GetAllArticles() ([]model.Article, error)
-	GetArticleByID(id string) (model.Article, error)
-	UpdateArticleStatus(id string, isRead, isLater bool) (model.Article, error)
-	GetLaterArticles() ([]model.Article, error)
+	GetAllArticles(userID string, filter model.ArticleFilter) ([]model.Article, error)
+	GetArticleByID(userID, id string) (model.Article, error)
+	GetArticleItemIDs(userID string, filter model.ArticleFilter) ([]int64, error)
+	GetArticlesByFeed(userID, feedID string) ([]model.Article, error)
+	GetArticlesByFolder(userID, folderID string) ([]model.Article, error)
+	UpdateArticleStatus(userID, id string, isRead, isLater bool) (model.Article, error)
+	UpdateArticleMedia(userID, id string, update MediaStateUpdate) (model.Article, error)
+	UpdateArticleStates(userID string, ids []string, update ArticleStateUpdate) error
+	MarkArticlesRead(userID string, filter model.ArticleFilter) (int64, error)
+	GetLaterArticles(userID string) ([]model.Article, error)
+	ExtractArticleContent(ctx context.Context, userID, id string) (model.Article, error)
+}
+
+// MediaStateUpdate は記事の添付メディア再生状態の更新内容です。
+// nil のフィールドは現在の値を維持します。
+type MediaStateUpdate struct {
+	PlaybackPosition *int  // 再生位置（秒）
+	Played           *bool // 再生済みフラグ
+	Downloaded       *bool // ダウンロード済みフラグ
+}
+
+// ArticleStateUpdate は複数記事の既読・後で見る状態の一括更新内容です。
+// nil のフィールドは現在の値を維持します。
+type ArticleStateUpdate struct {
+	IsRead  *bool // 既読フラグ
+	IsLater *bool // 後で見るフラグ
 }
 
 // articleService は ArticleService インターフェースの実装です。
 type articleService struct {
 	articleRepo repository.ArticleRepository
+	feedRepo    repository.FeedRepository
+	folderRepo  repository.FolderRepository
+	extractor   *extractor.Extractor
+	events      *events.Broker
+	snapshots   SnapshotService
+	images      *imageproxy.Signer
 }
 
 // NewArticleService は新しい articleService インスタンスを作成します。
-func NewArticleService(repo repository.ArticleRepository) ArticleService {
+// images が nil の場合は画像URLを書き換えません。
+func NewArticleService(repo repository.ArticleRepository, feedRepo repository.FeedRepository, folderRepo repository.FolderRepository, extractor *extractor.Extractor, broker *events.Broker, snapshots SnapshotService, images *imageproxy.Signer) ArticleService {
 	return &articleService{
 		articleRepo: repo,
+		feedRepo:    feedRepo,
+		folderRepo:  folderRepo,
+		extractor:   extractor,
+		events:      broker,
+		snapshots:   snapshots,
+		images:      images,
 	}
 }
 
-func (s *articleService) GetAllArticles() ([]model.Article, error) {
-	articles, err := s.articleRepo.GetAll()
+// GetAllArticles は絞り込み条件に一致する記事を取得します。
+// 購読していないフィードや他のユーザーのフォルダで絞り込んだ場合は ErrFeedNotFound / ErrFolderNotFound を返します。
+func (s *articleService) GetAllArticles(userID string, filter model.ArticleFilter) ([]model.Article, error) {
+	if err := s.checkFilter(userID, filter); err != nil {
+		return nil, err
+	}
+	articles, err := s.articleRepo.GetAll(userID, filter)
 	if err != nil {
 		return nil, err
 	}
-	return articles, nil
+	return s.proxyImagesAll(articles), nil
 }
 
-func (s *articleService) GetArticleByID(id string) (model.Article, error) {
-	article, err := s.articleRepo.GetByID(id)
+func (s *articleService) GetArticleByID(userID, id string) (model.Article, error) {
+	article, err := s.articleRepo.GetByID(userID, id)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return model.Article{}, ErrArticleNotFound
 		}
 		return model.Article{}, err
 	}
-	return article, nil
+	return s.proxyImages(article), nil
+}
+
+// GetArticleItemIDs は絞り込み条件に一致する記事の連番（Article.ItemID）を取得します。
+func (s *articleService) GetArticleItemIDs(userID string, filter model.ArticleFilter) ([]int64, error) {
+	if err := s.checkFilter(userID, filter); err != nil {
+		return nil, err
+	}
+	return s.articleRepo.GetItemIDs(userID, filter)
+}
+
+// GetArticlesByFeed は指定フィードが配信した記事を取得します。
+// 複数のフィードから配信された記事は、それぞれのフィードの記事として返されます。
+// 購読していないフィードの場合は ErrFeedNotFound を返します。
+func (s *articleService) GetArticlesByFeed(userID, feedID string) ([]model.Article, error) {
+	if _, err := s.feedRepo.GetByID(userID, feedID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrFeedNotFound
+		}
+		return nil, err
+	}
+	articles, err := s.articleRepo.GetByFeedID(userID, feedID)
+	if err != nil {
+		return nil, err
+	}
+	return s.proxyImagesAll(articles), nil
+}
+
+// GetArticlesByFolder は指定フォルダ内のフィードが配信した記事を取得します。
+// 他のユーザーのフォルダの場合は ErrFolderNotFound を返します。
+func (s *articleService) GetArticlesByFolder(userID, folderID string) ([]model.Article, error) {
+	if _, err := s.folderRepo.GetByID(userID, folderID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrFolderNotFound
+		}
+		return nil, err
+	}
+	articles, err := s.articleRepo.GetByFolderID(userID, folderID)
+	if err != nil {
+		return nil, err
+	}
+	return s.proxyImagesAll(articles), nil
 }
 
-func (s *articleService) UpdateArticleStatus(id string, isRead, isLater bool) (model.Article, error) {
-	article, err := s.articleRepo.GetByID(id)
+func (s *articleService) UpdateArticleStatus(userID, id string, isRead, isLater bool) (model.Article, error) {
+	article, err := s.articleRepo.GetByID(userID, id)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return model.Article{}, ErrArticleNotFound
@@ -56,20 +150,188 @@ func (s *articleService) UpdateArticleStatus(id string, isRead, isLater bool) (m
 		return model.Article{}, err
 	}
 
+	wasLater := article.IsLater
 	article.IsRead = isRead
 	article.IsLater = isLater
 
-	updatedArticle, err := s.articleRepo.Update(article)
+	updatedArticle, err := s.updateState(userID, article)
 	if err != nil {
 		return model.Article{}, err
 	}
-	return updatedArticle, nil
+	if isLater && !wasLater && s.snapshots.Request(updatedArticle) {
+		updatedArticle.SnapshotStatus = model.SnapshotStatusPending
+	}
+	s.publishUpdated(userID, events.ArticleUpdatedData{
+		ArticleIDs: []string{updatedArticle.ID},
+		IsRead:     &updatedArticle.IsRead,
+		IsLater:    &updatedArticle.IsLater,
+	})
+	return s.proxyImages(updatedArticle), nil
+}
+
+// UpdateArticleMedia は記事の添付メディア再生状態を更新します。
+// 複数のクライアントから再生位置を同期するため、指定されたフィールドのみを更新します。
+func (s *articleService) UpdateArticleMedia(userID, id string, update MediaStateUpdate) (model.Article, error) {
+	article, err := s.articleRepo.GetByID(userID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.Article{}, ErrArticleNotFound
+		}
+		return model.Article{}, err
+	}
+
+	if update.PlaybackPosition != nil {
+		article.PlaybackPosition = *update.PlaybackPosition
+	}
+	if update.Played != nil {
+		article.Played = *update.Played
+	}
+	if update.Downloaded != nil {
+		article.Downloaded = *update.Downloaded
+	}
+
+	updatedArticle, err := s.updateState(userID, article)
+	if err != nil {
+		return model.Article{}, err
+	}
+	return s.proxyImages(updatedArticle), nil
+}
+
+// UpdateArticleStates は複数の記事の既読・後で見る状態をまとめて更新します。
+// 参照できない記事は無視します。
+func (s *articleService) UpdateArticleStates(userID string, ids []string, update ArticleStateUpdate) error {
+	if err := s.articleRepo.UpdateStates(userID, ids, update.IsRead, update.IsLater); err != nil {
+		return err
+	}
+	if len(ids) > 0 {
+		s.publishUpdated(userID, events.ArticleUpdatedData{ArticleIDs: ids, IsRead: update.IsRead, IsLater: update.IsLater})
+	}
+	return nil
 }
 
-func (s *articleService) GetLaterArticles() ([]model.Article, error) {
-	articles, err := s.articleRepo.GetLaterArticles()
+// MarkArticlesRead は絞り込み条件に一致する記事をまとめて既読にし、既読にした件数を返します。
+func (s *articleService) MarkArticlesRead(userID string, filter model.ArticleFilter) (int64, error) {
+	if err := s.checkFilter(userID, filter); err != nil {
+		return 0, err
+	}
+	count, err := s.articleRepo.MarkRead(userID, filter)
+	if err != nil {
+		return 0, err
+	}
+	if count > 0 {
+		isRead := true
+		data := events.ArticleUpdatedData{FeedID: filter.FeedID, FolderID: filter.FolderID, IsRead: &isRead}
+		if !filter.Until.IsZero() {
+			data.Until = &filter.Until
+		}
+		s.publishUpdated(userID, data)
+	}
+	return count, nil
+}
+
+func (s *articleService) GetLaterArticles(userID string) ([]model.Article, error) {
+	articles, err := s.articleRepo.GetLaterArticles(userID)
 	if err != nil {
 		return nil, err
 	}
-	return articles, nil
+	return s.proxyImagesAll(articles), nil
+}
+
+// ExtractArticleContent は記事ページから本文を抽出し、記事の本文を置き換えます。
+// 抽出前の本文は要約が空の場合のみ要約として残します。
+// 記事本体は共有のため、抽出した本文は同じ記事を参照するすべてのユーザーに反映されます。
+// 記事ページの取得には、記事を取り込んだフィードのプロキシを使用します。
+// フィードの認証情報は、そのフィードのみの記事（IsPrivate）の場合にのみ付与し、
+// 他のユーザーと共有する記事には認証情報を付けて取得した本文を保存しません。
+func (s *articleService) ExtractArticleContent(ctx context.Context, userID, id string) (model.Article, error) {
+	article, err := s.articleRepo.GetByID(userID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.Article{}, ErrArticleNotFound
+		}
+		return model.Article{}, err
+	}
+	feed, err := s.feedRepo.GetSourceByID(article.FeedID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return model.Article{}, err
+	}
+	if !article.IsPrivate {
+		feed.Auth = nil
+	}
+	if ctx, err = withFeedRequest(ctx, feed); err != nil {
+		return model.Article{}, fmt.Errorf("%w: %v", ErrExtractionFailed, err)
+	}
+
+	if err := extractContent(ctx, s.extractor, &article); err != nil {
+		return model.Article{}, fmt.Errorf("%w: %v", ErrExtractionFailed, err)
+	}
+
+	if err := s.articleRepo.UpdateExtractedContent(article); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.Article{}, ErrArticleNotFound
+		}
+		return model.Article{}, err
+	}
+	return s.proxyImages(article), nil
+}
+
+// updateState はユーザーごとの記事の状態を保存します。
+func (s *articleService) updateState(userID string, article model.Article) (model.Article, error) {
+	updatedArticle, err := s.articleRepo.UpdateState(userID, article)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.Article{}, ErrArticleNotFound
+		}
+		return model.Article{}, err
+	}
+	return updatedArticle, nil
+}
+
+// proxyImages は出力する記事の本文・要約・画像URLの画像を画像プロキシのURLに書き換えます。
+func (s *articleService) proxyImages(article model.Article) model.Article {
+	if s.images == nil {
+		return article
+	}
+	article.Content = s.images.RewriteHTML(article.Content)
+	article.Summary = s.images.RewriteHTML(article.Summary)
+	if article.ImageURL != "" {
+		article.ImageURL = s.images.URL(article.ImageURL)
+	}
+	return article
+}
+
+// proxyImagesAll は出力する記事の一覧の画像を画像プロキシのURLに書き換えます。
+func (s *articleService) proxyImagesAll(articles []model.Article) []model.Article {
+	for i := range articles {
+		articles[i] = s.proxyImages(articles[i])
+	}
+	return articles
+}
+
+// publishUpdated はユーザーのクライアントへ article.updated イベントを発行します。
+func (s *articleService) publishUpdated(userID string, data events.ArticleUpdatedData) {
+	if err := s.events.Publish([]string{userID}, events.ArticleUpdated, data); err != nil {
+		log.Printf("Failed to publish article update: %v", err)
+	}
+}
+
+// checkFilter は絞り込み条件のフィードとフォルダがユーザーのものであることを確認します。
+func (s *articleService) checkFilter(userID string, filter model.ArticleFilter) error {
+	if filter.FeedID != "" {
+		if _, err := s.feedRepo.GetByID(userID, filter.FeedID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrFeedNotFound
+			}
+			return err
+		}
+	}
+	if filter.FolderID != "" {
+		if _, err := s.folderRepo.GetByID(userID, filter.FolderID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrFolderNotFound
+			}
+			return err
+		}
+	}
+	return nil
 }
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"feedapp/internal/events"
+	"feedapp/internal/extractor"
+	"feedapp/internal/fetcher"
+	"feedapp/internal/model"
+)
+
+func TestArticleService_ExtractArticleContent_Credentials(t *testing.T) {
+	server := articlePageServer(t)
+	privateFeed := model.Feed{ID: "feed-private", URL: server.URL + "/feed", PluginType: "rss", Auth: &model.FeedAuth{Token: "secret"}}
+	feedRepo := &fakeFeedRepository{feeds: map[string]model.Feed{privateFeed.ID: privateFeed}}
+
+	tests := []struct {
+		name      string
+		isPrivate bool
+		expected  string
+	}{
+		// 他のユーザーと共有する記事は、フィードの認証情報を付けずに取得する
+		{name: "shared article", isPrivate: false, expected: "public teaser"},
+		{name: "private article", isPrivate: true, expected: "subscriber-only"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			articleRepo := newFakeArticleRepository()
+			articleRepo.articles = []model.Article{{ID: "article-1", FeedID: privateFeed.ID, URL: server.URL + "/article", IsPrivate: tt.isPrivate}}
+			s := NewArticleService(articleRepo, feedRepo, nil, extractor.New(fetcher.NewClient()), events.NewBroker(0), nil, nil)
+
+			article, err := s.ExtractArticleContent(context.Background(), "user-a", "article-1")
+			require.NoError(t, err)
+			assert.Contains(t, article.Content, tt.expected)
+			assert.Contains(t, articleRepo.articles[0].Content, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,372 @@
+package service
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"feedapp/internal/model"
+	"feedapp/internal/repository"
+)
+
+var (
+	// ErrEmailAlreadyExists はメールアドレスが登録済みの場合に返されます。
+	ErrEmailAlreadyExists = errors.New("email already exists")
+	// ErrInvalidCredentials はメールアドレスまたはパスワードが正しくない場合に返されます。
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrUnauthenticated はセッションやAPIトークンが無効な場合に返されます。
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrAPITokenNotFound はAPIトークンが見つからない場合に返されます。
+	ErrAPITokenNotFound = errors.New("api token not found")
+	// ErrSignupDisabled は新規ユーザー登録が無効な状態で未登録の外部アカウントがログインした場合に返されます。
+	ErrSignupDisabled = errors.New("signup is disabled")
+	// ErrEmailRequired は外部アカウントのメールアドレスが取得できない場合に返されます。
+	ErrEmailRequired = errors.New("email is required")
+)
+
+const (
+	// DefaultSessionTTL はセッションの有効期限が未設定の場合に使用する期間です。
+	DefaultSessionTTL = 30 * 24 * time.Hour
+	// APITokenPrefix は個人APIトークンの接頭辞です。セッショントークンと区別するために使用します。
+	APITokenPrefix = "fa_"
+	// tokenBytes はセッショントークン・APIトークンのランダム部分のバイト数です。
+	tokenBytes = 32
+)
+
+// AuthService は認証関連のビジネスロジックを定義するインターフェースです。
+type AuthService interface {
+	Signup(email, password, name string) (model.User, error)
+	Login(email, password string) (model.User, string, error)
+	LoginWithIdentity(identity ExternalIdentity, allowSignup bool) (model.User, string, error)
+	Logout(sessionToken string) error
+	AuthenticateSession(sessionToken string) (model.User, error)
+	AuthenticateAPIToken(token string) (model.User, error)
+	GetAPITokens(userID string) ([]model.APIToken, error)
+	CreateAPIToken(userID, name string) (model.APIToken, error)
+	DeleteAPIToken(userID, id string) error
+	SetFeverPassword(userID, password string) error
+	DeleteFeverPassword(userID string) error
+	AuthenticateFeverKey(apiKey string) (model.User, error)
+}
+
+// ExternalIdentity は OpenID Connect などの外部認証で確認されたアカウントの情報です。
+type ExternalIdentity struct {
+	Issuer        string // 発行者
+	Subject       string // 発行者内でのアカウントの識別子（sub クレーム）
+	Email         string // メールアドレス
+	EmailVerified bool   // メールアドレスが発行者によって確認済みか
+	Name          string // 表示名
+}
+
+// authService は AuthService インターフェースの実装です。
+type authService struct {
+	userRepo     repository.UserRepository
+	sessionRepo  repository.SessionRepository
+	apiTokenRepo repository.APITokenRepository
+	sessionTTL   time.Duration
+}
+
+// NewAuthService は新しい authService インスタンスを作成します。
+// sessionTTL が0以下の場合は DefaultSessionTTL を使用します。
+func NewAuthService(userRepo repository.UserRepository, sessionRepo repository.SessionRepository, apiTokenRepo repository.APITokenRepository, sessionTTL time.Duration) AuthService {
+	if sessionTTL <= 0 {
+		sessionTTL = DefaultSessionTTL
+	}
+	return &authService{
+		userRepo:     userRepo,
+		sessionRepo:  sessionRepo,
+		apiTokenRepo: apiTokenRepo,
+		sessionTTL:   sessionTTL,
+	}
+}
+
+// Signup はパスワードを bcrypt でハッシュ化してユーザーを登録します。
+func (s *authService) Signup(email, password, name string) (model.User, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	user := model.User{
+		ID:           model.GenerateUUID(),
+		Email:        strings.TrimSpace(email),
+		Name:         strings.TrimSpace(name),
+		PasswordHash: string(hash),
+		CreatedAt:    time.Now(),
+	}
+	createdUser, err := s.userRepo.Create(user)
+	if err != nil {
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			return model.User{}, ErrEmailAlreadyExists
+		}
+		return model.User{}, err
+	}
+	return createdUser, nil
+}
+
+// Login はメールアドレスとパスワードを検証し、新しいセッションを作成します。
+// 戻り値の文字列は Cookie に設定するセッショントークンです。
+func (s *authService) Login(email, password string) (model.User, string, error) {
+	user, err := s.userRepo.GetByEmail(strings.TrimSpace(email))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.User{}, "", ErrInvalidCredentials
+		}
+		return model.User{}, "", err
+	}
+	if user.PasswordHash == "" || bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return model.User{}, "", ErrInvalidCredentials
+	}
+
+	token, err := s.createSession(user.ID)
+	if err != nil {
+		return model.User{}, "", err
+	}
+	return user, token, nil
+}
+
+// LoginWithIdentity は外部認証のアカウントに対応するユーザーでログインし、新しいセッションを作成します。
+//
+// アカウントに対応するユーザーがいない場合は以下のように扱います。
+//   - 確認済みのメールアドレスが既存ユーザーと一致する場合は、そのユーザーにアカウントを関連付ける
+//   - 未確認のメールアドレスが既存ユーザーと一致する場合は ErrEmailAlreadyExists を返す
+//   - 一致するユーザーがいない場合は、allowSignup が true であればユーザーを作成し、false であれば ErrSignupDisabled を返す
+func (s *authService) LoginWithIdentity(identity ExternalIdentity, allowSignup bool) (model.User, string, error) {
+	user, err := s.userRepo.GetByIdentity(identity.Issuer, identity.Subject)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			return model.User{}, "", err
+		}
+		user, err = s.linkIdentity(identity, allowSignup)
+		if err != nil {
+			return model.User{}, "", err
+		}
+	}
+
+	token, err := s.createSession(user.ID)
+	if err != nil {
+		return model.User{}, "", err
+	}
+	return user, token, nil
+}
+
+// linkIdentity は未登録の外部認証のアカウントを既存ユーザーに関連付けるか、新しいユーザーを作成します。
+func (s *authService) linkIdentity(identity ExternalIdentity, allowSignup bool) (model.User, error) {
+	email := strings.TrimSpace(identity.Email)
+	if email == "" {
+		return model.User{}, ErrEmailRequired
+	}
+
+	existingUser, err := s.userRepo.GetByEmail(email)
+	if err == nil {
+		if !identity.EmailVerified {
+			return model.User{}, ErrEmailAlreadyExists
+		}
+		if err := s.userRepo.AddIdentity(existingUser.ID, identity.Issuer, identity.Subject); err != nil {
+			return model.User{}, err
+		}
+		return existingUser, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return model.User{}, err
+	}
+
+	if !allowSignup {
+		return model.User{}, ErrSignupDisabled
+	}
+	createdUser, err := s.userRepo.CreateWithIdentity(model.User{
+		ID:        model.GenerateUUID(),
+		Email:     email,
+		Name:      strings.TrimSpace(identity.Name),
+		CreatedAt: time.Now(),
+	}, identity.Issuer, identity.Subject)
+	if err != nil {
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			return model.User{}, ErrEmailAlreadyExists
+		}
+		return model.User{}, err
+	}
+	return createdUser, nil
+}
+
+// Logout はセッションを削除します。
+func (s *authService) Logout(sessionToken string) error {
+	return s.sessionRepo.Delete(hashToken(sessionToken))
+}
+
+// AuthenticateSession はセッショントークンに対応するユーザーを返します。
+func (s *authService) AuthenticateSession(sessionToken string) (model.User, error) {
+	session, err := s.sessionRepo.GetByTokenHash(hashToken(sessionToken))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.User{}, ErrUnauthenticated
+		}
+		return model.User{}, err
+	}
+	return s.getUser(session.UserID)
+}
+
+// AuthenticateAPIToken はAPIトークンに対応するユーザーを返し、トークンの最終使用日時を更新します。
+func (s *authService) AuthenticateAPIToken(token string) (model.User, error) {
+	if !strings.HasPrefix(token, APITokenPrefix) {
+		return model.User{}, ErrUnauthenticated
+	}
+	apiToken, err := s.apiTokenRepo.GetByTokenHash(hashToken(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.User{}, ErrUnauthenticated
+		}
+		return model.User{}, err
+	}
+	if err := s.apiTokenRepo.UpdateLastUsed(apiToken.ID, time.Now()); err != nil {
+		log.Printf("Failed to update last used time of api token %s: %v", apiToken.ID, err)
+	}
+	return s.getUser(apiToken.UserID)
+}
+
+func (s *authService) GetAPITokens(userID string) ([]model.APIToken, error) {
+	tokens, err := s.apiTokenRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// CreateAPIToken は新しいAPIトークンを発行します。
+// トークン文字列は戻り値の Token にのみ含まれ、再取得することはできません。
+func (s *authService) CreateAPIToken(userID, name string) (model.APIToken, error) {
+	secret, err := generateToken()
+	if err != nil {
+		return model.APIToken{}, err
+	}
+	token := APITokenPrefix + secret
+
+	createdToken, err := s.apiTokenRepo.Create(model.APIToken{
+		ID:        model.GenerateUUID(),
+		UserID:    userID,
+		Name:      strings.TrimSpace(name),
+		TokenHash: hashToken(token),
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return model.APIToken{}, err
+	}
+	createdToken.Token = token
+	return createdToken, nil
+}
+
+func (s *authService) DeleteAPIToken(userID, id string) error {
+	err := s.apiTokenRepo.Delete(userID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrAPITokenNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// SetFeverPassword は Fever API 用のパスワードを設定します。
+//
+// Fever API のクライアントは MD5(メールアドレス:パスワード) を api_key として送信するため、
+// ログインパスワードとは別のパスワードを使用し、api_key の SHA-256 ハッシュのみを保存します。
+func (s *authService) SetFeverPassword(userID, password string) error {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return err
+	}
+	return s.userRepo.UpdateFeverKeyHash(userID, hashToken(FeverAPIKey(user.Email, password)))
+}
+
+// DeleteFeverPassword は Fever API 用のパスワードを削除し、Fever API を無効にします。
+func (s *authService) DeleteFeverPassword(userID string) error {
+	err := s.userRepo.UpdateFeverKeyHash(userID, "")
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrUnauthenticated
+		}
+		return err
+	}
+	return nil
+}
+
+// AuthenticateFeverKey は Fever API の api_key に対応するユーザーを返します。
+func (s *authService) AuthenticateFeverKey(apiKey string) (model.User, error) {
+	apiKey = strings.ToLower(strings.TrimSpace(apiKey))
+	if apiKey == "" {
+		return model.User{}, ErrUnauthenticated
+	}
+	user, err := s.userRepo.GetByFeverKeyHash(hashToken(apiKey))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.User{}, ErrUnauthenticated
+		}
+		return model.User{}, err
+	}
+	return user, nil
+}
+
+// FeverAPIKey は Fever API の api_key（MD5(email:password) の16進数表記）を返します。
+func FeverAPIKey(email, password string) string {
+	sum := md5.Sum([]byte(email + ":" + password))
+	return hex.EncodeToString(sum[:])
+}
+
+// createSession は新しいセッションを作成し、セッショントークンを返します。
+// あわせて期限切れのセッションを削除します。
+func (s *authService) createSession(userID string) (string, error) {
+	token, err := generateToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	if err := s.sessionRepo.DeleteExpired(now); err != nil {
+		log.Printf("Failed to delete expired sessions: %v", err)
+	}
+	if err := s.sessionRepo.Create(model.Session{
+		TokenHash: hashToken(token),
+		UserID:    userID,
+		ExpiresAt: now.Add(s.sessionTTL),
+		CreatedAt: now,
+	}); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// getUser はセッションやトークンの所有者を取得します。
+// ユーザーが削除されている場合は ErrUnauthenticated を返します。
+func (s *authService) getUser(id string) (model.User, error) {
+	user, err := s.userRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.User{}, ErrUnauthenticated
+		}
+		return model.User{}, err
+	}
+	return user, nil
+}
+
+// generateToken は暗号論的に安全なランダムトークンを生成します。
+func generateToken() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken はトークンを保存・検索用の SHA-256 ハッシュに変換します。
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
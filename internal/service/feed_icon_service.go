@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"time"
+
+	"feedapp/internal/favicon"
+	"feedapp/internal/model"
+	"feedapp/internal/repository"
+)
+
+// ErrFeedIconNotFound はフィードのアイコンが見つかっていない場合に返されます。
+var ErrFeedIconNotFound = errors.New("feed icon not found")
+
+// feedIconRefreshInterval はフィードのアイコンを再確認する間隔です。
+const feedIconRefreshInterval = 7 * 24 * time.Hour
+
+// FeedIconService はフィードのアイコン（ファビコン）に関するビジネスロジックを定義するインターフェースです。
+//
+// アイコンはフィードの取得時に feedIconRefreshInterval ごとに確認し、
+// フィードが指定するアイコン・サイトの <link rel="icon">・/favicon.ico の順に探して保存します。
+type FeedIconService interface {
+	GetIcon(userID, feedID string) (model.FeedIcon, error)
+	GetIcons(userID string) ([]model.FeedIcon, error)
+	RefreshIcon(ctx context.Context, feed model.Feed, siteURL, iconURL string)
+}
+
+// feedIconService は FeedIconService インターフェースの実装です。
+type feedIconService struct {
+	iconRepo repository.FeedIconRepository
+	feedRepo repository.FeedRepository
+	finder   *favicon.Finder
+}
+
+// NewFeedIconService は新しい feedIconService インスタンスを作成します。
+func NewFeedIconService(iconRepo repository.FeedIconRepository, feedRepo repository.FeedRepository, finder *favicon.Finder) FeedIconService {
+	return &feedIconService{
+		iconRepo: iconRepo,
+		feedRepo: feedRepo,
+		finder:   finder,
+	}
+}
+
+// GetIcon はユーザーが購読しているフィードのアイコンを取得します。
+// 購読していないフィードの場合は ErrFeedNotFound、アイコンがない場合は ErrFeedIconNotFound を返します。
+func (s *feedIconService) GetIcon(userID, feedID string) (model.FeedIcon, error) {
+	if _, err := s.feedRepo.GetByID(userID, feedID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.FeedIcon{}, ErrFeedNotFound
+		}
+		return model.FeedIcon{}, err
+	}
+	icon, err := s.iconRepo.GetByFeedID(feedID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.FeedIcon{}, ErrFeedIconNotFound
+		}
+		return model.FeedIcon{}, err
+	}
+	return icon, nil
+}
+
+// GetIcons はユーザーが購読しているフィードのうち、アイコンがあるもののアイコンを取得します。
+func (s *feedIconService) GetIcons(userID string) ([]model.FeedIcon, error) {
+	return s.iconRepo.GetByUserID(userID)
+}
+
+// RefreshIcon は前回の確認から feedIconRefreshInterval を過ぎている場合に、フィードのアイコンを取得して保存します。
+// siteURL と iconURL はフィードから取得したサイトのURLとアイコンのURLで、空の場合もあります。
+// アイコンが見つからない場合は保存済みのアイコンを維持します。エラーはログに出力するのみです。
+func (s *feedIconService) RefreshIcon(ctx context.Context, feed model.Feed, siteURL, iconURL string) {
+	checkedAt, err := s.iconRepo.GetCheckedAt(feed.ID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		log.Printf("Failed to get icon check time of feed %s: %v", feed.ID, err)
+		return
+	}
+	now := time.Now()
+	if err == nil && now.Before(checkedAt.Add(feedIconRefreshInterval)) {
+		return
+	}
+
+	found, err := s.finder.Find(ctx, favicon.Source{FeedURL: feed.URL, SiteURL: siteURL, IconURL: iconURL})
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		if !errors.Is(err, favicon.ErrNotFound) {
+			log.Printf("Failed to find icon of feed %s: %v", feed.ID, err)
+		}
+		if err := s.iconRepo.MarkChecked(feed.ID, now); err != nil {
+			log.Printf("Failed to update icon check time of feed %s: %v", feed.ID, err)
+		}
+		return
+	}
+
+	sum := sha256.Sum256(found.Data)
+	icon := model.FeedIcon{
+		FeedID:    feed.ID,
+		URL:       found.URL,
+		MimeType:  found.MimeType,
+		Hash:      hex.EncodeToString(sum[:]),
+		Data:      found.Data,
+		FetchedAt: now,
+	}
+	if err := s.iconRepo.Save(icon, now); err != nil {
+		log.Printf("Failed to save icon of feed %s: %v", feed.ID, err)
+	}
+}
@@ -2,81 +2,221 @@ package service
 
 import (
 	"errors"
+	"feedapp/internal/fetcher"
 	"feedapp/internal/model"
 	"feedapp/internal/repository"
+	"feedapp/internal/secretbox"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
 	"time"
+
+	"golang.org/x/net/http/httpguts"
 )
 
 var ErrFeedNotFound = errors.New("feed not found")
 
+// ErrFeedAlreadyExists は既に購読しているフィードを購読しようとした場合に返されます。
+var ErrFeedAlreadyExists = errors.New("feed already exists")
+
+// ErrFeedSourceChanged はフィードのURLやプラグイン種別を変更しようとした場合に返されます。
+// 取得元は他のユーザーと共有しているため、変更する場合は購読し直す必要があります。
+var ErrFeedSourceChanged = errors.New("feed url and plugin type cannot be changed")
+
+// ErrInvalidFeedAuth はフィードの認証情報やリクエストヘッダーが不正な場合に返されます。
+var ErrInvalidFeedAuth = errors.New("invalid feed auth")
+
+// ErrInvalidFeedProxy はフィードのプロキシのURLが不正な場合に返されます。
+var ErrInvalidFeedProxy = errors.New("invalid feed proxy")
+
+// ErrFeedAuthUnavailable は暗号化キーが設定されておらず、フィードの認証情報やプロキシを保存できない場合に返されます。
+var ErrFeedAuthUnavailable = errors.New("feed auth requires an encryption key")
+
+// ErrFeedAuthShared は他のユーザーと共有しているフィードに認証情報を設定しようとした場合に返されます。
+// 認証情報を設定したフィードは共有しないため、認証情報を指定して購読し直す必要があります。
+var ErrFeedAuthShared = errors.New("auth cannot be added to a shared feed")
+
+// ErrFeedProxyShared は他のユーザーと共有しているフィードのプロキシを変更しようとした場合に返されます。
+// プロキシは購読者全員の取得に使用されるため、購読者が1人のフィードでのみ変更できます。
+var ErrFeedProxyShared = errors.New("proxy cannot be changed on a shared feed")
+
+// ErrFeedRetentionShared は他のユーザーと共有しているフィードの保持設定を変更しようとした場合に返されます。
+// 保持設定による削除は購読者全員の記事に適用されるため、購読者が1人のフィードでのみ変更できます。
+var ErrFeedRetentionShared = errors.New("retention cannot be changed on a shared feed")
+
+// ErrFeedFetchSettingsShared は他のユーザーと共有しているフィードの取得の設定を変更しようとした場合に返されます。
+// 更新間隔・本文抽出・取得の制限・robots.txt の確認は購読者全員の取得に適用されるため、購読者が1人のフィードでのみ変更できます。
+var ErrFeedFetchSettingsShared = errors.New("fetch settings cannot be changed on a shared feed")
+
+// forbiddenAuthHeaders はフィードのリクエストヘッダーとして指定できないヘッダーです。
+var forbiddenAuthHeaders = map[string]bool{
+	"Host":                true,
+	"Content-Length":      true,
+	"Transfer-Encoding":   true,
+	"Connection":          true,
+	"Keep-Alive":          true,
+	"Upgrade":             true,
+	"Te":                  true,
+	"Trailer":             true,
+	"Proxy-Connection":    true,
+	"Proxy-Authorization": true,
+}
+
 // FeedService はフィード関連のビジネスロジックを定義するインターフェースです。
+// フィードはユーザーの購読として扱い、購読していないフィードは ErrFeedNotFound となります。
+// 返すフィードの認証情報はユーザー名以外を、プロキシのURLはパスワードを model.RedactedSecret に置き換えます。
 type FeedService interface {
-	GetAllFeeds() ([]model.Feed, error)
-	GetFeedByID(id string) (model.Feed, error)
-	CreateFeed(feed model.Feed) (model.Feed, error)
-	UpdateFeed(id string, feed model.Feed) (model.Feed, error)
-	DeleteFeed(id string) error
+	GetAllFeeds(userID string) ([]model.Feed, error)
+	GetFeedByID(userID, id string) (model.Feed, error)
+	CreateFeed(userID string, feed model.Feed) (model.Feed, error)
+	UpdateFeed(userID, id string, feed model.Feed) (model.Feed, error)
+	DeleteFeed(userID, id string) error
 }
 
 // feedService は FeedService インターフェースの実装です。
 type feedService struct {
-	feedRepo repository.FeedRepository
+	feedRepo   repository.FeedRepository
+	folderRepo repository.FolderRepository
 }
 
 // NewFeedService は新しい feedService インスタンスを作成します。
-func NewFeedService(repo repository.FeedRepository) FeedService {
+func NewFeedService(repo repository.FeedRepository, folderRepo repository.FolderRepository) FeedService {
 	return &feedService{
-		feedRepo: repo,
+		feedRepo:   repo,
+		folderRepo: folderRepo,
 	}
 }
 
-func (s *feedService) GetAllFeeds() ([]model.Feed, error) {
-	feeds, err := s.feedRepo.GetAll()
+func (s *feedService) GetAllFeeds(userID string) ([]model.Feed, error) {
+	feeds, err := s.feedRepo.GetAll(userID)
 	if err != nil {
 		return nil, err
 	}
+	for i := range feeds {
+		redactFeed(&feeds[i])
+	}
 	return feeds, nil
 }
 
-func (s *feedService) GetFeedByID(id string) (model.Feed, error) {
-	feed, err := s.feedRepo.GetByID(id)
+func (s *feedService) GetFeedByID(userID, id string) (model.Feed, error) {
+	feed, err := s.feedRepo.GetByID(userID, id)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return model.Feed{}, ErrFeedNotFound
 		}
 		return model.Feed{}, err
 	}
+	redactFeed(&feed)
 	return feed, nil
 }
 
-func (s *feedService) CreateFeed(feed model.Feed) (model.Feed, error) {
+// CreateFeed はフィードを購読します。
+// 同じURLのフィードを他のユーザーが購読している場合は、そのフィードを共有します。
+// 認証情報やプロキシを指定した場合は共有せず、新しいフィードを作成します。
+func (s *feedService) CreateFeed(userID string, feed model.Feed) (model.Feed, error) {
+	if err := s.checkFolder(userID, feed.FolderID); err != nil {
+		return model.Feed{}, err
+	}
+	auth, err := normalizeFeedAuth(feed.Auth)
+	if err != nil {
+		return model.Feed{}, err
+	}
+	feed.Auth = auth
+	if feed.Proxy, err = normalizeFeedProxy(feed.Proxy); err != nil {
+		return model.Feed{}, err
+	}
+
 	feed.ID = model.GenerateUUID()
 	feed.CreatedAt = time.Now()
-	createdFeed, err := s.feedRepo.Create(feed)
+	createdFeed, err := s.feedRepo.Create(userID, feed)
 	if err != nil {
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			return model.Feed{}, ErrFeedAlreadyExists
+		}
+		if errors.Is(err, secretbox.ErrNoKey) {
+			return model.Feed{}, ErrFeedAuthUnavailable
+		}
 		return model.Feed{}, err
 	}
+	redactFeed(&createdFeed)
 	return createdFeed, nil
 }
 
-func (s *feedService) UpdateFeed(id string, feed model.Feed) (model.Feed, error) {
-	_, err := s.feedRepo.GetByID(id)
+// UpdateFeed は購読とフィードの設定を更新します。
+// 省略した共有の設定項目と、認証情報とプロキシのパスワードのうち model.RedactedSecret を指定した項目は、保存済みの値を維持します。
+// 他のユーザーと共有しているフィードに認証情報を設定しようとした場合は ErrFeedAuthShared を、
+// プロキシを変更しようとした場合は ErrFeedProxyShared を、保持設定（retention_days・max_articles）を
+// 変更しようとした場合は ErrFeedRetentionShared を、取得の設定（update_interval・fetch_full_content・
+// rate_limit・max_connections・respect_robots）を変更しようとした場合は ErrFeedFetchSettingsShared を返します。
+func (s *feedService) UpdateFeed(userID, id string, feed model.Feed) (model.Feed, error) {
+	currentFeed, err := s.feedRepo.GetByID(userID, id)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return model.Feed{}, ErrFeedNotFound
 		}
 		return model.Feed{}, err
 	}
+	if feed.URL != currentFeed.URL || feed.PluginType != currentFeed.PluginType {
+		return model.Feed{}, ErrFeedSourceChanged
+	}
+	if err := s.checkFolder(userID, feed.FolderID); err != nil {
+		return model.Feed{}, err
+	}
+	mergeFeedSettings(&feed, currentFeed)
+	auth, err := normalizeFeedAuth(feed.Auth.Restore(currentFeed.Auth))
+	if err != nil {
+		return model.Feed{}, err
+	}
+	feed.Auth = auth
+	if feed.Proxy, err = normalizeFeedProxy(model.RestoreProxyURL(feed.Proxy, currentFeed.Proxy)); err != nil {
+		return model.Feed{}, err
+	}
+	addsAuth := auth != nil && currentFeed.Auth == nil
+	changesProxy := feed.Proxy != currentFeed.Proxy
+	changesRetention := !sameValue(feed.RetentionDays, currentFeed.RetentionDays) || !sameValue(feed.MaxArticles, currentFeed.MaxArticles)
+	changesFetchSettings := feed.UpdateInterval != currentFeed.UpdateInterval || feed.FetchFullContent != currentFeed.FetchFullContent ||
+		!sameValue(feed.RateLimit, currentFeed.RateLimit) || !sameValue(feed.MaxConnections, currentFeed.MaxConnections) ||
+		!sameValue(feed.RespectRobots, currentFeed.RespectRobots)
+	if addsAuth || changesProxy || changesRetention || changesFetchSettings {
+		subscribers, err := s.feedRepo.GetSubscriberIDs(id)
+		if err != nil {
+			return model.Feed{}, err
+		}
+		if len(subscribers) > 1 {
+			switch {
+			case addsAuth:
+				return model.Feed{}, ErrFeedAuthShared
+			case changesProxy:
+				return model.Feed{}, ErrFeedProxyShared
+			case changesRetention:
+				return model.Feed{}, ErrFeedRetentionShared
+			default:
+				return model.Feed{}, ErrFeedFetchSettingsShared
+			}
+		}
+	}
+
 	feed.ID = id
-	updatedFeed, err := s.feedRepo.Update(feed)
+	updatedFeed, err := s.feedRepo.Update(userID, feed)
 	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.Feed{}, ErrFeedNotFound
+		}
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			return model.Feed{}, ErrFeedAlreadyExists
+		}
+		if errors.Is(err, secretbox.ErrNoKey) {
+			return model.Feed{}, ErrFeedAuthUnavailable
+		}
 		return model.Feed{}, err
 	}
+	redactFeed(&updatedFeed)
 	return updatedFeed, nil
 }
 
-func (s *feedService) DeleteFeed(id string) error {
-	err := s.feedRepo.Delete(id)
+func (s *feedService) DeleteFeed(userID, id string) error {
+	err := s.feedRepo.Delete(userID, id)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return ErrFeedNotFound
@@ -85,3 +225,118 @@ func (s *feedService) DeleteFeed(id string) error {
 	}
 	return nil
 }
+
+// checkFolder はフォルダがユーザーの所有であることを確認します。
+// フォルダが未指定の場合は何もしません。
+func (s *feedService) checkFolder(userID, folderID string) error {
+	if folderID == "" {
+		return nil
+	}
+	if _, err := s.folderRepo.GetByID(userID, folderID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrFolderNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// normalizeFeedAuth はフィードの認証情報を検証し、値が空のヘッダーを取り除きます。
+// 何も設定されていない場合は nil を返します。
+func normalizeFeedAuth(auth *model.FeedAuth) (*model.FeedAuth, error) {
+	if auth.IsZero() {
+		return nil, nil
+	}
+	normalized := *auth
+	normalized.Headers = nil
+	if normalized.Username != "" && normalized.Token != "" {
+		return nil, fmt.Errorf("%w: username and token cannot be specified together", ErrInvalidFeedAuth)
+	}
+	if strings.Contains(normalized.Username, ":") {
+		return nil, fmt.Errorf("%w: username must not contain ':'", ErrInvalidFeedAuth)
+	}
+	for name, value := range map[string]string{"username": normalized.Username, "password": normalized.Password, "token": normalized.Token, "cookie": normalized.Cookie} {
+		if !httpguts.ValidHeaderFieldValue(value) {
+			return nil, fmt.Errorf("%w: %s contains invalid characters", ErrInvalidFeedAuth, name)
+		}
+	}
+	for name, value := range auth.Headers {
+		if value == "" {
+			continue
+		}
+		if !httpguts.ValidHeaderFieldName(name) || !httpguts.ValidHeaderFieldValue(value) {
+			return nil, fmt.Errorf("%w: invalid header %q", ErrInvalidFeedAuth, name)
+		}
+		if forbiddenAuthHeaders[http.CanonicalHeaderKey(name)] {
+			return nil, fmt.Errorf("%w: header %q cannot be specified", ErrInvalidFeedAuth, name)
+		}
+		if normalized.Headers == nil {
+			normalized.Headers = map[string]string{}
+		}
+		normalized.Headers[name] = value
+	}
+	if normalized.IsZero() {
+		return nil, nil
+	}
+	return &normalized, nil
+}
+
+// mergeFeedSettings は更新で省略された共有の設定項目に current の値を設定します。
+// null を指定した項目（ClearedFields）は省略とみなさず、全体設定に戻します（認証情報とプロキシは削除します）。
+// update_interval・fetch_full_content は省略した項目（OmittedFields）のみ current の値を設定します。
+func mergeFeedSettings(feed *model.Feed, current model.Feed) {
+	kept := func(name string) bool { return !slices.Contains(feed.ClearedFields, name) }
+	if slices.Contains(feed.OmittedFields, "update_interval") {
+		feed.UpdateInterval = current.UpdateInterval
+	}
+	if slices.Contains(feed.OmittedFields, "fetch_full_content") {
+		feed.FetchFullContent = current.FetchFullContent
+	}
+	if feed.RetentionDays == nil && kept("retention_days") {
+		feed.RetentionDays = current.RetentionDays
+	}
+	if feed.MaxArticles == nil && kept("max_articles") {
+		feed.MaxArticles = current.MaxArticles
+	}
+	if feed.RateLimit == nil && kept("rate_limit") {
+		feed.RateLimit = current.RateLimit
+	}
+	if feed.MaxConnections == nil && kept("max_connections") {
+		feed.MaxConnections = current.MaxConnections
+	}
+	if feed.RespectRobots == nil && kept("respect_robots") {
+		feed.RespectRobots = current.RespectRobots
+	}
+	if feed.Auth == nil && kept("auth") {
+		feed.Auth = current.Auth
+	}
+	if feed.Proxy == "" && kept("proxy") {
+		feed.Proxy = current.Proxy
+	}
+}
+
+// sameValue は2つのポインタがともに nil か、同じ値を指しているかを返します。
+func sameValue[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// normalizeFeedProxy はフィードのプロキシのURLを検証し、正規化したURLを返します。未設定の場合は空文字列です。
+func normalizeFeedProxy(raw string) (string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return "", nil
+	}
+	u, err := fetcher.ParseProxyURL(raw)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidFeedProxy, err)
+	}
+	return u.String(), nil
+}
+
+// redactFeed はフィードの認証情報とプロキシのパスワードを伏せ字にします。
+func redactFeed(feed *model.Feed) {
+	feed.Auth = feed.Auth.Redacted()
+	feed.Proxy = model.RedactProxyURL(feed.Proxy)
+}
@@ -11,12 +11,13 @@ import (
 var ErrFolderNotFound = errors.New("folder not found")
 
 // FolderService はフォルダ関連のビジネスロジックを定義するインターフェースです。
+// フォルダはユーザーごとに管理し、他のユーザーのフォルダは ErrFolderNotFound となります。
 type FolderService interface {
-	GetAllFolders() ([]model.Folder, error)
-	GetFolderByID(id string) (model.Folder, error)
-	CreateFolder(folder model.Folder) (model.Folder, error)
-	UpdateFolder(id string, folder model.Folder) (model.Folder, error)
-	DeleteFolder(id string) error
+	GetAllFolders(userID string) ([]model.Folder, error)
+	GetFolderByID(userID, id string) (model.Folder, error)
+	CreateFolder(userID string, folder model.Folder) (model.Folder, error)
+	UpdateFolder(userID, id string, folder model.Folder) (model.Folder, error)
+	DeleteFolder(userID, id string) error
 }
 
 // folderService は FolderService インターフェースの実装です。
@@ -31,16 +32,16 @@ func NewFolderService(repo repository.FolderRepository) FolderService {
 	}
 }
 
-func (s *folderService) GetAllFolders() ([]model.Folder, error) {
-	folders, err := s.folderRepo.GetAll()
+func (s *folderService) GetAllFolders(userID string) ([]model.Folder, error) {
+	folders, err := s.folderRepo.GetAll(userID)
 	if err != nil {
 		return nil, err
 	}
 	return folders, nil
 }
 
-func (s *folderService) GetFolderByID(id string) (model.Folder, error) {
-	folder, err := s.folderRepo.GetByID(id)
+func (s *folderService) GetFolderByID(userID, id string) (model.Folder, error) {
+	folder, err := s.folderRepo.GetByID(userID, id)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) { // repository.ErrNotFound を使用
 			return model.Folder{}, ErrFolderNotFound
@@ -50,9 +51,10 @@ func (s *folderService) GetFolderByID(id string) (model.Folder, error) {
 	return folder, nil
 }
 
-func (s *folderService) CreateFolder(folder model.Folder) (model.Folder, error) {
-	// IDと作成日時をService層で設定
+func (s *folderService) CreateFolder(userID string, folder model.Folder) (model.Folder, error) {
+	// IDと所有者、作成日時をService層で設定
 	folder.ID = model.GenerateUUID() // UUID生成関数を呼び出す
+	folder.UserID = userID
 	folder.CreatedAt = time.Now()
 
 	createdFolder, err := s.folderRepo.Create(folder)
@@ -62,9 +64,9 @@ func (s *folderService) CreateFolder(folder model.Folder) (model.Folder, error)
 	return createdFolder, nil
 }
 
-func (s *folderService) UpdateFolder(id string, folder model.Folder) (model.Folder, error) {
+func (s *folderService) UpdateFolder(userID, id string, folder model.Folder) (model.Folder, error) {
 	// 存在チェック
-	_, err := s.folderRepo.GetByID(id)
+	_, err := s.folderRepo.GetByID(userID, id)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return model.Folder{}, ErrFolderNotFound
@@ -73,6 +75,7 @@ func (s *folderService) UpdateFolder(id string, folder model.Folder) (model.Fold
 	}
 
 	folder.ID = id // IDを設定
+	folder.UserID = userID
 	updatedFolder, err := s.folderRepo.Update(folder)
 	if err != nil {
 		return model.Folder{}, err
@@ -80,8 +83,8 @@ func (s *folderService) UpdateFolder(id string, folder model.Folder) (model.Fold
 	return updatedFolder, nil
 }
 
-func (s *folderService) DeleteFolder(id string) error {
-	err := s.folderRepo.Delete(id)
+func (s *folderService) DeleteFolder(userID, id string) error {
+	err := s.folderRepo.Delete(userID, id)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return ErrFolderNotFound
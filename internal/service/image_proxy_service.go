@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"feedapp/internal/fetcher"
+	"feedapp/internal/imageproxy"
+)
+
+var (
+	// ErrInvalidImageSignature は画像URLの署名が一致しない場合に返されます。
+	ErrInvalidImageSignature = errors.New("invalid image signature")
+	// ErrInvalidImageURL は画像URLが http・https の絶対URLでない場合に返されます。
+	ErrInvalidImageURL = errors.New("invalid image url")
+	// ErrUnsupportedImage は取得した内容が配信を許可する画像形式でない場合に返されます。
+	ErrUnsupportedImage = errors.New("unsupported image type")
+	// ErrImageTooLarge は画像が最大サイズを超える場合に返されます。
+	ErrImageTooLarge = errors.New("image too large")
+	// ErrImageAddressNotAllowed は画像の配信元がプライベートアドレスなど接続を許可しないアドレスの場合に返されます。
+	ErrImageAddressNotAllowed = errors.New("image address not allowed")
+)
+
+// ImageProxyService は記事中の画像を中継する画像プロキシのビジネスロジックを定義するインターフェースです。
+type ImageProxyService interface {
+	GetImage(ctx context.Context, rawURL, sig string) (imageproxy.Image, error)
+}
+
+// imageProxyService は ImageProxyService インターフェースの実装です。
+type imageProxyService struct {
+	signer *imageproxy.Signer
+	proxy  *imageproxy.Proxy
+}
+
+// NewImageProxyService は新しい imageProxyService インスタンスを作成します。
+func NewImageProxyService(signer *imageproxy.Signer, proxy *imageproxy.Proxy) ImageProxyService {
+	return &imageProxyService{
+		signer: signer,
+		proxy:  proxy,
+	}
+}
+
+// GetImage は署名を検証し、画像をキャッシュまたは配信元から取得します。
+// 署名は記事の出力時に付与したもので、このサーバーを任意のURLを取得する踏み台として使わせないために検証します。
+func (s *imageProxyService) GetImage(ctx context.Context, rawURL, sig string) (imageproxy.Image, error) {
+	if !s.signer.Verify(rawURL, sig) {
+		return imageproxy.Image{}, ErrInvalidImageSignature
+	}
+	image, err := s.proxy.Get(ctx, rawURL)
+	if err != nil {
+		switch {
+		case errors.Is(err, imageproxy.ErrInvalidURL):
+			return imageproxy.Image{}, ErrInvalidImageURL
+		case errors.Is(err, imageproxy.ErrUnsupportedType):
+			return imageproxy.Image{}, ErrUnsupportedImage
+		case errors.Is(err, fetcher.ErrBodyTooLarge):
+			return imageproxy.Image{}, ErrImageTooLarge
+		case errors.Is(err, fetcher.ErrAddressNotAllowed):
+			return imageproxy.Image{}, ErrImageAddressNotAllowed
+		}
+		return imageproxy.Image{}, err
+	}
+	return image, nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"feedapp/internal/model"
+	"feedapp/internal/repository"
+)
+
+// purgedTombstoneTTL は削除した記事のURLを再登録防止のために保持する期間です。
+// これより古い記事がフィードに残り続けることはほぼないため、期間を過ぎたものは削除します。
+const purgedTombstoneTTL = 365 * 24 * time.Hour
+
+// MaintenanceService はデータベースの保守に関するビジネスロジックを定義するインターフェースです。
+type MaintenanceService interface {
+	Purge(userID string, dryRun bool) (model.PurgeResult, error)
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// maintenanceService は MaintenanceService インターフェースの実装です。
+type maintenanceService struct {
+	retentionRepo repository.RetentionRepository
+	policy        model.RetentionPolicy
+}
+
+// NewMaintenanceService は新しい maintenanceService インスタンスを作成します。
+// policy はフィードごとの保持設定が未指定の場合に使用する既定値です。
+func NewMaintenanceService(retentionRepo repository.RetentionRepository, policy model.RetentionPolicy) MaintenanceService {
+	return &maintenanceService{
+		retentionRepo: retentionRepo,
+		policy:        policy,
+	}
+}
+
+// Purge は保持設定に従って古い記事を削除します。
+//
+// 保持日数を過ぎた既読（または非表示）の記事と、フィードごとの上限件数を超えた古い記事が対象です。
+// 「後で見る」・タグ・注釈が付いた記事は削除しません。
+// userID を指定した場合は、そのユーザーが購読しているフィードの記事のみを対象とします。
+// 空の場合（定期実行）はすべての記事が対象で、期間を過ぎた削除済み記事のURLの記録も削除します。
+// dryRun が true の場合は削除せずに対象の件数のみを返します。
+func (s *maintenanceService) Purge(userID string, dryRun bool) (model.PurgeResult, error) {
+	now := time.Now()
+	result, err := s.retentionRepo.Purge(userID, s.policy, now, dryRun)
+	if err != nil {
+		return model.PurgeResult{}, err
+	}
+	if userID == "" && !dryRun {
+		if _, err := s.retentionRepo.DeleteTombstones(now.Add(-purgedTombstoneTTL)); err != nil {
+			return model.PurgeResult{}, err
+		}
+	}
+	return result, nil
+}
+
+// Run は指定された間隔で記事の削除を実行します。ctx がキャンセルされるまでブロックします。
+func (s *maintenanceService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.purge()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.purge()
+		}
+	}
+}
+
+// purge は定期実行で記事を削除し、結果をログに出力します。
+func (s *maintenanceService) purge() {
+	result, err := s.Purge("", false)
+	if err != nil {
+		log.Printf("Failed to purge articles: %v", err)
+		return
+	}
+	if result.Deleted > 0 {
+		log.Printf("Purged %d articles", result.Deleted)
+	}
+}
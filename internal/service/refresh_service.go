@@ -0,0 +1,589 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"feedapp/internal/events"
+	"feedapp/internal/extractor"
+	"feedapp/internal/fetcher"
+	"feedapp/internal/model"
+	"feedapp/internal/plugin"
+	"feedapp/internal/repository"
+	"feedapp/internal/sanitizer"
+)
+
+// DefaultUpdateInterval はフィードの更新間隔が未設定の場合に使用する間隔（分）です。
+const DefaultUpdateInterval = 360
+
+// resanitizeBatchSize は再サニタイズ時に一度に処理する記事数です。
+const resanitizeBatchSize = 100
+
+// normalizeBatchSize は正規化URLの設定時に一度に処理する記事数です。
+const normalizeBatchSize = 100
+
+// defaultMaxConcurrentFeeds は定期取得で同時に取得するフィード数が未設定の場合に使用する値です。
+const defaultMaxConcurrentFeeds = 8
+
+// websubPollInterval は WebSub で更新がプッシュされるフィードを、取りこぼしに備えてポーリングする間隔です。
+const websubPollInterval = 24 * time.Hour
+
+// ErrInvalidFeedContent はプッシュされた内容をフィードとして解析できない場合に返されます。
+var ErrInvalidFeedContent = errors.New("invalid feed content")
+
+// RefreshService はフィードの定期取得と記事の取り込みを定義するインターフェースです。
+type RefreshService interface {
+	RefreshFeed(ctx context.Context, id string) (int, error)
+	RefreshDueFeeds(ctx context.Context)
+	IngestContent(ctx context.Context, id string, data []byte) (int, error)
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// refreshService は RefreshService インターフェースの実装です。
+type refreshService struct {
+	feedRepo    repository.FeedRepository
+	articleRepo repository.ArticleRepository
+	plugins     *plugin.Registry
+	extractor   *extractor.Extractor
+	ruleRepo    repository.RuleRepository
+	tagRepo     repository.TagRepository
+	webhooks    WebhookDispatcher
+	events      *events.Broker
+	websub      WebSubService
+	icons       FeedIconService
+	policy      model.FetchPolicy
+}
+
+// NewRefreshService は新しい refreshService インスタンスを作成します。
+func NewRefreshService(feedRepo repository.FeedRepository, articleRepo repository.ArticleRepository, ruleRepo repository.RuleRepository, tagRepo repository.TagRepository, plugins *plugin.Registry, extractor *extractor.Extractor, webhooks WebhookDispatcher, broker *events.Broker, websub WebSubService, icons FeedIconService, policy model.FetchPolicy) RefreshService {
+	return &refreshService{
+		feedRepo:    feedRepo,
+		articleRepo: articleRepo,
+		ruleRepo:    ruleRepo,
+		tagRepo:     tagRepo,
+		plugins:     plugins,
+		extractor:   extractor,
+		webhooks:    webhooks,
+		events:      broker,
+		websub:      websub,
+		icons:       icons,
+		policy:      policy,
+	}
+}
+
+// RefreshFeed は指定フィードを取得し、新しい記事を取り込みます。
+// 戻り値は新規に作成された記事の件数です。
+func (s *refreshService) RefreshFeed(ctx context.Context, id string) (int, error) {
+	feed, err := s.feedRepo.GetSourceByID(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return 0, ErrFeedNotFound
+		}
+		return 0, err
+	}
+	return s.refresh(ctx, feed)
+}
+
+// IngestContent は WebSub のハブからプッシュされたフィードの内容を解析し、新しい記事を取り込みます。
+// 戻り値は新規に作成された記事の件数です。
+// 記事ページの本文抽出には、refresh と同じくフィードの認証情報・プロキシ・取得の制限・robots.txt の確認を適用します。
+func (s *refreshService) IngestContent(ctx context.Context, id string, data []byte) (int, error) {
+	feed, err := s.feedRepo.GetSourceByID(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return 0, ErrFeedNotFound
+		}
+		return 0, err
+	}
+	result, err := plugin.ParseFeed(data)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidFeedContent, err)
+	}
+	s.recordParseWarning(feed, result.Warnings)
+	ctx, err = withFeedRequest(ctx, feed)
+	if err != nil {
+		return 0, err
+	}
+	return s.store(s.fetchContext(ctx, feed), feed, result.Items)
+}
+
+// RefreshDueFeeds は更新間隔を過ぎたすべてのフィードを並行して取得します。
+// 同時に取得するフィード数は policy.MaxConcurrentFeeds までです。
+// 個々のフィードのエラーはログに出力するのみで、処理は継続します。
+// WebSub で更新がプッシュされるフィードは websubPollInterval ごとにのみ取得します。
+func (s *refreshService) RefreshDueFeeds(ctx context.Context) {
+	feeds, err := s.feedRepo.GetAllSources()
+	if err != nil {
+		log.Printf("Failed to get feeds for refresh: %v", err)
+		return
+	}
+	pushed, err := s.websub.ActiveFeedIDs()
+	if err != nil {
+		// 購読状態を取得できない場合は、すべてのフィードを通常の間隔で取得する
+		log.Printf("Failed to get websub subscriptions: %v", err)
+	}
+
+	now := time.Now()
+	var due []model.Feed
+	for _, feed := range feeds {
+		if isDue(feed, now, pushed[feed.ID]) {
+			due = append(due, feed)
+		}
+	}
+	limit := s.policy.MaxConcurrentFeeds
+	if limit <= 0 {
+		limit = defaultMaxConcurrentFeeds
+	}
+	runLimited(due, limit, func(feed model.Feed) {
+		created, err := s.refresh(ctx, feed)
+		if err != nil {
+			log.Printf("Failed to refresh feed %s (%s): %v", feed.ID, feed.URL, err)
+			return
+		}
+		log.Printf("Refreshed feed %s: %d new articles", feed.ID, created)
+	})
+}
+
+// runLimited は items のそれぞれについて fn を最大 limit 件まで並行して実行し、すべての完了を待ちます。
+func runLimited[T any](items []T, limit int, fn func(T)) {
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for _, item := range items {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(item)
+		}()
+	}
+	wg.Wait()
+}
+
+// Run は interval ごとに RefreshDueFeeds を実行します。ctx がキャンセルされると終了します。
+// 開始時に、正規化URLが未設定の記事に正規化URLを設定し、古いサニタイズルールで保存された記事を再サニタイズします。
+func (s *refreshService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	if count, err := s.normalizeURLs(ctx); err != nil {
+		log.Printf("Failed to normalize article URLs: %v", err)
+	} else if count > 0 {
+		log.Printf("Normalized URLs of %d articles", count)
+	}
+	if count, err := s.resanitize(ctx); err != nil {
+		log.Printf("Failed to resanitize articles: %v", err)
+	} else if count > 0 {
+		log.Printf("Resanitized %d articles", count)
+	}
+	s.RefreshDueFeeds(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.RefreshDueFeeds(ctx)
+		}
+	}
+}
+
+// refresh はプラグインでフィードを取得し、各エントリーを取り込みます。
+// 取得に失敗した場合は購読者へ feed.error イベントを発行します。
+// フィードが WebSub のハブを通知している場合は、ハブへの購読を要求します。
+// フィードのアイコンは前回の確認から一定期間を過ぎている場合に取得します。
+// フィードと記事ページの取得には、フィードごとの取得の制限と robots.txt の確認を適用します。
+// フィードに認証情報が設定されている場合は、フィードと同じオリジンへのリクエストに付与します。
+// フィードにプロキシが設定されている場合は、アイコンを含むすべての取得に全体設定に代えて使用します。
+// 解析時に回復した問題（文字コードの誤りや不正なXMLなど）は、取得を失敗とせずにフィードの警告として保存します。
+func (s *refreshService) refresh(ctx context.Context, feed model.Feed) (int, error) {
+	ctx, err := withFeedRequest(ctx, feed)
+	if err != nil {
+		return 0, err
+	}
+	fetchCtx := s.fetchContext(ctx, feed)
+	result, err := s.fetch(fetchCtx, feed)
+	if err != nil {
+		s.publish(feed.ID, func(subscribers []string) {
+			s.publishEvent(subscribers, events.FeedError, events.FeedErrorData{FeedID: feed.ID, Error: err.Error()})
+		})
+		return 0, err
+	}
+	s.recordParseWarning(feed, result.Warnings)
+	if result.Hub != "" {
+		topic := result.Self
+		if topic == "" {
+			topic = feed.URL
+		}
+		s.websub.Discover(ctx, feed, result.Hub, topic)
+	}
+	s.icons.RefreshIcon(ctx, feed, result.Link, result.Icon)
+	return s.store(fetchCtx, feed, result.Items)
+}
+
+// recordParseWarning はフィードの解析時の警告を保存します。前回の取得と同じ場合は更新しません。
+func (s *refreshService) recordParseWarning(feed model.Feed, warnings []string) {
+	warning := strings.Join(warnings, "; ")
+	if warning == feed.ParseWarning {
+		return
+	}
+	if warning != "" {
+		log.Printf("Parsed feed %s (%s) with warnings: %s", feed.ID, feed.URL, warning)
+	}
+	if err := s.feedRepo.UpdateParseWarning(feed.ID, warning); err != nil {
+		log.Printf("Failed to update parse warning of feed %s: %v", feed.ID, err)
+	}
+}
+
+// withFeedRequest はフィードの認証情報とプロキシを適用したコンテキストを返します。
+// 認証情報はフィードと同じオリジン（スキーム・ホスト・ポート）へのリクエストにのみ付与し、プロキシはすべてのリクエストに使用します。
+func withFeedRequest(ctx context.Context, feed model.Feed) (context.Context, error) {
+	if !feed.Auth.IsZero() {
+		if u, err := url.Parse(feed.URL); err == nil {
+			ctx = fetcher.ContextWithCredentials(ctx, u, feed.Auth.Header())
+		}
+	}
+	if feed.Proxy != "" {
+		proxyURL, err := fetcher.ParseProxyURL(feed.Proxy)
+		if err != nil {
+			return ctx, err
+		}
+		ctx = fetcher.ContextWithProxy(ctx, proxyURL)
+	}
+	return ctx, nil
+}
+
+// fetchContext はフィードの取得に使用するコンテキストを返します。
+// フィードに rate_limit・max_connections が設定されている場合は、全体設定より厳しい項目のみを追加で適用し、
+// robots.txt を確認する設定の場合は確認を要求します。
+func (s *refreshService) fetchContext(ctx context.Context, feed model.Feed) context.Context {
+	if feed.RateLimit != nil || feed.MaxConnections != nil {
+		limits := fetcher.HostLimits{
+			RequestsPerSecond: s.policy.RequestsPerSecond,
+			Burst:             s.policy.Burst,
+			MaxConcurrent:     s.policy.MaxConcurrentPerHost,
+		}
+		if feed.RateLimit != nil {
+			limits.RequestsPerSecond = *feed.RateLimit
+		}
+		if feed.MaxConnections != nil {
+			limits.MaxConcurrent = *feed.MaxConnections
+		}
+		ctx = fetcher.ContextWithHostLimits(ctx, limits)
+	}
+	if s.policy.RespectsRobots(feed) {
+		ctx = fetcher.ContextWithRobots(ctx)
+	}
+	return ctx
+}
+
+// store は取得したエントリーを取り込み、フィードの最終更新日時を更新します。
+// 取り込み後は購読者へ新しい記事の article.created と feed.refreshed イベントを発行します。
+// article.created はルールで記事を skip した購読者には発行しません。
+func (s *refreshService) store(ctx context.Context, feed model.Feed, items []model.Article) (int, error) {
+	rules, err := loadFeedRuleSet(s.ruleRepo, s.feedRepo, feed.ID)
+	if err != nil {
+		// ルールを取得できない場合も、記事の取り込みは継続する
+		log.Printf("Failed to get rules for feed %s: %v", feed.ID, err)
+	}
+
+	var created []ingestedArticle
+	for _, item := range items {
+		ingested, isNew, err := s.ingest(ctx, feed, item, rules)
+		if err != nil {
+			log.Printf("Failed to ingest item %q of feed %s: %v", item.URL, feed.ID, err)
+			continue
+		}
+		if isNew {
+			created = append(created, ingested)
+		}
+	}
+
+	now := time.Now()
+	if err := s.feedRepo.UpdateLastUpdated(feed.ID, now); err != nil {
+		return len(created), err
+	}
+	s.publish(feed.ID, func(subscribers []string) {
+		for _, ingested := range created {
+			// 本文は一覧の更新に不要なため、イベントのサイズを抑えるために含めない
+			article := ingested.article
+			article.Content = ""
+			s.publishEvent(ingested.visibleTo(subscribers), events.ArticleCreated, article)
+		}
+		s.publishEvent(subscribers, events.FeedRefreshed, events.FeedRefreshedData{FeedID: feed.ID, Created: len(created), LastUpdated: now})
+	})
+	return len(created), nil
+}
+
+// fetch はフィードのプラグイン種別に対応するプラグインでエントリーを取得します。
+func (s *refreshService) fetch(ctx context.Context, feed model.Feed) (*plugin.Result, error) {
+	p, err := s.plugins.Get(feed.PluginType)
+	if err != nil {
+		return nil, err
+	}
+	return p.Fetch(ctx, feed)
+}
+
+// publish はフィードの購読者を取得し、購読者がいる場合に fn でイベントを発行します。
+func (s *refreshService) publish(feedID string, fn func(subscribers []string)) {
+	subscribers, err := s.feedRepo.GetSubscriberIDs(feedID)
+	if err != nil {
+		log.Printf("Failed to get subscribers of feed %s: %v", feedID, err)
+		return
+	}
+	if len(subscribers) > 0 {
+		fn(subscribers)
+	}
+}
+
+// publishEvent は購読者へイベントを発行します。
+func (s *refreshService) publishEvent(subscribers []string, eventType string, data any) {
+	if err := s.events.Publish(subscribers, eventType, data); err != nil {
+		log.Printf("Failed to publish %s event: %v", eventType, err)
+	}
+}
+
+// ingest は1件のエントリーを記事として取り込みます。
+//
+// 重複判定は以下の順で行います。
+//  1. 同一フィード内で同じGUIDを持つ記事が既にあれば、取り込み済みとして何もしない
+//  2. 正規化URLが一致する記事があれば、その記事にこのフィードを関連付ける
+//  3. いずれにも該当しなければ新しい記事を作成する
+//
+// 認証情報を設定したフィードの記事は、認証情報を付けて取得した内容を他のユーザーと共有しないよう、
+// 2 では同じフィードの記事のみを照合し、他のフィードと統合しない記事（IsPrivate）として作成します。
+//
+// フィードは購読者全員で共有し、一度だけ取得します。既読・後で見る状態はユーザーごとに
+// article_states で管理するため、作成した記事は全購読者にとって未読となります。
+// フィードで本文抽出が有効な場合、新規作成する記事の本文は記事ページから抽出します。
+// 新規作成する記事には作成前に購読者のルールを評価し、購読者全員が skip する記事は作成しません。
+// 一部の購読者のみが skip する記事は作成し、その購読者の一覧から除外します。
+// 新規作成した記事は、条件に一致する Webhook への配信をキューに追加します。
+// 既存の記事にこのフィードを新たに関連付けた場合も、記事の作成以外は同様にルールを適用し、Webhook への配信をキューに追加します。
+// 戻り値の bool は新規作成された場合に true となります。
+func (s *refreshService) ingest(ctx context.Context, feed model.Feed, item model.Article, rules feedRuleSet) (ingestedArticle, bool, error) {
+	if item.URL == "" {
+		return ingestedArticle{}, false, errors.New("item has no URL")
+	}
+
+	if item.GUID != "" {
+		existing, err := s.articleRepo.GetByFeedGUID(feed.ID, item.GUID)
+		if err == nil {
+			return ingestedArticle{article: existing}, false, nil
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			return ingestedArticle{}, false, err
+		}
+	}
+
+	if existing, ok, err := s.linkExisting(feed, item, rules); err != nil || ok {
+		return ingestedArticle{article: existing}, false, err
+	}
+	// 保持設定により削除した記事は、フィードに残っていても未読として再登録しない
+	if purged, err := s.isPurged(feed, item); err != nil || purged {
+		return ingestedArticle{}, false, err
+	}
+
+	article := item
+	sanitize(&article)
+	actions := rules.evaluate(article)
+	if rules.skipsAll(actions) {
+		return ingestedArticle{}, false, nil
+	}
+	if feed.FetchFullContent {
+		if err := extractContent(ctx, s.extractor, &article); err != nil {
+			// 抽出に失敗してもフィードの本文のまま取り込む
+			log.Printf("Failed to extract content of %q: %v", item.URL, err)
+		}
+	}
+	article.ID = model.GenerateUUID()
+	article.FeedID = feed.ID
+	article.CreatedAt = time.Now()
+	article.IsPrivate = !feed.Auth.IsZero()
+	created, err := s.articleRepo.Create(article)
+	if err != nil {
+		// 別フィードの取り込みと競合した場合は、先に作成された記事へ関連付ける
+		if existing, ok, linkErr := s.linkExisting(feed, item, rules); linkErr == nil && ok {
+			return ingestedArticle{article: existing}, false, nil
+		}
+		return ingestedArticle{}, false, err
+	}
+
+	if _, err := s.articleRepo.AddSource(created.ID, feed.ID, item.GUID); err != nil {
+		return ingestedArticle{}, false, err
+	}
+	s.processAdded(feed, created, actions)
+	return ingestedArticle{article: created, actions: actions}, true, nil
+}
+
+// processAdded はフィードに追加した記事に購読者のルールのアクションを適用し、Webhook への配信をキューに追加します。
+func (s *refreshService) processAdded(feed model.Feed, article model.Article, actions map[string]ruleActions) {
+	for userID, userActions := range actions {
+		if err := applyRuleActions(s.articleRepo, s.tagRepo, userID, []string{article.ID}, userActions); err != nil {
+			// ルールの適用に失敗しても記事は取り込み済みのため、ログに出力して継続する
+			log.Printf("Failed to apply rules of user %s to article %s: %v", userID, article.ID, err)
+		}
+	}
+	s.webhooks.Enqueue(feed, article)
+}
+
+// ingestedArticle は取り込んだ記事と、記事に一致したユーザーごとのルールのアクションです。
+type ingestedArticle struct {
+	article model.Article
+	actions map[string]ruleActions
+}
+
+// visibleTo は購読者のうち、ルールで記事を skip していないユーザーを返します。
+func (a ingestedArticle) visibleTo(subscribers []string) []string {
+	visible := make([]string, 0, len(subscribers))
+	for _, userID := range subscribers {
+		if !a.actions[userID].skip {
+			visible = append(visible, userID)
+		}
+	}
+	return visible
+}
+
+// linkExisting は正規化URLが一致する既存記事を探し、見つかればフィードとの関連を追加します。
+// 関連を新たに追加した場合は、このフィードの購読者のルールを評価して適用し、Webhook への配信をキューに追加します。
+// 認証情報を設定したフィードでは、同じフィードの記事のみを探します。
+func (s *refreshService) linkExisting(feed model.Feed, item model.Article, rules feedRuleSet) (model.Article, bool, error) {
+	normalizedURL := model.NormalizeURL(item.URL)
+	if !feed.Auth.IsZero() {
+		existing, err := s.articleRepo.GetPrivateByNormalizedURL(feed.ID, normalizedURL)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return model.Article{}, false, nil
+			}
+			return model.Article{}, false, err
+		}
+		return existing, true, nil
+	}
+	existing, err := s.articleRepo.GetByNormalizedURL(normalizedURL)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.Article{}, false, nil
+		}
+		return model.Article{}, false, err
+	}
+	added, err := s.articleRepo.AddSource(existing.ID, feed.ID, item.GUID)
+	if err != nil {
+		return model.Article{}, false, err
+	}
+	if added {
+		s.processAdded(feed, existing, rules.evaluate(existing))
+	}
+	return existing, true, nil
+}
+
+// normalizeURLs は正規化URLが未設定の記事（重複判定の導入前に作成された記事）に正規化URLを設定します。
+// 正規化URLは Go の model.NormalizeURL で生成するため、マイグレーションではなく起動時に設定します。
+func (s *refreshService) normalizeURLs(ctx context.Context) (int, error) {
+	count := 0
+	for ctx.Err() == nil {
+		articles, err := s.articleRepo.GetUnnormalized(normalizeBatchSize)
+		if err != nil {
+			return count, err
+		}
+		if len(articles) == 0 {
+			break
+		}
+		for _, article := range articles {
+			if err := s.articleRepo.SetNormalizedURL(article.ID, model.NormalizeURL(article.URL)); err != nil {
+				return count, err
+			}
+			count++
+		}
+	}
+	return count, nil
+}
+
+// isPurged はエントリーの記事が保持設定により削除済みかを返します。
+// 認証情報を設定したフィードでは、同じフィードで削除した記事のみを対象とします。
+func (s *refreshService) isPurged(feed model.Feed, item model.Article) (bool, error) {
+	normalizedURL := model.NormalizeURL(item.URL)
+	if !feed.Auth.IsZero() {
+		return s.articleRepo.IsPrivatePurged(feed.ID, normalizedURL)
+	}
+	return s.articleRepo.IsPurged(normalizedURL)
+}
+
+// resanitize は現在のサニタイズルールより古いルールで保存された記事を、元HTMLから再サニタイズします。
+func (s *refreshService) resanitize(ctx context.Context) (int, error) {
+	count := 0
+	for ctx.Err() == nil {
+		articles, err := s.articleRepo.GetOutdatedSanitized(sanitizer.Version, resanitizeBatchSize)
+		if err != nil {
+			return count, err
+		}
+		if len(articles) == 0 {
+			break
+		}
+		for _, article := range articles {
+			sanitize(&article)
+			if err := s.articleRepo.UpdateSanitizedContent(article); err != nil {
+				return count, err
+			}
+			count++
+		}
+	}
+	return count, nil
+}
+
+// sanitize は元HTMLを保持したうえで本文と要約をサニタイズします。
+// RawContent / RawSummary が未設定の場合は現在の Content / Summary を元HTMLとして扱います。
+func sanitize(article *model.Article) {
+	if article.RawContent == "" {
+		article.RawContent = article.Content
+	}
+	if article.RawSummary == "" {
+		article.RawSummary = article.Summary
+	}
+	article.Content = sanitizer.Sanitize(article.RawContent, article.URL)
+	article.Summary = sanitizer.Sanitize(article.RawSummary, article.URL)
+	article.SanitizerVersion = sanitizer.Version
+}
+
+// extractContent は記事ページから本文を抽出し、サニタイズして article に設定します。
+// フィードの本文は要約が空の場合のみ要約として残します。
+func extractContent(ctx context.Context, ext *extractor.Extractor, article *model.Article) error {
+	content, finalURL, err := ext.Fetch(ctx, article.URL)
+	if err != nil {
+		return err
+	}
+	if article.RawContent == "" {
+		article.RawContent = article.Content
+	}
+	if article.RawSummary == "" {
+		article.RawSummary = article.RawContent
+	}
+	article.RawContent = content
+	article.Content = sanitizer.Sanitize(content, finalURL)
+	article.Summary = sanitizer.Sanitize(article.RawSummary, article.URL)
+	article.SanitizerVersion = sanitizer.Version
+	now := time.Now()
+	article.ExtractedAt = &now
+	return nil
+}
+
+// isDue はフィードが更新間隔を過ぎているかを判定します。
+// pushed が true（WebSub で更新がプッシュされる）の場合は websubPollInterval 未満の間隔では取得しません。
+func isDue(feed model.Feed, now time.Time, pushed bool) bool {
+	if feed.LastUpdated.IsZero() {
+		return true
+	}
+	interval := feed.UpdateInterval
+	if interval <= 0 {
+		interval = DefaultUpdateInterval
+	}
+	wait := time.Duration(interval) * time.Minute
+	if pushed && wait < websubPollInterval {
+		wait = websubPollInterval
+	}
+	return !now.Before(feed.LastUpdated.Add(wait))
+}
@@ -0,0 +1,331 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"feedapp/internal/events"
+	"feedapp/internal/extractor"
+	"feedapp/internal/fetcher"
+	"feedapp/internal/model"
+	"feedapp/internal/repository"
+)
+
+// fakeFeedRepository は取り込みに必要なメソッドのみを実装した repository.FeedRepository です。
+type fakeFeedRepository struct {
+	repository.FeedRepository
+	feeds       map[string]model.Feed
+	subscribers map[string][]string
+}
+
+func (r *fakeFeedRepository) GetSourceByID(id string) (model.Feed, error) {
+	feed, ok := r.feeds[id]
+	if !ok {
+		return model.Feed{}, repository.ErrNotFound
+	}
+	return feed, nil
+}
+
+func (r *fakeFeedRepository) GetSubscriberIDs(id string) ([]string, error) {
+	return r.subscribers[id], nil
+}
+
+func (r *fakeFeedRepository) UpdateLastUpdated(id string, lastUpdated time.Time) error {
+	return nil
+}
+
+func (r *fakeFeedRepository) UpdateParseWarning(id, warning string) error {
+	return nil
+}
+
+// fakeArticleRepository は記事と取得元フィードをメモリ上に保持する repository.ArticleRepository です。
+// 正規化URLによる統合と、認証情報を設定したフィードの記事（IsPrivate）の扱いはデータベースの一意制約と同じです。
+type fakeArticleRepository struct {
+	repository.ArticleRepository
+	articles []model.Article
+	sources  map[string][]string
+	read     map[string][]string // ユーザーIDごとに既読にした記事ID
+}
+
+func newFakeArticleRepository() *fakeArticleRepository {
+	return &fakeArticleRepository{sources: make(map[string][]string)}
+}
+
+func (r *fakeArticleRepository) find(match func(model.Article) bool) (model.Article, error) {
+	for _, article := range r.articles {
+		if match(article) {
+			return article, nil
+		}
+	}
+	return model.Article{}, repository.ErrNotFound
+}
+
+func (r *fakeArticleRepository) GetByID(userID, id string) (model.Article, error) {
+	return r.find(func(a model.Article) bool { return a.ID == id })
+}
+
+func (r *fakeArticleRepository) GetByFeedGUID(feedID, guid string) (model.Article, error) {
+	return r.find(func(a model.Article) bool {
+		return a.GUID == guid && r.hasSource(a.ID, feedID)
+	})
+}
+
+func (r *fakeArticleRepository) GetByNormalizedURL(normalizedURL string) (model.Article, error) {
+	return r.find(func(a model.Article) bool {
+		return !a.IsPrivate && model.NormalizeURL(a.URL) == normalizedURL
+	})
+}
+
+func (r *fakeArticleRepository) GetPrivateByNormalizedURL(feedID, normalizedURL string) (model.Article, error) {
+	return r.find(func(a model.Article) bool {
+		return a.IsPrivate && a.FeedID == feedID && model.NormalizeURL(a.URL) == normalizedURL
+	})
+}
+
+func (r *fakeArticleRepository) IsPurged(normalizedURL string) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeArticleRepository) IsPrivatePurged(feedID, normalizedURL string) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeArticleRepository) Create(article model.Article) (model.Article, error) {
+	if !article.IsPrivate {
+		if _, err := r.GetByNormalizedURL(model.NormalizeURL(article.URL)); err == nil {
+			return model.Article{}, fmt.Errorf("duplicate normalized URL %q", article.URL)
+		}
+	}
+	r.articles = append(r.articles, article)
+	return article, nil
+}
+
+func (r *fakeArticleRepository) AddSource(articleID, feedID, guid string) (bool, error) {
+	if r.hasSource(articleID, feedID) {
+		return false, nil
+	}
+	r.sources[articleID] = append(r.sources[articleID], feedID)
+	return true, nil
+}
+
+func (r *fakeArticleRepository) UpdateStates(userID string, ids []string, isRead, isLater *bool) error {
+	if isRead != nil && *isRead {
+		if r.read == nil {
+			r.read = make(map[string][]string)
+		}
+		r.read[userID] = append(r.read[userID], ids...)
+	}
+	return nil
+}
+
+func (r *fakeArticleRepository) UpdateExtractedContent(article model.Article) error {
+	for i := range r.articles {
+		if r.articles[i].ID == article.ID {
+			r.articles[i] = article
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (r *fakeArticleRepository) hasSource(articleID, feedID string) bool {
+	for _, id := range r.sources[articleID] {
+		if id == feedID {
+			return true
+		}
+	}
+	return false
+}
+
+// fakeRuleRepository はフィードを指定したルールのみを保持する repository.RuleRepository です。
+type fakeRuleRepository struct {
+	repository.RuleRepository
+	rules []model.Rule
+}
+
+func (r *fakeRuleRepository) GetMatching(feedID string) ([]model.Rule, error) {
+	var matching []model.Rule
+	for _, rule := range r.rules {
+		if rule.FeedID == feedID {
+			matching = append(matching, rule)
+		}
+	}
+	return matching, nil
+}
+
+// fakeWebhookDispatcher は配信をキューに追加した記事を記録する WebhookDispatcher です。
+type fakeWebhookDispatcher struct {
+	WebhookDispatcher
+	enqueued []string
+}
+
+func (d *fakeWebhookDispatcher) Enqueue(feed model.Feed, article model.Article) {
+	d.enqueued = append(d.enqueued, feed.ID+":"+article.ID)
+}
+
+// articlePageServer は Authorization ヘッダーの有無で本文が変わる記事ページを返すテスト用のサーバーです。
+func articlePageServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		text := "This is the public teaser of the article, shown to everyone, with enough text to be extracted."
+		if r.Header.Get("Authorization") != "" {
+			text = "This is the subscriber-only body of the article, paid content, with enough text to be extracted."
+		}
+		fmt.Fprintf(w, "<html><body><article><p>%s</p><p>%s</p></article></body></html>", text, text)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestRefreshService(feedRepo *fakeFeedRepository, articleRepo *fakeArticleRepository, webhooks *fakeWebhookDispatcher) *refreshService {
+	return &refreshService{
+		feedRepo:    feedRepo,
+		articleRepo: articleRepo,
+		ruleRepo:    &fakeRuleRepository{},
+		extractor:   extractor.New(fetcher.NewClient()),
+		webhooks:    webhooks,
+		events:      events.NewBroker(0),
+	}
+}
+
+func TestRefreshService_PrivateArticles(t *testing.T) {
+	server := articlePageServer(t)
+	articleURL := server.URL + "/article"
+
+	// ユーザー A は認証情報を設定したフィード、ユーザー B は公開フィードで同じURLの記事を購読している
+	privateFeed := model.Feed{ID: "feed-private", URL: server.URL + "/feed", PluginType: "rss", FetchFullContent: true, Auth: &model.FeedAuth{Token: "secret"}}
+	publicFeed := model.Feed{ID: "feed-public", URL: "https://public.example.com/feed", PluginType: "rss"}
+	item := model.Article{GUID: "entry-1", Title: "Article", URL: articleURL, Content: "<p>Teaser</p>"}
+
+	tests := []struct {
+		name  string
+		order []model.Feed
+	}{
+		{name: "private feed first", order: []model.Feed{privateFeed, publicFeed}},
+		{name: "public feed first", order: []model.Feed{publicFeed, privateFeed}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feedRepo := &fakeFeedRepository{
+				feeds:       map[string]model.Feed{privateFeed.ID: privateFeed, publicFeed.ID: publicFeed},
+				subscribers: map[string][]string{privateFeed.ID: {"user-a"}, publicFeed.ID: {"user-b"}},
+			}
+			articleRepo := newFakeArticleRepository()
+			s := newTestRefreshService(feedRepo, articleRepo, &fakeWebhookDispatcher{})
+
+			for _, feed := range tt.order {
+				ctx, err := withFeedRequest(context.Background(), feed)
+				require.NoError(t, err)
+				created, err := s.store(ctx, feed, []model.Article{item})
+				require.NoError(t, err)
+				assert.Equal(t, 1, created, feed.ID)
+			}
+
+			// 再取得しても、認証情報を設定したフィードの記事は重複して作成しない
+			ctx, err := withFeedRequest(context.Background(), privateFeed)
+			require.NoError(t, err)
+			created, err := s.store(ctx, privateFeed, []model.Article{{Title: "Article", URL: articleURL + "#top"}})
+			require.NoError(t, err)
+			assert.Zero(t, created)
+
+			require.Len(t, articleRepo.articles, 2)
+			private, err := articleRepo.GetPrivateByNormalizedURL(privateFeed.ID, model.NormalizeURL(articleURL))
+			require.NoError(t, err)
+			shared, err := articleRepo.GetByNormalizedURL(model.NormalizeURL(articleURL))
+			require.NoError(t, err)
+
+			assert.NotEqual(t, private.ID, shared.ID)
+			assert.Equal(t, []string{privateFeed.ID}, articleRepo.sources[private.ID])
+			assert.Equal(t, []string{publicFeed.ID}, articleRepo.sources[shared.ID])
+			assert.Contains(t, private.Content, "subscriber-only")
+			assert.NotContains(t, shared.Content, "subscriber-only")
+		})
+	}
+}
+
+func TestRefreshService_IngestContent_FeedRequest(t *testing.T) {
+	server := articlePageServer(t)
+	feed := model.Feed{ID: "feed-private", URL: server.URL + "/feed", PluginType: "rss", FetchFullContent: true, Auth: &model.FeedAuth{Token: "secret"}}
+	feedRepo := &fakeFeedRepository{
+		feeds:       map[string]model.Feed{feed.ID: feed},
+		subscribers: map[string][]string{feed.ID: {"user-a"}},
+	}
+	articleRepo := newFakeArticleRepository()
+	s := newTestRefreshService(feedRepo, articleRepo, &fakeWebhookDispatcher{})
+
+	// プッシュされた記事の本文抽出にも、フィードの認証情報を付与する
+	data := fmt.Sprintf(`<rss version="2.0"><channel><title>Feed</title><item><guid>entry-1</guid><title>Article</title><link>%s/article</link></item></channel></rss>`, server.URL)
+	created, err := s.IngestContent(context.Background(), feed.ID, []byte(data))
+	require.NoError(t, err)
+	assert.Equal(t, 1, created)
+	require.Len(t, articleRepo.articles, 1)
+	assert.Contains(t, articleRepo.articles[0].Content, "subscriber-only")
+}
+
+func TestRefreshService_LinkedArticleRulesAndWebhooks(t *testing.T) {
+	feedA := model.Feed{ID: "feed-a", URL: "https://a.example.com/feed", PluginType: "rss"}
+	feedB := model.Feed{ID: "feed-b", URL: "https://b.example.com/feed", PluginType: "rss"}
+	feedRepo := &fakeFeedRepository{
+		feeds:       map[string]model.Feed{feedA.ID: feedA, feedB.ID: feedB},
+		subscribers: map[string][]string{feedA.ID: {"user-a"}, feedB.ID: {"user-b"}},
+	}
+	articleRepo := newFakeArticleRepository()
+	webhooks := &fakeWebhookDispatcher{}
+	s := newTestRefreshService(feedRepo, articleRepo, webhooks)
+	s.ruleRepo = &fakeRuleRepository{rules: []model.Rule{{
+		ID: "rule-1", UserID: "user-b", FeedID: feedB.ID, Action: model.RuleActionMarkRead,
+		Conditions: []model.RuleCondition{{Field: model.RuleFieldTitle, Operator: model.RuleOperatorContains, Value: "Article"}},
+	}}}
+
+	created, err := s.store(context.Background(), feedA, []model.Article{{GUID: "a-1", Title: "Article", URL: "https://example.com/article"}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, created)
+	require.Len(t, articleRepo.articles, 1)
+	articleID := articleRepo.articles[0].ID
+
+	// 同じ記事を別のフィードから取り込んだ場合は、記事を作成せずにそのフィードのルールと Webhook を適用する
+	created, err = s.store(context.Background(), feedB, []model.Article{{Title: "Article", URL: "http://example.com/article/?utm_source=b"}})
+	require.NoError(t, err)
+	assert.Zero(t, created)
+	assert.Equal(t, []string{feedA.ID, feedB.ID}, articleRepo.sources[articleID])
+	assert.Equal(t, map[string][]string{"user-b": {articleID}}, articleRepo.read)
+	assert.Equal(t, []string{feedA.ID + ":" + articleID, feedB.ID + ":" + articleID}, webhooks.enqueued)
+
+	// 関連付け済みの記事を再取得しても、再度は適用しない
+	_, err = s.store(context.Background(), feedB, []model.Article{{Title: "Article", URL: "https://example.com/article"}})
+	require.NoError(t, err)
+	assert.Len(t, articleRepo.read["user-b"], 1)
+	assert.Len(t, webhooks.enqueued, 2)
+}
+
+func TestRunLimited(t *testing.T) {
+	var mu sync.Mutex
+	running, maxRunning, done := 0, 0, 0
+	items := make([]int, 20)
+
+	runLimited(items, 3, func(int) {
+		mu.Lock()
+		running++
+		maxRunning = max(maxRunning, running)
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		done++
+		mu.Unlock()
+	})
+
+	// 同時に実行するのは上限までで、すべての項目を処理してから戻る
+	assert.LessOrEqual(t, maxRunning, 3)
+	assert.Equal(t, len(items), done)
+}
@@ -0,0 +1,366 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"feedapp/internal/model"
+	"feedapp/internal/repository"
+)
+
+var ErrRuleNotFound = errors.New("rule not found")
+
+// ErrInvalidRule はルールの適用範囲・条件・アクションの組み合わせが不正な場合に返されます。
+var ErrInvalidRule = errors.New("invalid rule")
+
+// ruleApplyBatchSize は既存の記事にルールを適用する際に一度に読み込む記事数です。
+const ruleApplyBatchSize = 500
+
+// RuleService はルールの管理に関するビジネスロジックを定義するインターフェースです。
+// ルールはユーザーごとに管理し、他のユーザーのルールは ErrRuleNotFound となります。
+type RuleService interface {
+	GetAllRules(userID string) ([]model.Rule, error)
+	GetRuleByID(userID, id string) (model.Rule, error)
+	CreateRule(userID string, rule model.Rule) (model.Rule, error)
+	UpdateRule(userID, id string, rule model.Rule) (model.Rule, error)
+	DeleteRule(userID, id string) error
+	ApplyRule(userID, id string, dryRun bool) (model.RuleApplyResult, error)
+}
+
+// ruleService は RuleService インターフェースの実装です。
+type ruleService struct {
+	ruleRepo    repository.RuleRepository
+	articleRepo repository.ArticleRepository
+	tagRepo     repository.TagRepository
+	feedRepo    repository.FeedRepository
+	folderRepo  repository.FolderRepository
+}
+
+// NewRuleService は新しい ruleService インスタンスを作成します。
+func NewRuleService(ruleRepo repository.RuleRepository, articleRepo repository.ArticleRepository, tagRepo repository.TagRepository, feedRepo repository.FeedRepository, folderRepo repository.FolderRepository) RuleService {
+	return &ruleService{
+		ruleRepo:    ruleRepo,
+		articleRepo: articleRepo,
+		tagRepo:     tagRepo,
+		feedRepo:    feedRepo,
+		folderRepo:  folderRepo,
+	}
+}
+
+func (s *ruleService) GetAllRules(userID string) ([]model.Rule, error) {
+	return s.ruleRepo.GetAll(userID)
+}
+
+func (s *ruleService) GetRuleByID(userID, id string) (model.Rule, error) {
+	rule, err := s.ruleRepo.GetByID(userID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.Rule{}, ErrRuleNotFound
+		}
+		return model.Rule{}, err
+	}
+	return rule, nil
+}
+
+func (s *ruleService) CreateRule(userID string, rule model.Rule) (model.Rule, error) {
+	if err := s.validate(userID, &rule); err != nil {
+		return model.Rule{}, err
+	}
+	rule.ID = model.GenerateUUID()
+	rule.UserID = userID
+	rule.CreatedAt = time.Now()
+	return s.ruleRepo.Create(rule)
+}
+
+func (s *ruleService) UpdateRule(userID, id string, rule model.Rule) (model.Rule, error) {
+	if err := s.validate(userID, &rule); err != nil {
+		return model.Rule{}, err
+	}
+	rule.ID = id
+	rule.UserID = userID
+	updated, err := s.ruleRepo.Update(rule)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.Rule{}, ErrRuleNotFound
+		}
+		return model.Rule{}, err
+	}
+	return updated, nil
+}
+
+func (s *ruleService) DeleteRule(userID, id string) error {
+	err := s.ruleRepo.Delete(userID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrRuleNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// ApplyRule はルールを適用範囲内の既存の記事に適用し、条件に一致した件数を返します。
+// dryRun が true の場合は件数の確認のみで、アクションは実行しません。
+func (s *ruleService) ApplyRule(userID, id string, dryRun bool) (model.RuleApplyResult, error) {
+	rule, err := s.GetRuleByID(userID, id)
+	if err != nil {
+		return model.RuleApplyResult{}, err
+	}
+	compiled, err := compileRule(rule)
+	if err != nil {
+		return model.RuleApplyResult{}, err
+	}
+
+	result := model.RuleApplyResult{DryRun: dryRun}
+	filter := model.ArticleFilter{FeedID: rule.FeedID, FolderID: rule.FolderID, OldestFirst: true, Limit: ruleApplyBatchSize}
+	for {
+		articles, err := s.articleRepo.GetAll(userID, filter)
+		if err != nil {
+			return model.RuleApplyResult{}, err
+		}
+		var matched []string
+		for _, article := range articles {
+			if compiled.matches(article) {
+				matched = append(matched, article.ID)
+			}
+		}
+		result.Matched += len(matched)
+		if !dryRun {
+			actions := ruleActions{}
+			actions.add(rule)
+			if err := applyRuleActions(s.articleRepo, s.tagRepo, userID, matched, actions); err != nil {
+				return model.RuleApplyResult{}, err
+			}
+		}
+		if len(articles) < ruleApplyBatchSize {
+			break
+		}
+		filter.Cursor = articles[len(articles)-1].ItemID
+	}
+	return result, nil
+}
+
+// validate はルールの適用範囲・条件・アクションを検証し、タグ名の前後の空白を取り除きます。
+// フィードは購読しているもの、フォルダは所有しているもののみ指定できます。
+func (s *ruleService) validate(userID string, rule *model.Rule) error {
+	if rule.FeedID != "" && rule.FolderID != "" {
+		return fmt.Errorf("%w: folder_id and feed_id cannot be specified together", ErrInvalidRule)
+	}
+	if rule.Action == model.RuleActionAddTag {
+		tag, err := normalizeTagName(rule.Tag)
+		if err != nil {
+			return fmt.Errorf("%w: tag is required for add_tag", ErrInvalidRule)
+		}
+		rule.Tag = tag
+	} else {
+		rule.Tag = ""
+	}
+	if _, err := compileRule(*rule); err != nil {
+		return err
+	}
+	if rule.FeedID != "" {
+		if _, err := s.feedRepo.GetByID(userID, rule.FeedID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrFeedNotFound
+			}
+			return err
+		}
+	}
+	if rule.FolderID != "" {
+		if _, err := s.folderRepo.GetByID(userID, rule.FolderID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrFolderNotFound
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// compiledRule は正規表現をコンパイル済みのルールです。
+type compiledRule struct {
+	rule     model.Rule
+	patterns []*regexp.Regexp // conditions と同じ順。contains の条件は nil
+}
+
+// compileRule はルールの正規表現の条件をコンパイルします。
+func compileRule(rule model.Rule) (compiledRule, error) {
+	compiled := compiledRule{rule: rule, patterns: make([]*regexp.Regexp, len(rule.Conditions))}
+	for i, cond := range rule.Conditions {
+		if cond.Operator != model.RuleOperatorRegex {
+			continue
+		}
+		expr := cond.Value
+		if cond.IgnoreCase {
+			expr = "(?i)" + expr
+		}
+		pattern, err := regexp.Compile(expr)
+		if err != nil {
+			return compiledRule{}, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+		}
+		compiled.patterns[i] = pattern
+	}
+	return compiled, nil
+}
+
+// matches は記事がルールのすべての条件に一致するかを判定します。
+func (r compiledRule) matches(article model.Article) bool {
+	for i, cond := range r.rule.Conditions {
+		text := ruleFieldValue(article, cond.Field)
+		if pattern := r.patterns[i]; pattern != nil {
+			if !pattern.MatchString(text) {
+				return false
+			}
+			continue
+		}
+		value := cond.Value
+		if cond.IgnoreCase {
+			text, value = strings.ToLower(text), strings.ToLower(value)
+		}
+		if !strings.Contains(text, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// ruleFieldValue は条件の対象となる記事のフィールドの値を返します。
+func ruleFieldValue(article model.Article, field string) string {
+	switch field {
+	case model.RuleFieldTitle:
+		return article.Title
+	case model.RuleFieldContent:
+		if article.Content != "" {
+			return article.Content
+		}
+		return article.Summary
+	case model.RuleFieldAuthor:
+		return article.Author
+	case model.RuleFieldURL:
+		return article.URL
+	default:
+		return ""
+	}
+}
+
+// ruleActions は1人のユーザーについて、記事に一致したルールのアクションをまとめたものです。
+type ruleActions struct {
+	markRead  bool
+	markLater bool
+	skip      bool
+	tags      []string
+}
+
+// add はルールのアクションを追加します。
+func (a *ruleActions) add(rule model.Rule) {
+	switch rule.Action {
+	case model.RuleActionMarkRead:
+		a.markRead = true
+	case model.RuleActionMarkLater:
+		a.markLater = true
+	case model.RuleActionSkip:
+		a.skip = true
+	case model.RuleActionAddTag:
+		for _, tag := range a.tags {
+			if tag == rule.Tag {
+				return
+			}
+		}
+		a.tags = append(a.tags, rule.Tag)
+	}
+}
+
+// applyRuleActions はユーザーの記事にアクションを実行します。skip の記事は一覧から除外します。
+func applyRuleActions(articleRepo repository.ArticleRepository, tagRepo repository.TagRepository, userID string, articleIDs []string, actions ruleActions) error {
+	if len(articleIDs) == 0 {
+		return nil
+	}
+	if actions.markRead || actions.markLater {
+		var isRead, isLater *bool
+		if actions.markRead {
+			isRead = &actions.markRead
+		}
+		if actions.markLater {
+			isLater = &actions.markLater
+		}
+		if err := articleRepo.UpdateStates(userID, articleIDs, isRead, isLater); err != nil {
+			return err
+		}
+	}
+	for _, tag := range actions.tags {
+		if err := tagRepo.AddToArticles(userID, tag, articleIDs); err != nil {
+			return err
+		}
+	}
+	if actions.skip {
+		return articleRepo.Hide(userID, articleIDs)
+	}
+	return nil
+}
+
+// feedRuleSet はフィードの記事の取り込みで適用する、購読者ごとのルールです。
+type feedRuleSet struct {
+	subscribers []string
+	rules       map[string][]compiledRule // ユーザーIDごとのルール
+}
+
+// loadFeedRuleSet はフィードの購読者と、購読者ごとに適用するルールを読み込みます。
+// 正規表現が不正なルールはログに出力して無視します。
+func loadFeedRuleSet(ruleRepo repository.RuleRepository, feedRepo repository.FeedRepository, feedID string) (feedRuleSet, error) {
+	rules, err := ruleRepo.GetMatching(feedID)
+	if err != nil {
+		return feedRuleSet{}, err
+	}
+	set := feedRuleSet{rules: make(map[string][]compiledRule)}
+	if len(rules) == 0 {
+		return set, nil
+	}
+	if set.subscribers, err = feedRepo.GetSubscriberIDs(feedID); err != nil {
+		return feedRuleSet{}, err
+	}
+	for _, rule := range rules {
+		compiled, err := compileRule(rule)
+		if err != nil {
+			log.Printf("Ignored rule %s: %v", rule.ID, err)
+			continue
+		}
+		set.rules[rule.UserID] = append(set.rules[rule.UserID], compiled)
+	}
+	return set, nil
+}
+
+// evaluate は記事に一致したルールのアクションをユーザーごとに返します。
+func (set feedRuleSet) evaluate(article model.Article) map[string]ruleActions {
+	result := make(map[string]ruleActions)
+	for userID, rules := range set.rules {
+		var actions ruleActions
+		matched := false
+		for _, rule := range rules {
+			if rule.matches(article) {
+				actions.add(rule.rule)
+				matched = true
+			}
+		}
+		if matched {
+			result[userID] = actions
+		}
+	}
+	return result
+}
+
+// skipsAll は購読者全員が記事を skip するかを判定します。
+func (set feedRuleSet) skipsAll(actions map[string]ruleActions) bool {
+	if len(set.subscribers) == 0 {
+		return false
+	}
+	for _, userID := range set.subscribers {
+		if !actions[userID].skip {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"feedapp/internal/model"
+	"feedapp/internal/repository"
+	"feedapp/internal/snapshot"
+)
+
+var (
+	ErrSnapshotNotFound = errors.New("snapshot not found")
+	// ErrSnapshotNotReady はスナップショットが取得待ちまたは取得失敗の場合に返されます。
+	ErrSnapshotNotReady = errors.New("snapshot not ready")
+)
+
+const (
+	// snapshotQueueSize は取得を要求したスナップショットを待機させるキューの長さです。
+	// キューに入らなかった要求は定期確認で取得します。
+	snapshotQueueSize = 100
+	// snapshotBatchSize は定期確認で一度に取得するスナップショットの件数です。
+	snapshotBatchSize = 20
+	// snapshotCaptureTimeout はスナップショット1件の取得のタイムアウトです。
+	snapshotCaptureTimeout = 2 * time.Minute
+)
+
+// SnapshotService は記事の元ページのスナップショットに関するビジネスロジックを定義するインターフェースです。
+//
+// 「後で見る」に追加した記事の元ページを、元ページが削除されても読めるよう
+// 画像とスタイルシートを埋め込んだHTMLとして非同期に保存します。
+type SnapshotService interface {
+	Request(article model.Article) bool
+	GetSnapshot(userID, articleID string) (model.ArticleSnapshot, []byte, error)
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// snapshotService は SnapshotService インターフェースの実装です。
+type snapshotService struct {
+	snapshotRepo repository.SnapshotRepository
+	articleRepo  repository.ArticleRepository
+	store        *snapshot.Store
+	capturer     *snapshot.Capturer
+	queue        chan string
+}
+
+// NewSnapshotService は新しい snapshotService インスタンスを作成します。
+func NewSnapshotService(snapshotRepo repository.SnapshotRepository, articleRepo repository.ArticleRepository, store *snapshot.Store, capturer *snapshot.Capturer) SnapshotService {
+	return &snapshotService{
+		snapshotRepo: snapshotRepo,
+		articleRepo:  articleRepo,
+		store:        store,
+		capturer:     capturer,
+		queue:        make(chan string, snapshotQueueSize),
+	}
+}
+
+// Request は記事のスナップショットの取得を要求します。
+// スナップショットが未取得または取得に失敗している場合のみ取得待ちにし、true を返します。
+// 取得は Run のバックグラウンド処理で行います。
+func (s *snapshotService) Request(article model.Article) bool {
+	requested, err := s.snapshotRepo.Request(article.ID, time.Now())
+	if err != nil {
+		log.Printf("Failed to request snapshot of article %s: %v", article.ID, err)
+		return false
+	}
+	if !requested {
+		return false
+	}
+	select {
+	case s.queue <- article.ID:
+	default:
+	}
+	return true
+}
+
+// GetSnapshot はユーザーが参照できる記事のスナップショットとHTMLを取得します。
+// 取得待ちまたは取得失敗の場合は、取得状況とともに ErrSnapshotNotReady を返します。
+func (s *snapshotService) GetSnapshot(userID, articleID string) (model.ArticleSnapshot, []byte, error) {
+	if _, err := s.articleRepo.GetByID(userID, articleID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.ArticleSnapshot{}, nil, ErrArticleNotFound
+		}
+		return model.ArticleSnapshot{}, nil, err
+	}
+	snap, err := s.snapshotRepo.GetByArticleID(articleID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.ArticleSnapshot{}, nil, ErrSnapshotNotFound
+		}
+		return model.ArticleSnapshot{}, nil, err
+	}
+	if snap.Status != model.SnapshotStatusReady {
+		return snap, nil, ErrSnapshotNotReady
+	}
+	data, err := s.store.Get(snap.Hash)
+	if err != nil {
+		if errors.Is(err, snapshot.ErrBlobNotFound) {
+			return model.ArticleSnapshot{}, nil, ErrSnapshotNotFound
+		}
+		return model.ArticleSnapshot{}, nil, err
+	}
+	return snap, data, nil
+}
+
+// Run は取得を要求されたスナップショットを順に取得します。ctx がキャンセルされるまでブロックします。
+// 起動時と interval ごとに、キューに入らなかった要求や再起動前の要求を取得します。
+func (s *snapshotService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.capturePending(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case articleID := <-s.queue:
+			snap, err := s.snapshotRepo.GetByArticleID(articleID)
+			if err != nil {
+				if !errors.Is(err, repository.ErrNotFound) {
+					log.Printf("Failed to get snapshot of article %s: %v", articleID, err)
+				}
+				continue
+			}
+			// 定期確認で取得済みの場合は何もしない
+			if snap.Status == model.SnapshotStatusPending {
+				s.capture(ctx, snap)
+			}
+		case <-ticker.C:
+			s.capturePending(ctx)
+		}
+	}
+}
+
+// capturePending は取得待ちのスナップショットをすべて取得します。
+func (s *snapshotService) capturePending(ctx context.Context) {
+	for ctx.Err() == nil {
+		snapshots, err := s.snapshotRepo.GetPending(snapshotBatchSize)
+		if err != nil {
+			log.Printf("Failed to get pending snapshots: %v", err)
+			return
+		}
+		for _, snap := range snapshots {
+			s.capture(ctx, snap)
+		}
+		if len(snapshots) < snapshotBatchSize {
+			return
+		}
+	}
+}
+
+// capture は元ページを取得してブロブストアに保存し、取得状況を更新します。
+// 取得に失敗した場合は取得失敗とし、再度「後で見る」に追加されたときに再取得します。
+func (s *snapshotService) capture(ctx context.Context, snap model.ArticleSnapshot) {
+	captureCtx, cancel := context.WithTimeout(ctx, snapshotCaptureTimeout)
+	defer cancel()
+
+	data, err := s.capturer.Capture(captureCtx, snap.URL)
+	var hash string
+	if err == nil {
+		hash, err = s.store.Put(data)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			// 終了処理による中断は、次回の起動時に取得する
+			return
+		}
+		log.Printf("Failed to capture snapshot of %q: %v", snap.URL, err)
+		if err := s.snapshotRepo.Fail(snap.ArticleID, err.Error()); err != nil {
+			log.Printf("Failed to update snapshot of article %s: %v", snap.ArticleID, err)
+		}
+		return
+	}
+	if err := s.snapshotRepo.Complete(snap.ArticleID, hash, int64(len(data)), time.Now()); err != nil {
+		log.Printf("Failed to update snapshot of article %s: %v", snap.ArticleID, err)
+	}
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"feedapp/internal/model"
+	"feedapp/internal/repository"
+)
+
+// ErrTagNotFound は記事に指定されたタグが付いていない場合に返されます。
+var ErrTagNotFound = errors.New("tag not found")
+
+// ErrInvalidTagName はタグ名が空または長すぎる場合に返されます。
+var ErrInvalidTagName = errors.New("invalid tag name")
+
+// TagService は記事のタグに関するビジネスロジックを定義するインターフェースです。
+// タグはユーザーごとに管理し、ユーザーが参照できる記事にのみ付けられます。
+type TagService interface {
+	GetAllTags(userID string) ([]model.Tag, error)
+	AddArticleTag(userID, articleID, name string) (model.Article, error)
+	RemoveArticleTag(userID, articleID, name string) (model.Article, error)
+}
+
+// tagService は TagService インターフェースの実装です。
+type tagService struct {
+	tagRepo     repository.TagRepository
+	articleRepo repository.ArticleRepository
+}
+
+// NewTagService は新しい tagService インスタンスを作成します。
+func NewTagService(tagRepo repository.TagRepository, articleRepo repository.ArticleRepository) TagService {
+	return &tagService{
+		tagRepo:     tagRepo,
+		articleRepo: articleRepo,
+	}
+}
+
+func (s *tagService) GetAllTags(userID string) ([]model.Tag, error) {
+	return s.tagRepo.GetAll(userID)
+}
+
+// AddArticleTag は記事にタグを付け、タグを付けた記事を返します。既に付いている場合は何もしません。
+func (s *tagService) AddArticleTag(userID, articleID, name string) (model.Article, error) {
+	name, err := normalizeTagName(name)
+	if err != nil {
+		return model.Article{}, err
+	}
+	if _, err := s.getArticle(userID, articleID); err != nil {
+		return model.Article{}, err
+	}
+	if err := s.tagRepo.AddToArticles(userID, name, []string{articleID}); err != nil {
+		return model.Article{}, err
+	}
+	return s.getArticle(userID, articleID)
+}
+
+// RemoveArticleTag は記事からタグを外し、タグを外した記事を返します。
+// タグが付いていない場合は ErrTagNotFound を返します。
+func (s *tagService) RemoveArticleTag(userID, articleID, name string) (model.Article, error) {
+	name, err := normalizeTagName(name)
+	if err != nil {
+		return model.Article{}, err
+	}
+	if _, err := s.getArticle(userID, articleID); err != nil {
+		return model.Article{}, err
+	}
+	if err := s.tagRepo.RemoveFromArticle(userID, name, articleID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.Article{}, ErrTagNotFound
+		}
+		return model.Article{}, err
+	}
+	return s.getArticle(userID, articleID)
+}
+
+// getArticle はユーザーが参照できる記事を取得します。
+func (s *tagService) getArticle(userID, articleID string) (model.Article, error) {
+	article, err := s.articleRepo.GetByID(userID, articleID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.Article{}, ErrArticleNotFound
+		}
+		return model.Article{}, err
+	}
+	return article, nil
+}
+
+// normalizeTagName はタグ名の前後の空白を取り除き、空または長すぎる場合は ErrInvalidTagName を返します。
+func normalizeTagName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > model.MaxTagNameLength {
+		return "", ErrInvalidTagName
+	}
+	return name, nil
+}
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"feedapp/internal/fetcher"
+	"feedapp/internal/model"
+	"feedapp/internal/repository"
+)
+
+const (
+	// webhookMaxAttempts は1件の配信を送信する最大回数です。
+	webhookMaxAttempts = 6
+	// webhookRetryDelay は最初の再試行までの待ち時間です。以降は再試行のたびに2倍になります。
+	webhookRetryDelay = time.Minute
+	// webhookBatchSize は一度に送信する配信の最大件数です。
+	webhookBatchSize = 50
+)
+
+// Webhook の送信時に付与するヘッダーです。
+const (
+	WebhookEventHeader     = "X-FeedApp-Event"     // イベント名
+	WebhookDeliveryHeader  = "X-FeedApp-Delivery"  // 配信ID
+	WebhookSignatureHeader = "X-FeedApp-Signature" // "sha256=" + ボディの HMAC-SHA256（16進数）
+)
+
+// WebhookDispatcher は新着記事の Webhook への配信を定義するインターフェースです。
+//
+// Enqueue で条件に一致する Webhook への配信をキューに追加し、Run がバックグラウンドで
+// 送信します。送信に失敗した配信は間隔を空けて webhookMaxAttempts 回まで再試行します。
+type WebhookDispatcher interface {
+	Enqueue(feed model.Feed, article model.Article)
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// webhookDispatcher は WebhookDispatcher インターフェースの実装です。
+type webhookDispatcher struct {
+	webhookRepo repository.WebhookRepository
+	client      *fetcher.Client
+	wake        chan struct{}
+}
+
+// NewWebhookDispatcher は新しい webhookDispatcher インスタンスを作成します。
+func NewWebhookDispatcher(webhookRepo repository.WebhookRepository, client *fetcher.Client) WebhookDispatcher {
+	return &webhookDispatcher{
+		webhookRepo: webhookRepo,
+		client:      client,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// webhookPayloadData はペイロードのテンプレートに渡すデータです。
+// テンプレートでは {{.Article.Title}} や {{json .Feed.Name}} のように参照します。
+type webhookPayloadData struct {
+	Event     string
+	WebhookID string
+	Feed      model.Feed
+	Article   model.Article
+}
+
+// webhookPayload はテンプレートが未指定の場合に送信する標準のペイロードです。
+type webhookPayload struct {
+	Event     string                `json:"event"`
+	WebhookID string                `json:"webhook_id"`
+	Feed      webhookPayloadFeed    `json:"feed"`
+	Article   webhookPayloadArticle `json:"article"`
+}
+
+type webhookPayloadFeed struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type webhookPayloadArticle struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	Author      string    `json:"author,omitempty"`
+	Summary     string    `json:"summary,omitempty"`
+	ImageURL    string    `json:"image_url,omitempty"`
+	Categories  []string  `json:"categories,omitempty"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// payloadTemplateFuncs はペイロードのテンプレートで使用できる関数です。
+// json は値をJSONとしてエスケープした文字列（文字列の場合は引用符を含む）に変換します。
+var payloadTemplateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// validatePayloadTemplate はテンプレートを見本の記事で実行し、有効なJSONを生成することを確認します。
+func validatePayloadTemplate(text string) error {
+	if text == "" {
+		return nil
+	}
+	sample := webhookPayloadData{
+		Event:     model.WebhookEventArticleCreated,
+		WebhookID: "00000000-0000-0000-0000-000000000000",
+		Feed:      model.Feed{ID: "00000000-0000-0000-0000-000000000000", Name: "Example Feed", URL: "https://example.com/feed.xml"},
+		Article:   model.Article{ID: "00000000-0000-0000-0000-000000000000", Title: "Example Article", URL: "https://example.com/article", PublishedAt: time.Now()},
+	}
+	if _, err := renderPayload(text, sample); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWebhookTemplate, err)
+	}
+	return nil
+}
+
+// renderPayload は送信するJSONを生成します。テンプレートが空の場合は標準のペイロードを生成します。
+func renderPayload(text string, data webhookPayloadData) (string, error) {
+	if text == "" {
+		b, err := json.Marshal(webhookPayload{
+			Event:     data.Event,
+			WebhookID: data.WebhookID,
+			Feed:      webhookPayloadFeed{ID: data.Feed.ID, Name: data.Feed.Name, URL: data.Feed.URL},
+			Article: webhookPayloadArticle{
+				ID:          data.Article.ID,
+				Title:       data.Article.Title,
+				URL:         data.Article.URL,
+				Author:      data.Article.Author,
+				Summary:     data.Article.Summary,
+				ImageURL:    data.Article.ImageURL,
+				Categories:  data.Article.Categories,
+				PublishedAt: data.Article.PublishedAt,
+			},
+		})
+		return string(b), err
+	}
+
+	tmpl, err := template.New("payload").Funcs(payloadTemplateFuncs).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	if !json.Valid(buf.Bytes()) {
+		return "", errors.New("template output is not valid JSON")
+	}
+	return buf.String(), nil
+}
+
+// signPayload はボディの HMAC-SHA256 署名を WebhookSignatureHeader の形式で返します。
+func signPayload(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// matchesKeyword は記事のタイトル・要約・本文にキーワードが含まれるかを大文字小文字を区別せずに判定します。
+// キーワードが空の場合はすべての記事に一致します。
+func matchesKeyword(keyword string, article model.Article) bool {
+	if keyword == "" {
+		return true
+	}
+	keyword = strings.ToLower(keyword)
+	for _, text := range []string{article.Title, article.Summary, article.Content} {
+		if strings.Contains(strings.ToLower(text), keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// Enqueue は新しく取り込まれた記事について、条件に一致する Webhook への配信をキューに追加します。
+// ペイロードを生成できない場合は、送信せずに失敗した配信として記録します。
+// フィードの認証情報とプロキシはテンプレートから参照できないよう伏せ字にします。
+func (d *webhookDispatcher) Enqueue(feed model.Feed, article model.Article) {
+	redactFeed(&feed)
+	webhooks, err := d.webhookRepo.GetMatching(feed.ID, article.ID)
+	if err != nil {
+		log.Printf("Failed to get webhooks for feed %s: %v", feed.ID, err)
+		return
+	}
+
+	queued := false
+	for _, webhook := range webhooks {
+		if !matchesKeyword(webhook.Keyword, article) {
+			continue
+		}
+		now := time.Now()
+		delivery := model.WebhookDelivery{
+			ID:            model.GenerateUUID(),
+			WebhookID:     webhook.ID,
+			ArticleID:     article.ID,
+			Event:         model.WebhookEventArticleCreated,
+			Status:        model.WebhookDeliveryPending,
+			NextAttemptAt: &now,
+			CreatedAt:     now,
+		}
+		payload, err := renderPayload(webhook.PayloadTemplate, webhookPayloadData{
+			Event:     delivery.Event,
+			WebhookID: webhook.ID,
+			Feed:      feed,
+			Article:   article,
+		})
+		if err != nil {
+			delivery.Status = model.WebhookDeliveryFailed
+			delivery.Error = fmt.Sprintf("failed to render payload: %v", err)
+			delivery.NextAttemptAt = nil
+		}
+		delivery.Payload = payload
+		if _, err := d.webhookRepo.CreateDelivery(delivery); err != nil {
+			log.Printf("Failed to enqueue webhook %s for article %s: %v", webhook.ID, article.ID, err)
+			continue
+		}
+		if delivery.Status == model.WebhookDeliveryPending {
+			queued = true
+		}
+	}
+	if queued {
+		d.notify()
+	}
+}
+
+// Run は interval ごと、および配信がキューに追加されるたびに送信日時を過ぎた配信を送信します。
+// ctx がキャンセルされると終了します。
+func (d *webhookDispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	d.deliverDue(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.deliverDue(ctx)
+		case <-d.wake:
+			d.deliverDue(ctx)
+		}
+	}
+}
+
+// notify は Run に送信を促します。既に通知済みの場合は何もしません。
+func (d *webhookDispatcher) notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// deliverDue は送信日時を過ぎた配信を並行して送信します。
+// 1回で送信しきれなかった場合は、続けて送信するよう Run に通知します。
+func (d *webhookDispatcher) deliverDue(ctx context.Context) {
+	deliveries, err := d.webhookRepo.GetDueDeliveries(time.Now(), webhookBatchSize)
+	if err != nil {
+		log.Printf("Failed to get webhook deliveries: %v", err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func(delivery model.WebhookDelivery) {
+			defer wg.Done()
+			d.deliver(ctx, delivery)
+		}(delivery)
+	}
+	wg.Wait()
+
+	if len(deliveries) == webhookBatchSize && ctx.Err() == nil {
+		d.notify()
+	}
+}
+
+// deliver は1件の配信を送信し、結果を記録します。
+// 失敗した場合は webhookMaxAttempts 回に達するまで、待ち時間を倍にしながら再試行を予約します。
+func (d *webhookDispatcher) deliver(ctx context.Context, delivery model.WebhookDelivery) {
+	webhook, err := d.webhookRepo.GetTarget(delivery.WebhookID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			log.Printf("Failed to get webhook %s: %v", delivery.WebhookID, err)
+		}
+		return
+	}
+
+	resp, err := d.send(ctx, webhook, delivery)
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.ResponseStatus = 0
+	if err == nil {
+		delivery.Status = model.WebhookDeliverySucceeded
+		delivery.ResponseStatus = resp.StatusCode
+		delivery.Error = ""
+		delivery.NextAttemptAt = nil
+	} else {
+		var statusErr *fetcher.StatusError
+		if errors.As(err, &statusErr) {
+			delivery.ResponseStatus = statusErr.StatusCode
+		}
+		delivery.Error = err.Error()
+		if delivery.Attempts >= webhookMaxAttempts {
+			delivery.Status = model.WebhookDeliveryFailed
+			delivery.NextAttemptAt = nil
+		} else {
+			next := now.Add(webhookRetryDelay << (delivery.Attempts - 1))
+			delivery.NextAttemptAt = &next
+		}
+		log.Printf("Failed to deliver webhook %s (attempt %d): %v", webhook.ID, delivery.Attempts, err)
+	}
+
+	if err := d.webhookRepo.UpdateDelivery(delivery); err != nil {
+		log.Printf("Failed to record webhook delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// send は署名付きのペイロードを Webhook の URL へ POST します。
+func (d *webhookDispatcher) send(ctx context.Context, webhook model.Webhook, delivery model.WebhookDelivery) (*fetcher.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, strings.NewReader(delivery.Payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, delivery.Event)
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID)
+	req.Header.Set(WebhookSignatureHeader, signPayload(webhook.Secret, delivery.Payload))
+	return d.client.Do(req)
+}
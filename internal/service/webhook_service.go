@@ -0,0 +1,175 @@
+package service
+
+import (
+	"errors"
+	"net/url"
+	"time"
+
+	"feedapp/internal/model"
+	"feedapp/internal/repository"
+)
+
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+// ErrInvalidWebhookURL は通知先URLが http / https 以外の場合に返されます。
+var ErrInvalidWebhookURL = errors.New("webhook url must be http or https")
+
+// ErrInvalidWebhookTemplate はペイロードのテンプレートが不正、または有効なJSONを生成しない場合に返されます。
+var ErrInvalidWebhookTemplate = errors.New("invalid webhook payload template")
+
+// webhookDeliveryLimit は配信ログとして返す最大件数です。
+const webhookDeliveryLimit = 100
+
+// WebhookService は Webhook の管理に関するビジネスロジックを定義するインターフェースです。
+// Webhook はユーザーごとに管理し、他のユーザーの Webhook は ErrWebhookNotFound となります。
+// 署名の鍵は作成時のレスポンスにのみ含まれます。
+type WebhookService interface {
+	GetAllWebhooks(userID string) ([]model.Webhook, error)
+	GetWebhookByID(userID, id string) (model.Webhook, error)
+	CreateWebhook(userID string, webhook model.Webhook) (model.Webhook, error)
+	UpdateWebhook(userID, id string, webhook model.Webhook) (model.Webhook, error)
+	DeleteWebhook(userID, id string) error
+	GetWebhookDeliveries(userID, id string) ([]model.WebhookDelivery, error)
+}
+
+// webhookService は WebhookService インターフェースの実装です。
+type webhookService struct {
+	webhookRepo repository.WebhookRepository
+	feedRepo    repository.FeedRepository
+	folderRepo  repository.FolderRepository
+}
+
+// NewWebhookService は新しい webhookService インスタンスを作成します。
+func NewWebhookService(webhookRepo repository.WebhookRepository, feedRepo repository.FeedRepository, folderRepo repository.FolderRepository) WebhookService {
+	return &webhookService{
+		webhookRepo: webhookRepo,
+		feedRepo:    feedRepo,
+		folderRepo:  folderRepo,
+	}
+}
+
+func (s *webhookService) GetAllWebhooks(userID string) ([]model.Webhook, error) {
+	webhooks, err := s.webhookRepo.GetAll(userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+func (s *webhookService) GetWebhookByID(userID, id string) (model.Webhook, error) {
+	webhook, err := s.webhookRepo.GetByID(userID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.Webhook{}, ErrWebhookNotFound
+		}
+		return model.Webhook{}, err
+	}
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+// CreateWebhook は Webhook を作成します。署名の鍵が未指定の場合は生成し、レスポンスに含めます。
+func (s *webhookService) CreateWebhook(userID string, webhook model.Webhook) (model.Webhook, error) {
+	if err := s.validate(userID, webhook); err != nil {
+		return model.Webhook{}, err
+	}
+	if webhook.Secret == "" {
+		secret, err := generateToken()
+		if err != nil {
+			return model.Webhook{}, err
+		}
+		webhook.Secret = secret
+	}
+
+	webhook.ID = model.GenerateUUID()
+	webhook.UserID = userID
+	webhook.CreatedAt = time.Now()
+	created, err := s.webhookRepo.Create(webhook)
+	if err != nil {
+		return model.Webhook{}, err
+	}
+	return created, nil
+}
+
+// UpdateWebhook は Webhook を更新します。署名の鍵が未指定の場合は現在の鍵を維持します。
+func (s *webhookService) UpdateWebhook(userID, id string, webhook model.Webhook) (model.Webhook, error) {
+	current, err := s.webhookRepo.GetByID(userID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.Webhook{}, ErrWebhookNotFound
+		}
+		return model.Webhook{}, err
+	}
+	if err := s.validate(userID, webhook); err != nil {
+		return model.Webhook{}, err
+	}
+	if webhook.Secret == "" {
+		webhook.Secret = current.Secret
+	}
+
+	webhook.ID = id
+	webhook.UserID = userID
+	updated, err := s.webhookRepo.Update(webhook)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.Webhook{}, ErrWebhookNotFound
+		}
+		return model.Webhook{}, err
+	}
+	updated.Secret = ""
+	return updated, nil
+}
+
+func (s *webhookService) DeleteWebhook(userID, id string) error {
+	err := s.webhookRepo.Delete(userID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrWebhookNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// GetWebhookDeliveries は Webhook の配信ログを新しい順に取得します。
+func (s *webhookService) GetWebhookDeliveries(userID, id string) ([]model.WebhookDelivery, error) {
+	if _, err := s.webhookRepo.GetByID(userID, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return s.webhookRepo.GetDeliveries(id, webhookDeliveryLimit)
+}
+
+// validate は通知先URL・テンプレート・絞り込み条件のフィードとフォルダを検証します。
+// フィードは購読しているもの、フォルダは所有しているもののみ指定できます。
+func (s *webhookService) validate(userID string, webhook model.Webhook) error {
+	target, err := url.Parse(webhook.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return ErrInvalidWebhookURL
+	}
+	if err := validatePayloadTemplate(webhook.PayloadTemplate); err != nil {
+		return err
+	}
+	if webhook.FeedID != "" {
+		if _, err := s.feedRepo.GetByID(userID, webhook.FeedID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrFeedNotFound
+			}
+			return err
+		}
+	}
+	if webhook.FolderID != "" {
+		if _, err := s.folderRepo.GetByID(userID, webhook.FolderID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrFolderNotFound
+			}
+			return err
+		}
+	}
+	return nil
+}
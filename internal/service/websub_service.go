@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"feedapp/internal/model"
+	"feedapp/internal/repository"
+	"feedapp/internal/websub"
+)
+
+// ErrWebSubNotFound はコールバックに対応する購読がない、またはトピックが一致しない場合に返されます。
+var ErrWebSubNotFound = errors.New("websub subscription not found")
+
+// ErrInvalidWebSubVerification は意図の確認の hub.mode や hub.challenge が不正な場合に返されます。
+var ErrInvalidWebSubVerification = errors.New("invalid websub verification request")
+
+// ErrInvalidWebSubSignature はプッシュされた内容の X-Hub-Signature が不正な場合に返されます。
+var ErrInvalidWebSubSignature = errors.New("invalid websub signature")
+
+const (
+	// DefaultWebSubLeaseSeconds はハブへ要求する購読期間のデフォルト値（10日）です。
+	DefaultWebSubLeaseSeconds = 10 * 24 * 60 * 60
+	// websubRenewBefore は購読期間が終了する前に更新を要求し始めるまでの余裕です。
+	websubRenewBefore = 24 * time.Hour
+	// websubRetryInterval は確認待ちのまま、またはハブへの要求に失敗した購読を再度要求するまでの間隔です。
+	websubRetryInterval = time.Hour
+	// websubCallbackPath はコールバックURLのパスです。末尾にフィードIDを付けます。
+	websubCallbackPath = "/websub/callback/"
+)
+
+// WebSubVerification はハブから届いた意図の確認（コールバックへの GET）の内容です。
+type WebSubVerification struct {
+	Mode         string // hub.mode（subscribe / unsubscribe / denied）
+	Topic        string // hub.topic
+	Challenge    string // hub.challenge（denied の場合は空）
+	LeaseSeconds int    // hub.lease_seconds（subscribe の場合のみ）
+	Reason       string // hub.reason（denied の場合のみ）
+}
+
+// WebSubService は WebSub によるフィード更新の購読を定義するインターフェースです。
+//
+// フィードの取得時に見つかったハブへ Discover で購読を要求し、ハブからの意図の確認に
+// VerifyIntent で応答します。プッシュされた内容は VerifyContent で署名を検証してから取り込みます。
+// Run は購読期間が終了する前に購読を更新します。ハブに接続できない場合は購読を failed とし、
+// フィードは通常の間隔でのポーリングに戻ります。
+type WebSubService interface {
+	Discover(ctx context.Context, feed model.Feed, hub, topic string)
+	VerifyIntent(feedID string, verification WebSubVerification) (string, error)
+	VerifyContent(feedID, signature string, body []byte) error
+	ActiveFeedIDs() (map[string]bool, error)
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// webSubService は WebSubService インターフェースの実装です。
+type webSubService struct {
+	websubRepo      repository.WebSubRepository
+	client          *websub.Client
+	callbackBaseURL string
+	leaseSeconds    int
+}
+
+// NewWebSubService は新しい webSubService インスタンスを作成します。
+//
+// callbackBaseURL はハブからアクセスできるこのサーバーのURLで、空の場合はハブへの購読を行いません。
+// leaseSeconds が0以下の場合は DefaultWebSubLeaseSeconds を使用します。
+func NewWebSubService(websubRepo repository.WebSubRepository, client *websub.Client, callbackBaseURL string, leaseSeconds int) WebSubService {
+	if leaseSeconds <= 0 {
+		leaseSeconds = DefaultWebSubLeaseSeconds
+	}
+	return &webSubService{
+		websubRepo:      websubRepo,
+		client:          client,
+		callbackBaseURL: strings.TrimRight(callbackBaseURL, "/"),
+		leaseSeconds:    leaseSeconds,
+	}
+}
+
+// Discover はフィードの取得時に見つかったハブへ、必要であれば購読を要求します。
+//
+// 同じハブ・トピックの購読が成立していて更新の時期でない場合や、確認待ち・失敗から
+// websubRetryInterval が経過していない場合は何もしません。ハブやトピックが変わった場合は
+// 新しい鍵で購読し直します。
+func (s *webSubService) Discover(ctx context.Context, feed model.Feed, hub, topic string) {
+	if s.callbackBaseURL == "" {
+		return
+	}
+	sub, err := s.websubRepo.GetByFeedID(feed.ID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		log.Printf("Failed to get websub subscription of feed %s: %v", feed.ID, err)
+		return
+	}
+
+	now := time.Now()
+	if err == nil && sub.Hub == hub && sub.Topic == topic {
+		if !needsSubscribe(sub, now) {
+			return
+		}
+	} else {
+		secret, err := generateToken()
+		if err != nil {
+			log.Printf("Failed to generate websub secret: %v", err)
+			return
+		}
+		sub = model.WebSubSubscription{FeedID: feed.ID, Hub: hub, Topic: topic, Secret: secret, State: model.WebSubPending}
+	}
+	s.subscribe(ctx, sub)
+}
+
+// needsSubscribe は購読の要求（更新・再試行）が必要かを判定します。
+// 成立済みの購読は期限が websubRenewBefore 以内になったら更新し、いずれの場合も
+// 前回の要求から websubRetryInterval が経過するまでは要求しません。
+func needsSubscribe(sub model.WebSubSubscription, now time.Time) bool {
+	if sub.State == model.WebSubActive && sub.LeaseExpiresAt != nil && now.Add(websubRenewBefore).Before(*sub.LeaseExpiresAt) {
+		return false
+	}
+	return now.Sub(sub.UpdatedAt) >= websubRetryInterval
+}
+
+// subscribe はハブへ購読を要求し、結果を保存します。
+//
+// ハブは要求への応答より前に意図の確認を送信する場合があるため、要求の前に購読を保存します。
+// 要求に失敗した場合は failed とし、フィードは通常の間隔でのポーリングに戻ります。
+func (s *webSubService) subscribe(ctx context.Context, sub model.WebSubSubscription) {
+	if sub.State != model.WebSubActive {
+		sub.State = model.WebSubPending
+	}
+	sub.UpdatedAt = time.Now()
+	if err := s.websubRepo.Save(sub); err != nil {
+		log.Printf("Failed to save websub subscription of feed %s: %v", sub.FeedID, err)
+		return
+	}
+
+	err := s.client.Subscribe(ctx, websub.Request{
+		Hub:          sub.Hub,
+		Topic:        sub.Topic,
+		Callback:     s.callbackBaseURL + websubCallbackPath + sub.FeedID,
+		Secret:       sub.Secret,
+		LeaseSeconds: s.leaseSeconds,
+	})
+	if err == nil {
+		return
+	}
+
+	log.Printf("Failed to subscribe feed %s to websub hub, falling back to polling: %v", sub.FeedID, err)
+	// 保存後にハブからの確認で成立していた場合に備えて取得し直す
+	if current, getErr := s.websubRepo.GetByFeedID(sub.FeedID); getErr == nil {
+		sub = current
+	}
+	sub.State = model.WebSubFailed
+	sub.LeaseExpiresAt = nil
+	sub.LastError = err.Error()
+	sub.UpdatedAt = time.Now()
+	if err := s.websubRepo.Save(sub); err != nil {
+		log.Printf("Failed to save websub subscription of feed %s: %v", sub.FeedID, err)
+	}
+}
+
+// VerifyIntent はハブからの意図の確認に応答し、返すべき hub.challenge を返します。
+//
+//   - subscribe: 保存している購読とトピックが一致すれば、購読を成立させる（期限を更新する）
+//   - unsubscribe: 購読を継続したいトピックであれば拒否し、それ以外は解除を承認する
+//   - denied: ハブが購読を拒否したため、購読を failed とする（戻り値の challenge は空）
+//
+// 拒否する場合は ErrWebSubNotFound を返します。
+func (s *webSubService) VerifyIntent(feedID string, verification WebSubVerification) (string, error) {
+	if verification.Mode != websub.ModeDenied && verification.Challenge == "" {
+		return "", ErrInvalidWebSubVerification
+	}
+	sub, err := s.websubRepo.GetByFeedID(feedID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return "", err
+	}
+	matched := err == nil && sub.Topic == verification.Topic
+
+	switch verification.Mode {
+	case websub.ModeSubscribe:
+		if !matched {
+			return "", ErrWebSubNotFound
+		}
+		leaseSeconds := verification.LeaseSeconds
+		if leaseSeconds <= 0 {
+			leaseSeconds = s.leaseSeconds
+		}
+		now := time.Now()
+		expiresAt := now.Add(time.Duration(leaseSeconds) * time.Second)
+		sub.State = model.WebSubActive
+		sub.LeaseExpiresAt = &expiresAt
+		sub.LastError = ""
+		sub.UpdatedAt = now
+		if err := s.websubRepo.Save(sub); err != nil {
+			return "", err
+		}
+		return verification.Challenge, nil
+	case websub.ModeUnsubscribe:
+		if matched {
+			return "", ErrWebSubNotFound
+		}
+		return verification.Challenge, nil
+	case websub.ModeDenied:
+		if !matched {
+			return "", nil
+		}
+		sub.State = model.WebSubFailed
+		sub.LeaseExpiresAt = nil
+		sub.LastError = strings.TrimSpace("subscription denied by hub: " + verification.Reason)
+		sub.UpdatedAt = time.Now()
+		if err := s.websubRepo.Save(sub); err != nil {
+			return "", err
+		}
+		return "", nil
+	default:
+		return "", ErrInvalidWebSubVerification
+	}
+}
+
+// VerifyContent はプッシュされた内容の署名を購読の鍵で検証します。
+func (s *webSubService) VerifyContent(feedID, signature string, body []byte) error {
+	sub, err := s.websubRepo.GetByFeedID(feedID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrWebSubNotFound
+		}
+		return err
+	}
+	if err := websub.VerifySignature(sub.Secret, signature, body); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWebSubSignature, err)
+	}
+	return nil
+}
+
+// ActiveFeedIDs は購読が成立しており、更新がプッシュされるフィードのIDを返します。
+func (s *webSubService) ActiveFeedIDs() (map[string]bool, error) {
+	active := make(map[string]bool)
+	if s.callbackBaseURL == "" {
+		return active, nil
+	}
+	feedIDs, err := s.websubRepo.GetActiveFeedIDs(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range feedIDs {
+		active[id] = true
+	}
+	return active, nil
+}
+
+// Run は interval ごとに、期限が近づいた購読の更新をハブへ要求します。ctx がキャンセルされると終了します。
+func (s *webSubService) Run(ctx context.Context, interval time.Duration) {
+	if s.callbackBaseURL == "" {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.renewExpiring(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// renewExpiring は期限が websubRenewBefore 以内の購読の更新を要求します。
+func (s *webSubService) renewExpiring(ctx context.Context) {
+	now := time.Now()
+	subs, err := s.websubRepo.GetExpiring(now.Add(websubRenewBefore))
+	if err != nil {
+		log.Printf("Failed to get expiring websub subscriptions: %v", err)
+		return
+	}
+	for _, sub := range subs {
+		if ctx.Err() != nil {
+			return
+		}
+		if needsSubscribe(sub, now) {
+			s.subscribe(ctx, sub)
+		}
+	}
+}
@@ -0,0 +1,485 @@
+// Package snapshot は記事ページをオフラインで閲覧できる単一のHTMLとして保存する処理を提供します。
+//
+// 「後で見る」に追加した記事の元ページが削除・変更されても読めるよう、ページのHTMLを取得し、
+// 画像・スタイルシート・CSS から参照される画像やフォントを data URI として埋め込みます。
+// スクリプトや iframe などの外部に依存する要素とイベントハンドラ属性は除去します。
+// 生成したHTMLは内容のハッシュをキーとして Store に保存します。
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"feedapp/internal/fetcher"
+)
+
+const (
+	// DefaultMaxSize はスナップショット1件のデフォルトの最大サイズ（バイト）です。
+	DefaultMaxSize = 10 << 20
+	// maxResources は1件のスナップショットで取得する画像・スタイルシートの最大数です。
+	maxResources = 200
+	// maxImportDepth は展開する CSS の @import の最大の深さです。
+	maxImportDepth = 3
+)
+
+var (
+	// ErrNotHTML は取得したページがHTML文書でない場合に返されます。
+	ErrNotHTML = errors.New("page is not an HTML document")
+	// ErrTooLarge はページ自体がスナップショットの最大サイズを超えた場合に返されます。
+	ErrTooLarge = errors.New("snapshot exceeds the size limit")
+)
+
+var (
+	// cssURLPattern は CSS の url() 参照です。
+	cssURLPattern = regexp.MustCompile(`url\(\s*(?:"([^"]*)"|'([^']*)'|([^)\s]*))\s*\)`)
+	// cssImportPattern は CSS の @import 規則です。最後のグループはメディアクエリです。
+	cssImportPattern = regexp.MustCompile(`@import\s+(?:url\(\s*)?(?:"([^"]*)"|'([^']*)'|([^)\s;]+))\s*\)?([^;]*);`)
+)
+
+// removedTags はスナップショットから子要素を含めて除去するタグです。
+var removedTags = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Iframe:   true,
+	atom.Frame:    true,
+	atom.Frameset: true,
+	atom.Object:   true,
+	atom.Embed:    true,
+	atom.Applet:   true,
+	atom.Base:     true,
+	atom.Template: true,
+}
+
+// urlAttributes は絶対URLに変換する属性です。
+var urlAttributes = map[string]bool{
+	"href":   true,
+	"src":    true,
+	"action": true,
+	"poster": true,
+	"cite":   true,
+}
+
+// Capturer は記事ページを取得してスナップショットを作成します。
+type Capturer struct {
+	client  *fetcher.Client
+	maxSize int64
+}
+
+// New は client を使用してページを取得する Capturer を作成します。
+// maxSize はスナップショット1件の最大サイズで、0以下の場合は DefaultMaxSize を使用します。
+func New(client *fetcher.Client, maxSize int64) *Capturer {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	return &Capturer{client: client, maxSize: maxSize}
+}
+
+// Capture は pageURL のHTMLを取得し、画像とスタイルシートを埋め込んだHTMLを返します。
+//
+// 埋め込むと最大サイズを超える画像やスタイルシートは、埋め込まずに絶対URLでの参照のまま残します。
+// ページ自体が最大サイズを超える場合は ErrTooLarge を返します。
+func (c *Capturer) Capture(ctx context.Context, pageURL string) ([]byte, error) {
+	resp, err := c.client.Get(ctx, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	if !isHTML(mediaType(resp)) {
+		return nil, ErrNotHTML
+	}
+	if int64(len(resp.Body)) > c.maxSize {
+		return nil, ErrTooLarge
+	}
+	base, err := url.Parse(resp.URL)
+	if err != nil {
+		return nil, err
+	}
+	// スクリプトを除去するため、noscript の内容は要素として扱う
+	doc, err := html.ParseWithOptions(bytes.NewReader(resp.Body), html.ParseOptionEnableScripting(false))
+	if err != nil {
+		return nil, err
+	}
+
+	in := &inliner{
+		ctx:       ctx,
+		client:    c.client,
+		remaining: c.maxSize - int64(len(resp.Body)),
+		cache:     map[string]string{},
+	}
+	in.document(doc, base)
+
+	var b bytes.Buffer
+	if err := html.Render(&b, doc); err != nil {
+		return nil, err
+	}
+	if int64(b.Len()) > c.maxSize {
+		return nil, ErrTooLarge
+	}
+	return b.Bytes(), nil
+}
+
+// inliner はHTML文書が参照するリソースを取得して埋め込みます。
+type inliner struct {
+	ctx       context.Context
+	client    *fetcher.Client
+	remaining int64             // 埋め込みに使用できる残りのバイト数
+	fetched   int               // 取得したリソースの数
+	cache     map[string]string // 取得済みのURLと data URI（取得に失敗した場合は空文字）
+}
+
+// document は <base> を考慮した基準URLで文書全体を処理します。
+func (in *inliner) document(doc *html.Node, base *url.URL) {
+	if n := findFirst(doc, atom.Base); n != nil {
+		if href, ok := attr(n, "href"); ok {
+			if u, err := base.Parse(strings.TrimSpace(href)); err == nil {
+				base = u
+			}
+		}
+	}
+	in.walk(doc, base)
+}
+
+// walk は要素を再帰的に処理し、除去・埋め込み・URLの絶対化を行います。
+func (in *inliner) walk(n *html.Node, base *url.URL) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == html.ElementNode {
+			if removed(c) {
+				n.RemoveChild(c)
+				c = next
+				continue
+			}
+			in.element(c, base)
+		}
+		in.walk(c, base)
+		c = next
+	}
+}
+
+// removed はスナップショットから除去する要素かを返します。
+func removed(n *html.Node) bool {
+	if removedTags[n.DataAtom] {
+		return true
+	}
+	if n.DataAtom == atom.Meta {
+		// 再読み込みと、埋め込んだ data URI を妨げる Content-Security-Policy を除去する
+		equiv, _ := attr(n, "http-equiv")
+		equiv = strings.ToLower(strings.TrimSpace(equiv))
+		return equiv == "refresh" || equiv == "content-security-policy"
+	}
+	if n.DataAtom == atom.Source && n.Parent != nil && n.Parent.DataAtom == atom.Picture {
+		// picture 内の img を表示させるため、代替画像の指定は除去する
+		return true
+	}
+	return false
+}
+
+// element は1つの要素の属性を処理します。
+func (in *inliner) element(n *html.Node, base *url.URL) {
+	attrs := n.Attr[:0]
+	for _, a := range n.Attr {
+		key := strings.ToLower(a.Key)
+		if strings.HasPrefix(key, "on") || strings.HasPrefix(strings.ToLower(strings.TrimSpace(a.Val)), "javascript:") {
+			continue
+		}
+		attrs = append(attrs, a)
+	}
+	n.Attr = attrs
+
+	switch n.DataAtom {
+	case atom.Img:
+		in.image(n, base)
+	case atom.Link:
+		in.link(n, base)
+	case atom.Style:
+		if text := n.FirstChild; text != nil && text.Type == html.TextNode {
+			text.Data = escapeStyle(in.css(text.Data, base, 0))
+		}
+	}
+	for i, a := range n.Attr {
+		switch {
+		case a.Key == "style":
+			n.Attr[i].Val = in.css(a.Val, base, 0)
+		case urlAttributes[a.Key]:
+			n.Attr[i].Val = absolute(base, a.Val)
+		}
+	}
+}
+
+// image は img 要素の画像を埋め込みます。遅延読み込みの data-src・srcset の画像にも対応します。
+func (in *inliner) image(n *html.Node, base *url.URL) {
+	src, _ := attr(n, "src")
+	src = strings.TrimSpace(src)
+	if lazy, ok := attr(n, "data-src"); ok && (src == "" || strings.HasPrefix(src, "data:")) {
+		src = strings.TrimSpace(lazy)
+	}
+	if srcset, ok := attr(n, "srcset"); ok && src == "" {
+		src = firstSrcset(srcset)
+	}
+	removeAttr(n, "srcset", "sizes", "data-src", "data-srcset", "loading")
+	if src == "" || strings.HasPrefix(src, "data:") {
+		return
+	}
+	u := resolve(base, src)
+	if u == nil {
+		return
+	}
+	if data, ok := in.dataURI(u, isImage); ok {
+		setAttr(n, "src", data)
+		return
+	}
+	setAttr(n, "src", u.String())
+}
+
+// link は link 要素のスタイルシートとアイコンを埋め込みます。
+// スタイルシートは style 要素に置き換えます。
+func (in *inliner) link(n *html.Node, base *url.URL) {
+	href, _ := attr(n, "href")
+	rel, _ := attr(n, "rel")
+	u := resolve(base, strings.TrimSpace(href))
+	if u == nil {
+		return
+	}
+	rels := strings.Fields(strings.ToLower(rel))
+	switch {
+	case contains(rels, "stylesheet"):
+		css, final, ok := in.stylesheet(u)
+		if !ok {
+			return
+		}
+		style := &html.Node{Type: html.ElementNode, Data: "style", DataAtom: atom.Style}
+		if media, ok := attr(n, "media"); ok {
+			style.Attr = []html.Attribute{{Key: "media", Val: media}}
+		}
+		style.AppendChild(&html.Node{Type: html.TextNode, Data: escapeStyle(in.css(css, final, 0))})
+		n.Parent.InsertBefore(style, n)
+		n.Parent.RemoveChild(n)
+	case contains(rels, "icon"):
+		if data, ok := in.dataURI(u, isImage); ok {
+			setAttr(n, "href", data)
+		}
+	}
+}
+
+// css は CSS の @import を展開し、url() で参照するリソースを埋め込みます。
+func (in *inliner) css(css string, base *url.URL, depth int) string {
+	css = cssImportPattern.ReplaceAllStringFunc(css, func(m string) string {
+		groups := cssImportPattern.FindStringSubmatch(m)
+		u := resolve(base, firstNonEmpty(groups[1:4]...))
+		if u == nil {
+			return ""
+		}
+		media := strings.TrimSpace(groups[4])
+		imported, final, ok := in.stylesheet(u)
+		if !ok || depth >= maxImportDepth {
+			return `@import url("` + u.String() + `") ` + media + ";"
+		}
+		imported = in.css(imported, final, depth+1)
+		if media != "" {
+			return "@media " + media + " {\n" + imported + "\n}"
+		}
+		return imported
+	})
+	return cssURLPattern.ReplaceAllStringFunc(css, func(m string) string {
+		groups := cssURLPattern.FindStringSubmatch(m)
+		ref := strings.TrimSpace(firstNonEmpty(groups[1:4]...))
+		if ref == "" || strings.HasPrefix(ref, "data:") || strings.HasPrefix(ref, "#") {
+			return m
+		}
+		u := resolve(base, ref)
+		if u == nil {
+			return m
+		}
+		if data, ok := in.dataURI(u, isEmbeddable); ok {
+			return `url("` + data + `")`
+		}
+		return `url("` + u.String() + `")`
+	})
+}
+
+// stylesheet はスタイルシートを取得し、内容と相対URLの解決に使用する最終URLを返します。
+func (in *inliner) stylesheet(u *url.URL) (string, *url.URL, bool) {
+	resp, ok := in.fetch(u)
+	if !ok || !isEmbeddable(mediaType(resp)) || !in.charge(len(resp.Body)) {
+		return "", nil, false
+	}
+	final, err := url.Parse(resp.URL)
+	if err != nil {
+		final = u
+	}
+	return string(resp.Body), final, true
+}
+
+// dataURI はリソースを取得して data URI に変換します。
+// accept がメディアタイプを受け入れない場合や、最大サイズを超える場合は false を返します。
+func (in *inliner) dataURI(u *url.URL, accept func(string) bool) (string, bool) {
+	key := u.String()
+	data, cached := in.cache[key]
+	if !cached {
+		resp, ok := in.fetch(u)
+		if ok && accept(mediaType(resp)) {
+			data = "data:" + mediaType(resp) + ";base64," + base64.StdEncoding.EncodeToString(resp.Body)
+		}
+		in.cache[key] = data
+	}
+	if data == "" || !in.charge(len(data)) {
+		return "", false
+	}
+	return data, true
+}
+
+// fetch はリソースの取得数と残りのサイズの範囲内でリソースを取得します。
+func (in *inliner) fetch(u *url.URL) (*fetcher.Response, bool) {
+	if in.fetched >= maxResources || in.remaining <= 0 || in.ctx.Err() != nil {
+		return nil, false
+	}
+	in.fetched++
+	resp, err := in.client.Get(in.ctx, u.String())
+	if err != nil {
+		return nil, false
+	}
+	return resp, true
+}
+
+// charge は埋め込みに n バイトを使用します。残りのサイズを超える場合は false を返します。
+func (in *inliner) charge(n int) bool {
+	if int64(n) > in.remaining {
+		return false
+	}
+	in.remaining -= int64(n)
+	return true
+}
+
+// mediaType はレスポンスのメディアタイプを返します。Content-Type がない場合は内容から判定します。
+func mediaType(resp *fetcher.Response) string {
+	if value := resp.Header.Get("Content-Type"); value != "" {
+		if mt, _, err := mime.ParseMediaType(value); err == nil {
+			return strings.ToLower(mt)
+		}
+	}
+	mt, _, _ := mime.ParseMediaType(http.DetectContentType(resp.Body))
+	return mt
+}
+
+// isHTML はメディアタイプがHTML文書かを返します。
+func isHTML(mt string) bool {
+	return mt == "text/html" || mt == "application/xhtml+xml"
+}
+
+// isImage はメディアタイプが画像かを返します。
+func isImage(mt string) bool {
+	return strings.HasPrefix(mt, "image/")
+}
+
+// isEmbeddable は CSS から参照するリソースとして埋め込めるメディアタイプかを返します。
+// 誤ってHTMLのエラーページなどを埋め込まないよう、HTMLとスクリプトは除外します。
+func isEmbeddable(mt string) bool {
+	return mt != "" && !isHTML(mt) && !strings.Contains(mt, "javascript")
+}
+
+// resolve は参照を基準URLで解決します。http・https 以外のURLは nil を返します。
+func resolve(base *url.URL, ref string) *url.URL {
+	if ref == "" {
+		return nil
+	}
+	u, err := base.Parse(ref)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil
+	}
+	return u
+}
+
+// absolute は属性値のURLを絶対URLに変換します。ページ内リンクと data URI はそのまま返します。
+func absolute(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.HasPrefix(ref, "#") || strings.HasPrefix(ref, "data:") {
+		return ref
+	}
+	u, err := base.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return u.String()
+}
+
+// firstSrcset は srcset の最初の候補のURLを返します。
+func firstSrcset(srcset string) string {
+	candidate, _, _ := strings.Cut(strings.TrimSpace(srcset), ",")
+	fields := strings.Fields(candidate)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// escapeStyle は style 要素の内容が要素の外に出ないよう終了タグをエスケープします。
+func escapeStyle(css string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(css, "</style", `<\/style`), "</STYLE", `<\/STYLE`)
+}
+
+func findFirst(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findFirst(c, a); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func attr(n *html.Node, key string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+func setAttr(n *html.Node, key, val string) {
+	for i, a := range n.Attr {
+		if a.Key == key {
+			n.Attr[i].Val = val
+			return
+		}
+	}
+	n.Attr = append(n.Attr, html.Attribute{Key: key, Val: val})
+}
+
+func removeAttr(n *html.Node, keys ...string) {
+	attrs := n.Attr[:0]
+	for _, a := range n.Attr {
+		if !contains(keys, a.Key) {
+			attrs = append(attrs, a)
+		}
+	}
+	n.Attr = attrs
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
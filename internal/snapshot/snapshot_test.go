@@ -0,0 +1,137 @@
+package snapshot
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"feedapp/internal/fetcher"
+)
+
+// pngPixel は 1x1 の PNG 画像です。
+var pngPixel, _ = base64.StdEncoding.DecodeString("iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg==")
+
+// newSite はスナップショットの取得元となるテスト用サイトを起動します。
+func newSite(t *testing.T, page string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/article", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(page))
+	})
+	mux.HandleFunc("/img/photo.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(pngPixel)
+	})
+	mux.HandleFunc("/css/site.css", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/css")
+		w.Write([]byte(`@import "print.css" print; body { background: url(../img/photo.png); }`))
+	})
+	mux.HandleFunc("/css/print.css", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/css")
+		w.Write([]byte(`h1 { color: black; }`))
+	})
+	mux.HandleFunc("/missing.png", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCapture(t *testing.T) {
+	server := newSite(t, `<!DOCTYPE html>
+<html>
+<head>
+  <title>Example</title>
+  <meta http-equiv="refresh" content="5; url=/other">
+  <link rel="stylesheet" href="/css/site.css">
+  <script>var tracking = true;</script>
+</head>
+<body onload="track()">
+  <h1>Article title</h1>
+  <p>See <a href="/related">related</a> and <a href="#notes">notes</a>.</p>
+  <img src="/img/photo.png" alt="photo">
+  <img src="data:image/gif;base64,R0lGODlhAQABAAAAACw=" data-src="img/photo.png" alt="lazy">
+  <img src="/missing.png" alt="missing">
+  <a href="javascript:alert(1)">click</a>
+  <iframe src="https://ads.example.com/"></iframe>
+</body>
+</html>`)
+
+	capturer := New(fetcher.NewClient(), 0)
+	snapshot, err := capturer.Capture(context.Background(), server.URL+"/article")
+	require.NoError(t, err)
+	document := string(snapshot)
+
+	photo := "data:image/png;base64," + base64.StdEncoding.EncodeToString(pngPixel)
+	assert.Contains(t, document, `<img src="`+photo+`" alt="photo"/>`)
+	assert.Contains(t, document, `<img src="`+photo+`" alt="lazy"/>`)
+	assert.Contains(t, document, `<img src="`+server.URL+`/missing.png" alt="missing"/>`)
+	assert.Contains(t, document, `<style>@media print {`)
+	assert.Contains(t, document, `h1 { color: black; }`)
+	assert.Contains(t, document, `body { background: url("`+photo+`"); }`)
+	assert.Contains(t, document, `<a href="`+server.URL+`/related">`)
+	assert.Contains(t, document, `<a href="#notes">`)
+	assert.NotContains(t, document, "<link")
+	assert.NotContains(t, document, "tracking")
+	assert.NotContains(t, document, "onload")
+	assert.NotContains(t, document, "javascript:")
+	assert.NotContains(t, document, "refresh")
+	assert.NotContains(t, document, "iframe")
+}
+
+func TestCapture_SizeLimit(t *testing.T) {
+	page := `<html><body><p>` + strings.Repeat("text ", 40) + `</p><img src="/img/photo.png"></body></html>`
+	server := newSite(t, page)
+
+	// 画像を埋め込むと最大サイズを超える場合は参照のまま残す
+	t.Run("should keep resources that exceed the limit as links", func(t *testing.T) {
+		capturer := New(fetcher.NewClient(), int64(len(page)+64))
+		snapshot, err := capturer.Capture(context.Background(), server.URL+"/article")
+		require.NoError(t, err)
+		assert.Contains(t, string(snapshot), `<img src="`+server.URL+`/img/photo.png"/>`)
+	})
+
+	// ページ自体が最大サイズを超える場合はエラー
+	t.Run("should fail if page exceeds the limit", func(t *testing.T) {
+		capturer := New(fetcher.NewClient(), 100)
+		_, err := capturer.Capture(context.Background(), server.URL+"/article")
+		assert.ErrorIs(t, err, ErrTooLarge)
+	})
+}
+
+func TestCapture_NotHTML(t *testing.T) {
+	server := newSite(t, "")
+
+	capturer := New(fetcher.NewClient(), 0)
+	_, err := capturer.Capture(context.Background(), server.URL+"/img/photo.png")
+	assert.ErrorIs(t, err, ErrNotHTML)
+}
+
+func TestStore(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	hash, err := store.Put([]byte("<html>snapshot</html>"))
+	require.NoError(t, err)
+	assert.Len(t, hash, 64)
+
+	// 同じ内容は同じハッシュになる
+	again, err := store.Put([]byte("<html>snapshot</html>"))
+	require.NoError(t, err)
+	assert.Equal(t, hash, again)
+
+	data, err := store.Get(hash)
+	require.NoError(t, err)
+	assert.Equal(t, "<html>snapshot</html>", string(data))
+
+	_, err = store.Get(strings.Repeat("0", 64))
+	assert.ErrorIs(t, err, ErrBlobNotFound)
+	_, err = store.Get("../../etc/passwd")
+	assert.ErrorIs(t, err, ErrBlobNotFound)
+}
@@ -0,0 +1,81 @@
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// ErrBlobNotFound は指定されたハッシュのデータが保存されていない場合に返されます。
+var ErrBlobNotFound = errors.New("blob not found")
+
+// hashPattern は Store のキーとなる SHA-256 の16進表記です。
+var hashPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// Store はデータを内容の SHA-256 ハッシュをキーとしてディスクに保存するブロブストアです。
+//
+// 同じ内容のデータは一度だけ保存されます。ファイルはハッシュの先頭2文字の
+// ディレクトリに分けて配置します（例: <dir>/ab/abcdef...）。
+type Store struct {
+	dir string
+}
+
+// NewStore は dir にデータを保存する Store を作成します。ディレクトリは最初の保存時に作成します。
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Put はデータを保存し、キーとなるハッシュを返します。既に保存されている場合は書き込みません。
+func (s *Store) Put(data []byte) (string, error) {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	path := s.path(hash)
+	if _, err := os.Stat(path); err == nil {
+		return hash, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create blob directory: %w", err)
+	}
+	// 書き込み途中のファイルを読み出さないよう、一時ファイルに書き込んでから名前を変更する
+	tmp, err := os.CreateTemp(filepath.Dir(path), hash+".tmp-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create blob file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write blob: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write blob: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to store blob: %w", err)
+	}
+	return hash, nil
+}
+
+// Get はハッシュに対応するデータを読み出します。
+func (s *Store) Get(hash string) ([]byte, error) {
+	if !hashPattern.MatchString(hash) {
+		return nil, ErrBlobNotFound
+	}
+	data, err := os.ReadFile(s.path(hash))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrBlobNotFound
+		}
+		return nil, fmt.Errorf("failed to read blob: %w", err)
+	}
+	return data, nil
+}
+
+// path はハッシュに対応するファイルのパスを返します。
+func (s *Store) path(hash string) string {
+	return filepath.Join(s.dir, hash[:2], hash)
+}
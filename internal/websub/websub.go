@@ -0,0 +1,133 @@
+// Package websub は WebSub（旧 PubSubHubbub）の購読者側の処理を提供します。
+//
+// フィードが rel="hub" でハブを通知している場合、ハブへ購読を要求すると、フィードの更新時に
+// ハブからコールバックURLへ新しい内容がプッシュされます。ハブは購読の要求を受け取ると
+// コールバックURLへ hub.challenge を付けた GET リクエストを送信して購読者の意図を確認し、
+// 購読者がその値をそのまま返すことで購読が成立します。プッシュされる内容は購読時に
+// 指定した hub.secret による HMAC 署名（X-Hub-Signature ヘッダー）で検証します。
+//
+// 仕様: https://www.w3.org/TR/websub/
+package websub
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"feedapp/internal/fetcher"
+)
+
+// hub.mode の値です。
+const (
+	ModeSubscribe   = "subscribe"   // 購読
+	ModeUnsubscribe = "unsubscribe" // 購読解除
+	ModeDenied      = "denied"      // ハブによる購読の拒否
+)
+
+// SignatureHeader はプッシュされた内容の署名を含むヘッダーです（例: "sha256=<16進数>"）。
+const SignatureHeader = "X-Hub-Signature"
+
+var (
+	// ErrMissingSignature は署名ヘッダーがない場合に返されます。
+	ErrMissingSignature = errors.New("missing signature")
+	// ErrInvalidSignature は署名の形式・アルゴリズム・値が不正な場合に返されます。
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// signatureAlgorithms は X-Hub-Signature で使用できるハッシュ関数です。
+var signatureAlgorithms = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha384": sha512.New384,
+	"sha512": sha512.New,
+}
+
+// Request はハブへの購読の要求内容です。
+type Request struct {
+	Hub          string // ハブのURL
+	Topic        string // 購読するフィードのURL（hub.topic）
+	Callback     string // 更新を受け取るコールバックURL（hub.callback）
+	Secret       string // 署名の鍵（hub.secret、空の場合は署名なし）
+	LeaseSeconds int    // 要求する購読期間（秒、0以下の場合はハブの既定値）
+}
+
+// Client はハブへ購読を要求するクライアントです。
+type Client struct {
+	client *fetcher.Client
+}
+
+// NewClient は client を使用してハブへリクエストを送信する Client を作成します。
+func NewClient(client *fetcher.Client) *Client {
+	return &Client{client: client}
+}
+
+// Subscribe はハブへ購読を要求します。
+//
+// ハブが要求を受け付けた（2xx を返した）場合に nil を返します。購読が成立するのは、
+// その後ハブから届く意図の確認にコールバックが応答した時点です。
+func (c *Client) Subscribe(ctx context.Context, req Request) error {
+	return c.send(ctx, ModeSubscribe, req)
+}
+
+// send は hub.mode を指定してハブへフォームを POST します。
+func (c *Client) send(ctx context.Context, mode string, req Request) error {
+	form := url.Values{
+		"hub.mode":     {mode},
+		"hub.topic":    {req.Topic},
+		"hub.callback": {req.Callback},
+	}
+	if req.Secret != "" {
+		form.Set("hub.secret", req.Secret)
+	}
+	if req.LeaseSeconds > 0 {
+		form.Set("hub.lease_seconds", strconv.Itoa(req.LeaseSeconds))
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.Hub, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create hub request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if _, err := c.client.Do(httpReq); err != nil {
+		return fmt.Errorf("failed to %s to hub %s: %w", mode, req.Hub, err)
+	}
+	return nil
+}
+
+// Sign は body の HMAC 署名を SignatureHeader の形式で返します。algorithm は "sha1" / "sha256" / "sha384" / "sha512" のいずれかです。
+func Sign(algorithm, secret string, body []byte) string {
+	newHash, ok := signatureAlgorithms[algorithm]
+	if !ok {
+		return ""
+	}
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write(body)
+	return algorithm + "=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature は SignatureHeader の値が secret による body の署名と一致するかを検証します。
+func VerifySignature(secret, signature string, body []byte) error {
+	if signature == "" {
+		return ErrMissingSignature
+	}
+	algorithm, value, ok := strings.Cut(signature, "=")
+	algorithm = strings.ToLower(strings.TrimSpace(algorithm))
+	if _, supported := signatureAlgorithms[algorithm]; !ok || !supported {
+		return fmt.Errorf("%w: unsupported format %q", ErrInvalidSignature, algorithm)
+	}
+	expected := Sign(algorithm, secret, body)
+	if !hmac.Equal([]byte(expected), []byte(algorithm+"="+strings.ToLower(strings.TrimSpace(value)))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package websub_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"feedapp/internal/fetcher"
+	"feedapp/internal/websub"
+	"feedapp/internal/websub/websubtest"
+)
+
+func TestClient_Subscribe(t *testing.T) {
+	hub := websubtest.NewHub()
+	defer hub.Close()
+	client := websub.NewClient(fetcher.NewClient())
+
+	err := client.Subscribe(context.Background(), websub.Request{
+		Hub:          hub.URL,
+		Topic:        "https://example.com/feed.xml",
+		Callback:     "https://feeds.example.com/websub/callback/feed-1",
+		Secret:       "secret-1",
+		LeaseSeconds: 3600,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []websubtest.Subscription{{
+		Mode:         websub.ModeSubscribe,
+		Topic:        "https://example.com/feed.xml",
+		Callback:     "https://feeds.example.com/websub/callback/feed-1",
+		Secret:       "secret-1",
+		LeaseSeconds: 3600,
+	}}, hub.Requests())
+}
+
+func TestClient_Subscribe_hubErrors(t *testing.T) {
+	client := websub.NewClient(fetcher.NewClient())
+	req := websub.Request{Topic: "https://example.com/feed.xml", Callback: "https://feeds.example.com/websub/callback/feed-1"}
+
+	t.Run("rejected", func(t *testing.T) {
+		hub := websubtest.NewHub()
+		defer hub.Close()
+		hub.SetStatus(http.StatusBadRequest)
+
+		req.Hub = hub.URL
+		err := client.Subscribe(context.Background(), req)
+		var statusErr *fetcher.StatusError
+		require.ErrorAs(t, err, &statusErr)
+		assert.Equal(t, http.StatusBadRequest, statusErr.StatusCode)
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		hub := websubtest.NewHub()
+		hub.Close()
+
+		req.Hub = hub.URL
+		assert.Error(t, client.Subscribe(context.Background(), req))
+	})
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`<feed xmlns="http://www.w3.org/2005/Atom"></feed>`)
+
+	for _, algorithm := range []string{"sha1", "sha256", "sha384", "sha512"} {
+		t.Run(algorithm, func(t *testing.T) {
+			assert.NoError(t, websub.VerifySignature("secret", websub.Sign(algorithm, "secret", body), body))
+		})
+	}
+
+	tests := []struct {
+		name      string
+		signature string
+		want      error
+	}{
+		{name: "missing", signature: "", want: websub.ErrMissingSignature},
+		{name: "wrong secret", signature: websub.Sign("sha256", "other", body), want: websub.ErrInvalidSignature},
+		{name: "unsupported algorithm", signature: "md5=0123", want: websub.ErrInvalidSignature},
+		{name: "malformed", signature: "sha256", want: websub.ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, websub.VerifySignature("secret", tt.signature, body), tt.want)
+		})
+	}
+
+	assert.ErrorIs(t, websub.VerifySignature("secret", websub.Sign("sha256", "secret", body), []byte("tampered")), websub.ErrInvalidSignature)
+}
@@ -0,0 +1,143 @@
+// Package websubtest はテスト用の WebSub ハブを提供します。
+//
+// httptest サーバーで購読の要求を受け付けて記録し、テストから任意のタイミングで
+// コールバックURLへの意図の確認（Verify）と更新のプッシュ（Publish）を行えます。
+package websubtest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"feedapp/internal/websub"
+)
+
+// Subscription はハブが受け付けた購読の要求です。
+type Subscription struct {
+	Mode         string
+	Topic        string
+	Callback     string
+	Secret       string
+	LeaseSeconds int
+}
+
+// Hub はテスト用の WebSub ハブです。
+type Hub struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	status   int
+	requests []Subscription
+}
+
+// NewHub はテスト用ハブを起動します。使用後は Close を呼び出してください。
+func NewHub() *Hub {
+	h := &Hub{status: http.StatusAccepted}
+	h.Server = httptest.NewServer(http.HandlerFunc(h.handle))
+	return h
+}
+
+// SetStatus は購読の要求に返すHTTPステータスを設定します（既定値は 202 Accepted）。
+func (h *Hub) SetStatus(status int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.status = status
+}
+
+// Requests は受け付けた購読の要求を受信順に返します。
+func (h *Hub) Requests() []Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]Subscription(nil), h.requests...)
+}
+
+// Verify は購読のコールバックURLへ意図の確認を送信し、購読者が hub.challenge を
+// そのまま返した場合に true を返します。
+func (h *Hub) Verify(sub Subscription) (bool, error) {
+	challenge := randomString()
+	callback, err := url.Parse(sub.Callback)
+	if err != nil {
+		return false, err
+	}
+	query := callback.Query()
+	query.Set("hub.mode", sub.Mode)
+	query.Set("hub.topic", sub.Topic)
+	query.Set("hub.challenge", challenge)
+	if sub.Mode == websub.ModeSubscribe {
+		query.Set("hub.lease_seconds", strconv.Itoa(sub.LeaseSeconds))
+	}
+	callback.RawQuery = query.Encode()
+
+	resp, err := http.Get(callback.String())
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+	return resp.StatusCode/100 == 2 && string(body) == challenge, nil
+}
+
+// Publish は購読のコールバックURLへ更新された内容をプッシュし、購読者の応答のステータスを返します。
+// 購読に鍵が設定されている場合は HMAC-SHA256 で署名します。
+func (h *Hub) Publish(sub Subscription, contentType string, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, sub.Callback, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Link", fmt.Sprintf(`<%s>; rel="hub", <%s>; rel="self"`, h.URL, sub.Topic))
+	if sub.Secret != "" {
+		req.Header.Set(websub.SignatureHeader, websub.Sign("sha256", sub.Secret, body))
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func (h *Hub) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	sub := Subscription{
+		Mode:     r.PostForm.Get("hub.mode"),
+		Topic:    r.PostForm.Get("hub.topic"),
+		Callback: r.PostForm.Get("hub.callback"),
+		Secret:   r.PostForm.Get("hub.secret"),
+	}
+	sub.LeaseSeconds, _ = strconv.Atoi(r.PostForm.Get("hub.lease_seconds"))
+	if (sub.Mode != websub.ModeSubscribe && sub.Mode != websub.ModeUnsubscribe) || sub.Topic == "" || sub.Callback == "" {
+		http.Error(w, "invalid hub request", http.StatusBadRequest)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.requests = append(h.requests, sub)
+	w.WriteHeader(h.status)
+}
+
+func randomString() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
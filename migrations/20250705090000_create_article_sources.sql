@@ -0,0 +1,22 @@
+-- 20250705090000_create_article_sources.sql
+
+-- 重複判定用の正規化URL（utm_*・フラグメント・末尾スラッシュ除去、https統一）
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS normalized_url TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_articles_normalized_url ON articles (normalized_url);
+
+-- 記事とフィードの多対多の関連
+-- 同一記事が複数のフィードから配信された場合も記事本体（既読状態を含む）は1件のみ保持する
+CREATE TABLE IF NOT EXISTS article_sources (
+    article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    feed_id UUID NOT NULL REFERENCES feeds(id) ON DELETE CASCADE,
+    guid TEXT, -- フィード内でのエントリーGUID（存在する場合）
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (article_id, feed_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_article_sources_feed_id_guid ON article_sources (feed_id, guid) WHERE guid IS NOT NULL;
+
+-- 既存記事の所属フィードを関連テーブルへ移行
+INSERT INTO article_sources (article_id, feed_id)
+SELECT id, feed_id FROM articles WHERE feed_id IS NOT NULL
+ON CONFLICT DO NOTHING;
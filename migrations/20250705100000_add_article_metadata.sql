@@ -0,0 +1,26 @@
+-- 20250705100000_add_article_metadata.sql
+
+-- フィードのエントリーから取得する付加情報
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS guid TEXT;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS author TEXT;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS summary TEXT;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS image_url TEXT;
+
+-- 記事のカテゴリ（RSSの<category>、Atomの<category term>）
+CREATE TABLE IF NOT EXISTS article_categories (
+    article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL, -- フィード内での出現順
+    name TEXT NOT NULL,
+    PRIMARY KEY (article_id, position)
+);
+
+-- 記事の添付メディア（RSSの<enclosure>、Atomの<link rel="enclosure">、Media RSS）
+CREATE TABLE IF NOT EXISTS article_enclosures (
+    article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL, -- フィード内での出現順
+    url TEXT NOT NULL,
+    mime_type VARCHAR(255),
+    length BIGINT, -- バイト数
+    duration INTEGER, -- 再生時間（秒）
+    PRIMARY KEY (article_id, position)
+);
@@ -0,0 +1,9 @@
+-- 20250705110000_add_article_media_state.sql
+
+-- ポッドキャストのエピソード番号（itunes:episode）
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS episode INTEGER;
+
+-- 添付メディアの再生状態（クライアント間で同期する）
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS playback_position_seconds INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS played BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS downloaded BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,9 @@
+-- 20250705120000_add_article_raw_content.sql
+
+-- サニタイズ前の元HTML（サニタイズルール変更時の再処理用）
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS content_raw TEXT;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS summary_raw TEXT;
+
+-- content / summary に適用済みのサニタイズルールのバージョン（0 は未サニタイズ）
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS sanitizer_version INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_articles_sanitizer_version ON articles (sanitizer_version);
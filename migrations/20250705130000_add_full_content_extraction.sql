@@ -0,0 +1,7 @@
+-- 20250705130000_add_full_content_extraction.sql
+
+-- 新着記事の本文を記事ページから抽出するかどうか
+ALTER TABLE feeds ADD COLUMN IF NOT EXISTS fetch_full_content BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- 記事ページから本文を抽出した日時（未抽出の場合は NULL）
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS extracted_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,39 @@
+-- 20250705150000_create_subscriptions.sql
+
+-- ユーザーごとのフィード購読。feeds は全ユーザーで共有する取得元として一度だけ取得し、
+-- 表示名とフォルダは購読ごとに保持する。
+CREATE TABLE IF NOT EXISTS subscriptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    feed_id UUID NOT NULL REFERENCES feeds(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    folder_id UUID REFERENCES folders(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, feed_id)
+);
+CREATE INDEX IF NOT EXISTS idx_subscriptions_feed_id ON subscriptions (feed_id);
+CREATE INDEX IF NOT EXISTS idx_subscriptions_folder_id ON subscriptions (folder_id);
+
+-- ユーザーごとの記事の状態。articles.is_read / is_later などを置き換える。
+-- 行が存在しない記事は未読・通常・未再生として扱う。
+CREATE TABLE IF NOT EXISTS article_states (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    is_read BOOLEAN NOT NULL DEFAULT FALSE,
+    is_later BOOLEAN NOT NULL DEFAULT FALSE,
+    playback_position_seconds INTEGER NOT NULL DEFAULT 0,
+    played BOOLEAN NOT NULL DEFAULT FALSE,
+    downloaded BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, article_id)
+);
+CREATE INDEX IF NOT EXISTS idx_article_states_article_id ON article_states (article_id);
+
+-- フォルダの所有者をユーザーに関連付ける
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'folders_user_id_fkey') THEN
+        ALTER TABLE folders ADD CONSTRAINT folders_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+    END IF;
+END $$;
+CREATE INDEX IF NOT EXISTS idx_folders_user_id ON folders (user_id);
@@ -0,0 +1,12 @@
+-- 20250705160000_create_user_identities.sql
+
+-- OpenID Connect などの外部認証のアカウントとローカルユーザーの対応。
+-- 発行者（issuer）と sub クレームの組でユーザーを識別する。
+CREATE TABLE IF NOT EXISTS user_identities (
+    issuer TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (issuer, subject)
+);
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities (user_id);
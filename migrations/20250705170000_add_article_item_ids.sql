@@ -0,0 +1,6 @@
+-- 20250705170000_add_article_item_ids.sql
+
+-- Google Reader API など整数の記事IDを必要とするクライアント向けの連番。
+-- 追加した順に採番されるため、記事の取得順のカーソルとしても使用する。
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS item_id BIGSERIAL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_articles_item_id ON articles (item_id);
@@ -0,0 +1,12 @@
+-- 20250705180000_add_fever_api.sql
+
+-- Fever API の api_key（MD5(メールアドレス:Feverパスワード)）の SHA-256 ハッシュ。
+-- NULL の場合は Fever API を利用できない。
+ALTER TABLE users ADD COLUMN IF NOT EXISTS fever_key_hash TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_fever_key_hash ON users (fever_key_hash);
+
+-- Fever API など整数IDを必要とするクライアント向けの連番。記事は articles.item_id を使用する。
+ALTER TABLE feeds ADD COLUMN IF NOT EXISTS int_id BIGSERIAL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_feeds_int_id ON feeds (int_id);
+ALTER TABLE folders ADD COLUMN IF NOT EXISTS int_id BIGSERIAL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_folders_int_id ON folders (int_id);
@@ -0,0 +1,37 @@
+-- 20250705190000_create_webhooks.sql
+
+-- 新着記事を外部サービス（Slack・Discord・社内ツールなど）へ通知する Webhook。
+-- folder_id / feed_id / keyword が設定されている場合は、すべてに一致する記事のみ通知する。
+-- 絞り込み対象のフォルダやフィードが削除された場合は、すべての記事に一致しないよう Webhook も削除する。
+CREATE TABLE IF NOT EXISTS webhooks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    url TEXT NOT NULL,
+    folder_id UUID REFERENCES folders(id) ON DELETE CASCADE,
+    feed_id UUID REFERENCES feeds(id) ON DELETE CASCADE,
+    keyword TEXT NOT NULL DEFAULT '',
+    payload_template TEXT NOT NULL DEFAULT '', -- 空の場合は標準のJSONペイロードを送信する
+    secret TEXT NOT NULL,                      -- HMAC-SHA256 署名の鍵
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_webhooks_user_id ON webhooks (user_id);
+
+-- Webhook の配信キュー兼配信ログ。status が pending の配信は next_attempt_at 以降に送信し、
+-- 失敗した場合は attempts を増やして再試行する。
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    article_id UUID REFERENCES articles(id) ON DELETE SET NULL,
+    event VARCHAR(64) NOT NULL,
+    payload TEXT NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending', -- pending / succeeded / failed
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_status INTEGER,
+    error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP WITH TIME ZONE,
+    last_attempt_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_pending ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
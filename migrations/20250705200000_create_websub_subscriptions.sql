@@ -0,0 +1,16 @@
+-- 20250705200000_create_websub_subscriptions.sql
+
+-- フィードが通知する WebSub ハブへの購読。ハブから更新がプッシュされるフィードは
+-- 定期取得の間隔を延ばし、購読が切れた場合やハブに接続できない場合は通常のポーリングに戻す。
+CREATE TABLE IF NOT EXISTS websub_subscriptions (
+    feed_id UUID PRIMARY KEY REFERENCES feeds(id) ON DELETE CASCADE,
+    hub TEXT NOT NULL,
+    topic TEXT NOT NULL,
+    secret TEXT NOT NULL,                          -- X-Hub-Signature の HMAC の鍵
+    state VARCHAR(16) NOT NULL DEFAULT 'pending',  -- pending / active / failed
+    lease_expires_at TIMESTAMP WITH TIME ZONE,
+    last_error TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_websub_subscriptions_lease ON websub_subscriptions (lease_expires_at) WHERE state = 'active';
@@ -0,0 +1,36 @@
+-- 20250705210000_create_rules.sql
+
+-- ユーザーごとのタグ。記事は全ユーザーで共有するため、タグと記事の関連もユーザーのタグを介して保持する。
+CREATE TABLE IF NOT EXISTS tags (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS article_tags (
+    tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tag_id, article_id)
+);
+CREATE INDEX IF NOT EXISTS idx_article_tags_article_id ON article_tags (article_id);
+
+-- 取り込み時に記事を自動的に処理するルール。folder_id / feed_id がいずれも NULL の場合は
+-- 所有者が購読しているすべてのフィードに適用する。conditions は {field, operator, value, ignore_case} の配列。
+CREATE TABLE IF NOT EXISTS rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    folder_id UUID REFERENCES folders(id) ON DELETE CASCADE,
+    feed_id UUID REFERENCES feeds(id) ON DELETE CASCADE,
+    conditions JSONB NOT NULL,
+    action VARCHAR(16) NOT NULL, -- mark_read / mark_later / skip / add_tag
+    tag VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_rules_user_id ON rules (user_id);
+
+-- ルールの skip で一覧から除外した記事。除外した記事はそのユーザーからは存在しないものとして扱う。
+ALTER TABLE article_states ADD COLUMN IF NOT EXISTS is_hidden BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,18 @@
+-- 20250705220000_create_annotations.sql
+
+-- 記事の本文に付けたユーザーごとの注釈。selector_* は W3C Web Annotation の TextQuoteSelector
+-- （exact / prefix / suffix）で、本文中の箇所を引用した文字列とその前後の文字列で特定する。
+CREATE TABLE IF NOT EXISTS annotations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    selector_exact TEXT NOT NULL,
+    selector_prefix TEXT NOT NULL DEFAULT '',
+    selector_suffix TEXT NOT NULL DEFAULT '',
+    body TEXT NOT NULL DEFAULT '',
+    color VARCHAR(16) NOT NULL DEFAULT 'yellow', -- yellow / green / blue / pink / purple
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_annotations_user_id ON annotations (user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_annotations_article_id ON annotations (article_id, user_id);
@@ -0,0 +1,13 @@
+-- 20250705230000_add_article_retention.sql
+
+-- フィードごとの記事の保持設定。NULL の場合は全体設定（config の retention）を使用し、0 は無期限・無制限を表す。
+ALTER TABLE feeds ADD COLUMN IF NOT EXISTS retention_days INTEGER;
+ALTER TABLE feeds ADD COLUMN IF NOT EXISTS max_articles INTEGER;
+
+-- 保持期間の経過などで削除した記事の正規化URL。次回の取得で未読として再登録しないために保持する。
+CREATE TABLE IF NOT EXISTS purged_articles (
+    normalized_url TEXT PRIMARY KEY,
+    feed_id UUID REFERENCES feeds(id) ON DELETE CASCADE,
+    purged_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_purged_articles_purged_at ON purged_articles (purged_at);
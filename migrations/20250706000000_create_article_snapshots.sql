@@ -0,0 +1,14 @@
+-- 20250706000000_create_article_snapshots.sql
+
+-- 「後で見る」に追加した記事の元ページのスナップショット。HTML本体は内容の SHA-256 をキーとして
+-- ディスク上のブロブストアに保存し、このテーブルには取得状況とキーのみを保持する。
+CREATE TABLE IF NOT EXISTS article_snapshots (
+    article_id UUID PRIMARY KEY REFERENCES articles(id) ON DELETE CASCADE,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending', -- pending / ready / failed
+    blob_hash CHAR(64),
+    size BIGINT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    requested_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    captured_at TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX IF NOT EXISTS idx_article_snapshots_pending ON article_snapshots (requested_at) WHERE status = 'pending';
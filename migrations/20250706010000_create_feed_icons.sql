@@ -0,0 +1,13 @@
+-- 20250706010000_create_feed_icons.sql
+
+-- フィードのサイトのアイコン（ファビコン）。フィードごとに1件で、すべての購読者に共有する。
+-- アイコンが見つからなかった場合は data を NULL とし、checked_at で次回の確認時期を判定する。
+CREATE TABLE IF NOT EXISTS feed_icons (
+    feed_id UUID PRIMARY KEY REFERENCES feeds(id) ON DELETE CASCADE,
+    data BYTEA,
+    mime_type VARCHAR(64) NOT NULL DEFAULT '',
+    hash CHAR(64),
+    url TEXT NOT NULL DEFAULT '',
+    fetched_at TIMESTAMP WITH TIME ZONE,
+    checked_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,8 @@
+-- 20250706020000_add_feed_fetch_limits.sql
+
+-- フィードごとの取得の制限。NULL の場合は全体設定（config の fetch）を使用する。
+-- rate_limit は取得先のホストへの1秒あたりのリクエスト数、max_connections は同時リクエスト数で、0 は無制限を表す。
+-- respect_robots が NULL の場合は、プラグイン種別ごとの全体設定（fetch.robots_plugin_types）に従う。
+ALTER TABLE feeds ADD COLUMN IF NOT EXISTS rate_limit DOUBLE PRECISION;
+ALTER TABLE feeds ADD COLUMN IF NOT EXISTS max_connections INTEGER;
+ALTER TABLE feeds ADD COLUMN IF NOT EXISTS respect_robots BOOLEAN;
@@ -0,0 +1,8 @@
+-- 20250706030000_add_feed_auth.sql
+
+-- フィードの取得時の認証情報とリクエストヘッダー。アプリケーションの暗号化キー（ENCRYPTION_KEY）で暗号化して保存する。
+ALTER TABLE feeds ADD COLUMN IF NOT EXISTS auth BYTEA;
+
+-- 認証情報を設定したフィードは他のユーザーと共有しないため、URLの一意制約は認証情報のないフィードのみに適用する。
+ALTER TABLE feeds DROP CONSTRAINT IF EXISTS feeds_url_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_feeds_url_shared ON feeds (url) WHERE auth IS NULL;
@@ -0,0 +1,5 @@
+-- 20250706040000_add_feed_proxy.sql
+
+-- フィードごとのプロキシ（http・https・socks5）のURL。認証情報を含みうるため、auth と同様に暗号化して保存する。
+-- NULL の場合は全体設定（FETCH_PROXY）を使用する。
+ALTER TABLE feeds ADD COLUMN IF NOT EXISTS proxy BYTEA;
@@ -0,0 +1,5 @@
+-- 20250706050000_add_feed_parse_warning.sql
+
+-- 直近の取得でフィードの解析時に回復した問題（文字コードの誤りや不正なXMLなど）。
+-- 問題がなかった場合は NULL とし、次回の取得で上書きする。
+ALTER TABLE feeds ADD COLUMN IF NOT EXISTS parse_warning TEXT;
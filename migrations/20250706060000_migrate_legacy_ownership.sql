@@ -0,0 +1,42 @@
+-- 20250706060000_migrate_legacy_ownership.sql
+
+-- マルチユーザー化以前のデータを最初に登録したユーザーに引き継ぎ、articles の状態のカラムを削除する。
+-- 所有者のないフォルダ・購読者のいないフィード・articles の既読などの状態が対象。
+-- ユーザーが未登録の場合は、最初のユーザーの登録時に引き継ぐ（UserRepository.Create）ため、カラムを残す。
+-- カラムの削除後は何もしない（毎回の起動で購読者のいないフィードを引き継がないため）。
+DO $$
+DECLARE
+    first_user UUID;
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = 'articles' AND column_name = 'is_read'
+    ) THEN
+        RETURN;
+    END IF;
+    SELECT id INTO first_user FROM users ORDER BY created_at, id LIMIT 1;
+    IF first_user IS NULL THEN
+        RETURN;
+    END IF;
+
+    UPDATE folders SET user_id = first_user WHERE user_id IS NULL;
+
+    INSERT INTO subscriptions (id, user_id, feed_id, name, folder_id, created_at)
+    SELECT gen_random_uuid(), first_user, f.id, f.name, fo.id, f.created_at
+    FROM feeds f
+    LEFT JOIN folders fo ON fo.id = f.folder_id AND fo.user_id = first_user
+    WHERE NOT EXISTS (SELECT 1 FROM subscriptions s WHERE s.feed_id = f.id)
+    ON CONFLICT (user_id, feed_id) DO NOTHING;
+
+    INSERT INTO article_states (user_id, article_id, is_read, is_later, playback_position_seconds, played, downloaded)
+    SELECT first_user, id, is_read, is_later, playback_position_seconds, played, downloaded FROM articles
+    WHERE is_read OR is_later OR playback_position_seconds > 0 OR played OR downloaded
+    ON CONFLICT (user_id, article_id) DO NOTHING;
+
+    ALTER TABLE articles
+        DROP COLUMN is_read,
+        DROP COLUMN is_later,
+        DROP COLUMN playback_position_seconds,
+        DROP COLUMN played,
+        DROP COLUMN downloaded;
+END $$;
@@ -0,0 +1,18 @@
+-- 20250706070000_drop_article_guid.sql
+
+-- エントリー識別子は article_sources.guid（フィードごと）を正とし、重複していた articles.guid を削除する。
+-- 削除前に、article_sources に未設定のものを最初に取得したフィードの値で補完する。
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = 'articles' AND column_name = 'guid'
+    ) THEN
+        UPDATE article_sources src SET guid = a.guid
+        FROM articles a
+        WHERE src.article_id = a.id AND src.feed_id = a.feed_id AND src.guid IS NULL AND a.guid IS NOT NULL
+        AND NOT EXISTS (SELECT 1 FROM article_sources dup WHERE dup.feed_id = src.feed_id AND dup.guid = a.guid);
+
+        ALTER TABLE articles DROP COLUMN guid;
+    END IF;
+END $$;
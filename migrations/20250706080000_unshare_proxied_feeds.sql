@@ -0,0 +1,6 @@
+-- 20250706080000_unshare_proxied_feeds.sql
+
+-- プロキシを設定したフィードは、認証情報を設定したフィードと同様に他のユーザーと共有しない。
+-- プロキシのホストや認証情報を他の購読者に返さないよう、共有の一意制約の対象から除く。
+CREATE UNIQUE INDEX IF NOT EXISTS idx_feeds_url_public ON feeds (url) WHERE auth IS NULL AND proxy IS NULL;
+DROP INDEX IF EXISTS idx_feeds_url_shared;
@@ -0,0 +1,5 @@
+-- 20250706090000_drop_article_url_key.sql
+
+-- 重複判定は normalized_url で行うため、元URLの一意制約は不要
+-- （既存記事の normalized_url は起動時に設定し、正規化後に一致する記事は統合する）
+ALTER TABLE articles DROP CONSTRAINT IF EXISTS articles_url_key;
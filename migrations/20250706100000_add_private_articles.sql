@@ -0,0 +1,14 @@
+-- 20250706100000_add_private_articles.sql
+
+-- 認証情報を設定したフィードの記事は、他のフィードの記事と統合せずにフィードごとに保持する（is_private）。
+-- 認証情報を付けて取得した本文が、同じURLの記事を参照する他のユーザーに表示されないようにするため。
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS is_private BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_articles_normalized_url_shared ON articles (normalized_url) WHERE NOT is_private;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_articles_private_feed_url ON articles (feed_id, normalized_url) WHERE is_private;
+DROP INDEX IF EXISTS idx_articles_normalized_url;
+
+-- 削除済み記事の記録も、認証情報を設定したフィードの記事はフィードごとに保持する
+ALTER TABLE purged_articles ADD COLUMN IF NOT EXISTS is_private BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE purged_articles DROP CONSTRAINT IF EXISTS purged_articles_pkey;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_purged_articles_shared ON purged_articles (normalized_url) WHERE NOT is_private;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_purged_articles_private ON purged_articles (feed_id, normalized_url) WHERE is_private;
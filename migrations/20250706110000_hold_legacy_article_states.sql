@@ -0,0 +1,38 @@
+-- 20250706110000_hold_legacy_article_states.sql
+
+-- ユーザーの登録前に残っている articles の既読などの状態（マルチユーザー化以前のカラム）を
+-- legacy_article_states に移し、articles のカラムを削除する。
+-- 移した状態は最初に登録したユーザーに引き継いで削除する（UserRepository.Create）。
+-- ユーザーの登録後にカラムが残っている場合は、最初のユーザーの登録時に引き継ぎ済みのため移さない。
+CREATE TABLE IF NOT EXISTS legacy_article_states (
+    article_id UUID PRIMARY KEY REFERENCES articles(id) ON DELETE CASCADE,
+    is_read BOOLEAN NOT NULL DEFAULT FALSE,
+    is_later BOOLEAN NOT NULL DEFAULT FALSE,
+    playback_position_seconds INTEGER NOT NULL DEFAULT 0,
+    played BOOLEAN NOT NULL DEFAULT FALSE,
+    downloaded BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = 'articles' AND column_name = 'is_read'
+    ) THEN
+        RETURN;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM users) THEN
+        INSERT INTO legacy_article_states (article_id, is_read, is_later, playback_position_seconds, played, downloaded)
+        SELECT id, is_read, is_later, playback_position_seconds, played, downloaded FROM articles
+        WHERE is_read OR is_later OR playback_position_seconds > 0 OR played OR downloaded
+        ON CONFLICT (article_id) DO NOTHING;
+    END IF;
+
+    ALTER TABLE articles
+        DROP COLUMN is_read,
+        DROP COLUMN is_later,
+        DROP COLUMN playback_position_seconds,
+        DROP COLUMN played,
+        DROP COLUMN downloaded;
+END $$;